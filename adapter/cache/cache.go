@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"api/domain"
+	"context"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"golang.org/x/sync/singleflight"
+	"log"
+	"sync/atomic"
+)
+
+const traceNameTasksCache = "TasksCache"
+
+// Backend holds cached tasks under keys which are unique across organizations. Entries
+// expire after the time to live of the backend.
+type Backend interface {
+	// Get returns false for a missing or expired key.
+	Get(ctx context.Context, key string) (domain.Task, bool, error)
+	Set(ctx context.Context, key string, task domain.Task) error
+	Delete(ctx context.Context, keys ...string) error
+	// Clear drops every entry of the backend.
+	Clear(ctx context.Context) error
+}
+
+// Cache holds the tasks read through the repos returned by Wrap. Tasks are evicted when
+// they are written through one of those repos, and from the caches of the other
+// instances when Invalidate is called with the changes Postgres announces.
+type Cache struct {
+	backend Backend
+	loads   singleflight.Group
+	// generation counts invalidations. A task loaded while it changed is dropped again
+	// instead of being cached stale.
+	generation atomic.Uint64
+
+	hits   metric.Int64Counter
+	misses metric.Int64Counter
+}
+
+func New(backend Backend) (*Cache, error) {
+	meter := otel.GetMeterProvider().Meter(traceNameTasksCache)
+
+	hits, err := meter.Int64Counter("tasks_cache.hits",
+		metric.WithDescription("Number of tasks read from the cache"))
+	if err != nil {
+		return nil, err
+	}
+
+	misses, err := meter.Int64Counter("tasks_cache.misses",
+		metric.WithDescription("Number of tasks read from the repo because they were not cached"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cache{backend: backend, hits: hits, misses: misses}, nil
+}
+
+// Wrap returns repo with GetTaskById read through the cache.
+func (c *Cache) Wrap(repo domain.TasksRepo) *TasksRepo {
+	return &TasksRepo{next: repo, cache: c}
+}
+
+// Invalidate evicts the tasks of a change. It suits the handler of the task changes
+// listener, which sees the changes made through every instance.
+func (c *Cache) Invalidate(change domain.TaskChange) {
+	c.evict(context.Background(), change.OrgID, change.Task.ID)
+}
+
+// Clear evicts every task, for when changes may have been missed.
+func (c *Cache) Clear(ctx context.Context) {
+	c.generation.Add(1)
+	if err := c.backend.Clear(ctx); err != nil {
+		log.Printf("error while clearing tasks cache: %v", err)
+	}
+}
+
+// get returns the task from the cache, or loads it with load and caches it. Concurrent
+// misses of the same task share a single load.
+func (c *Cache) get(ctx context.Context, orgID, id uuid.UUID, load func(ctx context.Context) (domain.Task, error)) (domain.Task, error) {
+	key := cacheKey(orgID, id)
+	task, ok, err := c.backend.Get(ctx, key)
+	if err != nil {
+		log.Printf("error while reading task %s from cache: %v", id, err)
+	}
+	if ok {
+		c.hits.Add(ctx, 1)
+		return task, nil
+	}
+	c.misses.Add(ctx, 1)
+
+	// The load is shared, so it must not be cancelled with the request which began it.
+	loaded, err, _ := c.loads.Do(key, func() (any, error) {
+		loadCtx := context.WithoutCancel(ctx)
+		generation := c.generation.Load()
+		task, err := load(loadCtx)
+		if err != nil {
+			return domain.Task{}, err
+		}
+		if err := c.backend.Set(loadCtx, key, task); err != nil {
+			log.Printf("error while caching task %s: %v", id, err)
+		}
+		// An invalidation after the check deletes the task itself, as it increments
+		// the generation before deleting.
+		if c.generation.Load() != generation {
+			_ = c.backend.Delete(loadCtx, key)
+		}
+		return task, nil
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return loaded.(domain.Task), nil
+}
+
+func (c *Cache) evict(ctx context.Context, orgID uuid.UUID, ids ...uuid.UUID) {
+	if len(ids) == 0 {
+		return
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = cacheKey(orgID, id)
+	}
+
+	c.generation.Add(1)
+	if err := c.backend.Delete(ctx, keys...); err != nil {
+		log.Printf("error while evicting tasks from cache: %v", err)
+	}
+}
+
+func cacheKey(orgID, id uuid.UUID) string {
+	return orgID.String() + ":" + id.String()
+}
@@ -0,0 +1,268 @@
+package cache
+
+import (
+	"api/adapter/repo/memory"
+	"api/adapter/repo/repotest"
+	"api/domain"
+	mock "api/mocks/mock_domain"
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+// The cache must not change what the repos return, whichever storage it wraps.
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repos {
+		c, err := New(NewMemoryBackend(100, time.Minute))
+		require.NoError(t, err)
+		store := memory.NewStore()
+		return repotest.Repos{
+			Tasks:         c.Wrap(memory.NewTasksRepo(store)),
+			CalendarFeeds: memory.NewCalendarFeedsRepo(store),
+			Organizations: memory.NewOrganizationsRepo(store),
+		}
+	})
+}
+
+func orgContext() context.Context {
+	return domain.WithOrganization(context.Background(), domain.DefaultOrganizationID)
+}
+
+func newTestRepo(t *testing.T) (*TasksRepo, *mock.MockTasksRepo) {
+	t.Helper()
+	c, err := New(NewMemoryBackend(100, time.Minute))
+	require.NoError(t, err)
+	repoMock := mock.NewMockTasksRepo(gomock.NewController(t))
+	return c.Wrap(repoMock), repoMock
+}
+
+func TestTasksRepo_GetTaskById(t *testing.T) {
+	repo, repoMock := newTestRepo(t)
+	task := domain.Task{ID: uuid.New(), Title: "Do unit tests"}
+	repoMock.EXPECT().GetTaskById(gomock.Any(), task.ID).Return(task, nil).Times(1)
+
+	for range 3 {
+		got, err := repo.GetTaskById(orgContext(), task.ID)
+		require.NoError(t, err)
+		require.Equal(t, task, got)
+	}
+
+	// Tasks are cached per organization.
+	otherOrg := domain.WithOrganization(context.Background(), uuid.New())
+	repoMock.EXPECT().GetTaskById(gomock.Any(), task.ID).Return(domain.Task{}, domain.ErrTaskNotFound).Times(2)
+	for range 2 {
+		_, err := repo.GetTaskById(otherOrg, task.ID)
+		require.ErrorIs(t, err, domain.ErrTaskNotFound)
+	}
+}
+
+func TestTasksRepo_GetTaskById_Concurrently(t *testing.T) {
+	repo, repoMock := newTestRepo(t)
+	task := domain.Task{ID: uuid.New(), Title: "Do unit tests"}
+	release := make(chan struct{})
+	repoMock.EXPECT().GetTaskById(gomock.Any(), task.ID).DoAndReturn(func(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+		<-release
+		return task, nil
+	}).Times(1)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := repo.GetTaskById(orgContext(), task.ID)
+			require.NoError(t, err)
+			require.Equal(t, task, got)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+}
+
+func TestTasksRepo_GetTaskById_ReadYourWrites(t *testing.T) {
+	repo, repoMock := newTestRepo(t)
+	task := domain.Task{ID: uuid.New(), Title: "Do unit tests"}
+	updated := domain.Task{ID: task.ID, Title: "Do integration tests"}
+
+	// The cache is filled from the primary.
+	repoMock.EXPECT().GetTaskById(gomock.Any(), task.ID).DoAndReturn(func(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+		require.True(t, domain.ReadYourWritesFromContext(ctx))
+		return task, nil
+	})
+	_, err := repo.GetTaskById(orgContext(), task.ID)
+	require.NoError(t, err)
+
+	// Reads which have to see their own writes bypass it.
+	repoMock.EXPECT().GetTaskById(gomock.Any(), task.ID).Return(updated, nil).Times(2)
+	for range 2 {
+		got, err := repo.GetTaskById(domain.WithReadYourWrites(orgContext()), task.ID)
+		require.NoError(t, err)
+		require.Equal(t, updated, got)
+	}
+
+	got, err := repo.GetTaskById(orgContext(), task.ID)
+	require.NoError(t, err)
+	require.Equal(t, task, got)
+}
+
+func TestTasksRepo_Writes(t *testing.T) {
+	repo, repoMock := newTestRepo(t)
+	task := domain.Task{ID: uuid.New(), Title: "Do unit tests"}
+	updated := domain.Task{ID: task.ID, Title: "Do integration tests"}
+
+	repoMock.EXPECT().GetTaskById(gomock.Any(), task.ID).Return(task, nil)
+	got, err := repo.GetTaskById(orgContext(), task.ID)
+	require.NoError(t, err)
+	require.Equal(t, task, got)
+
+	repoMock.EXPECT().UpdateTask(gomock.Any(), updated).Return(updated, nil)
+	_, err = repo.UpdateTask(orgContext(), updated)
+	require.NoError(t, err)
+
+	repoMock.EXPECT().GetTaskById(gomock.Any(), task.ID).Return(updated, nil)
+	got, err = repo.GetTaskById(orgContext(), task.ID)
+	require.NoError(t, err)
+	require.Equal(t, updated, got)
+
+	repoMock.EXPECT().DeleteTask(gomock.Any(), task.ID).Return(updated, nil)
+	_, err = repo.DeleteTask(orgContext(), task.ID)
+	require.NoError(t, err)
+
+	repoMock.EXPECT().GetTaskById(gomock.Any(), task.ID).Return(domain.Task{}, domain.ErrTaskNotFound)
+	_, err = repo.GetTaskById(orgContext(), task.ID)
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+}
+
+func TestTasksRepo_InTx(t *testing.T) {
+	repo, repoMock := newTestRepo(t)
+	txRepoMock := mock.NewMockTasksRepo(gomock.NewController(t))
+	task := domain.Task{ID: uuid.New(), Title: "Do unit tests"}
+
+	repoMock.EXPECT().GetTaskById(gomock.Any(), task.ID).Return(task, nil).Times(2)
+	repoMock.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repo domain.TasksRepo) error) error {
+		return fn(txRepoMock)
+	})
+	// Reads within the transaction are never served from the cache.
+	txRepoMock.EXPECT().GetTaskById(gomock.Any(), task.ID).Return(task, nil)
+	txRepoMock.EXPECT().DeleteTask(gomock.Any(), task.ID).Return(task, nil)
+
+	_, err := repo.GetTaskById(orgContext(), task.ID)
+	require.NoError(t, err)
+	err = repo.InTx(orgContext(), func(txRepo domain.TasksRepo) error {
+		if _, err := txRepo.GetTaskById(orgContext(), task.ID); err != nil {
+			return err
+		}
+		_, err := txRepo.DeleteTask(orgContext(), task.ID)
+		return err
+	})
+	require.NoError(t, err)
+	_, err = repo.GetTaskById(orgContext(), task.ID)
+	require.NoError(t, err)
+}
+
+func TestCache_Invalidate(t *testing.T) {
+	c, err := New(NewMemoryBackend(100, time.Minute))
+	require.NoError(t, err)
+	repoMock := mock.NewMockTasksRepo(gomock.NewController(t))
+	repo := c.Wrap(repoMock)
+	task := domain.Task{ID: uuid.New(), Title: "Do unit tests"}
+	change := domain.TaskChange{Type: domain.EventTaskUpdated, Task: task, OrgID: domain.DefaultOrganizationID}
+
+	// A task changed while it is loaded is not cached, as it may be stale.
+	repoMock.EXPECT().GetTaskById(gomock.Any(), task.ID).DoAndReturn(func(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+		c.Invalidate(change)
+		return task, nil
+	})
+	repoMock.EXPECT().GetTaskById(gomock.Any(), task.ID).Return(task, nil).Times(2)
+
+	for range 2 {
+		_, err = repo.GetTaskById(orgContext(), task.ID)
+		require.NoError(t, err)
+	}
+	c.Invalidate(change)
+	_, err = repo.GetTaskById(orgContext(), task.ID)
+	require.NoError(t, err)
+}
+
+func TestMemoryBackend(t *testing.T) {
+	ctx := context.Background()
+	current := time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)
+	backend := NewMemoryBackend(2, time.Minute)
+	backend.now = func() time.Time { return current }
+
+	require.NoError(t, backend.Set(ctx, "a", domain.Task{Title: "a"}))
+	require.NoError(t, backend.Set(ctx, "b", domain.Task{Title: "b"}))
+	_, ok, err := backend.Get(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// b is the least recently used task, so it makes room for c.
+	require.NoError(t, backend.Set(ctx, "c", domain.Task{Title: "c"}))
+	require.Equal(t, 2, backend.Len())
+	_, ok, _ = backend.Get(ctx, "b")
+	require.False(t, ok)
+
+	current = current.Add(30 * time.Second)
+	require.NoError(t, backend.Set(ctx, "a", domain.Task{Title: "a2"}))
+	current = current.Add(45 * time.Second)
+	_, ok, _ = backend.Get(ctx, "c")
+	require.False(t, ok)
+	task, ok, _ := backend.Get(ctx, "a")
+	require.True(t, ok)
+	require.Equal(t, "a2", task.Title)
+
+	require.NoError(t, backend.Delete(ctx, "a"))
+	require.Zero(t, backend.Len())
+}
+
+func TestRedisBackend(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	backend, err := NewRedisBackend("redis://"+server.Addr()+"/0", time.Minute)
+	require.NoError(t, err)
+	t.Cleanup(func() { backend.Close() })
+
+	dueDate := time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)
+	task := domain.Task{ID: uuid.New(), Title: "Do unit tests", DueDate: dueDate}
+	require.NoError(t, backend.Set(ctx, "a", task))
+	require.NoError(t, backend.Set(ctx, "b", task))
+	require.NoError(t, server.Set("other", "kept"))
+
+	got, ok, err := backend.Get(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, task, got)
+
+	require.NoError(t, backend.Delete(ctx, "a"))
+	_, ok, err = backend.Get(ctx, "a")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	server.FastForward(2 * time.Minute)
+	_, ok, err = backend.Get(ctx, "b")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, backend.Set(ctx, "c", task))
+	require.NoError(t, backend.Clear(ctx))
+	_, ok, err = backend.Get(ctx, "c")
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.True(t, server.Exists("other"))
+
+	// The cache falls back to the repo while the server is down.
+	c, err := New(backend)
+	require.NoError(t, err)
+	repoMock := mock.NewMockTasksRepo(gomock.NewController(t))
+	repoMock.EXPECT().GetTaskById(gomock.Any(), task.ID).Return(task, nil)
+	server.Close()
+	got, err = c.Wrap(repoMock).GetTaskById(orgContext(), task.ID)
+	require.NoError(t, err)
+	require.Equal(t, task, got)
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"api/domain"
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryBackend keeps the tasks in the memory of the process, evicting the least
+// recently used one once it holds size tasks.
+type MemoryBackend struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	// order holds the entries, most recently used first.
+	order *list.List
+	now   func() time.Time
+}
+
+type memoryEntry struct {
+	key       string
+	task      domain.Task
+	expiresAt time.Time
+}
+
+func NewMemoryBackend(size int, ttl time.Duration) *MemoryBackend {
+	return &MemoryBackend{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+func (mb *MemoryBackend) Get(_ context.Context, key string) (domain.Task, bool, error) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	element, ok := mb.entries[key]
+	if !ok {
+		return domain.Task{}, false, nil
+	}
+	entry := element.Value.(*memoryEntry)
+	if !mb.now().Before(entry.expiresAt) {
+		mb.remove(element)
+		return domain.Task{}, false, nil
+	}
+	mb.order.MoveToFront(element)
+	return entry.task, true, nil
+}
+
+func (mb *MemoryBackend) Set(_ context.Context, key string, task domain.Task) error {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	expiresAt := mb.now().Add(mb.ttl)
+	if element, ok := mb.entries[key]; ok {
+		entry := element.Value.(*memoryEntry)
+		entry.task, entry.expiresAt = task, expiresAt
+		mb.order.MoveToFront(element)
+		return nil
+	}
+
+	mb.entries[key] = mb.order.PushFront(&memoryEntry{key: key, task: task, expiresAt: expiresAt})
+	for mb.order.Len() > mb.size {
+		mb.remove(mb.order.Back())
+	}
+	return nil
+}
+
+func (mb *MemoryBackend) Delete(_ context.Context, keys ...string) error {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := mb.entries[key]; ok {
+			mb.remove(element)
+		}
+	}
+	return nil
+}
+
+func (mb *MemoryBackend) Clear(_ context.Context) error {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	mb.entries = make(map[string]*list.Element)
+	mb.order.Init()
+	return nil
+}
+
+// Len returns the number of tasks held, expired ones included.
+func (mb *MemoryBackend) Len() int {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	return mb.order.Len()
+}
+
+func (mb *MemoryBackend) remove(element *list.Element) {
+	mb.order.Remove(element)
+	delete(mb.entries, element.Value.(*memoryEntry).key)
+}
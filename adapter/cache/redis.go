@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"api/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+const redisKeyPrefix = "tasks:"
+
+// RedisBackend keeps the tasks in a server speaking the Redis protocol, which the
+// instances of the API can share. Redis evicts the expired tasks itself.
+type RedisBackend struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func NewRedisBackend(url string, ttl time.Duration) (*RedisBackend, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisBackend{client: redis.NewClient(opts), ttl: ttl}, nil
+}
+
+func (rb *RedisBackend) Get(ctx context.Context, key string) (domain.Task, bool, error) {
+	data, err := rb.client.Get(ctx, redisKeyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return domain.Task{}, false, nil
+	}
+	if err != nil {
+		return domain.Task{}, false, err
+	}
+
+	var task domain.Task
+	if err := json.Unmarshal(data, &task); err != nil {
+		return domain.Task{}, false, err
+	}
+	return task, true, nil
+}
+
+func (rb *RedisBackend) Set(ctx context.Context, key string, task domain.Task) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	return rb.client.Set(ctx, redisKeyPrefix+key, data, rb.ttl).Err()
+}
+
+func (rb *RedisBackend) Delete(ctx context.Context, keys ...string) error {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = redisKeyPrefix + key
+	}
+	return rb.client.Del(ctx, prefixed...).Err()
+}
+
+// Clear deletes the tasks only, so the server may hold other data.
+func (rb *RedisBackend) Clear(ctx context.Context) error {
+	iter := rb.client.Scan(ctx, 0, redisKeyPrefix+"*", 100).Iterator()
+	var keys []string
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return rb.client.Del(ctx, keys...).Err()
+}
+
+func (rb *RedisBackend) Close() error {
+	return rb.client.Close()
+}
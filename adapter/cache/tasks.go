@@ -0,0 +1,195 @@
+package cache
+
+import (
+	"api/domain"
+	"context"
+	"github.com/google/uuid"
+	"sync"
+)
+
+// TasksRepo reads tasks by id through the cache and evicts the tasks it writes. Every
+// other operation goes to the wrapped repo.
+type TasksRepo struct {
+	next  domain.TasksRepo
+	cache *Cache
+}
+
+// GetTaskById bypasses the cache for reads which have to see their own writes, as the
+// cache may still hold a task which a replica lagging behind loaded before them. The
+// cache itself is only filled from the primary for the same reason.
+func (tr *TasksRepo) GetTaskById(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	orgID, ok := domain.OrganizationFromContext(ctx)
+	if !ok || domain.ReadYourWritesFromContext(ctx) {
+		return tr.next.GetTaskById(ctx, id)
+	}
+	return tr.cache.get(ctx, orgID, id, func(ctx context.Context) (domain.Task, error) {
+		return tr.next.GetTaskById(domain.WithReadYourWrites(ctx), id)
+	})
+}
+
+func (tr *TasksRepo) GetTaskByIdForUpdate(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	return tr.next.GetTaskByIdForUpdate(ctx, id)
+}
+
+func (tr *TasksRepo) GetTasks(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
+	return tr.next.GetTasks(ctx, filter)
+}
+
+func (tr *TasksRepo) GetTasksPage(ctx context.Context, filter domain.TaskFilter, after *domain.TaskCursor, limit int) ([]domain.Task, error) {
+	return tr.next.GetTasksPage(ctx, filter, after, limit)
+}
+
+func (tr *TasksRepo) CountTasks(ctx context.Context, filter domain.TaskFilter) (int, error) {
+	return tr.next.CountTasks(ctx, filter)
+}
+
+func (tr *TasksRepo) GetTasksByIds(ctx context.Context, ids []uuid.UUID) ([]domain.Task, error) {
+	return tr.next.GetTasksByIds(ctx, ids)
+}
+
+func (tr *TasksRepo) GetTaskEvents(ctx context.Context, taskIDs []uuid.UUID) ([]domain.Event, error) {
+	return tr.next.GetTaskEvents(ctx, taskIDs)
+}
+
+func (tr *TasksRepo) CreateTask(ctx context.Context, data domain.Task) (domain.Task, error) {
+	task, err := tr.next.CreateTask(ctx, data)
+	if err == nil {
+		tr.evict(ctx, task.ID)
+	}
+	return task, err
+}
+
+func (tr *TasksRepo) UpdateTask(ctx context.Context, data domain.Task) (domain.Task, error) {
+	task, err := tr.next.UpdateTask(ctx, data)
+	tr.evict(ctx, data.ID)
+	return task, err
+}
+
+func (tr *TasksRepo) SearchTasks(ctx context.Context, query string, limit int) ([]domain.TaskSearchResult, error) {
+	return tr.next.SearchTasks(ctx, query, limit)
+}
+
+func (tr *TasksRepo) SearchTasksBySimilarity(ctx context.Context, query string, limit int) ([]domain.TaskSearchResult, error) {
+	return tr.next.SearchTasksBySimilarity(ctx, query, limit)
+}
+
+func (tr *TasksRepo) DeleteTask(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	task, err := tr.next.DeleteTask(ctx, id)
+	tr.evict(ctx, id)
+	return task, err
+}
+
+func (tr *TasksRepo) GetDeletedTasks(ctx context.Context) ([]domain.Task, error) {
+	return tr.next.GetDeletedTasks(ctx)
+}
+
+func (tr *TasksRepo) RestoreTask(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	task, err := tr.next.RestoreTask(ctx, id)
+	tr.evict(ctx, id)
+	return task, err
+}
+
+func (tr *TasksRepo) PurgeTask(ctx context.Context, id uuid.UUID) error {
+	err := tr.next.PurgeTask(ctx, id)
+	tr.evict(ctx, id)
+	return err
+}
+
+// InTx gives fn a repo which bypasses the cache, as the transaction may see tasks no
+// other one does, and evicts the tasks it wrote once it is over.
+func (tr *TasksRepo) InTx(ctx context.Context, fn func(repo domain.TasksRepo) error) error {
+	written := &writtenTasks{}
+	defer func() { tr.evict(ctx, written.ids...) }()
+	return tr.next.InTx(ctx, func(repo domain.TasksRepo) error {
+		return fn(&txTasksRepo{TasksRepo: repo, written: written})
+	})
+}
+
+func (tr *TasksRepo) ImportTasks(ctx context.Context, next func() (domain.Task, error), dryRun bool) (int, error) {
+	written := &writtenTasks{}
+	defer func() { tr.evict(ctx, written.ids...) }()
+	return tr.next.ImportTasks(ctx, func() (domain.Task, error) {
+		task, err := next()
+		if err == nil {
+			written.add(task.ID)
+		}
+		return task, err
+	}, dryRun)
+}
+
+func (tr *TasksRepo) ExportTasks(ctx context.Context, filter domain.TaskFilter, fn func(task domain.Task) error) error {
+	return tr.next.ExportTasks(ctx, filter, fn)
+}
+
+// evict runs after a write whether or not it failed, since a failed write may still have
+// been committed.
+func (tr *TasksRepo) evict(ctx context.Context, ids ...uuid.UUID) {
+	orgID, ok := domain.OrganizationFromContext(ctx)
+	if !ok {
+		return
+	}
+	tr.cache.evict(context.WithoutCancel(ctx), orgID, ids...)
+}
+
+// writtenTasks records the tasks written in a transaction, which fn may write from
+// several goroutines.
+type writtenTasks struct {
+	mu  sync.Mutex
+	ids []uuid.UUID
+}
+
+func (wt *writtenTasks) add(id uuid.UUID) {
+	wt.mu.Lock()
+	defer wt.mu.Unlock()
+	wt.ids = append(wt.ids, id)
+}
+
+// txTasksRepo is the repo of a transaction, recording the tasks written through it.
+type txTasksRepo struct {
+	domain.TasksRepo
+	written *writtenTasks
+}
+
+func (tr *txTasksRepo) CreateTask(ctx context.Context, data domain.Task) (domain.Task, error) {
+	task, err := tr.TasksRepo.CreateTask(ctx, data)
+	if err == nil {
+		tr.written.add(task.ID)
+	}
+	return task, err
+}
+
+func (tr *txTasksRepo) UpdateTask(ctx context.Context, data domain.Task) (domain.Task, error) {
+	tr.written.add(data.ID)
+	return tr.TasksRepo.UpdateTask(ctx, data)
+}
+
+func (tr *txTasksRepo) DeleteTask(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	tr.written.add(id)
+	return tr.TasksRepo.DeleteTask(ctx, id)
+}
+
+func (tr *txTasksRepo) RestoreTask(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	tr.written.add(id)
+	return tr.TasksRepo.RestoreTask(ctx, id)
+}
+
+func (tr *txTasksRepo) PurgeTask(ctx context.Context, id uuid.UUID) error {
+	tr.written.add(id)
+	return tr.TasksRepo.PurgeTask(ctx, id)
+}
+
+func (tr *txTasksRepo) ImportTasks(ctx context.Context, next func() (domain.Task, error), dryRun bool) (int, error) {
+	return tr.TasksRepo.ImportTasks(ctx, func() (domain.Task, error) {
+		task, err := next()
+		if err == nil {
+			tr.written.add(task.ID)
+		}
+		return task, err
+	}, dryRun)
+}
+
+func (tr *txTasksRepo) InTx(ctx context.Context, fn func(repo domain.TasksRepo) error) error {
+	return tr.TasksRepo.InTx(ctx, func(repo domain.TasksRepo) error {
+		return fn(&txTasksRepo{TasksRepo: repo, written: tr.written})
+	})
+}
@@ -0,0 +1,49 @@
+package publisher
+
+import (
+	"api/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/nats-io/nats.go"
+)
+
+// NatsPublisher publishes every event on "<subject>.<event type>". The event ID is sent
+// as the Nats-Msg-Id header, which JetStream streams use for deduplication.
+type NatsPublisher struct {
+	conn    *nats.Conn
+	subject string
+}
+
+func NewNatsPublisher(url, subject string) (*NatsPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name("go-task-tracker-outbox"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats %s: %v", url, err)
+	}
+	return &NatsPublisher{conn: conn, subject: subject}, nil
+}
+
+func (np *NatsPublisher) Publish(ctx context.Context, event domain.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	msg := nats.NewMsg(np.subject + "." + string(event.Type))
+	msg.Header.Set(nats.MsgIdHdr, event.ID.String())
+	msg.Data = data
+
+	if err := np.conn.PublishMsg(msg); err != nil {
+		return err
+	}
+	// Flushing waits for the server to acknowledge the buffered message, so the relay
+	// only marks the event as published once it has left this process.
+	if _, ok := ctx.Deadline(); !ok {
+		return np.conn.Flush()
+	}
+	return np.conn.FlushWithContext(ctx)
+}
+
+func (np *NatsPublisher) Close() error {
+	return np.conn.Drain()
+}
@@ -0,0 +1,67 @@
+package publisher
+
+import (
+	"api/domain"
+	"context"
+	"encoding/json"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func runNatsServer(t *testing.T) *server.Server {
+	ns, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: server.RANDOM_PORT, NoLog: true, NoSigs: true})
+	require.NoError(t, err)
+
+	go ns.Start()
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server did not start")
+	}
+	t.Cleanup(ns.Shutdown)
+	return ns
+}
+
+func TestNatsPublisher_Publish(t *testing.T) {
+	ns := runNatsServer(t)
+
+	sub, err := nats.Connect(ns.ClientURL())
+	require.NoError(t, err)
+	defer sub.Close()
+
+	messages := make(chan *nats.Msg, 1)
+	_, err = sub.ChanSubscribe("tasks.events.>", messages)
+	require.NoError(t, err)
+	require.NoError(t, sub.Flush())
+
+	publisher, err := NewNatsPublisher(ns.ClientURL(), "tasks.events")
+	require.NoError(t, err)
+	defer publisher.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, publisher.Publish(ctx, getEvent()))
+
+	select {
+	case msg := <-messages:
+		require.Equal(t, "tasks.events.TaskCreated", msg.Subject)
+		require.Equal(t, getEvent().ID.String(), msg.Header.Get(nats.MsgIdHdr))
+
+		var event domain.Event
+		require.NoError(t, json.Unmarshal(msg.Data, &event))
+		require.Equal(t, getEvent().AggregateID, event.AggregateID)
+	case <-ctx.Done():
+		t.Fatal("message was not delivered")
+	}
+}
+
+func TestNatsPublisher_PublishWithoutDeadline(t *testing.T) {
+	ns := runNatsServer(t)
+
+	publisher, err := NewNatsPublisher(ns.ClientURL(), "tasks.events")
+	require.NoError(t, err)
+	defer publisher.Close()
+
+	require.NoError(t, publisher.Publish(context.Background(), getEvent()))
+}
@@ -0,0 +1,60 @@
+package publisher
+
+import (
+	"api/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// WriterPublisher writes every event as a single NDJSON line.
+type WriterPublisher struct {
+	mu     sync.Mutex
+	writer io.Writer
+	closer io.Closer
+}
+
+func NewWriterPublisher(writer io.Writer) *WriterPublisher {
+	return &WriterPublisher{writer: writer}
+}
+
+func NewStdoutPublisher() *WriterPublisher {
+	return NewWriterPublisher(os.Stdout)
+}
+
+func NewFilePublisher(path string) (*WriterPublisher, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open events file %s: %v", path, err)
+	}
+	return &WriterPublisher{writer: file, closer: file}, nil
+}
+
+func (wp *WriterPublisher) Publish(_ context.Context, event domain.Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	if _, err := wp.writer.Write(line); err != nil {
+		return err
+	}
+	if file, ok := wp.writer.(*os.File); ok && wp.closer != nil {
+		return file.Sync()
+	}
+	return nil
+}
+
+func (wp *WriterPublisher) Close() error {
+	if wp.closer == nil {
+		return nil
+	}
+	return wp.closer.Close()
+}
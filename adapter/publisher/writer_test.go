@@ -0,0 +1,69 @@
+package publisher
+
+import (
+	"api/domain"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func getEvent() domain.Event {
+	return domain.Event{
+		ID:          uuid.MustParse("7b0a2f5e-3c59-4a3c-9a43-5d1f7a2e8c11"),
+		Type:        domain.EventTaskCreated,
+		AggregateID: uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce"),
+		Payload:     json.RawMessage(`{"id":"1461ec84-ccff-4f3c-af34-65d0856ac3ce","title":"Do unit tests"}`),
+		OccurredAt:  time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestWriterPublisher_Publish(t *testing.T) {
+	var buf bytes.Buffer
+	publisher := NewWriterPublisher(&buf)
+
+	require.NoError(t, publisher.Publish(context.Background(), getEvent()))
+	require.NoError(t, publisher.Publish(context.Background(), getEvent()))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Equal(t, 2, len(lines))
+
+	var event domain.Event
+	require.NoError(t, json.Unmarshal(lines[0], &event))
+	require.Equal(t, getEvent().ID, event.ID)
+	require.Equal(t, getEvent().Type, event.Type)
+	require.JSONEq(t, string(getEvent().Payload), string(event.Payload))
+}
+
+func TestFilePublisher_Appends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+
+	publisher, err := NewFilePublisher(path)
+	require.NoError(t, err)
+	require.NoError(t, publisher.Publish(context.Background(), getEvent()))
+	require.NoError(t, publisher.Close())
+
+	publisher, err = NewFilePublisher(path)
+	require.NoError(t, err)
+	require.NoError(t, publisher.Publish(context.Background(), getEvent()))
+	require.NoError(t, publisher.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	count := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event domain.Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		count++
+	}
+	require.Equal(t, 2, count)
+}
@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"api/domain"
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps the token buckets in the memory of the process, so every replica
+// of the API enforces the limits on its own.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]memoryBucket
+}
+
+type memoryBucket struct {
+	domain.TokenBucket
+	window time.Duration
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]memoryBucket)}
+}
+
+func (ms *MemoryStore) Take(_ context.Context, key string, policy domain.RateLimitPolicy, now time.Time) (domain.RateLimitResult, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	var current *domain.TokenBucket
+	if bucket, ok := ms.buckets[key]; ok {
+		current = &bucket.TokenBucket
+	}
+	bucket, result := policy.Take(current, now)
+	ms.buckets[key] = memoryBucket{TokenBucket: bucket, window: policy.Window}
+
+	return result, nil
+}
+
+// Run drops the buckets of idle clients every interval until ctx is cancelled.
+func (ms *MemoryStore) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			ms.Sweep(now)
+		}
+	}
+}
+
+// Sweep drops the buckets which have been idle for a whole window and returns how many
+// were dropped. Such buckets are full again, just like the bucket of a new client.
+func (ms *MemoryStore) Sweep(now time.Time) int {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	count := 0
+	for key, bucket := range ms.buckets {
+		if now.Sub(bucket.UpdatedAt) >= bucket.window {
+			delete(ms.buckets, key)
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"api/domain"
+	"context"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_Take(t *testing.T) {
+	store := NewMemoryStore()
+	policy := domain.RateLimitPolicy{Pattern: "/api/*", Limit: 2, Window: time.Minute}
+	now := time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)
+
+	for _, allowed := range []bool{true, true, false} {
+		result, err := store.Take(context.Background(), "a", policy, now)
+		require.NoError(t, err)
+		require.Equal(t, allowed, result.Allowed)
+	}
+
+	// Clients have buckets of their own.
+	result, err := store.Take(context.Background(), "b", policy, now)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	result, err = store.Take(context.Background(), "a", policy, now.Add(30*time.Second))
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+}
+
+func TestMemoryStore_TakeConcurrently(t *testing.T) {
+	store := NewMemoryStore()
+	policy := domain.RateLimitPolicy{Pattern: "/api/*", Limit: 50, Window: time.Hour}
+	now := time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := 0
+	var errs []error
+	for range 200 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := store.Take(context.Background(), "a", policy, now)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+			}
+			if result.Allowed {
+				allowed++
+			}
+		}()
+	}
+	wg.Wait()
+	require.Empty(t, errs)
+	require.Equal(t, 50, allowed)
+}
+
+func TestMemoryStore_Sweep(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)
+
+	_, err := store.Take(context.Background(), "minute", domain.RateLimitPolicy{Limit: 1, Window: time.Minute}, now)
+	require.NoError(t, err)
+	_, err = store.Take(context.Background(), "hour", domain.RateLimitPolicy{Limit: 1, Window: time.Hour}, now)
+	require.NoError(t, err)
+
+	require.Equal(t, 0, store.Sweep(now.Add(30*time.Second)))
+	require.Equal(t, 1, store.Sweep(now.Add(time.Minute)))
+	require.Len(t, store.buckets, 1)
+	require.Contains(t, store.buckets, "hour")
+}
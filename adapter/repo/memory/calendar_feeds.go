@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"api/domain"
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel"
+)
+
+const traceNameCalendarFeedsRepo = "CalendarFeedsRepo"
+
+// CalendarFeedsRepo keeps feeds by their token hash, so that a token only ever opens the
+// feed of the organization it was created in.
+type CalendarFeedsRepo struct {
+	store *Store
+}
+
+func NewCalendarFeedsRepo(store *Store) *CalendarFeedsRepo {
+	return &CalendarFeedsRepo{store: store}
+}
+
+func (cr CalendarFeedsRepo) CreateFeed(ctx context.Context, data domain.CalendarFeed, tokenHash string) (domain.CalendarFeed, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameCalendarFeedsRepo).Start(ctx, traceNameCalendarFeedsRepo+".CreateFeed")
+	defer span.End()
+
+	feed := domain.CalendarFeed{
+		ID:        data.ID,
+		Name:      data.Name,
+		Status:    data.Status,
+		CreatedAt: now(),
+	}
+	err := cr.store.update(ctx, func(t *txn) error {
+		if _, ok := t.feeds[tokenHash]; ok {
+			return fmt.Errorf("failed to save calendar feed: token hash already exists")
+		}
+		t.feeds[tokenHash] = feedRow{feed: feed, orgID: t.orgID}
+		return nil
+	})
+	if err != nil {
+		return domain.CalendarFeed{}, err
+	}
+	return feed, nil
+}
+
+func (cr CalendarFeedsRepo) GetFeedByTokenHash(ctx context.Context, tokenHash string) (domain.CalendarFeed, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameCalendarFeedsRepo).Start(ctx, traceNameCalendarFeedsRepo+".GetFeedByTokenHash")
+	defer span.End()
+
+	var feed domain.CalendarFeed
+	err := cr.store.view(ctx, func(t *txn) error {
+		row, ok := t.feed(tokenHash)
+		if !ok {
+			return domain.ErrCalendarFeedNotFound
+		}
+		feed = row.feed
+		return nil
+	})
+	return feed, err
+}
+
+func (cr CalendarFeedsRepo) RotateFeedToken(ctx context.Context, tokenHash, newTokenHash string) (domain.CalendarFeed, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameCalendarFeedsRepo).Start(ctx, traceNameCalendarFeedsRepo+".RotateFeedToken")
+	defer span.End()
+
+	var feed domain.CalendarFeed
+	err := cr.store.update(ctx, func(t *txn) error {
+		row, ok := t.feed(tokenHash)
+		if !ok {
+			return domain.ErrCalendarFeedNotFound
+		}
+		if _, ok := t.feeds[newTokenHash]; ok {
+			return fmt.Errorf("failed to rotate calendar feed token: token hash already exists")
+		}
+		rotatedAt := now()
+		row.feed.RotatedAt = &rotatedAt
+		delete(t.feeds, tokenHash)
+		t.feeds[newTokenHash] = row
+		feed = row.feed
+		return nil
+	})
+	return feed, err
+}
+
+func (cr CalendarFeedsRepo) DeleteFeed(ctx context.Context, tokenHash string) error {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameCalendarFeedsRepo).Start(ctx, traceNameCalendarFeedsRepo+".DeleteFeed")
+	defer span.End()
+
+	return cr.store.update(ctx, func(t *txn) error {
+		if _, ok := t.feed(tokenHash); !ok {
+			return domain.ErrCalendarFeedNotFound
+		}
+		delete(t.feeds, tokenHash)
+		return nil
+	})
+}
+
+// feed returns the feed with the given token hash if it belongs to the organization of
+// the transaction.
+func (t *txn) feed(tokenHash string) (feedRow, bool) {
+	row, ok := t.feeds[tokenHash]
+	if !ok || row.orgID != t.orgID {
+		return feedRow{}, false
+	}
+	return row, true
+}
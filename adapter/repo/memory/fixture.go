@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"api/domain"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"os"
+)
+
+// Fixture is the initial content of a Store. Tasks belong to the organization with the
+// slug in their organization field, or to the default organization without one.
+type Fixture struct {
+	Organizations []domain.Organization `json:"organizations"`
+	Tasks         []FixtureTask         `json:"tasks"`
+}
+
+type FixtureTask struct {
+	domain.Task
+	Organization string `json:"organization,omitempty"`
+}
+
+// LoadFixture seeds the store with the fixture in the JSON file at path.
+func (s *Store) LoadFixture(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read fixture: %v", err)
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return fmt.Errorf("failed to parse fixture %s: %v", path, err)
+	}
+	return s.Seed(fixture)
+}
+
+// Seed adds the organizations and tasks of fixture to the store, all or none of them.
+// Unlike tasks created through the repos, seeded tasks keep their creation and deletion
+// times and are neither written to the outbox nor announced as changes.
+func (s *Store) Seed(fixture Fixture) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seeded := s.state.clone()
+	for _, org := range fixture.Organizations {
+		if org.ID == uuid.Nil {
+			org.ID = uuid.New()
+		}
+		if err := org.Validate(); err != nil {
+			return fmt.Errorf("failed to seed organization %q: %w", org.Slug, err)
+		}
+		if _, err := seeded.createOrganization(org); err != nil {
+			return fmt.Errorf("failed to seed organization: %w", err)
+		}
+	}
+
+	t := &txn{state: seeded, store: s}
+	for _, task := range fixture.Tasks {
+		t.orgID = domain.DefaultOrganizationID
+		if task.Organization != "" {
+			org, ok := seeded.organizationBySlug(task.Organization)
+			if !ok {
+				return fmt.Errorf("failed to seed task %s: organization %s: %w", task.ID, task.Organization, domain.ErrOrganizationNotFound)
+			}
+			t.orgID = org.ID
+		}
+
+		if task.ID == uuid.Nil {
+			task.ID = uuid.New()
+		}
+		if _, ok := seeded.tasks[task.ID]; ok {
+			return fmt.Errorf("failed to seed task %s: task already exists", task.ID)
+		}
+		if err := task.Validate(); err != nil {
+			return fmt.Errorf("failed to seed task %s: %w", task.ID, err)
+		}
+		if task.CreatedAt.IsZero() {
+			task.CreatedAt = now()
+		}
+		t.insertTask(task.Task)
+	}
+
+	s.state = seeded
+	return nil
+}
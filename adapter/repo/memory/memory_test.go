@@ -0,0 +1,267 @@
+package memory
+
+import (
+	"api/adapter/repo/repotest"
+	"api/domain"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repos {
+		store := NewStore()
+		return repotest.Repos{
+			Tasks:         NewTasksRepo(store),
+			CalendarFeeds: NewCalendarFeedsRepo(store),
+			Organizations: NewOrganizationsRepo(store),
+		}
+	})
+}
+
+// orgContext scopes the repos to the default organization.
+func orgContext() context.Context {
+	return domain.WithOrganization(context.Background(), domain.DefaultOrganizationID)
+}
+
+func newTask(id uuid.UUID) domain.Task {
+	return domain.Task{
+		ID:          id,
+		Title:       "Do unit tests",
+		Description: "Create extensive unit tests for all layers",
+		Status:      domain.StatusPending,
+		DueDate:     time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestStore_OnChange(t *testing.T) {
+	store := NewStore()
+	var changes []domain.TaskChange
+	store.OnChange(func(change domain.TaskChange) {
+		changes = append(changes, change)
+	})
+	repo := NewTasksRepo(store)
+	id := uuid.New()
+
+	_, err := repo.CreateTask(orgContext(), newTask(id))
+	require.NoError(t, err)
+	_, err = repo.DeleteTask(orgContext(), id)
+	require.NoError(t, err)
+	_, err = repo.RestoreTask(orgContext(), id)
+	require.NoError(t, err)
+
+	// Rolled back changes are never announced.
+	err = repo.InTx(orgContext(), func(txRepo domain.TasksRepo) error {
+		_, err := txRepo.UpdateTask(orgContext(), newTask(id))
+		require.NoError(t, err)
+		return errors.New("rolled back")
+	})
+	require.Error(t, err)
+
+	require.Len(t, changes, 3)
+	require.Equal(t, domain.EventTaskCreated, changes[0].Type)
+	require.Equal(t, "Do unit tests", changes[0].Task.Title)
+	require.Equal(t, domain.DefaultOrganizationID, changes[0].OrgID)
+	require.Equal(t, "1", changes[0].ID)
+	// A task moved to the trash is only sent with its identity, and a restored one as
+	// created, like the Postgres trigger does.
+	require.Equal(t, domain.EventTaskDeleted, changes[1].Type)
+	require.Equal(t, domain.Task{ID: id, Status: domain.StatusPending}, changes[1].Task)
+	require.Equal(t, domain.EventTaskCreated, changes[2].Type)
+	require.Equal(t, "3", changes[2].ID)
+}
+
+type fakePublisher struct {
+	events  []domain.Event
+	failAt  int
+	failErr error
+}
+
+func (fp *fakePublisher) Publish(_ context.Context, event domain.Event) error {
+	if fp.failErr != nil && len(fp.events) == fp.failAt {
+		return fp.failErr
+	}
+	fp.events = append(fp.events, event)
+	return nil
+}
+
+func (fp *fakePublisher) Close() error {
+	return nil
+}
+
+func TestOutboxRelay_PublishPending(t *testing.T) {
+	store := NewStore()
+	repo := NewTasksRepo(store)
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	for _, id := range ids {
+		_, err := repo.CreateTask(orgContext(), newTask(id))
+		require.NoError(t, err)
+	}
+
+	// The second event fails, so only the first one is marked as published.
+	publisher := &fakePublisher{failAt: 1, failErr: errors.New("nats is down")}
+	relay := NewOutboxRelay(store, publisher, time.Second, 2)
+	count, err := relay.PublishPending(context.Background())
+	require.Error(t, err)
+	require.Equal(t, 1, count)
+
+	publisher.failErr = nil
+	count, err = relay.PublishPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	count, err = relay.PublishPending(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	require.Len(t, publisher.events, 3)
+	for i, event := range publisher.events {
+		require.Equal(t, ids[i], event.AggregateID)
+	}
+}
+
+func TestTrashRetention_PurgeExpired(t *testing.T) {
+	store := NewStore()
+	repo := NewTasksRepo(store)
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	for _, id := range ids {
+		_, err := repo.CreateTask(orgContext(), newTask(id))
+		require.NoError(t, err)
+	}
+	for _, id := range ids[:2] {
+		_, err := repo.DeleteTask(orgContext(), id)
+		require.NoError(t, err)
+	}
+
+	// Only the first task has been in the trash for longer than the retention period.
+	expired := time.Now().UTC().Add(-31 * 24 * time.Hour)
+	row := store.state.tasks[ids[0]]
+	row.task.DeletedAt = &expired
+	store.state.tasks[ids[0]] = row
+
+	retention := NewTrashRetention(store, 30*24*time.Hour, time.Hour)
+	require.Equal(t, 1, retention.PurgeExpired(context.Background()))
+	require.Zero(t, retention.PurgeExpired(context.Background()))
+
+	trash, err := repo.GetDeletedTasks(orgContext())
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	require.Equal(t, ids[1], trash[0].ID)
+}
+
+func TestStore_LoadFixture(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	err := os.WriteFile(path, []byte(`{
+  "organizations": [{"slug": "acme", "name": "Acme"}],
+  "tasks": [
+    {"id": "1461ec84-ccff-4f3c-af34-65d0856ac3c0", "title": "Do unit tests", "status": "PENDING", "due_date": "2025-04-03T00:00:00Z"},
+    {"title": "Acme roadmap", "status": "PENDING", "due_date": "2025-04-03T00:00:00Z", "organization": "acme"},
+    {"title": "Old news", "status": "DONE", "due_date": "2025-04-03T00:00:00Z", "deleted_at": "2025-04-04T00:00:00Z"}
+  ]
+}`), 0o600)
+	require.NoError(t, err)
+
+	store := NewStore()
+	require.NoError(t, store.LoadFixture(path))
+	repo := NewTasksRepo(store)
+
+	task, err := repo.GetTaskById(orgContext(), uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3c0"))
+	require.NoError(t, err)
+	require.Equal(t, "Do unit tests", task.Title)
+	require.False(t, task.CreatedAt.IsZero())
+
+	trash, err := repo.GetDeletedTasks(orgContext())
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	require.Equal(t, "Old news", trash[0].Title)
+
+	acme, err := NewOrganizationsRepo(store).GetOrganizationBySlug(context.Background(), "acme")
+	require.NoError(t, err)
+	tasks, err := repo.GetTasks(domain.WithOrganization(context.Background(), acme.ID), domain.TaskFilter{})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	require.Equal(t, "Acme roadmap", tasks[0].Title)
+
+	// Seeded tasks are not written to the outbox.
+	events, err := repo.GetTaskEvents(orgContext(), []uuid.UUID{task.ID})
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+func TestStore_SeedInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture Fixture
+		err     error
+	}{
+		{
+			name:    "unknown organization",
+			fixture: Fixture{Tasks: []FixtureTask{{Task: newTask(uuid.New()), Organization: "acme"}}},
+			err:     domain.ErrOrganizationNotFound,
+		},
+		{
+			name:    "invalid organization",
+			fixture: Fixture{Organizations: []domain.Organization{{Slug: "Not a slug", Name: "Acme"}}},
+			err:     domain.ErrInvalidOrganization,
+		},
+		{
+			name:    "existing organization",
+			fixture: Fixture{Organizations: []domain.Organization{{Slug: "default", Name: "Default"}}},
+			err:     domain.ErrOrganizationExists,
+		},
+		{
+			name:    "invalid recurrence",
+			fixture: Fixture{Tasks: []FixtureTask{{Task: domain.Task{Title: "Daily", RRule: "FREQ=SOMETIMES"}}}},
+			err:     domain.ErrInvalidRecurrence,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewStore()
+			// The fixture is seeded all or nothing.
+			tt.fixture.Tasks = append([]FixtureTask{{Task: newTask(uuid.New())}}, tt.fixture.Tasks...)
+			require.ErrorIs(t, store.Seed(tt.fixture), tt.err)
+
+			tasks, err := NewTasksRepo(store).GetTasks(orgContext(), domain.TaskFilter{})
+			require.NoError(t, err)
+			require.Empty(t, tasks)
+		})
+	}
+}
+
+func TestTasksRepo_Concurrently(t *testing.T) {
+	store := NewStore()
+	repo := NewTasksRepo(store)
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := uuid.New()
+			err := repo.InTx(orgContext(), func(txRepo domain.TasksRepo) error {
+				if _, err := txRepo.CreateTask(orgContext(), newTask(id)); err != nil {
+					return err
+				}
+				_, err := txRepo.DeleteTask(orgContext(), id)
+				return err
+			})
+			require.NoError(t, err)
+			_, err = repo.RestoreTask(orgContext(), id)
+			require.NoError(t, err)
+			_, err = repo.GetTasks(orgContext(), domain.TaskFilter{})
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	tasks, err := repo.GetTasks(orgContext(), domain.TaskFilter{})
+	require.NoError(t, err)
+	require.Len(t, tasks, 50)
+}
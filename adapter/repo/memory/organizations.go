@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"api/domain"
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel"
+)
+
+const traceNameOrganizationsRepo = "OrganizationsRepo"
+
+type OrganizationsRepo struct {
+	store *Store
+}
+
+func NewOrganizationsRepo(store *Store) *OrganizationsRepo {
+	return &OrganizationsRepo{store: store}
+}
+
+func (or OrganizationsRepo) GetOrganizationBySlug(ctx context.Context, slug string) (domain.Organization, error) {
+	_, span := otel.GetTracerProvider().Tracer(traceNameOrganizationsRepo).Start(ctx, traceNameOrganizationsRepo+".GetOrganizationBySlug")
+	defer span.End()
+
+	or.store.mu.RLock()
+	defer or.store.mu.RUnlock()
+
+	org, ok := or.store.state.organizationBySlug(slug)
+	if !ok {
+		return domain.Organization{}, fmt.Errorf("organization %s: %w", slug, domain.ErrOrganizationNotFound)
+	}
+	return org, nil
+}
+
+func (or OrganizationsRepo) CreateOrganization(ctx context.Context, data domain.Organization) (domain.Organization, error) {
+	_, span := otel.GetTracerProvider().Tracer(traceNameOrganizationsRepo).Start(ctx, traceNameOrganizationsRepo+".CreateOrganization")
+	defer span.End()
+
+	or.store.mu.Lock()
+	defer or.store.mu.Unlock()
+
+	return or.store.state.createOrganization(data)
+}
+
+func (st *state) organizationBySlug(slug string) (domain.Organization, bool) {
+	for _, org := range st.organizations {
+		if org.Slug == slug {
+			return org, true
+		}
+	}
+	return domain.Organization{}, false
+}
+
+// createOrganization keeps ids and slugs unique, like the constraints of the table.
+func (st *state) createOrganization(data domain.Organization) (domain.Organization, error) {
+	if _, ok := st.organizationBySlug(data.Slug); ok {
+		return domain.Organization{}, fmt.Errorf("organization %s: %w", data.Slug, domain.ErrOrganizationExists)
+	}
+	if _, ok := st.organizations[data.ID]; ok {
+		return domain.Organization{}, fmt.Errorf("organization %s: %w", data.ID, domain.ErrOrganizationExists)
+	}
+
+	org := domain.Organization{
+		ID:        data.ID,
+		Slug:      data.Slug,
+		Name:      data.Name,
+		CreatedAt: now(),
+	}
+	st.organizations[org.ID] = org
+	return org, nil
+}
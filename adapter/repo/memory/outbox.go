@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"api/domain"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"log"
+	"time"
+)
+
+const traceNameOutboxRelay = "OutboxRelay"
+
+// OutboxRelay publishes the pending events of a Store in insertion order. Only a single
+// relay may run per store.
+type OutboxRelay struct {
+	store        *Store
+	publisher    domain.Publisher
+	pollInterval time.Duration
+	batchSize    int
+}
+
+func NewOutboxRelay(store *Store, publisher domain.Publisher, pollInterval time.Duration, batchSize int) *OutboxRelay {
+	return &OutboxRelay{
+		store:        store,
+		publisher:    publisher,
+		pollInterval: pollInterval,
+		batchSize:    batchSize,
+	}
+}
+
+// Run polls the outbox until ctx is cancelled. A full batch is followed immediately by
+// the next one, so a backlog drains without waiting for the poll interval.
+func (or *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(or.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		count, err := or.PublishPending(ctx)
+		if err != nil {
+			log.Printf("error while relaying outbox events: %v", err)
+		}
+		if err == nil && count == or.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishPending publishes a single batch of pending events and returns how many were
+// marked as published. Publishing stops at the first failure so that ordering is kept.
+// The store is not locked while publishing, so a slow publisher does not block writes.
+func (or *OutboxRelay) PublishPending(ctx context.Context) (int, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameOutboxRelay).Start(ctx, traceNameOutboxRelay+".PublishPending")
+	defer span.End()
+
+	var events []domain.Event
+	or.store.mu.RLock()
+	for _, row := range or.store.state.events {
+		if len(events) == or.batchSize {
+			break
+		}
+		if !row.published {
+			events = append(events, row.event)
+		}
+	}
+	or.store.mu.RUnlock()
+
+	published := make(map[uuid.UUID]bool)
+	var publishErr error
+	for _, event := range events {
+		if publishErr = or.publisher.Publish(ctx, event); publishErr != nil {
+			publishErr = fmt.Errorf("failed to publish event %s: %v", event.ID, publishErr)
+			break
+		}
+		published[event.ID] = true
+	}
+
+	or.store.mu.Lock()
+	defer or.store.mu.Unlock()
+	for i, row := range or.store.state.events {
+		if published[row.event.ID] {
+			or.store.state.events[i].published = true
+		}
+	}
+	return len(published), publishErr
+}
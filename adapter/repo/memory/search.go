@@ -0,0 +1,298 @@
+package memory
+
+import (
+	"api/adapter/repo/trigram"
+	"api/domain"
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	snippetMaxWords  = 30
+	snippetMaxLength = 200
+)
+
+// SearchTasks approximates the Postgres full-text search: the websearch-style query
+// (quoted phrases, OR and -negation) is matched against the words of the title and the
+// description, reduced by a light English stemmer. Matches in the title rank higher.
+func (tr TasksRepo) SearchTasks(ctx context.Context, query string, limit int) ([]domain.TaskSearchResult, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".SearchTasks")
+	span.SetAttributes(attribute.String("query", query))
+	defer span.End()
+
+	stem := tr.stemmer()
+	alternatives := parseSearchQuery(query, stem)
+	results := []domain.TaskSearchResult{}
+	err := tr.view(ctx, func(t *txn) error {
+		for _, row := range t.liveTasks() {
+			title := stemWords(row.task.Title, stem)
+			description := stemWords(row.task.Description, stem)
+			rank, terms, ok := alternatives.match(title, description)
+			if !ok {
+				continue
+			}
+			results = append(results, domain.TaskSearchResult{
+				Task:    row.task,
+				Rank:    rank,
+				Snippet: highlight(row.task.Description, terms, stem),
+				Match:   domain.MatchFullText,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rankResults(results, limit), nil
+}
+
+// SearchTasksBySimilarity returns the tasks whose title or description contain words
+// similar to query, measured with the trigrams pg_trgm uses.
+func (tr TasksRepo) SearchTasksBySimilarity(ctx context.Context, query string, limit int) ([]domain.TaskSearchResult, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".SearchTasksBySimilarity")
+	span.SetAttributes(attribute.String("query", query))
+	defer span.End()
+
+	results := []domain.TaskSearchResult{}
+	err := tr.view(ctx, func(t *txn) error {
+		for _, row := range t.liveTasks() {
+			rank := trigram.WordSimilarity(query, row.task.Title+" "+row.task.Description)
+			if rank < trigram.Threshold {
+				continue
+			}
+			snippet := []rune(row.task.Description)
+			if len(snippet) > snippetMaxLength {
+				snippet = snippet[:snippetMaxLength]
+			}
+			results = append(results, domain.TaskSearchResult{
+				Task:    row.task,
+				Rank:    rank,
+				Snippet: html.EscapeString(string(snippet)),
+				Match:   domain.MatchSimilarity,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rankResults(results, limit), nil
+}
+
+// WithSearchLanguage returns a copy of the repo searching with the given language. Only
+// "simple", which does not stem words, is told apart from the English default.
+func (tr TasksRepo) WithSearchLanguage(language string) *TasksRepo {
+	tr.searchLanguage = language
+	return &tr
+}
+
+func (tr TasksRepo) stemmer() func(word string) string {
+	if tr.searchLanguage == "simple" {
+		return func(word string) string { return word }
+	}
+	return stemEnglish
+}
+
+// rankResults orders results best match first, newer tasks first among equal ones.
+func rankResults(results []domain.TaskSearchResult, limit int) []domain.TaskSearchResult {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return results[i].Task.CreatedAt.After(results[j].Task.CreatedAt)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// searchTerm is a word or a quoted phrase of a query, whose words have to follow each
+// other in the title or in the description.
+type searchTerm struct {
+	words   []string
+	negated bool
+}
+
+// searchQuery holds the alternatives separated by OR, each of which matches when all its
+// terms do.
+type searchQuery [][]searchTerm
+
+func parseSearchQuery(query string, stem func(string) string) searchQuery {
+	alternatives := searchQuery{nil}
+	add := func(text string, negated bool) {
+		if words := stemWords(text, stem); len(words) > 0 {
+			last := len(alternatives) - 1
+			alternatives[last] = append(alternatives[last], searchTerm{words: words, negated: negated})
+		}
+	}
+
+	// The odd parts between quotes are phrases, which a lone dash in front negates.
+	negatePhrase := false
+	for i, part := range strings.Split(query, `"`) {
+		if i%2 == 1 {
+			add(part, negatePhrase)
+			continue
+		}
+		negatePhrase = false
+		for _, field := range strings.Fields(part) {
+			negatePhrase = field == "-"
+			switch {
+			case strings.EqualFold(field, "or"):
+				alternatives = append(alternatives, nil)
+			case strings.HasPrefix(field, "-"):
+				add(field[1:], true)
+			default:
+				add(field, false)
+			}
+		}
+	}
+	return alternatives
+}
+
+// match returns the rank of a task with the given title and description words together
+// with the words to highlight, or false if no alternative matches.
+func (q searchQuery) match(title, description []string) (float64, map[string]bool, bool) {
+	rank := 0.0
+	terms := make(map[string]bool)
+	matched := false
+	for _, alternative := range q {
+		if len(alternative) == 0 || !alternativeMatches(alternative, title, description) {
+			continue
+		}
+		matched = true
+		for _, term := range alternative {
+			if term.negated {
+				continue
+			}
+			rank += float64(countPhrase(title, term.words)) + 0.4*float64(countPhrase(description, term.words))
+			for _, word := range term.words {
+				terms[word] = true
+			}
+		}
+	}
+	return rank / 10, terms, matched
+}
+
+func alternativeMatches(terms []searchTerm, title, description []string) bool {
+	for _, term := range terms {
+		found := countPhrase(title, term.words) > 0 || countPhrase(description, term.words) > 0
+		if found == term.negated {
+			return false
+		}
+	}
+	return true
+}
+
+func countPhrase(words, phrase []string) int {
+	count := 0
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		if equalWords(words[i:i+len(phrase)], phrase) {
+			count++
+		}
+	}
+	return count
+}
+
+func equalWords(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func stemWords(text string, stem func(string) string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !isWordRune(r) })
+	for i, word := range words {
+		words[i] = stem(word)
+	}
+	return words
+}
+
+// stemEnglish strips the most common English inflections, after the first steps of the
+// Porter stemmer Postgres uses: "tests" becomes "test" and "running" becomes "run".
+func stemEnglish(word string) string {
+	switch {
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "ies"):
+		word = word[:len(word)-2]
+	case strings.HasSuffix(word, "ss"):
+	case strings.HasSuffix(word, "s") && len(word) > 3:
+		word = word[:len(word)-1]
+	}
+
+	for _, suffix := range []string{"ing", "ed"} {
+		base, ok := strings.CutSuffix(word, suffix)
+		if !ok || !strings.ContainsAny(base, "aeiouy") {
+			continue
+		}
+		word = base
+		if n := len(word); n >= 2 && word[n-1] == word[n-2] && !strings.ContainsRune("aeiouylsz", rune(word[n-1])) {
+			word = word[:n-1]
+		}
+		break
+	}
+	return word
+}
+
+// highlight marks the words of text whose stems are among terms, like ts_headline, and
+// keeps at most snippetMaxWords words starting with the first match. The snippet is
+// HTML, so text is escaped.
+func highlight(text string, terms map[string]bool, stem func(string) string) string {
+	type segment struct {
+		text   string
+		isWord bool
+	}
+	var segments []segment
+	runes := []rune(text)
+	for start := 0; start < len(runes); {
+		end := start + 1
+		isWord := isWordRune(runes[start])
+		for end < len(runes) && isWordRune(runes[end]) == isWord {
+			end++
+		}
+		segments = append(segments, segment{text: string(runes[start:end]), isWord: isWord})
+		start = end
+	}
+
+	first := -1
+	words := 0
+	var snippet strings.Builder
+	for i, seg := range segments {
+		marked := seg.isWord && terms[stem(strings.ToLower(seg.text))]
+		if first < 0 {
+			if !marked {
+				continue
+			}
+			first = i
+		}
+		if seg.isWord {
+			if words == snippetMaxWords {
+				break
+			}
+			words++
+		}
+		if marked {
+			snippet.WriteString("<mark>" + html.EscapeString(seg.text) + "</mark>")
+		} else {
+			snippet.WriteString(html.EscapeString(seg.text))
+		}
+	}
+	if first < 0 {
+		fields := strings.Fields(text)
+		return html.EscapeString(strings.Join(fields[:min(len(fields), snippetMaxWords)], " "))
+	}
+	return strings.TrimSpace(snippet.String())
+}
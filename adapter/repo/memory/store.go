@@ -0,0 +1,192 @@
+package memory
+
+import (
+	"api/domain"
+	"context"
+	"github.com/google/uuid"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Store holds the data of every repo of the package in memory. It is meant for
+// development and tests: nothing survives a restart and it cannot be shared between
+// replicas.
+type Store struct {
+	mu      sync.RWMutex
+	state   *state
+	seq     int64
+	handler func(change domain.TaskChange)
+	// position orders the tasks by insertion, which is the order Postgres returns an
+	// unsorted listing of a freshly written table in.
+	position int64
+}
+
+// state is everything a transaction may change. InTx works on a copy of it, which
+// replaces the committed state only when the transaction succeeds.
+type state struct {
+	tasks         map[uuid.UUID]taskRow
+	events        []eventRow
+	feeds         map[string]feedRow
+	organizations map[uuid.UUID]domain.Organization
+}
+
+type taskRow struct {
+	task     domain.Task
+	orgID    uuid.UUID
+	position int64
+}
+
+type eventRow struct {
+	event     domain.Event
+	orgID     uuid.UUID
+	published bool
+}
+
+type feedRow struct {
+	feed  domain.CalendarFeed
+	orgID uuid.UUID
+}
+
+// NewStore returns an empty store holding only the default organization, like a freshly
+// migrated database.
+func NewStore() *Store {
+	return &Store{state: &state{
+		tasks: make(map[uuid.UUID]taskRow),
+		feeds: make(map[string]feedRow),
+		organizations: map[uuid.UUID]domain.Organization{
+			domain.DefaultOrganizationID: {
+				ID:        domain.DefaultOrganizationID,
+				Slug:      "default",
+				Name:      "Default",
+				CreatedAt: now(),
+			},
+		},
+	}}
+}
+
+// OnChange registers handle to be called with every committed task change, in commit
+// order. It is called while the store is locked, so it must neither block nor use the
+// store itself.
+func (s *Store) OnChange(handle func(change domain.TaskChange)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handler = handle
+}
+
+// notify delivers the changes of a committed transaction. The store has to be locked.
+func (s *Store) notify(changes []domain.TaskChange) {
+	for _, change := range changes {
+		s.seq++
+		change.ID = strconv.FormatInt(s.seq, 10)
+		if s.handler != nil {
+			s.handler(change)
+		}
+	}
+}
+
+func (st *state) clone() *state {
+	clone := &state{
+		tasks:         make(map[uuid.UUID]taskRow, len(st.tasks)),
+		events:        append([]eventRow(nil), st.events...),
+		feeds:         make(map[string]feedRow, len(st.feeds)),
+		organizations: make(map[uuid.UUID]domain.Organization, len(st.organizations)),
+	}
+	for id, row := range st.tasks {
+		clone.tasks[id] = row
+	}
+	for hash, row := range st.feeds {
+		clone.feeds[hash] = row
+	}
+	for id, org := range st.organizations {
+		clone.organizations[id] = org
+	}
+	return clone
+}
+
+// txn is a state scoped to a single organization, together with the task changes to
+// deliver once it is committed.
+type txn struct {
+	*state
+	store   *Store
+	orgID   uuid.UUID
+	changes []domain.TaskChange
+}
+
+// task returns the task with the given id if it belongs to the organization of the
+// transaction.
+func (t *txn) task(id uuid.UUID) (taskRow, bool) {
+	row, ok := t.tasks[id]
+	if !ok || row.orgID != t.orgID {
+		return taskRow{}, false
+	}
+	return row, true
+}
+
+func (t *txn) insertTask(task domain.Task) {
+	t.store.position++
+	t.tasks[task.ID] = taskRow{task: task, orgID: t.orgID, position: t.store.position}
+}
+
+func (t *txn) addEvent(eventType domain.EventType, payload []byte, aggregateID uuid.UUID) {
+	t.events = append(t.events, eventRow{
+		event: domain.Event{
+			ID:          uuid.New(),
+			Type:        eventType,
+			AggregateID: aggregateID,
+			Payload:     payload,
+			OccurredAt:  now(),
+		},
+		orgID: t.orgID,
+	})
+}
+
+// addChange records the live notification of a task mutation. Like the Postgres trigger,
+// it announces a restored task as created and a task moved to the trash only with its
+// identity.
+func (t *txn) addChange(eventType domain.EventType, task domain.Task) {
+	switch eventType {
+	case domain.EventTaskRestored:
+		eventType = domain.EventTaskCreated
+	case domain.EventTaskDeleted:
+		task = domain.Task{ID: task.ID, Status: task.Status}
+	}
+	t.changes = append(t.changes, domain.TaskChange{Type: eventType, Task: task, OrgID: t.orgID})
+}
+
+// view runs fn against the committed state of the organization of ctx.
+func (s *Store) view(ctx context.Context, fn func(t *txn) error) error {
+	orgID, ok := domain.OrganizationFromContext(ctx)
+	if !ok {
+		return domain.ErrNoOrganization
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn(&txn{state: s.state, store: s, orgID: orgID})
+}
+
+// update runs fn against the committed state of the organization of ctx. The operations
+// of the package check everything that can fail before changing the state, so fn never
+// leaves a partial change behind.
+func (s *Store) update(ctx context.Context, fn func(t *txn) error) error {
+	orgID, ok := domain.OrganizationFromContext(ctx)
+	if !ok {
+		return domain.ErrNoOrganization
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := &txn{state: s.state, store: s, orgID: orgID}
+	if err := fn(t); err != nil {
+		return err
+	}
+	s.notify(t.changes)
+	return nil
+}
+
+// now matches the precision of Postgres timestamps, so that values read back from either
+// storage compare equal.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
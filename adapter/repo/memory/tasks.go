@@ -0,0 +1,487 @@
+package memory
+
+import (
+	"api/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"io"
+	"slices"
+	"sort"
+)
+
+const (
+	traceNameTasksRepo    = "TasksRepo"
+	defaultSearchLanguage = "english"
+)
+
+// TasksRepo keeps the tasks of every organization in a Store. It behaves like the
+// Postgres repo, down to the outbox events and live changes of every mutation.
+type TasksRepo struct {
+	store          *Store
+	tx             *txn
+	searchLanguage string
+}
+
+func NewTasksRepo(store *Store) *TasksRepo {
+	return &TasksRepo{store: store, searchLanguage: defaultSearchLanguage}
+}
+
+func (tr TasksRepo) GetTaskById(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".GetTaskById")
+	span.SetAttributes(attribute.String("task_id", id.String()))
+	defer span.End()
+
+	var task domain.Task
+	err := tr.view(ctx, func(t *txn) error {
+		row, ok := t.task(id)
+		if !ok || row.task.DeletedAt != nil {
+			return fmt.Errorf("task not found in db %s: %w", id, domain.ErrTaskNotFound)
+		}
+		task = row.task
+		return nil
+	})
+	return task, err
+}
+
+// GetTaskByIdForUpdate needs no lock of its own: InTx holds the lock of the store until
+// the transaction ends.
+func (tr TasksRepo) GetTaskByIdForUpdate(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	return tr.GetTaskById(ctx, id)
+}
+
+func (tr TasksRepo) GetTasks(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".GetTasks")
+	defer span.End()
+
+	tasks := []domain.Task{}
+	err := tr.view(ctx, func(t *txn) error {
+		for _, row := range t.liveTasks() {
+			if filter.Matches(row.task) {
+				tasks = append(tasks, row.task)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (tr TasksRepo) GetTasksPage(ctx context.Context, filter domain.TaskFilter, after *domain.TaskCursor, limit int) ([]domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".GetTasksPage")
+	span.SetAttributes(attribute.Int("limit", limit))
+	defer span.End()
+
+	tasks := []domain.Task{}
+	err := tr.view(ctx, func(t *txn) error {
+		for _, row := range t.liveTasks() {
+			if filter.Matches(row.task) && (after == nil || after.Precedes(row.task)) {
+				tasks = append(tasks, row.task)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortOldestFirst(tasks)
+	return tasks[:min(limit, len(tasks))], nil
+}
+
+func (tr TasksRepo) CountTasks(ctx context.Context, filter domain.TaskFilter) (int, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".CountTasks")
+	defer span.End()
+
+	count := 0
+	err := tr.view(ctx, func(t *txn) error {
+		for _, row := range t.liveTasks() {
+			if filter.Matches(row.task) {
+				count++
+			}
+		}
+		return nil
+	})
+	return count, err
+}
+
+// GetTasksByIds returns the live tasks among ids. Unknown ids are left out, so the
+// result may be shorter than ids.
+func (tr TasksRepo) GetTasksByIds(ctx context.Context, ids []uuid.UUID) ([]domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".GetTasksByIds")
+	span.SetAttributes(attribute.Int("task_count", len(ids)))
+	defer span.End()
+
+	tasks := []domain.Task{}
+	err := tr.view(ctx, func(t *txn) error {
+		for _, row := range t.liveTasks() {
+			if slices.Contains(ids, row.task.ID) {
+				tasks = append(tasks, row.task)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// GetTaskEvents returns the outbox events of the tasks with the given ids, oldest first.
+func (tr TasksRepo) GetTaskEvents(ctx context.Context, taskIDs []uuid.UUID) ([]domain.Event, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".GetTaskEvents")
+	span.SetAttributes(attribute.Int("task_count", len(taskIDs)))
+	defer span.End()
+
+	events := []domain.Event{}
+	err := tr.view(ctx, func(t *txn) error {
+		for _, row := range t.events {
+			if row.orgID == t.orgID && slices.Contains(taskIDs, row.event.AggregateID) {
+				events = append(events, row.event)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (tr TasksRepo) CreateTask(ctx context.Context, data domain.Task) (domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".CreateTask")
+	defer span.End()
+
+	task := data
+	task.CreatedAt = now()
+	task.DeletedAt = nil
+	err := tr.update(ctx, func(t *txn) error {
+		// Ids are unique across organizations, like the primary key of the table.
+		if _, ok := t.tasks[task.ID]; ok {
+			return fmt.Errorf("task %s: %w", task.ID, domain.ErrTaskExists)
+		}
+		if err := t.saveEvent(domain.EventTaskCreated, task); err != nil {
+			return err
+		}
+		t.insertTask(task)
+		return nil
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return task, nil
+}
+
+func (tr TasksRepo) UpdateTask(ctx context.Context, data domain.Task) (domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".UpdateTask")
+	span.SetAttributes(attribute.String("task_id", data.ID.String()))
+	defer span.End()
+
+	var task domain.Task
+	err := tr.update(ctx, func(t *txn) error {
+		row, ok := t.task(data.ID)
+		if !ok || row.task.DeletedAt != nil {
+			return fmt.Errorf("task not found in db %s: %w", data.ID, domain.ErrTaskNotFound)
+		}
+		task = row.task
+		task.Title = data.Title
+		task.Description = data.Description
+		task.Status = data.Status
+		task.DueDate = data.DueDate
+		task.RRule = data.RRule
+		task.Timezone = data.Timezone
+		if err := t.saveEvent(domain.EventTaskUpdated, task); err != nil {
+			return err
+		}
+		t.changes[len(t.changes)-1].PreviousStatus = row.task.Status
+		row.task = task
+		t.tasks[task.ID] = row
+		return nil
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return task, nil
+}
+
+// DeleteTask moves the task to the trash, from which it can be restored until it is
+// purged.
+func (tr TasksRepo) DeleteTask(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".DeleteTask")
+	span.SetAttributes(attribute.String("task_id", id.String()))
+	defer span.End()
+
+	var task domain.Task
+	err := tr.update(ctx, func(t *txn) error {
+		row, ok := t.task(id)
+		if !ok || row.task.DeletedAt != nil {
+			return fmt.Errorf("task not found in db %s: %w", id, domain.ErrTaskNotFound)
+		}
+		task = row.task
+		deletedAt := now()
+		task.DeletedAt = &deletedAt
+		if err := t.saveEvent(domain.EventTaskDeleted, task); err != nil {
+			return err
+		}
+		row.task = task
+		t.tasks[id] = row
+		return nil
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return task, nil
+}
+
+// GetDeletedTasks returns the tasks in the trash, most recently deleted first.
+func (tr TasksRepo) GetDeletedTasks(ctx context.Context) ([]domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".GetDeletedTasks")
+	defer span.End()
+
+	tasks := []domain.Task{}
+	err := tr.view(ctx, func(t *txn) error {
+		for _, row := range t.tasks {
+			if row.orgID == t.orgID && row.task.DeletedAt != nil {
+				tasks = append(tasks, row.task)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].DeletedAt.After(*tasks[j].DeletedAt)
+	})
+	return tasks, nil
+}
+
+func (tr TasksRepo) RestoreTask(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".RestoreTask")
+	span.SetAttributes(attribute.String("task_id", id.String()))
+	defer span.End()
+
+	var task domain.Task
+	err := tr.update(ctx, func(t *txn) error {
+		row, ok := t.task(id)
+		if !ok || row.task.DeletedAt == nil {
+			return fmt.Errorf("task not found in trash %s: %w", id, domain.ErrTaskNotFound)
+		}
+		task = row.task
+		task.DeletedAt = nil
+		if err := t.saveEvent(domain.EventTaskRestored, task); err != nil {
+			return err
+		}
+		row.task = task
+		t.tasks[id] = row
+		return nil
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return task, nil
+}
+
+// PurgeTask permanently deletes a task from the trash. Consumers of the outbox were
+// already told about the delete when the task was moved to the trash.
+func (tr TasksRepo) PurgeTask(ctx context.Context, id uuid.UUID) error {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".PurgeTask")
+	span.SetAttributes(attribute.String("task_id", id.String()))
+	defer span.End()
+
+	return tr.update(ctx, func(t *txn) error {
+		row, ok := t.task(id)
+		if !ok || row.task.DeletedAt == nil {
+			return fmt.Errorf("task not found in trash %s: %w", id, domain.ErrTaskNotFound)
+		}
+		delete(t.tasks, id)
+		return nil
+	})
+}
+
+// InTx runs fn with a copy of the repo bound to a copy of the state, which replaces the
+// state of the store only if fn returns nil. The store stays locked until then, so fn
+// must not use any other repo of the store. A repo which is already bound to a
+// transaction runs fn within it.
+func (tr TasksRepo) InTx(ctx context.Context, fn func(repo domain.TasksRepo) error) error {
+	if tr.tx != nil {
+		return fn(tr)
+	}
+
+	orgID, ok := domain.OrganizationFromContext(ctx)
+	if !ok {
+		return domain.ErrNoOrganization
+	}
+
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	txRepo := tr
+	txRepo.tx = &txn{state: tr.store.state.clone(), store: tr.store, orgID: orgID}
+	if err := fn(txRepo); err != nil {
+		return err
+	}
+
+	tr.store.state = txRepo.tx.state
+	tr.store.notify(txRepo.tx.changes)
+	return nil
+}
+
+// ImportTasks reads all tasks from next before taking the lock of the store, so that a
+// slow source does not block other requests. Only the first task of every id is
+// imported and ids which already exist are left untouched.
+func (tr TasksRepo) ImportTasks(ctx context.Context, next func() (domain.Task, error), dryRun bool) (int, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".ImportTasks")
+	span.SetAttributes(attribute.Bool("dry_run", dryRun))
+	defer span.End()
+
+	if _, ok := domain.OrganizationFromContext(ctx); !ok && tr.tx == nil {
+		return 0, domain.ErrNoOrganization
+	}
+
+	createdAt := now()
+	var tasks []domain.Task
+	seen := make(map[uuid.UUID]bool)
+	for {
+		task, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		if seen[task.ID] {
+			continue
+		}
+		seen[task.ID] = true
+
+		task.CreatedAt = createdAt
+		task.DeletedAt = nil
+		tasks = append(tasks, task)
+	}
+
+	imported := 0
+	err := tr.update(ctx, func(t *txn) error {
+		var inserted []domain.Task
+		for _, task := range tasks {
+			if _, ok := t.tasks[task.ID]; !ok {
+				inserted = append(inserted, task)
+			}
+		}
+		imported = len(inserted)
+		if dryRun {
+			return nil
+		}
+
+		payloads := make([][]byte, len(inserted))
+		for i, task := range inserted {
+			payload, err := json.Marshal(task)
+			if err != nil {
+				return fmt.Errorf("failed to marshal task %s: %v", task.ID, err)
+			}
+			payloads[i] = payload
+		}
+		for i, task := range inserted {
+			t.insertTask(task)
+			t.addEvent(domain.EventTaskCreated, payloads[i], task.ID)
+			t.addChange(domain.EventTaskCreated, task)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	span.SetAttributes(attribute.Int("imported", imported))
+	return imported, nil
+}
+
+// ExportTasks calls fn for every task matching filter, oldest first. The matching tasks
+// are copied before fn is called, so that a slow consumer does not block the store.
+func (tr TasksRepo) ExportTasks(ctx context.Context, filter domain.TaskFilter, fn func(task domain.Task) error) error {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".ExportTasks")
+	defer span.End()
+
+	var tasks []domain.Task
+	err := tr.view(ctx, func(t *txn) error {
+		for _, row := range t.liveTasks() {
+			if filter.Matches(row.task) {
+				tasks = append(tasks, row.task)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	sortOldestFirst(tasks)
+	for _, task := range tasks {
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sortOldestFirst(tasks []domain.Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if !tasks[i].CreatedAt.Equal(tasks[j].CreatedAt) {
+			return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+		}
+		return compareIDs(tasks[i].ID, tasks[j].ID) < 0
+	})
+}
+
+// view runs fn within the transaction of the repo, if it is bound to one.
+func (tr TasksRepo) view(ctx context.Context, fn func(t *txn) error) error {
+	if tr.tx != nil {
+		return fn(tr.tx)
+	}
+	return tr.store.view(ctx, fn)
+}
+
+// update runs fn within the transaction of the repo, if it is bound to one.
+func (tr TasksRepo) update(ctx context.Context, fn func(t *txn) error) error {
+	if tr.tx != nil {
+		return fn(tr.tx)
+	}
+	return tr.store.update(ctx, fn)
+}
+
+// liveTasks returns the tasks of the organization which are not in the trash, in
+// insertion order.
+func (t *txn) liveTasks() []taskRow {
+	var rows []taskRow
+	for _, row := range t.tasks {
+		if row.orgID == t.orgID && row.task.DeletedAt == nil {
+			rows = append(rows, row)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].position < rows[j].position
+	})
+	return rows
+}
+
+// saveEvent records the outbox event and the live change of a task mutation.
+func (t *txn) saveEvent(eventType domain.EventType, task domain.Task) error {
+	payload, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %v", eventType, err)
+	}
+	t.addEvent(eventType, payload, task.ID)
+	t.addChange(eventType, task)
+	return nil
+}
+
+// compareIDs orders ids like Postgres orders UUID columns, byte by byte.
+func compareIDs(a, b uuid.UUID) int {
+	return slices.Compare(a[:], b[:])
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"log"
+	"time"
+)
+
+const traceNameTrashRetention = "TrashRetention"
+
+// TrashRetention permanently deletes tasks which have been in the trash of a Store for
+// longer than the retention period.
+type TrashRetention struct {
+	store     *Store
+	retention time.Duration
+	interval  time.Duration
+}
+
+func NewTrashRetention(store *Store, retention, interval time.Duration) *TrashRetention {
+	return &TrashRetention{store: store, retention: retention, interval: interval}
+}
+
+// Run purges expired tasks every interval until ctx is cancelled.
+func (tr *TrashRetention) Run(ctx context.Context) {
+	ticker := time.NewTicker(tr.interval)
+	defer ticker.Stop()
+
+	for {
+		if count := tr.PurgeExpired(ctx); count > 0 {
+			log.Printf("purged %d expired tasks from trash", count)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeExpired deletes the expired tasks of every organization and returns how many
+// were deleted.
+func (tr *TrashRetention) PurgeExpired(ctx context.Context) int {
+	_, span := otel.GetTracerProvider().Tracer(traceNameTrashRetention).Start(ctx, traceNameTrashRetention+".PurgeExpired")
+	defer span.End()
+
+	deletedBefore := time.Now().UTC().Add(-tr.retention)
+
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	count := 0
+	for id, row := range tr.store.state.tasks {
+		if row.task.DeletedAt != nil && row.task.DeletedAt.Before(deletedBefore) {
+			delete(tr.store.state.tasks, id)
+			count++
+		}
+	}
+	span.SetAttributes(attribute.Int("purged", count))
+	return count
+}
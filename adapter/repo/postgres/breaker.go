@@ -0,0 +1,118 @@
+package repo
+
+import (
+	"api/domain"
+	"context"
+	"database/sql/driver"
+	"log"
+	"sync"
+	"time"
+)
+
+// Breaker stops connecting to the database once failures connections in a row failed,
+// so that requests fail at once instead of each waiting for the connection attempt to
+// time out. After cooldown a single connection is tried again, which closes the breaker
+// when it succeeds.
+type Breaker struct {
+	mu       sync.Mutex
+	failures int
+	cooldown time.Duration
+	// failed counts the connections failed in a row.
+	failed   int
+	openedAt time.Time
+	// trying is set while the single connection of a half-open breaker is tried.
+	trying bool
+	now    func() time.Time
+}
+
+func NewBreaker(failures int, cooldown time.Duration) *Breaker {
+	return &Breaker{failures: failures, cooldown: cooldown, now: time.Now}
+}
+
+// Open tells whether connections fail at once, and how long until one is tried again.
+// Once the cooldown has passed it reports the breaker closed, so that requests get
+// through to make the trial connection.
+func (b *Breaker) Open() (bool, time.Duration) {
+	if b == nil {
+		return false, 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failed < b.failures {
+		return false, 0
+	}
+	retryAfter := b.openedAt.Add(b.cooldown).Sub(b.now())
+	if retryAfter <= 0 {
+		return false, 0
+	}
+	return true, retryAfter
+}
+
+// allow reports whether a connection may be attempted.
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failed < b.failures {
+		return true
+	}
+	if b.trying || b.now().Before(b.openedAt.Add(b.cooldown)) {
+		return false
+	}
+	b.trying = true
+	return true
+}
+
+// release lets another connection be tried without recording the outcome of this one.
+func (b *Breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trying = false
+}
+
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trying = false
+	if err == nil {
+		if b.failed >= b.failures {
+			log.Println("database is reachable again, closing circuit breaker")
+		}
+		b.failed = 0
+		return
+	}
+
+	b.failed++
+	if b.failed >= b.failures {
+		if b.failed == b.failures {
+			log.Printf("database is unreachable, opening circuit breaker: %v", err)
+		}
+		b.openedAt = b.now()
+	}
+}
+
+// breakerConnector connects through a breaker, failing at once while it is open.
+type breakerConnector struct {
+	driver.Connector
+	breaker *Breaker
+}
+
+func (bc breakerConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	if bc.breaker == nil {
+		return bc.Connector.Connect(ctx)
+	}
+	if !bc.breaker.allow() {
+		return nil, domain.ErrStorageUnavailable
+	}
+
+	conn, err := bc.Connector.Connect(ctx)
+	// A request giving up is not a failure of the database.
+	if err != nil && ctx.Err() != nil {
+		bc.breaker.release()
+		return nil, err
+	}
+	bc.breaker.record(err)
+	return conn, err
+}
@@ -0,0 +1,133 @@
+package repo
+
+import (
+	"api/domain"
+	"api/handler"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeConnector fails to connect while err is set.
+type fakeConnector struct {
+	err      error
+	connects int
+}
+
+func (fc *fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	fc.connects++
+	return nil, fc.err
+}
+
+func (fc *fakeConnector) Driver() driver.Driver {
+	return nil
+}
+
+func TestBreaker(t *testing.T) {
+	current := time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)
+	breaker := NewBreaker(2, 10*time.Second)
+	breaker.now = func() time.Time { return current }
+	connector := &fakeConnector{err: errors.New("connection refused")}
+	bc := breakerConnector{Connector: connector, breaker: breaker}
+	ctx := context.Background()
+
+	for range 2 {
+		_, err := bc.Connect(ctx)
+		require.EqualError(t, err, "connection refused")
+	}
+	open, retryAfter := breaker.Open()
+	require.True(t, open)
+	require.Equal(t, 10*time.Second, retryAfter)
+
+	// While open, connections fail without being attempted.
+	_, err := bc.Connect(ctx)
+	require.ErrorIs(t, err, domain.ErrStorageUnavailable)
+	require.Equal(t, 2, connector.connects)
+
+	// After the cooldown a single connection is tried, and its failure opens the breaker again.
+	current = current.Add(10 * time.Second)
+	open, _ = breaker.Open()
+	require.False(t, open)
+	_, err = bc.Connect(ctx)
+	require.EqualError(t, err, "connection refused")
+	_, err = bc.Connect(ctx)
+	require.ErrorIs(t, err, domain.ErrStorageUnavailable)
+	require.Equal(t, 3, connector.connects)
+
+	current = current.Add(10 * time.Second)
+	connector.err = nil
+	_, err = bc.Connect(ctx)
+	require.NoError(t, err)
+	open, _ = breaker.Open()
+	require.False(t, open)
+}
+
+// Requests have to get past FailFast once the cooldown has passed, as nothing else may
+// try to connect.
+func TestBreaker_RecoversThroughFailFast(t *testing.T) {
+	current := time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)
+	breaker := NewBreaker(1, 10*time.Second)
+	breaker.now = func() time.Time { return current }
+	connector := &fakeConnector{err: errors.New("connection refused")}
+	bc := breakerConnector{Connector: connector, breaker: breaker}
+	router := handler.FailFast(breaker)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := bc.Connect(r.Context()); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func() int {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/tasks", nil))
+		return recorder.Code
+	}
+
+	require.Equal(t, http.StatusInternalServerError, serve())
+	require.Equal(t, http.StatusServiceUnavailable, serve())
+	require.Equal(t, 1, connector.connects)
+
+	current = current.Add(10 * time.Second)
+	connector.err = nil
+	require.Equal(t, http.StatusOK, serve())
+	require.Equal(t, http.StatusOK, serve())
+	require.Equal(t, 3, connector.connects)
+}
+
+func TestBreaker_CancelledConnect(t *testing.T) {
+	breaker := NewBreaker(1, time.Minute)
+	bc := breakerConnector{Connector: &fakeConnector{err: context.Canceled}, breaker: breaker}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := bc.Connect(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	open, _ := breaker.Open()
+	require.False(t, open)
+}
+
+func TestRetry(t *testing.T) {
+	attempts := 0
+	err := retry(context.Background(), time.Millisecond, 4*time.Millisecond, func(context.Context) error {
+		attempts++
+		if attempts < 4 {
+			return errors.New("the database system is starting up")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 4, attempts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = retry(ctx, time.Millisecond, 4*time.Millisecond, func(context.Context) error {
+		return errors.New("connection refused")
+	})
+	require.ErrorContains(t, err, "database not ready after")
+	require.ErrorContains(t, err, "connection refused")
+}
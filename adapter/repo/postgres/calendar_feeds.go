@@ -0,0 +1,108 @@
+package repo
+
+import (
+	"api/adapter/repo/postgres/gen"
+	"api/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/otel"
+)
+
+const traceNameCalendarFeedsRepo = "CalendarFeedsRepo"
+
+// CalendarFeedsRepo runs every query in a transaction of the organization of ctx, so
+// that a feed token only ever opens the tasks of the organization it was created in.
+type CalendarFeedsRepo struct {
+	db      *sql.DB
+	querier *gen.Queries
+}
+
+func NewCalendarFeedsRepo(db *sql.DB) *CalendarFeedsRepo {
+	return &CalendarFeedsRepo{db: db, querier: gen.New(db)}
+}
+
+func (cr CalendarFeedsRepo) CreateFeed(ctx context.Context, data domain.CalendarFeed, tokenHash string) (domain.CalendarFeed, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameCalendarFeedsRepo).Start(ctx, traceNameCalendarFeedsRepo+".CreateFeed")
+	defer span.End()
+
+	var feed gen.CalendarFeed
+	err := inTenantTx(ctx, cr.db, cr.querier, func(q *gen.Queries) error {
+		var err error
+		feed, err = q.SaveCalendarFeed(ctx, gen.SaveCalendarFeedParams{
+			ID:        data.ID,
+			Name:      data.Name,
+			Status:    data.Status,
+			TokenHash: tokenHash,
+		})
+		return err
+	})
+	if err != nil {
+		return domain.CalendarFeed{}, fmt.Errorf("failed to save calendar feed: %w", err)
+	}
+
+	return feed.ToDomain(), nil
+}
+
+func (cr CalendarFeedsRepo) GetFeedByTokenHash(ctx context.Context, tokenHash string) (domain.CalendarFeed, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameCalendarFeedsRepo).Start(ctx, traceNameCalendarFeedsRepo+".GetFeedByTokenHash")
+	defer span.End()
+
+	var feed gen.CalendarFeed
+	err := inTenantTx(ctx, cr.db, cr.querier, func(q *gen.Queries) error {
+		var err error
+		feed, err = q.GetCalendarFeedByTokenHash(ctx, tokenHash)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.CalendarFeed{}, domain.ErrCalendarFeedNotFound
+		}
+		return domain.CalendarFeed{}, fmt.Errorf("failed to get calendar feed: %w", err)
+	}
+
+	return feed.ToDomain(), nil
+}
+
+func (cr CalendarFeedsRepo) RotateFeedToken(ctx context.Context, tokenHash, newTokenHash string) (domain.CalendarFeed, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameCalendarFeedsRepo).Start(ctx, traceNameCalendarFeedsRepo+".RotateFeedToken")
+	defer span.End()
+
+	var feed gen.CalendarFeed
+	err := inTenantTx(ctx, cr.db, cr.querier, func(q *gen.Queries) error {
+		var err error
+		feed, err = q.RotateCalendarFeedToken(ctx, gen.RotateCalendarFeedTokenParams{
+			TokenHash:    tokenHash,
+			NewTokenHash: newTokenHash,
+		})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.CalendarFeed{}, domain.ErrCalendarFeedNotFound
+		}
+		return domain.CalendarFeed{}, fmt.Errorf("failed to rotate calendar feed token: %w", err)
+	}
+
+	return feed.ToDomain(), nil
+}
+
+func (cr CalendarFeedsRepo) DeleteFeed(ctx context.Context, tokenHash string) error {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameCalendarFeedsRepo).Start(ctx, traceNameCalendarFeedsRepo+".DeleteFeed")
+	defer span.End()
+
+	var count int64
+	err := inTenantTx(ctx, cr.db, cr.querier, func(q *gen.Queries) error {
+		var err error
+		count, err = q.DeleteCalendarFeed(ctx, tokenHash)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete calendar feed: %w", err)
+	}
+	if count == 0 {
+		return domain.ErrCalendarFeedNotFound
+	}
+	return nil
+}
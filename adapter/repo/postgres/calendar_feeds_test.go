@@ -0,0 +1,40 @@
+package repo
+
+import (
+	"api/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCalendarFeedsRepo(t *testing.T) {
+	t.Parallel()
+
+	db := getIsolatedDatabase(t)
+	repo := NewCalendarFeedsRepo(db)
+
+	created, err := repo.CreateFeed(orgContext(), domain.CalendarFeed{ID: uuid.New(), Name: "My tasks", Status: "PENDING"}, "hash-1")
+	require.NoError(t, err)
+	require.Equal(t, "My tasks", created.Name)
+	require.Equal(t, "PENDING", created.Status)
+	require.Nil(t, created.RotatedAt)
+
+	feed, err := repo.GetFeedByTokenHash(orgContext(), "hash-1")
+	require.NoError(t, err)
+	require.Equal(t, created.ID, feed.ID)
+
+	rotated, err := repo.RotateFeedToken(orgContext(), "hash-1", "hash-2")
+	require.NoError(t, err)
+	require.Equal(t, created.ID, rotated.ID)
+	require.NotNil(t, rotated.RotatedAt)
+
+	_, err = repo.GetFeedByTokenHash(orgContext(), "hash-1")
+	require.ErrorIs(t, err, domain.ErrCalendarFeedNotFound)
+	_, err = repo.RotateFeedToken(orgContext(), "hash-1", "hash-3")
+	require.ErrorIs(t, err, domain.ErrCalendarFeedNotFound)
+
+	require.NoError(t, repo.DeleteFeed(orgContext(), "hash-2"))
+	require.ErrorIs(t, repo.DeleteFeed(orgContext(), "hash-2"), domain.ErrCalendarFeedNotFound)
+	_, err = repo.GetFeedByTokenHash(orgContext(), "hash-2")
+	require.ErrorIs(t, err, domain.ErrCalendarFeedNotFound)
+}
@@ -0,0 +1,17 @@
+package repo
+
+import (
+	"api/adapter/repo/repotest"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repos {
+		db := getIsolatedDatabase(t)
+		return repotest.Repos{
+			Tasks:         NewTasksRepo(db),
+			CalendarFeeds: NewCalendarFeedsRepo(db),
+			Organizations: NewOrganizationsRepo(db),
+		}
+	})
+}
@@ -5,26 +5,95 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"log"
+	"math/rand/v2"
+	"strings"
+	"time"
 )
 
-func NewPostgresClient(ctx context.Context, conf config.Config) (*sql.DB, error) {
-	db, err := sql.Open("postgres", conf.DbConnectionUrl)
+// NewPostgresClient waits for the database to accept connections, which it may not yet
+// do right after it was started, for up to conf.DbConnectTimeout. The connections of the
+// client are made through breaker, which may be nil.
+func NewPostgresClient(ctx context.Context, conf config.Config, breaker *Breaker) (*sql.DB, error) {
+	connector, err := newConnector(conf.DbConnectionUrl, conf.DbStatementTimeout)
 	if err != nil {
 		return nil, err
 	}
 
-	if err = db.PingContext(ctx); err != nil {
+	// The breaker is left out until the database is up, so that it does not hold back
+	// the retries.
+	ctx, cancel := context.WithTimeout(ctx, conf.DbConnectTimeout)
+	defer cancel()
+	err = retry(ctx, conf.DbConnectBackoff, conf.DbConnectMaxBackoff, func(ctx context.Context) error {
+		conn, err := connector.Connect(ctx)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	db := sql.OpenDB(breakerConnector{Connector: connector, breaker: breaker})
+	configurePool(db, conf)
 	return db, nil
 }
 
+// newConnector makes every statement of the connections time out after statementTimeout,
+// unless it is zero.
+func newConnector(connectionUrl string, statementTimeout time.Duration) (*pq.Connector, error) {
+	dsn := connectionUrl
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		var err error
+		if dsn, err = pq.ParseURL(dsn); err != nil {
+			return nil, err
+		}
+	}
+	if statementTimeout > 0 {
+		dsn += fmt.Sprintf(" statement_timeout=%d", statementTimeout.Milliseconds())
+	}
+	return pq.NewConnector(dsn)
+}
+
+func configurePool(db *sql.DB, conf config.Config) {
+	db.SetMaxOpenConns(conf.DbMaxOpenConns)
+	db.SetMaxIdleConns(conf.DbMaxIdleConns)
+	db.SetConnMaxLifetime(conf.DbConnMaxLifetime)
+	db.SetConnMaxIdleTime(conf.DbConnMaxIdleTime)
+}
+
+// retry calls fn until it succeeds or ctx is done, waiting longer after every failure:
+// twice as long as before, up to maxBackoff. The waits are jittered, so that replicas
+// started together do not retry in step.
+func retry(ctx context.Context, backoff, maxBackoff time.Duration, fn func(ctx context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		wait := backoff/2 + rand.N(backoff/2+1)
+		deadline, ok := ctx.Deadline()
+		if ctx.Err() != nil || (ok && time.Until(deadline) < wait) {
+			return fmt.Errorf("database not ready after %d attempts: %v", attempt, err)
+		}
+		log.Printf("database not ready (attempt %d), retrying in %s: %v", attempt, wait.Round(time.Millisecond), err)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("database not ready after %d attempts: %v", attempt, err)
+		case <-time.After(wait):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
 func RunMigrations(migrationsPath string, conf config.Config) error {
 	m, err := migrate.New(migrationsPath, conf.DbConnectionUrl)
 	if err != nil {
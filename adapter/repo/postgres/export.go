@@ -0,0 +1,80 @@
+package repo
+
+import (
+	"api/domain"
+	"context"
+	"database/sql"
+	"fmt"
+	"go.opentelemetry.io/otel"
+)
+
+const exportBatchSize = 500
+
+// Cursors are not supported by sqlc, so the export queries are kept here. The columns
+// are listed explicitly to leave the search vector out.
+const (
+	declareExportCursor = `
+DECLARE export_cursor NO SCROLL CURSOR FOR
+    SELECT id, title, description, status, due_date, rrule, timezone, created_at
+    FROM tasks
+    WHERE deleted_at IS NULL
+      AND ($1::TEXT IS NULL OR status = $1)
+    ORDER BY created_at, id`
+
+	fetchExportCursor = `FETCH %d FROM export_cursor`
+)
+
+// ExportTasks calls fn for every task matching filter, oldest first. The tasks are
+// fetched in batches from a server-side cursor, so only one batch is held in memory
+// however many tasks there are.
+func (tr TasksRepo) ExportTasks(ctx context.Context, filter domain.TaskFilter, fn func(task domain.Task) error) error {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".ExportTasks")
+	defer span.End()
+
+	tx, err := beginTenantTx(ctx, tr.db, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status := sql.NullString{String: filter.Status, Valid: filter.Status != ""}
+	if _, err := tx.ExecContext(ctx, declareExportCursor, status); err != nil {
+		return fmt.Errorf("failed to declare export cursor: %w", err)
+	}
+
+	for {
+		count, err := fetchExportBatch(ctx, tx, fn)
+		if err != nil {
+			return err
+		}
+		if count < exportBatchSize {
+			return nil
+		}
+	}
+}
+
+func fetchExportBatch(ctx context.Context, tx *sql.Tx, fn func(task domain.Task) error) (int, error) {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(fetchExportCursor, exportBatchSize))
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch exported tasks: %w", err)
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var task domain.Task
+		err := rows.Scan(&task.ID, &task.Title, &task.Description, &task.Status, &task.DueDate,
+			&task.RRule, &task.Timezone, &task.CreatedAt)
+		if err != nil {
+			return 0, fmt.Errorf("failed to scan exported task: %w", err)
+		}
+		if err := fn(task); err != nil {
+			return 0, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to fetch exported tasks: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,63 @@
+package repo
+
+import (
+	"api/domain"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestExportTasks_Success(t *testing.T) {
+	t.Parallel()
+
+	db := getIsolatedDatabase(t)
+	repo := NewTasksRepo(db)
+
+	// More tasks than fit into a single batch.
+	var ids []uuid.UUID
+	for i := 0; i < exportBatchSize+3; i++ {
+		ids = append(ids, uuid.New())
+	}
+	_, err := repo.ImportTasks(orgContext(), taskIterator(getImportTasks(ids...)...), false)
+	require.NoError(t, err)
+
+	_, err = repo.DeleteTask(orgContext(), ids[0])
+	require.NoError(t, err)
+	_, err = repo.UpdateTask(orgContext(), domain.Task{ID: ids[1], Title: "Done", Status: "DONE"})
+	require.NoError(t, err)
+
+	var exported []domain.Task
+	err = repo.ExportTasks(orgContext(), domain.TaskFilter{}, func(task domain.Task) error {
+		exported = append(exported, task)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, exported, exportBatchSize+2)
+
+	exported = nil
+	err = repo.ExportTasks(orgContext(), domain.TaskFilter{Status: "DONE"}, func(task domain.Task) error {
+		exported = append(exported, task)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, exported, 1)
+	require.Equal(t, ids[1], exported[0].ID)
+}
+
+func TestExportTasks_CallbackError(t *testing.T) {
+	t.Parallel()
+
+	db := getIsolatedDatabase(t)
+	repo := NewTasksRepo(db)
+	createTasks(t, repo, uuid.New(), uuid.New())
+
+	calls := 0
+	errStop := errors.New("client went away")
+	err := repo.ExportTasks(orgContext(), domain.TaskFilter{}, func(task domain.Task) error {
+		calls++
+		return errStop
+	})
+	require.ErrorIs(t, err, errStop)
+	require.Equal(t, 1, calls)
+}
@@ -0,0 +1,116 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: calendar_feeds.sql
+
+package gen
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const deleteCalendarFeed = `-- name: DeleteCalendarFeed :execrows
+DELETE
+FROM calendar_feeds
+WHERE token_hash = $1
+`
+
+func (q *Queries) DeleteCalendarFeed(ctx context.Context, tokenHash string) (int64, error) {
+	result, err := q.exec(ctx, q.deleteCalendarFeedStmt, deleteCalendarFeed, tokenHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getCalendarFeedByTokenHash = `-- name: GetCalendarFeedByTokenHash :one
+SELECT id, name, status, token_hash, created_at, rotated_at, org_id
+FROM calendar_feeds
+WHERE token_hash = $1
+`
+
+func (q *Queries) GetCalendarFeedByTokenHash(ctx context.Context, tokenHash string) (CalendarFeed, error) {
+	row := q.queryRow(ctx, q.getCalendarFeedByTokenHashStmt, getCalendarFeedByTokenHash, tokenHash)
+	var i CalendarFeed
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Status,
+		&i.TokenHash,
+		&i.CreatedAt,
+		&i.RotatedAt,
+		&i.OrgID,
+	)
+	return i, err
+}
+
+const rotateCalendarFeedToken = `-- name: RotateCalendarFeedToken :one
+UPDATE calendar_feeds
+SET token_hash = $1,
+    rotated_at = now()
+WHERE token_hash = $2
+RETURNING id, name, status, token_hash, created_at, rotated_at, org_id
+`
+
+type RotateCalendarFeedTokenParams struct {
+	NewTokenHash string `json:"new_token_hash"`
+	TokenHash    string `json:"token_hash"`
+}
+
+func (q *Queries) RotateCalendarFeedToken(ctx context.Context, arg RotateCalendarFeedTokenParams) (CalendarFeed, error) {
+	row := q.queryRow(ctx, q.rotateCalendarFeedTokenStmt, rotateCalendarFeedToken, arg.NewTokenHash, arg.TokenHash)
+	var i CalendarFeed
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Status,
+		&i.TokenHash,
+		&i.CreatedAt,
+		&i.RotatedAt,
+		&i.OrgID,
+	)
+	return i, err
+}
+
+const saveCalendarFeed = `-- name: SaveCalendarFeed :one
+INSERT INTO calendar_feeds (id,
+                            name,
+                            status,
+                            token_hash,
+                            created_at)
+VALUES ($1,
+        $2,
+        $3,
+        $4,
+        now())
+RETURNING id, name, status, token_hash, created_at, rotated_at, org_id
+`
+
+type SaveCalendarFeedParams struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	TokenHash string    `json:"token_hash"`
+}
+
+func (q *Queries) SaveCalendarFeed(ctx context.Context, arg SaveCalendarFeedParams) (CalendarFeed, error) {
+	row := q.queryRow(ctx, q.saveCalendarFeedStmt, saveCalendarFeed,
+		arg.ID,
+		arg.Name,
+		arg.Status,
+		arg.TokenHash,
+	)
+	var i CalendarFeed
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Status,
+		&i.TokenHash,
+		&i.CreatedAt,
+		&i.RotatedAt,
+		&i.OrgID,
+	)
+	return i, err
+}
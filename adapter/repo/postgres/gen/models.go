@@ -5,16 +5,59 @@
 package gen
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type CalendarFeed struct {
+	ID        uuid.UUID    `json:"id"`
+	Name      string       `json:"name"`
+	Status    string       `json:"status"`
+	TokenHash string       `json:"token_hash"`
+	CreatedAt time.Time    `json:"created_at"`
+	RotatedAt sql.NullTime `json:"rotated_at"`
+	OrgID     uuid.UUID    `json:"org_id"`
+}
+
+type Organization struct {
+	ID        uuid.UUID `json:"id"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Outbox struct {
+	ID          int64           `json:"id"`
+	EventID     uuid.UUID       `json:"event_id"`
+	EventType   string          `json:"event_type"`
+	AggregateID uuid.UUID       `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at"`
+	PublishedAt sql.NullTime    `json:"published_at"`
+	OrgID       uuid.UUID       `json:"org_id"`
+}
+
+type RateLimitBucket struct {
+	Key       string    `json:"key"`
+	Tokens    float64   `json:"tokens"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
 type Task struct {
-	ID          uuid.UUID `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Status      string    `json:"status"`
-	DueDate     time.Time `json:"due_date"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID             uuid.UUID    `json:"id"`
+	Title          string       `json:"title"`
+	Description    string       `json:"description"`
+	Status         string       `json:"status"`
+	DueDate        time.Time    `json:"due_date"`
+	CreatedAt      time.Time    `json:"created_at"`
+	Rrule          string       `json:"rrule"`
+	Timezone       string       `json:"timezone"`
+	SearchLanguage string       `json:"search_language"`
+	SearchVector   string       `json:"search_vector"`
+	DeletedAt      sql.NullTime `json:"deleted_at"`
+	OrgID          uuid.UUID    `json:"org_id"`
 }
@@ -0,0 +1,71 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: organizations.sql
+
+package gen
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const getOrganizationBySlug = `-- name: GetOrganizationBySlug :one
+SELECT id, slug, name, created_at
+FROM organizations
+WHERE slug = $1
+`
+
+func (q *Queries) GetOrganizationBySlug(ctx context.Context, slug string) (Organization, error) {
+	row := q.queryRow(ctx, q.getOrganizationBySlugStmt, getOrganizationBySlug, slug)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const saveOrganization = `-- name: SaveOrganization :one
+INSERT INTO organizations (id,
+                           slug,
+                           name,
+                           created_at)
+VALUES ($1,
+        $2,
+        $3,
+        now())
+RETURNING id, slug, name, created_at
+`
+
+type SaveOrganizationParams struct {
+	ID   uuid.UUID `json:"id"`
+	Slug string    `json:"slug"`
+	Name string    `json:"name"`
+}
+
+func (q *Queries) SaveOrganization(ctx context.Context, arg SaveOrganizationParams) (Organization, error) {
+	row := q.queryRow(ctx, q.saveOrganizationStmt, saveOrganization, arg.ID, arg.Slug, arg.Name)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const setTenant = `-- name: SetTenant :exec
+SELECT set_config('role', 'tasks_tenant', TRUE),
+       set_config('app.current_org', $1::TEXT, TRUE)
+`
+
+// Both settings only last until the end of the transaction.
+func (q *Queries) SetTenant(ctx context.Context, orgID string) error {
+	_, err := q.exec(ctx, q.setTenantStmt, setTenant, orgID)
+	return err
+}
@@ -0,0 +1,153 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: outbox.sql
+
+package gen
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const getOutboxEventsByAggregateIds = `-- name: GetOutboxEventsByAggregateIds :many
+SELECT id, event_id, event_type, aggregate_id, payload, created_at, published_at, org_id
+FROM outbox
+WHERE aggregate_id = ANY ($1::UUID[])
+ORDER BY id
+`
+
+func (q *Queries) GetOutboxEventsByAggregateIds(ctx context.Context, aggregateIds []uuid.UUID) ([]Outbox, error) {
+	rows, err := q.query(ctx, q.getOutboxEventsByAggregateIdsStmt, getOutboxEventsByAggregateIds, pq.Array(aggregateIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Outbox{}
+	for rows.Next() {
+		var i Outbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.EventType,
+			&i.AggregateID,
+			&i.Payload,
+			&i.CreatedAt,
+			&i.PublishedAt,
+			&i.OrgID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOutboxLag = `-- name: GetOutboxLag :one
+SELECT COUNT(*)::BIGINT                                                     AS pending,
+       COALESCE(EXTRACT(EPOCH FROM now() - MIN(created_at)), 0)::FLOAT8 AS lag_seconds
+FROM outbox
+WHERE published_at IS NULL
+`
+
+type GetOutboxLagRow struct {
+	Pending    int64   `json:"pending"`
+	LagSeconds float64 `json:"lag_seconds"`
+}
+
+func (q *Queries) GetOutboxLag(ctx context.Context) (GetOutboxLagRow, error) {
+	row := q.queryRow(ctx, q.getOutboxLagStmt, getOutboxLag)
+	var i GetOutboxLagRow
+	err := row.Scan(&i.Pending, &i.LagSeconds)
+	return i, err
+}
+
+const getPendingOutboxEvents = `-- name: GetPendingOutboxEvents :many
+SELECT id, event_id, event_type, aggregate_id, payload, created_at, published_at, org_id
+FROM outbox
+WHERE published_at IS NULL
+ORDER BY id
+LIMIT $1 FOR UPDATE
+`
+
+func (q *Queries) GetPendingOutboxEvents(ctx context.Context, batchSize int32) ([]Outbox, error) {
+	rows, err := q.query(ctx, q.getPendingOutboxEventsStmt, getPendingOutboxEvents, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Outbox{}
+	for rows.Next() {
+		var i Outbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.EventType,
+			&i.AggregateID,
+			&i.Payload,
+			&i.CreatedAt,
+			&i.PublishedAt,
+			&i.OrgID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markOutboxEventsPublished = `-- name: MarkOutboxEventsPublished :exec
+UPDATE outbox
+SET published_at = now()
+WHERE id = ANY ($1::BIGINT[])
+`
+
+func (q *Queries) MarkOutboxEventsPublished(ctx context.Context, ids []int64) error {
+	_, err := q.exec(ctx, q.markOutboxEventsPublishedStmt, markOutboxEventsPublished, pq.Array(ids))
+	return err
+}
+
+const saveOutboxEvent = `-- name: SaveOutboxEvent :exec
+INSERT INTO outbox (event_id,
+                    event_type,
+                    aggregate_id,
+                    payload,
+                    created_at)
+VALUES ($1,
+        $2,
+        $3,
+        $4,
+        now())
+`
+
+type SaveOutboxEventParams struct {
+	EventID     uuid.UUID       `json:"event_id"`
+	EventType   string          `json:"event_type"`
+	AggregateID uuid.UUID       `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+func (q *Queries) SaveOutboxEvent(ctx context.Context, arg SaveOutboxEventParams) error {
+	_, err := q.exec(ctx, q.saveOutboxEventStmt, saveOutboxEvent,
+		arg.EventID,
+		arg.EventType,
+		arg.AggregateID,
+		arg.Payload,
+	)
+	return err
+}
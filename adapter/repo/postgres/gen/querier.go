@@ -6,14 +6,49 @@ package gen
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	CountTasks(ctx context.Context, status sql.NullString) (int64, error)
+	DeleteCalendarFeed(ctx context.Context, tokenHash string) (int64, error)
+	DeleteExpiredRateLimitBuckets(ctx context.Context, now time.Time) (int64, error)
+	DeleteTask(ctx context.Context, id uuid.UUID) (Task, error)
+	GetCalendarFeedByTokenHash(ctx context.Context, tokenHash string) (CalendarFeed, error)
+	GetDeletedTasks(ctx context.Context) ([]Task, error)
+	GetOrganizationBySlug(ctx context.Context, slug string) (Organization, error)
+	GetOutboxEventsByAggregateIds(ctx context.Context, aggregateIds []uuid.UUID) ([]Outbox, error)
+	GetOutboxLag(ctx context.Context) (GetOutboxLagRow, error)
+	GetPendingOutboxEvents(ctx context.Context, batchSize int32) ([]Outbox, error)
 	GetTaskById(ctx context.Context, id uuid.UUID) (Task, error)
-	GetTasks(ctx context.Context) ([]Task, error)
+	GetTaskByIdForUpdate(ctx context.Context, id uuid.UUID) (Task, error)
+	GetTasks(ctx context.Context, status sql.NullString) ([]Task, error)
+	GetTasksByIds(ctx context.Context, ids []uuid.UUID) ([]Task, error)
+	// created_at has no time zone, so the cursor is compared as one too. Comparing it as
+	// TIMESTAMPTZ would convert the column with the session TimeZone and shift the page.
+	GetTasksPage(ctx context.Context, arg GetTasksPageParams) ([]Task, error)
+	// Creates the bucket of a new client and locks it until the end of the transaction.
+	LockRateLimitBucket(ctx context.Context, arg LockRateLimitBucketParams) (LockRateLimitBucketRow, error)
+	MarkOutboxEventsPublished(ctx context.Context, ids []int64) error
+	// Rows locked by another purge are skipped, so concurrent replicas never wait on each
+	// other and every batch holds its locks only briefly.
+	PurgeDeletedTasks(ctx context.Context, arg PurgeDeletedTasksParams) (int64, error)
+	PurgeTask(ctx context.Context, id uuid.UUID) (int64, error)
+	RestoreTask(ctx context.Context, id uuid.UUID) (Task, error)
+	RotateCalendarFeedToken(ctx context.Context, arg RotateCalendarFeedTokenParams) (CalendarFeed, error)
+	SaveCalendarFeed(ctx context.Context, arg SaveCalendarFeedParams) (CalendarFeed, error)
+	SaveOrganization(ctx context.Context, arg SaveOrganizationParams) (Organization, error)
+	SaveOutboxEvent(ctx context.Context, arg SaveOutboxEventParams) error
 	SaveTask(ctx context.Context, arg SaveTaskParams) (Task, error)
+	SearchTasks(ctx context.Context, arg SearchTasksParams) ([]SearchTasksRow, error)
+	SearchTasksBySimilarity(ctx context.Context, arg SearchTasksBySimilarityParams) ([]SearchTasksBySimilarityRow, error)
+	// Both settings only last until the end of the transaction.
+	SetTenant(ctx context.Context, orgID string) error
+	UpdateRateLimitBucket(ctx context.Context, arg UpdateRateLimitBucketParams) error
+	UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, error)
 }
 
 var _ Querier = (*Queries)(nil)
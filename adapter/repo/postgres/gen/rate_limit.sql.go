@@ -0,0 +1,88 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: rate_limit.sql
+
+package gen
+
+import (
+	"context"
+	"time"
+)
+
+const deleteExpiredRateLimitBuckets = `-- name: DeleteExpiredRateLimitBuckets :execrows
+DELETE
+FROM rate_limit_buckets
+WHERE expires_at <= $1
+`
+
+func (q *Queries) DeleteExpiredRateLimitBuckets(ctx context.Context, now time.Time) (int64, error) {
+	result, err := q.exec(ctx, q.deleteExpiredRateLimitBucketsStmt, deleteExpiredRateLimitBuckets, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const lockRateLimitBucket = `-- name: LockRateLimitBucket :one
+INSERT INTO rate_limit_buckets (key,
+                                tokens,
+                                updated_at,
+                                expires_at)
+VALUES ($1,
+        $2,
+        $3,
+        $4)
+ON CONFLICT (key) DO UPDATE SET key = EXCLUDED.key
+RETURNING tokens, updated_at
+`
+
+type LockRateLimitBucketParams struct {
+	Key       string    `json:"key"`
+	Tokens    float64   `json:"tokens"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type LockRateLimitBucketRow struct {
+	Tokens    float64   `json:"tokens"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Creates the bucket of a new client and locks it until the end of the transaction.
+func (q *Queries) LockRateLimitBucket(ctx context.Context, arg LockRateLimitBucketParams) (LockRateLimitBucketRow, error) {
+	row := q.queryRow(ctx, q.lockRateLimitBucketStmt, lockRateLimitBucket,
+		arg.Key,
+		arg.Tokens,
+		arg.UpdatedAt,
+		arg.ExpiresAt,
+	)
+	var i LockRateLimitBucketRow
+	err := row.Scan(&i.Tokens, &i.UpdatedAt)
+	return i, err
+}
+
+const updateRateLimitBucket = `-- name: UpdateRateLimitBucket :exec
+UPDATE rate_limit_buckets
+SET tokens     = $1,
+    updated_at = $2,
+    expires_at = $3
+WHERE key = $4
+`
+
+type UpdateRateLimitBucketParams struct {
+	Tokens    float64   `json:"tokens"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Key       string    `json:"key"`
+}
+
+func (q *Queries) UpdateRateLimitBucket(ctx context.Context, arg UpdateRateLimitBucketParams) error {
+	_, err := q.exec(ctx, q.updateRateLimitBucketStmt, updateRateLimitBucket,
+		arg.Tokens,
+		arg.UpdatedAt,
+		arg.ExpiresAt,
+		arg.Key,
+	)
+	return err
+}
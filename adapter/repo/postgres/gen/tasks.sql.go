@@ -7,15 +7,103 @@ package gen
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const countTasks = `-- name: CountTasks :one
+SELECT COUNT(*)
+FROM tasks
+WHERE deleted_at IS NULL
+  AND ($1::TEXT IS NULL OR status = $1)
+`
+
+func (q *Queries) CountTasks(ctx context.Context, status sql.NullString) (int64, error) {
+	row := q.queryRow(ctx, q.countTasksStmt, countTasks, status)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteTask = `-- name: DeleteTask :one
+UPDATE tasks
+SET deleted_at = now()
+WHERE id = $1
+  AND deleted_at IS NULL
+RETURNING id, title, description, status, due_date, created_at, rrule, timezone, search_language, search_vector, deleted_at, org_id
+`
+
+func (q *Queries) DeleteTask(ctx context.Context, id uuid.UUID) (Task, error) {
+	row := q.queryRow(ctx, q.deleteTaskStmt, deleteTask, id)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.DueDate,
+		&i.CreatedAt,
+		&i.Rrule,
+		&i.Timezone,
+		&i.SearchLanguage,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.OrgID,
+	)
+	return i, err
+}
+
+const getDeletedTasks = `-- name: GetDeletedTasks :many
+SELECT id, title, description, status, due_date, created_at, rrule, timezone, search_language, search_vector, deleted_at, org_id
+FROM tasks
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
+`
+
+func (q *Queries) GetDeletedTasks(ctx context.Context) ([]Task, error) {
+	rows, err := q.query(ctx, q.getDeletedTasksStmt, getDeletedTasks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.DueDate,
+			&i.CreatedAt,
+			&i.Rrule,
+			&i.Timezone,
+			&i.SearchLanguage,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.OrgID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTaskById = `-- name: GetTaskById :one
-SELECT id, title, description, status, due_date, created_at
+SELECT id, title, description, status, due_date, created_at, rrule, timezone, search_language, search_vector, deleted_at, org_id
 FROM tasks AS t
 WHERE t.id = $1
+  AND t.deleted_at IS NULL
 `
 
 func (q *Queries) GetTaskById(ctx context.Context, id uuid.UUID) (Task, error) {
@@ -28,17 +116,53 @@ func (q *Queries) GetTaskById(ctx context.Context, id uuid.UUID) (Task, error) {
 		&i.Status,
 		&i.DueDate,
 		&i.CreatedAt,
+		&i.Rrule,
+		&i.Timezone,
+		&i.SearchLanguage,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.OrgID,
+	)
+	return i, err
+}
+
+const getTaskByIdForUpdate = `-- name: GetTaskByIdForUpdate :one
+SELECT id, title, description, status, due_date, created_at, rrule, timezone, search_language, search_vector, deleted_at, org_id
+FROM tasks AS t
+WHERE t.id = $1
+  AND t.deleted_at IS NULL
+FOR UPDATE
+`
+
+func (q *Queries) GetTaskByIdForUpdate(ctx context.Context, id uuid.UUID) (Task, error) {
+	row := q.queryRow(ctx, q.getTaskByIdForUpdateStmt, getTaskByIdForUpdate, id)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.DueDate,
+		&i.CreatedAt,
+		&i.Rrule,
+		&i.Timezone,
+		&i.SearchLanguage,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.OrgID,
 	)
 	return i, err
 }
 
 const getTasks = `-- name: GetTasks :many
-SELECT id, title, description, status, due_date, created_at
+SELECT id, title, description, status, due_date, created_at, rrule, timezone, search_language, search_vector, deleted_at, org_id
 FROM tasks
+WHERE deleted_at IS NULL
+  AND ($1::TEXT IS NULL OR status = $1)
 `
 
-func (q *Queries) GetTasks(ctx context.Context) ([]Task, error) {
-	rows, err := q.query(ctx, q.getTasksStmt, getTasks)
+func (q *Queries) GetTasks(ctx context.Context, status sql.NullString) ([]Task, error) {
+	rows, err := q.query(ctx, q.getTasksStmt, getTasks, status)
 	if err != nil {
 		return nil, err
 	}
@@ -53,6 +177,12 @@ func (q *Queries) GetTasks(ctx context.Context) ([]Task, error) {
 			&i.Status,
 			&i.DueDate,
 			&i.CreatedAt,
+			&i.Rrule,
+			&i.Timezone,
+			&i.SearchLanguage,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.OrgID,
 		); err != nil {
 			return nil, err
 		}
@@ -67,28 +197,209 @@ func (q *Queries) GetTasks(ctx context.Context) ([]Task, error) {
 	return items, nil
 }
 
+const getTasksByIds = `-- name: GetTasksByIds :many
+SELECT id, title, description, status, due_date, created_at, rrule, timezone, search_language, search_vector, deleted_at, org_id
+FROM tasks
+WHERE id = ANY ($1::UUID[])
+  AND deleted_at IS NULL
+`
+
+func (q *Queries) GetTasksByIds(ctx context.Context, ids []uuid.UUID) ([]Task, error) {
+	rows, err := q.query(ctx, q.getTasksByIdsStmt, getTasksByIds, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.DueDate,
+			&i.CreatedAt,
+			&i.Rrule,
+			&i.Timezone,
+			&i.SearchLanguage,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.OrgID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTasksPage = `-- name: GetTasksPage :many
+SELECT id, title, description, status, due_date, created_at, rrule, timezone, search_language, search_vector, deleted_at, org_id
+FROM tasks
+WHERE deleted_at IS NULL
+  AND ($1::TEXT IS NULL OR status = $1)
+  AND ($2::TIMESTAMP IS NULL
+    OR (created_at, id) > ($2::TIMESTAMP, $3::UUID))
+ORDER BY created_at, id
+LIMIT $4
+`
+
+type GetTasksPageParams struct {
+	Status         sql.NullString `json:"status"`
+	AfterCreatedAt sql.NullTime   `json:"after_created_at"`
+	AfterID        uuid.NullUUID  `json:"after_id"`
+	MaxResults     int32          `json:"max_results"`
+}
+
+// created_at has no time zone, so the cursor is compared as one too. Comparing it as
+// TIMESTAMPTZ would convert the column with the session TimeZone and shift the page.
+func (q *Queries) GetTasksPage(ctx context.Context, arg GetTasksPageParams) ([]Task, error) {
+	rows, err := q.query(ctx, q.getTasksPageStmt, getTasksPage,
+		arg.Status,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.DueDate,
+			&i.CreatedAt,
+			&i.Rrule,
+			&i.Timezone,
+			&i.SearchLanguage,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.OrgID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeDeletedTasks = `-- name: PurgeDeletedTasks :execrows
+DELETE
+FROM tasks
+WHERE id IN (SELECT t.id
+             FROM tasks AS t
+             WHERE t.deleted_at < $1::TIMESTAMP
+             ORDER BY t.deleted_at
+             LIMIT $2 FOR UPDATE SKIP LOCKED)
+`
+
+type PurgeDeletedTasksParams struct {
+	DeletedBefore time.Time `json:"deleted_before"`
+	BatchSize     int32     `json:"batch_size"`
+}
+
+// Rows locked by another purge are skipped, so concurrent replicas never wait on each
+// other and every batch holds its locks only briefly.
+func (q *Queries) PurgeDeletedTasks(ctx context.Context, arg PurgeDeletedTasksParams) (int64, error) {
+	result, err := q.exec(ctx, q.purgeDeletedTasksStmt, purgeDeletedTasks, arg.DeletedBefore, arg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const purgeTask = `-- name: PurgeTask :execrows
+DELETE
+FROM tasks
+WHERE id = $1
+  AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) PurgeTask(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.exec(ctx, q.purgeTaskStmt, purgeTask, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreTask = `-- name: RestoreTask :one
+UPDATE tasks
+SET deleted_at = NULL
+WHERE id = $1
+  AND deleted_at IS NOT NULL
+RETURNING id, title, description, status, due_date, created_at, rrule, timezone, search_language, search_vector, deleted_at, org_id
+`
+
+func (q *Queries) RestoreTask(ctx context.Context, id uuid.UUID) (Task, error) {
+	row := q.queryRow(ctx, q.restoreTaskStmt, restoreTask, id)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.DueDate,
+		&i.CreatedAt,
+		&i.Rrule,
+		&i.Timezone,
+		&i.SearchLanguage,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.OrgID,
+	)
+	return i, err
+}
+
 const saveTask = `-- name: SaveTask :one
 INSERT INTO tasks (id,
                    title,
                    description,
                    status,
                    due_date,
+                   rrule,
+                   timezone,
+                   search_language,
                    created_at)
 VALUES ($1,
         $2,
         $3,
         $4,
         $5,
+        $6,
+        $7,
+        $8::REGCONFIG,
         now())
-RETURNING id, title, description, status, due_date, created_at
+RETURNING id, title, description, status, due_date, created_at, rrule, timezone, search_language, search_vector, deleted_at, org_id
 `
 
 type SaveTaskParams struct {
-	ID          uuid.UUID `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Status      string    `json:"status"`
-	DueDate     time.Time `json:"due_date"`
+	ID             uuid.UUID `json:"id"`
+	Title          string    `json:"title"`
+	Description    string    `json:"description"`
+	Status         string    `json:"status"`
+	DueDate        time.Time `json:"due_date"`
+	Rrule          string    `json:"rrule"`
+	Timezone       string    `json:"timezone"`
+	SearchLanguage string    `json:"search_language"`
 }
 
 func (q *Queries) SaveTask(ctx context.Context, arg SaveTaskParams) (Task, error) {
@@ -98,6 +409,186 @@ func (q *Queries) SaveTask(ctx context.Context, arg SaveTaskParams) (Task, error
 		arg.Description,
 		arg.Status,
 		arg.DueDate,
+		arg.Rrule,
+		arg.Timezone,
+		arg.SearchLanguage,
+	)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.DueDate,
+		&i.CreatedAt,
+		&i.Rrule,
+		&i.Timezone,
+		&i.SearchLanguage,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.OrgID,
+	)
+	return i, err
+}
+
+const searchTasks = `-- name: SearchTasks :many
+SELECT t.id, t.title, t.description, t.status, t.due_date, t.created_at, t.rrule, t.timezone, t.search_language, t.search_vector, t.deleted_at, t.org_id,
+       ts_rank_cd(t.search_vector, query)::FLOAT8                                    AS rank,
+       ts_headline(t.search_language, escape_html(t.description), query,
+                   'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30')::TEXT AS snippet
+FROM tasks AS t,
+     websearch_to_tsquery($1::REGCONFIG, $2) AS query
+WHERE t.deleted_at IS NULL
+  AND t.search_vector @@ query
+ORDER BY rank DESC, t.created_at DESC
+LIMIT $3
+`
+
+type SearchTasksParams struct {
+	Language   string `json:"language"`
+	Query      string `json:"query"`
+	MaxResults int32  `json:"max_results"`
+}
+
+type SearchTasksRow struct {
+	Task    Task    `json:"task"`
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+func (q *Queries) SearchTasks(ctx context.Context, arg SearchTasksParams) ([]SearchTasksRow, error) {
+	rows, err := q.query(ctx, q.searchTasksStmt, searchTasks, arg.Language, arg.Query, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchTasksRow{}
+	for rows.Next() {
+		var i SearchTasksRow
+		if err := rows.Scan(
+			&i.Task.ID,
+			&i.Task.Title,
+			&i.Task.Description,
+			&i.Task.Status,
+			&i.Task.DueDate,
+			&i.Task.CreatedAt,
+			&i.Task.Rrule,
+			&i.Task.Timezone,
+			&i.Task.SearchLanguage,
+			&i.Task.SearchVector,
+			&i.Task.DeletedAt,
+			&i.Task.OrgID,
+			&i.Rank,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchTasksBySimilarity = `-- name: SearchTasksBySimilarity :many
+SELECT t.id, t.title, t.description, t.status, t.due_date, t.created_at, t.rrule, t.timezone, t.search_language, t.search_vector, t.deleted_at, t.org_id,
+       word_similarity($1, t.title || ' ' || t.description)::FLOAT8 AS rank,
+       escape_html(left(t.description, 200))::TEXT                              AS snippet
+FROM tasks AS t
+WHERE t.deleted_at IS NULL
+  AND $1 <% (t.title || ' ' || t.description)
+ORDER BY rank DESC, t.created_at DESC
+LIMIT $2
+`
+
+type SearchTasksBySimilarityParams struct {
+	Query      string `json:"query"`
+	MaxResults int32  `json:"max_results"`
+}
+
+type SearchTasksBySimilarityRow struct {
+	Task    Task    `json:"task"`
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+func (q *Queries) SearchTasksBySimilarity(ctx context.Context, arg SearchTasksBySimilarityParams) ([]SearchTasksBySimilarityRow, error) {
+	rows, err := q.query(ctx, q.searchTasksBySimilarityStmt, searchTasksBySimilarity, arg.Query, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchTasksBySimilarityRow{}
+	for rows.Next() {
+		var i SearchTasksBySimilarityRow
+		if err := rows.Scan(
+			&i.Task.ID,
+			&i.Task.Title,
+			&i.Task.Description,
+			&i.Task.Status,
+			&i.Task.DueDate,
+			&i.Task.CreatedAt,
+			&i.Task.Rrule,
+			&i.Task.Timezone,
+			&i.Task.SearchLanguage,
+			&i.Task.SearchVector,
+			&i.Task.DeletedAt,
+			&i.Task.OrgID,
+			&i.Rank,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateTask = `-- name: UpdateTask :one
+UPDATE tasks
+SET title           = $1,
+    description     = $2,
+    status          = $3,
+    due_date        = $4,
+    rrule           = $5,
+    timezone        = $6,
+    search_language = $7::REGCONFIG
+WHERE id = $8
+  AND deleted_at IS NULL
+RETURNING id, title, description, status, due_date, created_at, rrule, timezone, search_language, search_vector, deleted_at, org_id
+`
+
+type UpdateTaskParams struct {
+	Title          string    `json:"title"`
+	Description    string    `json:"description"`
+	Status         string    `json:"status"`
+	DueDate        time.Time `json:"due_date"`
+	Rrule          string    `json:"rrule"`
+	Timezone       string    `json:"timezone"`
+	SearchLanguage string    `json:"search_language"`
+	ID             uuid.UUID `json:"id"`
+}
+
+func (q *Queries) UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, error) {
+	row := q.queryRow(ctx, q.updateTaskStmt, updateTask,
+		arg.Title,
+		arg.Description,
+		arg.Status,
+		arg.DueDate,
+		arg.Rrule,
+		arg.Timezone,
+		arg.SearchLanguage,
+		arg.ID,
 	)
 	var i Task
 	err := row.Scan(
@@ -107,6 +598,12 @@ func (q *Queries) SaveTask(ctx context.Context, arg SaveTaskParams) (Task, error
 		&i.Status,
 		&i.DueDate,
 		&i.CreatedAt,
+		&i.Rrule,
+		&i.Timezone,
+		&i.SearchLanguage,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.OrgID,
 	)
 	return i, err
 }
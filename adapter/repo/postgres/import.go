@@ -0,0 +1,124 @@
+package repo
+
+import (
+	"api/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"io"
+	"time"
+)
+
+// COPY is not supported by sqlc for database/sql, so the import queries are kept here.
+const (
+	createImportStaging = `
+CREATE TEMP TABLE import_staging
+(
+    position    INT       NOT NULL,
+    id          UUID      NOT NULL,
+    title       TEXT      NOT NULL,
+    description TEXT      NOT NULL,
+    status      TEXT      NOT NULL,
+    due_date    TIMESTAMP NOT NULL,
+    rrule       TEXT      NOT NULL,
+    timezone    TEXT      NOT NULL,
+    created_at  TIMESTAMP NOT NULL,
+    payload     JSONB     NOT NULL
+) ON COMMIT DROP`
+
+	// Only the first row of every id is imported and ids which already exist are left
+	// untouched. A TaskCreated event is written for every inserted task, in input order.
+	insertFromImportStaging = `
+WITH source AS (SELECT DISTINCT ON (id) *
+                FROM import_staging
+                ORDER BY id, position),
+     inserted AS (
+         INSERT INTO tasks (id, title, description, status, due_date, rrule, timezone, search_language, created_at)
+             SELECT id, title, description, status, due_date, rrule, timezone, $1::REGCONFIG, created_at
+             FROM source
+             ON CONFLICT (id) DO NOTHING
+             RETURNING id),
+     events AS (
+         INSERT INTO outbox (event_id, event_type, aggregate_id, payload, created_at)
+             SELECT gen_random_uuid(), $2, s.id, s.payload, now()
+             FROM source AS s
+                      JOIN inserted AS i ON i.id = s.id
+             ORDER BY s.position
+             RETURNING 1)
+SELECT COUNT(*)
+FROM events`
+)
+
+// ImportTasks streams the tasks returned by next into a staging table with COPY and
+// inserts them into tasks with a single statement. It always runs in its own
+// transaction, which a dry run rolls back once the tasks have been counted.
+func (tr TasksRepo) ImportTasks(ctx context.Context, next func() (domain.Task, error), dryRun bool) (int, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".ImportTasks")
+	span.SetAttributes(attribute.Bool("dry_run", dryRun))
+	defer span.End()
+
+	tx, err := beginTenantTx(ctx, tr.db, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, createImportStaging); err != nil {
+		return 0, fmt.Errorf("failed to create import staging table: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("import_staging",
+		"position", "id", "title", "description", "status", "due_date", "rrule", "timezone", "created_at", "payload"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to start copy: %w", err)
+	}
+	defer stmt.Close()
+
+	createdAt := time.Now().UTC()
+	for position := 1; ; position++ {
+		task, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		task.CreatedAt = createdAt
+		payload, err := json.Marshal(task)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal task %s: %w", task.ID, err)
+		}
+
+		_, err = stmt.ExecContext(ctx, position, task.ID, task.Title, task.Description, task.Status,
+			task.DueDate, task.RRule, task.Timezone, task.CreatedAt, string(payload))
+		if err != nil {
+			return 0, fmt.Errorf("failed to copy task %s: %w", task.ID, err)
+		}
+	}
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return 0, fmt.Errorf("failed to finish copy: %w", err)
+	}
+	if err := stmt.Close(); err != nil {
+		return 0, fmt.Errorf("failed to finish copy: %w", err)
+	}
+
+	var imported int
+	err = tx.QueryRowContext(ctx, insertFromImportStaging, tr.searchLanguage, string(domain.EventTaskCreated)).Scan(&imported)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert imported tasks: %w", err)
+	}
+	span.SetAttributes(attribute.Int("imported", imported))
+
+	if dryRun {
+		return imported, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return imported, nil
+}
@@ -0,0 +1,104 @@
+package repo
+
+import (
+	"api/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"io"
+	"testing"
+	"time"
+)
+
+func taskIterator(tasks ...domain.Task) func() (domain.Task, error) {
+	return func() (domain.Task, error) {
+		if len(tasks) == 0 {
+			return domain.Task{}, io.EOF
+		}
+		task := tasks[0]
+		tasks = tasks[1:]
+		return task, nil
+	}
+}
+
+func getImportTasks(ids ...uuid.UUID) []domain.Task {
+	var tasks []domain.Task
+	for i, id := range ids {
+		tasks = append(tasks, domain.Task{
+			ID:          id,
+			Title:       "Imported task",
+			Description: "Imported from a spreadsheet",
+			Status:      "PENDING",
+			DueDate:     time.Date(2025, 4, 3+i, 0, 0, 0, 0, time.UTC),
+		})
+	}
+	return tasks
+}
+
+func TestImportTasks_Success(t *testing.T) {
+	t.Parallel()
+	existing := uuid.New()
+	ids := []uuid.UUID{uuid.New(), uuid.New(), existing}
+
+	db := getIsolatedDatabase(t)
+	repo := NewTasksRepo(db)
+	createTasks(t, repo, existing)
+
+	// The duplicate of the first task and the existing task are skipped.
+	tasks := append(getImportTasks(ids...), getImportTasks(ids[0])...)
+	imported, err := repo.ImportTasks(orgContext(), taskIterator(tasks...), false)
+	require.NoError(t, err)
+	require.Equal(t, 2, imported)
+
+	task, err := repo.GetTaskById(orgContext(), ids[1])
+	require.NoError(t, err)
+	require.Equal(t, "Imported task", task.Title)
+
+	task, err = repo.GetTaskById(orgContext(), existing)
+	require.NoError(t, err)
+	require.Equal(t, "Do unit tests", task.Title)
+
+	var events int
+	err = db.QueryRow("SELECT COUNT(*) FROM outbox WHERE event_type = 'TaskCreated'").Scan(&events)
+	require.NoError(t, err)
+	require.Equal(t, 3, events)
+}
+
+func TestImportTasks_DryRun(t *testing.T) {
+	t.Parallel()
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+
+	db := getIsolatedDatabase(t)
+	repo := NewTasksRepo(db)
+
+	imported, err := repo.ImportTasks(orgContext(), taskIterator(getImportTasks(ids...)...), true)
+	require.NoError(t, err)
+	require.Equal(t, 2, imported)
+
+	tasks, err := repo.GetTasks(orgContext(), domain.TaskFilter{})
+	require.NoError(t, err)
+	require.Empty(t, tasks)
+}
+
+func TestImportTasks_SourceError(t *testing.T) {
+	t.Parallel()
+
+	db := getIsolatedDatabase(t)
+	repo := NewTasksRepo(db)
+
+	tasks := getImportTasks(uuid.New())
+	next := func() (domain.Task, error) {
+		if len(tasks) == 0 {
+			return domain.Task{}, domain.ErrInvalidImport
+		}
+		task := tasks[0]
+		tasks = tasks[1:]
+		return task, nil
+	}
+
+	_, err := repo.ImportTasks(orgContext(), next, false)
+	require.ErrorIs(t, err, domain.ErrInvalidImport)
+
+	all, err := repo.GetTasks(orgContext(), domain.TaskFilter{})
+	require.NoError(t, err)
+	require.Empty(t, all)
+}
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS outbox
+(
+    id           BIGSERIAL NOT NULL,
+    event_id     UUID      NOT NULL,
+    event_type   TEXT      NOT NULL,
+    aggregate_id UUID      NOT NULL,
+    payload      JSONB     NOT NULL,
+    created_at   TIMESTAMP NOT NULL,
+    published_at TIMESTAMP NULL,
+
+    CONSTRAINT PK_OUTBOX PRIMARY KEY (id),
+    CONSTRAINT UQ_OUTBOX_EVENT_ID UNIQUE (event_id)
+);
+
+CREATE INDEX IF NOT EXISTS IDX_OUTBOX_PENDING ON outbox (id) WHERE published_at IS NULL;
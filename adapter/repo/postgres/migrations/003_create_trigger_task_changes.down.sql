@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS TRG_TASKS_NOTIFY ON tasks;
+DROP FUNCTION IF EXISTS notify_task_change();
+DROP SEQUENCE IF EXISTS task_changes_seq;
@@ -0,0 +1,29 @@
+CREATE SEQUENCE IF NOT EXISTS task_changes_seq;
+
+-- Only the identifying columns are sent, because NOTIFY payloads are limited to 8000
+-- bytes and an oversized payload would abort the writing transaction.
+CREATE OR REPLACE FUNCTION notify_task_change() RETURNS TRIGGER AS
+$$
+DECLARE
+    changed tasks;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed := OLD;
+    ELSE
+        changed := NEW;
+    END IF;
+
+    PERFORM pg_notify('task_changes', json_build_object(
+            'seq', nextval('task_changes_seq'),
+            'op', TG_OP,
+            'id', changed.id,
+            'status', changed.status)::TEXT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER TRG_TASKS_NOTIFY
+    AFTER INSERT OR UPDATE OR DELETE
+    ON tasks
+    FOR EACH ROW
+EXECUTE FUNCTION notify_task_change();
@@ -0,0 +1,3 @@
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS rrule,
+    DROP COLUMN IF EXISTS timezone;
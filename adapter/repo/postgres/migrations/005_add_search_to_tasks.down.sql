@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS IDX_TASKS_SEARCH_TRGM;
+DROP INDEX IF EXISTS IDX_TASKS_SEARCH_VECTOR;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS search_vector,
+    DROP COLUMN IF EXISTS search_language;
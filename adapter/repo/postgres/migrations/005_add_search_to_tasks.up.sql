@@ -0,0 +1,13 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS search_language REGCONFIG NOT NULL DEFAULT 'english';
+
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector(search_language, title), 'A') ||
+        setweight(to_tsvector(search_language, description), 'B')
+        ) STORED;
+
+CREATE INDEX IF NOT EXISTS IDX_TASKS_SEARCH_VECTOR ON tasks USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS IDX_TASKS_SEARCH_TRGM ON tasks USING GIN ((title || ' ' || description) gin_trgm_ops);
@@ -0,0 +1,24 @@
+CREATE OR REPLACE FUNCTION notify_task_change() RETURNS TRIGGER AS
+$$
+DECLARE
+    changed tasks;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed := OLD;
+    ELSE
+        changed := NEW;
+    END IF;
+
+    PERFORM pg_notify('task_changes', json_build_object(
+            'seq', nextval('task_changes_seq'),
+            'op', TG_OP,
+            'id', changed.id,
+            'status', changed.status)::TEXT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS IDX_TASKS_DELETED_AT;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,39 @@
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL;
+
+CREATE INDEX IF NOT EXISTS IDX_TASKS_DELETED_AT ON tasks (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Moving a task to the trash is announced as a delete and restoring it as an insert.
+-- Changes to tasks in the trash, including purging them, are not announced at all.
+CREATE OR REPLACE FUNCTION notify_task_change() RETURNS TRIGGER AS
+$$
+DECLARE
+    changed tasks;
+    op      TEXT := TG_OP;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed := OLD;
+        IF OLD.deleted_at IS NOT NULL THEN
+            RETURN NULL;
+        END IF;
+    ELSIF TG_OP = 'UPDATE' THEN
+        changed := NEW;
+        IF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+            op := 'DELETE';
+        ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+            op := 'INSERT';
+        ELSIF NEW.deleted_at IS NOT NULL THEN
+            RETURN NULL;
+        END IF;
+    ELSE
+        changed := NEW;
+    END IF;
+
+    PERFORM pg_notify('task_changes', json_build_object(
+            'seq', nextval('task_changes_seq'),
+            'op', op,
+            'id', changed.id,
+            'status', changed.status)::TEXT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
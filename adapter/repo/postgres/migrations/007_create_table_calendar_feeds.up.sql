@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS calendar_feeds
+(
+    id         UUID      NOT NULL,
+    name       TEXT      NOT NULL,
+    status     TEXT      NOT NULL DEFAULT '',
+    token_hash TEXT      NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    rotated_at TIMESTAMP NULL,
+
+    CONSTRAINT PK_CALENDAR_FEEDS PRIMARY KEY (id),
+    CONSTRAINT UQ_CALENDAR_FEEDS_TOKEN_HASH UNIQUE (token_hash)
+);
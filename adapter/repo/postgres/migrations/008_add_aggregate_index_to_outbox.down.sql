@@ -0,0 +1 @@
+DROP INDEX IF EXISTS IDX_OUTBOX_AGGREGATE_ID;
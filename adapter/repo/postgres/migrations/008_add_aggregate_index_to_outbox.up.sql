@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS IDX_OUTBOX_AGGREGATE_ID ON outbox (aggregate_id, id);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS rate_limit_buckets
+(
+    key        TEXT             NOT NULL,
+    tokens     DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP        NOT NULL,
+    expires_at TIMESTAMP        NOT NULL,
+
+    CONSTRAINT PK_RATE_LIMIT_BUCKETS PRIMARY KEY (key)
+);
+
+CREATE INDEX IF NOT EXISTS IDX_RATE_LIMIT_BUCKETS_EXPIRES_AT ON rate_limit_buckets (expires_at);
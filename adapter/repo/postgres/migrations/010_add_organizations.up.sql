@@ -0,0 +1,114 @@
+CREATE TABLE IF NOT EXISTS organizations
+(
+    id         UUID      NOT NULL,
+    slug       TEXT      NOT NULL,
+    name       TEXT      NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    CONSTRAINT PK_ORGANIZATIONS PRIMARY KEY (id),
+    CONSTRAINT UQ_ORGANIZATIONS_SLUG UNIQUE (slug)
+);
+
+-- Everything created before organizations existed belongs to the default organization,
+-- which is also the only one of single-tenant deployments.
+INSERT INTO organizations (id, slug, name, created_at)
+VALUES ('00000000-0000-0000-0000-000000000001', 'default', 'Default', now())
+ON CONFLICT DO NOTHING;
+
+-- New rows belong to the organization of the transaction, and cannot be written at all
+-- outside of one.
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS org_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+        CONSTRAINT FK_TASKS_ORG_ID REFERENCES organizations (id);
+ALTER TABLE tasks
+    ALTER COLUMN org_id SET DEFAULT NULLIF(current_setting('app.current_org', TRUE), '')::UUID;
+
+ALTER TABLE outbox
+    ADD COLUMN IF NOT EXISTS org_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+        CONSTRAINT FK_OUTBOX_ORG_ID REFERENCES organizations (id);
+ALTER TABLE outbox
+    ALTER COLUMN org_id SET DEFAULT NULLIF(current_setting('app.current_org', TRUE), '')::UUID;
+
+ALTER TABLE calendar_feeds
+    ADD COLUMN IF NOT EXISTS org_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+        CONSTRAINT FK_CALENDAR_FEEDS_ORG_ID REFERENCES organizations (id);
+ALTER TABLE calendar_feeds
+    ALTER COLUMN org_id SET DEFAULT NULLIF(current_setting('app.current_org', TRUE), '')::UUID;
+
+CREATE INDEX IF NOT EXISTS IDX_TASKS_ORG_ID ON tasks (org_id, created_at);
+CREATE INDEX IF NOT EXISTS IDX_CALENDAR_FEEDS_ORG_ID ON calendar_feeds (org_id);
+
+-- The repos switch to this role at the start of every transaction made for a tenant.
+-- It is subject to the policies below even when the API connects as a superuser or as
+-- the owner of the tables, which both bypass row-level security. Background jobs keep
+-- the role of the connection and see every organization.
+DO
+$$
+    BEGIN
+        IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = 'tasks_tenant') THEN
+            CREATE ROLE tasks_tenant NOLOGIN;
+        END IF;
+    END
+$$;
+
+GRANT tasks_tenant TO CURRENT_USER;
+GRANT SELECT ON organizations TO tasks_tenant;
+GRANT SELECT, INSERT, UPDATE, DELETE ON tasks, calendar_feeds TO tasks_tenant;
+GRANT SELECT, INSERT ON outbox TO tasks_tenant;
+GRANT USAGE ON SEQUENCE outbox_id_seq, task_changes_seq TO tasks_tenant;
+
+ALTER TABLE tasks
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE outbox
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE calendar_feeds
+    ENABLE ROW LEVEL SECURITY;
+
+-- Without app.current_org the policies match no rows.
+CREATE POLICY tenant_isolation ON tasks TO tasks_tenant
+    USING (org_id = NULLIF(current_setting('app.current_org', TRUE), '')::UUID)
+    WITH CHECK (org_id = NULLIF(current_setting('app.current_org', TRUE), '')::UUID);
+
+CREATE POLICY tenant_isolation ON outbox TO tasks_tenant
+    USING (org_id = NULLIF(current_setting('app.current_org', TRUE), '')::UUID)
+    WITH CHECK (org_id = NULLIF(current_setting('app.current_org', TRUE), '')::UUID);
+
+CREATE POLICY tenant_isolation ON calendar_feeds TO tasks_tenant
+    USING (org_id = NULLIF(current_setting('app.current_org', TRUE), '')::UUID)
+    WITH CHECK (org_id = NULLIF(current_setting('app.current_org', TRUE), '')::UUID);
+
+-- The organization is sent along, so that every replica delivers changes to the
+-- subscribers of that organization only.
+CREATE OR REPLACE FUNCTION notify_task_change() RETURNS TRIGGER AS
+$$
+DECLARE
+    changed tasks;
+    op      TEXT := TG_OP;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed := OLD;
+        IF OLD.deleted_at IS NOT NULL THEN
+            RETURN NULL;
+        END IF;
+    ELSIF TG_OP = 'UPDATE' THEN
+        changed := NEW;
+        IF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+            op := 'DELETE';
+        ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+            op := 'INSERT';
+        ELSIF NEW.deleted_at IS NOT NULL THEN
+            RETURN NULL;
+        END IF;
+    ELSE
+        changed := NEW;
+    END IF;
+
+    PERFORM pg_notify('task_changes', json_build_object(
+            'seq', nextval('task_changes_seq'),
+            'op', op,
+            'id', changed.id,
+            'org_id', changed.org_id,
+            'status', changed.status)::TEXT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
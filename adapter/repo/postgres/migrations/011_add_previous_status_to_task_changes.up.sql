@@ -0,0 +1,36 @@
+-- The status an updated task had before is sent along, so that subscribers filtering
+-- by status learn about the tasks leaving their filter.
+CREATE OR REPLACE FUNCTION notify_task_change() RETURNS TRIGGER AS
+$$
+DECLARE
+    changed tasks;
+    op      TEXT := TG_OP;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed := OLD;
+        IF OLD.deleted_at IS NOT NULL THEN
+            RETURN NULL;
+        END IF;
+    ELSIF TG_OP = 'UPDATE' THEN
+        changed := NEW;
+        IF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+            op := 'DELETE';
+        ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+            op := 'INSERT';
+        ELSIF NEW.deleted_at IS NOT NULL THEN
+            RETURN NULL;
+        END IF;
+    ELSE
+        changed := NEW;
+    END IF;
+
+    PERFORM pg_notify('task_changes', json_build_object(
+            'seq', nextval('task_changes_seq'),
+            'op', op,
+            'id', changed.id,
+            'org_id', changed.org_id,
+            'status', changed.status,
+            'previous_status', CASE WHEN op = 'UPDATE' THEN OLD.status END)::TEXT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1 @@
+DROP FUNCTION IF EXISTS escape_html(TEXT);
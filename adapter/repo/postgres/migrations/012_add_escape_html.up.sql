@@ -0,0 +1,12 @@
+-- Search snippets are HTML with the matched terms in <mark> tags, so the description
+-- they are cut from has to be escaped first. The entities are those of Go's
+-- html.EscapeString, which the other storages use.
+CREATE OR REPLACE FUNCTION escape_html(text TEXT) RETURNS TEXT AS
+$$
+SELECT replace(replace(replace(replace(replace(text,
+                                               '&', '&amp;'),
+                                       '<', '&lt;'),
+                               '>', '&gt;'),
+                       '"', '&#34;'),
+               '''', '&#39;');
+$$ LANGUAGE sql IMMUTABLE STRICT;
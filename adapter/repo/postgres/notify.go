@@ -0,0 +1,122 @@
+package repo
+
+import (
+	"api/adapter/repo/postgres/gen"
+	"api/domain"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"log"
+	"strconv"
+	"time"
+)
+
+const taskChangesChannel = "task_changes"
+
+type taskChangeNotification struct {
+	Seq            int64     `json:"seq"`
+	Op             string    `json:"op"`
+	ID             uuid.UUID `json:"id"`
+	Status         string    `json:"status"`
+	PreviousStatus string    `json:"previous_status"`
+	OrgID          uuid.UUID `json:"org_id"`
+}
+
+// TaskChangesListener turns the notifications sent by the tasks trigger into
+// domain.TaskChange values. Every replica runs its own listener, so changes made
+// through any replica reach the clients connected to all of them.
+type TaskChangesListener struct {
+	listener    *pq.Listener
+	querier     *gen.Queries
+	onReconnect func()
+}
+
+func NewTaskChangesListener(connectionUrl string, db *sql.DB) (*TaskChangesListener, error) {
+	listener := pq.NewListener(connectionUrl, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("task changes listener: %v", err)
+		}
+	})
+	if err := listener.Listen(taskChangesChannel); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return &TaskChangesListener{listener: listener, querier: gen.New(db)}, nil
+}
+
+// OnReconnect registers fn to be called by Run whenever changes may have been missed.
+func (tl *TaskChangesListener) OnReconnect(fn func()) {
+	tl.onReconnect = fn
+}
+
+// Run delivers every change to handle until ctx is cancelled.
+func (tl *TaskChangesListener) Run(ctx context.Context, handle func(change domain.TaskChange)) {
+	defer tl.listener.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-tl.listener.Notify:
+			// A nil notification means the connection was re-established and changes
+			// made in the meantime were lost.
+			if n == nil {
+				log.Println("task changes listener reconnected, notifications may have been missed")
+				if tl.onReconnect != nil {
+					tl.onReconnect()
+				}
+				continue
+			}
+			change, err := tl.toChange(ctx, n.Extra)
+			if err != nil {
+				log.Printf("error while handling task change notification: %v", err)
+				continue
+			}
+			handle(change)
+		case <-time.After(90 * time.Second):
+			if err := tl.listener.Ping(); err != nil {
+				log.Printf("task changes listener ping failed: %v", err)
+			}
+		}
+	}
+}
+
+func (tl *TaskChangesListener) toChange(ctx context.Context, payload string) (domain.TaskChange, error) {
+	var n taskChangeNotification
+	if err := json.Unmarshal([]byte(payload), &n); err != nil {
+		return domain.TaskChange{}, err
+	}
+
+	change := domain.TaskChange{
+		ID:    strconv.FormatInt(n.Seq, 10),
+		Task:  domain.Task{ID: n.ID, Status: n.Status},
+		OrgID: n.OrgID,
+	}
+
+	switch n.Op {
+	case "INSERT":
+		change.Type = domain.EventTaskCreated
+	case "UPDATE":
+		change.Type = domain.EventTaskUpdated
+		change.PreviousStatus = n.PreviousStatus
+	case "DELETE":
+		change.Type = domain.EventTaskDeleted
+		return change, nil
+	}
+
+	// The listener connects with the role owning the tables, which the row-level security
+	// policies do not apply to, so it reads the tasks of every organization.
+	task, err := tl.querier.GetTaskById(ctx, n.ID)
+	if err != nil {
+		// The task may already be gone again, in which case only its identity is sent.
+		if errors.Is(err, sql.ErrNoRows) {
+			return change, nil
+		}
+		return domain.TaskChange{}, err
+	}
+	change.Task = task.ToDomain()
+	return change, nil
+}
@@ -0,0 +1,111 @@
+package repo
+
+import (
+	"api/domain"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestTaskChangesListener_TaskCreated(t *testing.T) {
+	t.Parallel()
+	id := uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce")
+
+	db, url := getIsolatedDatabaseWithUrl(t)
+
+	listener, err := NewTaskChangesListener(url, db)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	changes := make(chan domain.TaskChange, 1)
+	go listener.Run(ctx, func(change domain.TaskChange) {
+		changes <- change
+	})
+
+	createTasks(t, NewTasksRepo(db), id)
+
+	select {
+	case change := <-changes:
+		require.Equal(t, domain.EventTaskCreated, change.Type)
+		require.Equal(t, id, change.Task.ID)
+		require.Equal(t, "Do unit tests", change.Task.Title)
+		require.Equal(t, domain.DefaultOrganizationID, change.OrgID)
+		require.NotEmpty(t, change.ID)
+	case <-ctx.Done():
+		t.Fatal("no task change received")
+	}
+}
+
+func TestTaskChangesListener_TaskMovedToTrash(t *testing.T) {
+	t.Parallel()
+	id := uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce")
+
+	db, url := getIsolatedDatabaseWithUrl(t)
+	repo := NewTasksRepo(db)
+	createTasks(t, repo, id)
+
+	listener, err := NewTaskChangesListener(url, db)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	changes := make(chan domain.TaskChange, 3)
+	go listener.Run(ctx, func(change domain.TaskChange) {
+		changes <- change
+	})
+
+	_, err = repo.DeleteTask(orgContext(), id)
+	require.NoError(t, err)
+	_, err = repo.RestoreTask(orgContext(), id)
+	require.NoError(t, err)
+
+	for _, expected := range []domain.EventType{domain.EventTaskDeleted, domain.EventTaskCreated} {
+		select {
+		case change := <-changes:
+			require.Equal(t, expected, change.Type)
+			require.Equal(t, id, change.Task.ID)
+		case <-ctx.Done():
+			t.Fatal("no task change received")
+		}
+	}
+}
+
+func TestTaskChangesListener_TaskUpdated(t *testing.T) {
+	t.Parallel()
+	id := uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce")
+
+	db, url := getIsolatedDatabaseWithUrl(t)
+	repo := NewTasksRepo(db)
+	createTasks(t, repo, id)
+
+	listener, err := NewTaskChangesListener(url, db)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	changes := make(chan domain.TaskChange, 1)
+	go listener.Run(ctx, func(change domain.TaskChange) {
+		changes <- change
+	})
+
+	task, err := repo.GetTaskById(orgContext(), id)
+	require.NoError(t, err)
+	task.Status = "DONE"
+	_, err = repo.UpdateTask(orgContext(), task)
+	require.NoError(t, err)
+
+	select {
+	case change := <-changes:
+		require.Equal(t, domain.EventTaskUpdated, change.Type)
+		require.Equal(t, "DONE", change.Task.Status)
+		require.Equal(t, "PENDING", change.PreviousStatus)
+	case <-ctx.Done():
+		t.Fatal("no task change received")
+	}
+}
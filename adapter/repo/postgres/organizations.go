@@ -0,0 +1,95 @@
+package repo
+
+import (
+	"api/adapter/repo/postgres/gen"
+	"api/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel"
+)
+
+const traceNameOrganizationsRepo = "OrganizationsRepo"
+
+type OrganizationsRepo struct {
+	querier *gen.Queries
+}
+
+func NewOrganizationsRepo(db *sql.DB) *OrganizationsRepo {
+	return &OrganizationsRepo{querier: gen.New(db)}
+}
+
+func (or OrganizationsRepo) GetOrganizationBySlug(ctx context.Context, slug string) (domain.Organization, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameOrganizationsRepo).Start(ctx, traceNameOrganizationsRepo+".GetOrganizationBySlug")
+	defer span.End()
+
+	org, err := or.querier.GetOrganizationBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Organization{}, fmt.Errorf("organization %s: %w", slug, domain.ErrOrganizationNotFound)
+		}
+		return domain.Organization{}, fmt.Errorf("failed to get organization %s: %w", slug, err)
+	}
+
+	return org.ToDomain(), nil
+}
+
+func (or OrganizationsRepo) CreateOrganization(ctx context.Context, data domain.Organization) (domain.Organization, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameOrganizationsRepo).Start(ctx, traceNameOrganizationsRepo+".CreateOrganization")
+	defer span.End()
+
+	org, err := or.querier.SaveOrganization(ctx, gen.SaveOrganizationParams{
+		ID:   data.ID,
+		Slug: data.Slug,
+		Name: data.Name,
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" {
+			return domain.Organization{}, fmt.Errorf("organization %s: %w", data.Slug, domain.ErrOrganizationExists)
+		}
+		return domain.Organization{}, fmt.Errorf("failed to save organization: %w", err)
+	}
+
+	return org.ToDomain(), nil
+}
+
+// beginTenantTx begins a transaction in which the row-level security policies only let
+// the rows of the organization of ctx through, whatever the queries run in it. Without
+// an organization no transaction is begun at all.
+func beginTenantTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions) (*sql.Tx, error) {
+	orgID, ok := domain.OrganizationFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrNoOrganization
+	}
+
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := gen.New(tx).SetTenant(ctx, orgID.String()); err != nil {
+		_ = tx.Rollback()
+		return nil, fmt.Errorf("failed to set tenant: %w", err)
+	}
+	return tx, nil
+}
+
+// inTenantTx runs fn in a transaction of its own begun with beginTenantTx.
+func inTenantTx(ctx context.Context, db *sql.DB, querier *gen.Queries, fn func(q *gen.Queries) error) error {
+	tx, err := beginTenantTx(ctx, db, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(querier.WithTx(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package repo
+
+import (
+	"api/domain"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func createOrganization(t *testing.T, repo *OrganizationsRepo, slug string) context.Context {
+	t.Helper()
+	org, err := repo.CreateOrganization(context.Background(), domain.Organization{ID: uuid.New(), Slug: slug, Name: slug})
+	require.NoError(t, err)
+	return domain.WithOrganization(context.Background(), org.ID)
+}
+
+func TestOrganizationsRepo(t *testing.T) {
+	t.Parallel()
+
+	db := getIsolatedDatabase(t)
+	repo := NewOrganizationsRepo(db)
+
+	created, err := repo.CreateOrganization(context.Background(), domain.Organization{ID: uuid.New(), Slug: "acme", Name: "Acme"})
+	require.NoError(t, err)
+	require.Equal(t, "acme", created.Slug)
+	require.Equal(t, "Acme", created.Name)
+
+	org, err := repo.GetOrganizationBySlug(context.Background(), "acme")
+	require.NoError(t, err)
+	require.Equal(t, created, org)
+
+	_, err = repo.CreateOrganization(context.Background(), domain.Organization{ID: uuid.New(), Slug: "acme", Name: "Acme again"})
+	require.ErrorIs(t, err, domain.ErrOrganizationExists)
+
+	_, err = repo.GetOrganizationBySlug(context.Background(), "globex")
+	require.ErrorIs(t, err, domain.ErrOrganizationNotFound)
+
+	// The migration creates the default organization.
+	org, err = repo.GetOrganizationBySlug(context.Background(), "default")
+	require.NoError(t, err)
+	require.Equal(t, domain.DefaultOrganizationID, org.ID)
+}
+
+// TestTenantIsolation checks that nothing of one organization can be read nor changed
+// through the repos from another one.
+func TestTenantIsolation(t *testing.T) {
+	t.Parallel()
+
+	db := getIsolatedDatabase(t)
+	orgs := NewOrganizationsRepo(db)
+	acme := createOrganization(t, orgs, "acme")
+	globex := createOrganization(t, orgs, "globex")
+	tasks := NewTasksRepo(db)
+	feeds := NewCalendarFeedsRepo(db)
+
+	acmeID := uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce")
+	globexID := uuid.MustParse("9d373cde-0ba2-45ba-b3dc-61bcfe2faadb")
+	_, err := tasks.CreateTask(acme, domain.Task{ID: acmeID, Title: "Acme roadmap", Status: "PENDING", DueDate: time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+	_, err = tasks.CreateTask(globex, domain.Task{ID: globexID, Title: "Globex budget", Status: "PENDING", DueDate: time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+	_, err = feeds.CreateFeed(acme, domain.CalendarFeed{ID: uuid.New(), Name: "Acme"}, "acme-hash")
+	require.NoError(t, err)
+
+	_, err = tasks.GetTaskById(globex, acmeID)
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+
+	list, err := tasks.GetTasks(globex, domain.TaskFilter{})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, globexID, list[0].ID)
+
+	list, err = tasks.GetTasksByIds(globex, []uuid.UUID{acmeID, globexID})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, globexID, list[0].ID)
+
+	results, err := tasks.SearchTasks(globex, "roadmap", 10)
+	require.NoError(t, err)
+	require.Empty(t, results)
+	results, err = tasks.SearchTasksBySimilarity(globex, "Acme roadmap", 10)
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	events, err := tasks.GetTaskEvents(globex, []uuid.UUID{acmeID})
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	var exported []uuid.UUID
+	err = tasks.ExportTasks(globex, domain.TaskFilter{}, func(task domain.Task) error {
+		exported = append(exported, task.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{globexID}, exported)
+
+	_, err = tasks.UpdateTask(globex, domain.Task{ID: acmeID, Title: "Taken over", Status: "DONE"})
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+	_, err = tasks.DeleteTask(globex, acmeID)
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+
+	_, err = feeds.GetFeedByTokenHash(globex, "acme-hash")
+	require.ErrorIs(t, err, domain.ErrCalendarFeedNotFound)
+	require.ErrorIs(t, feeds.DeleteFeed(globex, "acme-hash"), domain.ErrCalendarFeedNotFound)
+
+	// Acme still sees its task and feed untouched.
+	task, err := tasks.GetTaskById(acme, acmeID)
+	require.NoError(t, err)
+	require.Equal(t, "Acme roadmap", task.Title)
+	_, err = feeds.GetFeedByTokenHash(acme, "acme-hash")
+	require.NoError(t, err)
+}
+
+// TestTenantIsolation_RawQueries checks that the policies hold for queries which do not
+// filter by organization at all.
+func TestTenantIsolation_RawQueries(t *testing.T) {
+	t.Parallel()
+
+	db := getIsolatedDatabase(t)
+	orgs := NewOrganizationsRepo(db)
+	acme := createOrganization(t, orgs, "acme")
+	globex := createOrganization(t, orgs, "globex")
+	tasks := NewTasksRepo(db)
+	createTasks(t, tasks, uuid.New(), uuid.New())
+	_, err := tasks.CreateTask(acme, domain.Task{ID: uuid.New(), Title: "Acme roadmap", Status: "PENDING"})
+	require.NoError(t, err)
+
+	tx, err := beginTenantTx(globex, db, nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	var count int
+	require.NoError(t, tx.QueryRow("SELECT COUNT(*) FROM tasks").Scan(&count))
+	require.Zero(t, count)
+	require.NoError(t, tx.QueryRow("SELECT COUNT(*) FROM outbox").Scan(&count))
+	require.Zero(t, count)
+	require.NoError(t, tx.QueryRow("SELECT COUNT(*) FROM calendar_feeds").Scan(&count))
+	require.Zero(t, count)
+
+	// Rows of another organization can neither be written nor changed.
+	_, err = tx.Exec(`INSERT INTO tasks (id, title, description, status, due_date, created_at, org_id)
+		VALUES ($1, 'Planted', '', 'PENDING', now(), now(), $2)`, uuid.New(), domain.DefaultOrganizationID)
+	require.ErrorContains(t, err, "row-level security")
+	require.NoError(t, tx.Rollback())
+
+	tx, err = beginTenantTx(globex, db, nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	result, err := tx.Exec("UPDATE tasks SET title = 'Taken over'")
+	require.NoError(t, err)
+	updated, err := result.RowsAffected()
+	require.NoError(t, err)
+	require.Zero(t, updated)
+
+	// Outside of a tenant transaction, the connection sees all three tasks.
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM tasks").Scan(&count))
+	require.Equal(t, 3, count)
+}
+
+func TestTenantIsolation_NoOrganization(t *testing.T) {
+	t.Parallel()
+
+	db := getIsolatedDatabase(t)
+	tasks := NewTasksRepo(db)
+
+	_, err := tasks.GetTasks(context.Background(), domain.TaskFilter{})
+	require.ErrorContains(t, err, domain.ErrNoOrganization.Error())
+	_, err = tasks.CreateTask(context.Background(), domain.Task{ID: uuid.New(), Title: "Nowhere"})
+	require.ErrorContains(t, err, domain.ErrNoOrganization.Error())
+	_, err = NewCalendarFeedsRepo(db).GetFeedByTokenHash(context.Background(), "hash")
+	require.ErrorContains(t, err, domain.ErrNoOrganization.Error())
+}
@@ -0,0 +1,143 @@
+package repo
+
+import (
+	"api/adapter/repo/postgres/gen"
+	"api/domain"
+	"context"
+	"database/sql"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"log"
+	"time"
+)
+
+const traceNameOutboxRelay = "OutboxRelay"
+
+// OutboxRelay publishes pending outbox events in insertion order. The pending rows are
+// locked for the duration of a batch, so several API replicas can run a relay without
+// publishing the same batch concurrently or out of order.
+type OutboxRelay struct {
+	db           *sql.DB
+	querier      *gen.Queries
+	publisher    domain.Publisher
+	pollInterval time.Duration
+	batchSize    int32
+
+	lag       metric.Float64Gauge
+	pending   metric.Int64Gauge
+	published metric.Int64Counter
+}
+
+func NewOutboxRelay(db *sql.DB, publisher domain.Publisher, pollInterval time.Duration, batchSize int) (*OutboxRelay, error) {
+	meter := otel.GetMeterProvider().Meter(traceNameOutboxRelay)
+
+	lag, err := meter.Float64Gauge("outbox.lag",
+		metric.WithDescription("Age of the oldest unpublished outbox event"),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := meter.Int64Gauge("outbox.pending",
+		metric.WithDescription("Number of unpublished outbox events"))
+	if err != nil {
+		return nil, err
+	}
+
+	published, err := meter.Int64Counter("outbox.published",
+		metric.WithDescription("Number of outbox events delivered to the publisher"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutboxRelay{
+		db:           db,
+		querier:      gen.New(db),
+		publisher:    publisher,
+		pollInterval: pollInterval,
+		batchSize:    int32(batchSize),
+		lag:          lag,
+		pending:      pending,
+		published:    published,
+	}, nil
+}
+
+// Run polls the outbox until ctx is cancelled. A full batch is followed immediately by
+// the next one, so a backlog drains without waiting for the poll interval.
+func (or *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(or.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		count, err := or.PublishPending(ctx)
+		if err != nil {
+			log.Printf("error while relaying outbox events: %v", err)
+		}
+		or.recordLag(ctx)
+
+		if err == nil && count == int(or.batchSize) {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishPending publishes a single batch of pending events and returns how many were
+// marked as published. Publishing stops at the first failure so that ordering is kept;
+// the failed event and everything after it are retried on the next call.
+func (or *OutboxRelay) PublishPending(ctx context.Context) (int, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameOutboxRelay).Start(ctx, traceNameOutboxRelay+".PublishPending")
+	defer span.End()
+
+	tx, err := or.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	q := or.querier.WithTx(tx)
+	events, err := q.GetPendingOutboxEvents(ctx, or.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pending outbox events: %v", err)
+	}
+
+	var publishedIds []int64
+	var publishErr error
+	for _, event := range events {
+		if publishErr = or.publisher.Publish(ctx, event.ToDomain()); publishErr != nil {
+			publishErr = fmt.Errorf("failed to publish event %s: %v", event.EventID, publishErr)
+			break
+		}
+		publishedIds = append(publishedIds, event.ID)
+	}
+
+	if len(publishedIds) == 0 {
+		return 0, publishErr
+	}
+
+	if err := q.MarkOutboxEventsPublished(ctx, publishedIds); err != nil {
+		return 0, fmt.Errorf("failed to mark outbox events as published: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit published outbox events: %v", err)
+	}
+
+	or.published.Add(ctx, int64(len(publishedIds)))
+	return len(publishedIds), publishErr
+}
+
+func (or *OutboxRelay) recordLag(ctx context.Context) {
+	lag, err := or.querier.GetOutboxLag(ctx)
+	if err != nil {
+		log.Printf("error while reading outbox lag: %v", err)
+		return
+	}
+	or.lag.Record(ctx, lag.LagSeconds)
+	or.pending.Record(ctx, lag.Pending)
+}
@@ -0,0 +1,146 @@
+package repo
+
+import (
+	"api/domain"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type fakePublisher struct {
+	events  []domain.Event
+	failAt  int
+	failErr error
+}
+
+func (fp *fakePublisher) Publish(_ context.Context, event domain.Event) error {
+	if fp.failErr != nil && len(fp.events) == fp.failAt {
+		return fp.failErr
+	}
+	fp.events = append(fp.events, event)
+	return nil
+}
+
+func (fp *fakePublisher) Close() error {
+	return nil
+}
+
+func createTasks(t *testing.T, repo *TasksRepo, ids ...uuid.UUID) {
+	for _, id := range ids {
+		_, err := repo.CreateTask(orgContext(), domain.Task{
+			ID:          id,
+			Title:       "Do unit tests",
+			Description: "Create extensive unit tests for all layers",
+			Status:      "PENDING",
+			DueDate:     time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC),
+		})
+		require.NoError(t, err)
+	}
+}
+
+func TestCreateTask_WritesOutboxEvent(t *testing.T) {
+	t.Parallel()
+	id := uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce")
+
+	db := getIsolatedDatabase(t)
+	assert.NotEqual(t, nil, db)
+
+	createTasks(t, NewTasksRepo(db), id)
+
+	publisher := &fakePublisher{}
+	relay, err := NewOutboxRelay(db, publisher, time.Second, 10)
+	require.NoError(t, err)
+
+	count, err := relay.PublishPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	require.Equal(t, domain.EventTaskCreated, publisher.events[0].Type)
+	require.Equal(t, id, publisher.events[0].AggregateID)
+	require.NotEqual(t, uuid.Nil, publisher.events[0].ID)
+}
+
+func TestCreateTask_Conflict_NoOutboxEvent(t *testing.T) {
+	t.Parallel()
+	id := uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce")
+
+	db := getIsolatedDatabase(t)
+	assert.NotEqual(t, nil, db)
+
+	repo := NewTasksRepo(db)
+	createTasks(t, repo, id)
+
+	_, err := repo.CreateTask(orgContext(), domain.Task{ID: id, Title: "Duplicate"})
+	require.Error(t, err)
+
+	publisher := &fakePublisher{}
+	relay, err := NewOutboxRelay(db, publisher, time.Second, 10)
+	require.NoError(t, err)
+
+	count, err := relay.PublishPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
+func TestPublishPending_InOrder(t *testing.T) {
+	t.Parallel()
+	id1 := uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce")
+	id2 := uuid.MustParse("9d373cde-0ba2-45ba-b3dc-61bcfe2faadb")
+	id3 := uuid.MustParse("0b1c3a4e-4b8f-4e57-9f55-2a3c7f1a2b10")
+
+	db := getIsolatedDatabase(t)
+	assert.NotEqual(t, nil, db)
+
+	createTasks(t, NewTasksRepo(db), id1, id2, id3)
+
+	publisher := &fakePublisher{}
+	relay, err := NewOutboxRelay(db, publisher, time.Second, 2)
+	require.NoError(t, err)
+
+	count, err := relay.PublishPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	count, err = relay.PublishPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	count, err = relay.PublishPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+
+	require.Equal(t, id1, publisher.events[0].AggregateID)
+	require.Equal(t, id2, publisher.events[1].AggregateID)
+	require.Equal(t, id3, publisher.events[2].AggregateID)
+}
+
+func TestPublishPending_RetriesAfterFailure(t *testing.T) {
+	t.Parallel()
+	id1 := uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce")
+	id2 := uuid.MustParse("9d373cde-0ba2-45ba-b3dc-61bcfe2faadb")
+
+	db := getIsolatedDatabase(t)
+	assert.NotEqual(t, nil, db)
+
+	createTasks(t, NewTasksRepo(db), id1, id2)
+
+	publisher := &fakePublisher{failAt: 1, failErr: errors.New("broker unavailable")}
+	relay, err := NewOutboxRelay(db, publisher, time.Second, 10)
+	require.NoError(t, err)
+
+	count, err := relay.PublishPending(context.Background())
+	require.Error(t, err)
+	require.Equal(t, 1, count)
+
+	publisher.failErr = nil
+	count, err = relay.PublishPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	require.Equal(t, 2, len(publisher.events))
+	require.Equal(t, id1, publisher.events[0].AggregateID)
+	require.Equal(t, id2, publisher.events[1].AggregateID)
+}
@@ -1,6 +1,7 @@
 package repo
 
 import (
+	"api/domain"
 	"context"
 	"database/sql"
 	"fmt"
@@ -92,9 +93,7 @@ func getPort(db *TestDB) (int, error) {
 	return p.Int(), nil
 }
 
-func getIsolatedDatabase(t *testing.T) *sql.DB {
-	gm := golangmigrator.New("migrations")
-
+func getTestDbConfig(t *testing.T) pgtestdb.Config {
 	host, err := getHost(testDb)
 	if err != nil {
 		t.Fatal(err)
@@ -105,7 +104,7 @@ func getIsolatedDatabase(t *testing.T) *sql.DB {
 		t.Fatal(err)
 	}
 
-	db := pgtestdb.New(t, pgtestdb.Config{
+	return pgtestdb.Config{
 		Host:       host,
 		User:       dbUser,
 		Password:   dbPass,
@@ -113,7 +112,39 @@ func getIsolatedDatabase(t *testing.T) *sql.DB {
 		Options:    "sslmode=disable",
 		Database:   dbName,
 		DriverName: "postgres",
-	}, gm)
+		// The migrations create the role the tenant transactions switch to.
+		TestRole: &pgtestdb.Role{
+			Username:     pgtestdb.DefaultRoleUsername,
+			Password:     pgtestdb.DefaultRolePassword,
+			Capabilities: "NOSUPERUSER NOCREATEDB CREATEROLE",
+		},
+	}
+}
+
+// orgContext scopes the repos to the default organization.
+func orgContext() context.Context {
+	return domain.WithOrganization(context.Background(), domain.DefaultOrganizationID)
+}
+
+func getIsolatedDatabase(t *testing.T) *sql.DB {
+	gm := golangmigrator.New("migrations")
+
+	db := pgtestdb.New(t, getTestDbConfig(t), gm)
 
 	return db
 }
+
+// getIsolatedDatabaseWithUrl is used by tests that need a dedicated connection, such as
+// LISTEN/NOTIFY listeners.
+func getIsolatedDatabaseWithUrl(t *testing.T) (*sql.DB, string) {
+	gm := golangmigrator.New("migrations")
+
+	conf := pgtestdb.Custom(t, getTestDbConfig(t), gm)
+	db, err := conf.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db, conf.URL()
+}
@@ -0,0 +1,29 @@
+-- name: SaveCalendarFeed :one
+INSERT INTO calendar_feeds (id,
+                            name,
+                            status,
+                            token_hash,
+                            created_at)
+VALUES (@id,
+        @name,
+        @status,
+        @token_hash,
+        now())
+RETURNING *;
+
+-- name: GetCalendarFeedByTokenHash :one
+SELECT *
+FROM calendar_feeds
+WHERE token_hash = @token_hash;
+
+-- name: RotateCalendarFeedToken :one
+UPDATE calendar_feeds
+SET token_hash = sqlc.arg(new_token_hash),
+    rotated_at = now()
+WHERE token_hash = sqlc.arg(token_hash)
+RETURNING *;
+
+-- name: DeleteCalendarFeed :execrows
+DELETE
+FROM calendar_feeds
+WHERE token_hash = @token_hash;
@@ -0,0 +1,20 @@
+-- name: SetTenant :exec
+-- Both settings only last until the end of the transaction.
+SELECT set_config('role', 'tasks_tenant', TRUE),
+       set_config('app.current_org', sqlc.arg(org_id)::TEXT, TRUE);
+
+-- name: GetOrganizationBySlug :one
+SELECT *
+FROM organizations
+WHERE slug = @slug;
+
+-- name: SaveOrganization :one
+INSERT INTO organizations (id,
+                           slug,
+                           name,
+                           created_at)
+VALUES (@id,
+        @slug,
+        @name,
+        now())
+RETURNING *;
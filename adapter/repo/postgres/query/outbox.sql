@@ -0,0 +1,35 @@
+-- name: SaveOutboxEvent :exec
+INSERT INTO outbox (event_id,
+                    event_type,
+                    aggregate_id,
+                    payload,
+                    created_at)
+VALUES (@event_id,
+        @event_type,
+        @aggregate_id,
+        @payload,
+        now());
+
+-- name: GetPendingOutboxEvents :many
+SELECT *
+FROM outbox
+WHERE published_at IS NULL
+ORDER BY id
+LIMIT sqlc.arg(batch_size) FOR UPDATE;
+
+-- name: MarkOutboxEventsPublished :exec
+UPDATE outbox
+SET published_at = now()
+WHERE id = ANY (sqlc.arg(ids)::BIGINT[]);
+
+-- name: GetOutboxLag :one
+SELECT COUNT(*)::BIGINT                                                     AS pending,
+       COALESCE(EXTRACT(EPOCH FROM now() - MIN(created_at)), 0)::FLOAT8 AS lag_seconds
+FROM outbox
+WHERE published_at IS NULL;
+
+-- name: GetOutboxEventsByAggregateIds :many
+SELECT *
+FROM outbox
+WHERE aggregate_id = ANY (sqlc.arg(aggregate_ids)::UUID[])
+ORDER BY id;
@@ -0,0 +1,24 @@
+-- name: LockRateLimitBucket :one
+-- Creates the bucket of a new client and locks it until the end of the transaction.
+INSERT INTO rate_limit_buckets (key,
+                                tokens,
+                                updated_at,
+                                expires_at)
+VALUES (@key,
+        @tokens,
+        @updated_at,
+        @expires_at)
+ON CONFLICT (key) DO UPDATE SET key = EXCLUDED.key
+RETURNING tokens, updated_at;
+
+-- name: UpdateRateLimitBucket :exec
+UPDATE rate_limit_buckets
+SET tokens     = @tokens,
+    updated_at = @updated_at,
+    expires_at = @expires_at
+WHERE key = @key;
+
+-- name: DeleteExpiredRateLimitBuckets :execrows
+DELETE
+FROM rate_limit_buckets
+WHERE expires_at <= @now;
@@ -1,11 +1,39 @@
 -- name: GetTaskById :one
 SELECT *
 FROM tasks AS t
-WHERE t.id = sqlc.arg(id);
+WHERE t.id = sqlc.arg(id)
+  AND t.deleted_at IS NULL;
+
+-- name: GetTaskByIdForUpdate :one
+SELECT *
+FROM tasks AS t
+WHERE t.id = sqlc.arg(id)
+  AND t.deleted_at IS NULL
+FOR UPDATE;
 
 -- name: GetTasks :many
 SELECT *
-FROM tasks;
+FROM tasks
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(status)::TEXT IS NULL OR status = sqlc.narg(status));
+
+-- name: GetTasksPage :many
+-- created_at has no time zone, so the cursor is compared as one too. Comparing it as
+-- TIMESTAMPTZ would convert the column with the session TimeZone and shift the page.
+SELECT *
+FROM tasks
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(status)::TEXT IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(after_created_at)::TIMESTAMP IS NULL
+    OR (created_at, id) > (sqlc.narg(after_created_at)::TIMESTAMP, sqlc.narg(after_id)::UUID))
+ORDER BY created_at, id
+LIMIT sqlc.arg(max_results);
+
+-- name: CountTasks :one
+SELECT COUNT(*)
+FROM tasks
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(status)::TEXT IS NULL OR status = sqlc.narg(status));
 
 -- name: SaveTask :one
 INSERT INTO tasks (id,
@@ -13,11 +41,95 @@ INSERT INTO tasks (id,
                    description,
                    status,
                    due_date,
+                   rrule,
+                   timezone,
+                   search_language,
                    created_at)
 VALUES (@id,
         @title,
         @description,
         @status,
         @due_date,
+        @rrule,
+        @timezone,
+        sqlc.arg(search_language)::REGCONFIG,
         now())
 RETURNING *;
+
+-- name: UpdateTask :one
+UPDATE tasks
+SET title           = @title,
+    description     = @description,
+    status          = @status,
+    due_date        = @due_date,
+    rrule           = @rrule,
+    timezone        = @timezone,
+    search_language = sqlc.arg(search_language)::REGCONFIG
+WHERE id = @id
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SearchTasks :many
+SELECT sqlc.embed(t),
+       ts_rank_cd(t.search_vector, query)::FLOAT8                                    AS rank,
+       ts_headline(t.search_language, escape_html(t.description), query,
+                   'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30')::TEXT AS snippet
+FROM tasks AS t,
+     websearch_to_tsquery(sqlc.arg(language)::REGCONFIG, sqlc.arg(query)) AS query
+WHERE t.deleted_at IS NULL
+  AND t.search_vector @@ query
+ORDER BY rank DESC, t.created_at DESC
+LIMIT sqlc.arg(max_results);
+
+-- name: SearchTasksBySimilarity :many
+SELECT sqlc.embed(t),
+       word_similarity(sqlc.arg(query), t.title || ' ' || t.description)::FLOAT8 AS rank,
+       escape_html(left(t.description, 200))::TEXT                              AS snippet
+FROM tasks AS t
+WHERE t.deleted_at IS NULL
+  AND sqlc.arg(query) <% (t.title || ' ' || t.description)
+ORDER BY rank DESC, t.created_at DESC
+LIMIT sqlc.arg(max_results);
+
+-- name: DeleteTask :one
+UPDATE tasks
+SET deleted_at = now()
+WHERE id = @id
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: GetDeletedTasks :many
+SELECT *
+FROM tasks
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: RestoreTask :one
+UPDATE tasks
+SET deleted_at = NULL
+WHERE id = @id
+  AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PurgeTask :execrows
+DELETE
+FROM tasks
+WHERE id = @id
+  AND deleted_at IS NOT NULL;
+
+-- name: PurgeDeletedTasks :execrows
+-- Rows locked by another purge are skipped, so concurrent replicas never wait on each
+-- other and every batch holds its locks only briefly.
+DELETE
+FROM tasks
+WHERE id IN (SELECT t.id
+             FROM tasks AS t
+             WHERE t.deleted_at < sqlc.arg(deleted_before)::TIMESTAMP
+             ORDER BY t.deleted_at
+             LIMIT sqlc.arg(batch_size) FOR UPDATE SKIP LOCKED);
+
+-- name: GetTasksByIds :many
+SELECT *
+FROM tasks
+WHERE id = ANY (sqlc.arg(ids)::UUID[])
+  AND deleted_at IS NULL;
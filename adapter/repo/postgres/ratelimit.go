@@ -0,0 +1,96 @@
+package repo
+
+import (
+	"api/adapter/repo/postgres/gen"
+	"api/domain"
+	"context"
+	"database/sql"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"log"
+	"time"
+)
+
+const traceNameRateLimitRepo = "RateLimitRepo"
+
+// RateLimitRepo keeps the token buckets in Postgres, so that the replicas of the API
+// share them. The bucket of a client is locked while a request takes a token from it.
+type RateLimitRepo struct {
+	db      *sql.DB
+	querier *gen.Queries
+}
+
+func NewRateLimitRepo(db *sql.DB) *RateLimitRepo {
+	return &RateLimitRepo{db: db, querier: gen.New(db)}
+}
+
+func (rr RateLimitRepo) Take(ctx context.Context, key string, policy domain.RateLimitPolicy, now time.Time) (domain.RateLimitResult, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameRateLimitRepo).Start(ctx, traceNameRateLimitRepo+".Take")
+	defer span.End()
+
+	tx, err := rr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.RateLimitResult{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	querier := rr.querier.WithTx(tx)
+
+	// A new client starts with a full bucket.
+	now = now.UTC()
+	row, err := querier.LockRateLimitBucket(ctx, gen.LockRateLimitBucketParams{
+		Key:       key,
+		Tokens:    float64(policy.Limit),
+		UpdatedAt: now,
+		ExpiresAt: now.Add(policy.Window),
+	})
+	if err != nil {
+		return domain.RateLimitResult{}, fmt.Errorf("failed to lock rate limit bucket: %w", err)
+	}
+
+	bucket, result := policy.Take(&domain.TokenBucket{Tokens: row.Tokens, UpdatedAt: row.UpdatedAt}, now)
+	err = querier.UpdateRateLimitBucket(ctx, gen.UpdateRateLimitBucketParams{
+		Key:       key,
+		Tokens:    bucket.Tokens,
+		UpdatedAt: bucket.UpdatedAt,
+		// The bucket is full again by then, so it can be dropped.
+		ExpiresAt: bucket.UpdatedAt.Add(policy.Window),
+	})
+	if err != nil {
+		return domain.RateLimitResult{}, fmt.Errorf("failed to update rate limit bucket: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.RateLimitResult{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return result, nil
+}
+
+// Run deletes the buckets of idle clients every interval until ctx is cancelled.
+func (rr RateLimitRepo) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if _, err := rr.DeleteExpired(ctx, now); err != nil {
+				log.Printf("error while deleting expired rate limit buckets: %v", err)
+			}
+		}
+	}
+}
+
+// DeleteExpired deletes the buckets which have been idle for a whole window and returns
+// how many were deleted.
+func (rr RateLimitRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameRateLimitRepo).Start(ctx, traceNameRateLimitRepo+".DeleteExpired")
+	defer span.End()
+
+	count, err := rr.querier.DeleteExpiredRateLimitBuckets(ctx, now.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired rate limit buckets: %w", err)
+	}
+	return count, nil
+}
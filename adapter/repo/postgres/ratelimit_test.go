@@ -0,0 +1,93 @@
+package repo
+
+import (
+	"api/domain"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimitRepo_Take(t *testing.T) {
+	t.Parallel()
+	policy := domain.RateLimitPolicy{Pattern: "/api/*", Limit: 2, Window: time.Minute}
+	now := time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)
+
+	db := getIsolatedDatabase(t)
+	assert.NotEqual(t, nil, db)
+
+	repo := NewRateLimitRepo(db)
+
+	for _, allowed := range []bool{true, true, false} {
+		result, err := repo.Take(context.Background(), "a", policy, now)
+		require.NoError(t, err)
+		require.Equal(t, allowed, result.Allowed)
+	}
+
+	result, err := repo.Take(context.Background(), "b", policy, now)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	result, err = repo.Take(context.Background(), "a", policy, now.Add(30*time.Second))
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+	require.Equal(t, 0, result.Remaining)
+}
+
+func TestRateLimitRepo_TakeConcurrently(t *testing.T) {
+	t.Parallel()
+	policy := domain.RateLimitPolicy{Pattern: "/api/*", Limit: 10, Window: time.Hour}
+	now := time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)
+
+	db := getIsolatedDatabase(t)
+	assert.NotEqual(t, nil, db)
+
+	repo := NewRateLimitRepo(db)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := 0
+	var errs []error
+	for range 30 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := repo.Take(context.Background(), "a", policy, now)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+			}
+			if result.Allowed {
+				allowed++
+			}
+		}()
+	}
+	wg.Wait()
+	require.Empty(t, errs)
+	require.Equal(t, 10, allowed)
+}
+
+func TestRateLimitRepo_DeleteExpired(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)
+
+	db := getIsolatedDatabase(t)
+	assert.NotEqual(t, nil, db)
+
+	repo := NewRateLimitRepo(db)
+	_, err := repo.Take(context.Background(), "minute", domain.RateLimitPolicy{Limit: 1, Window: time.Minute}, now)
+	require.NoError(t, err)
+	_, err = repo.Take(context.Background(), "hour", domain.RateLimitPolicy{Limit: 1, Window: time.Hour}, now)
+	require.NoError(t, err)
+
+	count, err := repo.DeleteExpired(context.Background(), now.Add(30*time.Second))
+	require.NoError(t, err)
+	require.Equal(t, int64(0), count)
+
+	count, err = repo.DeleteExpired(context.Background(), now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
@@ -0,0 +1,138 @@
+package repo
+
+import (
+	"api/config"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"github.com/lib/pq"
+	"io"
+	"log"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+const replicaPingTimeout = 2 * time.Second
+
+// ReplicaSet spreads read-only queries over the read replicas of the primary database,
+// in turn. A replica failing its health check is skipped until it passes one again.
+type ReplicaSet struct {
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+type replica struct {
+	index   int
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+// NewReplicaSet connects to the replicas of conf.DbReplicaUrls lazily, with the pool
+// settings and statement timeout of the primary. A replica which cannot be reached yet
+// is only skipped.
+func NewReplicaSet(ctx context.Context, conf config.Config) (*ReplicaSet, error) {
+	dbs := make([]*sql.DB, len(conf.DbReplicaUrls))
+	for i, connectionUrl := range conf.DbReplicaUrls {
+		connector, err := newConnector(connectionUrl, conf.DbStatementTimeout)
+		if err != nil {
+			return nil, err
+		}
+		dbs[i] = sql.OpenDB(connector)
+		configurePool(dbs[i], conf)
+	}
+
+	rs := newReplicaSet(dbs...)
+	rs.Check(ctx)
+	return rs, nil
+}
+
+// newReplicaSet returns the replica set of dbs, all of them taken for healthy.
+func newReplicaSet(dbs ...*sql.DB) *ReplicaSet {
+	rs := &ReplicaSet{replicas: make([]*replica, len(dbs))}
+	for i, db := range dbs {
+		rs.replicas[i] = &replica{index: i, db: db}
+		rs.replicas[i].healthy.Store(true)
+	}
+	return rs
+}
+
+// Run checks the health of the replicas every interval until ctx is cancelled.
+func (rs *ReplicaSet) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rs.Check(ctx)
+		}
+	}
+}
+
+// Check pings every replica and returns how many of them answered.
+func (rs *ReplicaSet) Check(ctx context.Context) int {
+	healthy := 0
+	for _, r := range rs.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, replicaPingTimeout)
+		err := r.db.PingContext(pingCtx)
+		cancel()
+		if err != nil {
+			if r.healthy.Swap(false) {
+				log.Printf("read replica %d is down: %v", r.index, err)
+			}
+			continue
+		}
+		if !r.healthy.Swap(true) {
+			log.Printf("read replica %d is up", r.index)
+		}
+		healthy++
+	}
+	return healthy
+}
+
+// pick returns the next healthy replica, or nil when all of them are down.
+func (rs *ReplicaSet) pick() *replica {
+	if rs == nil {
+		return nil
+	}
+	for range rs.replicas {
+		r := rs.replicas[(rs.next.Add(1)-1)%uint64(len(rs.replicas))]
+		if r.healthy.Load() {
+			return r
+		}
+	}
+	return nil
+}
+
+// markDown skips r until its next successful health check.
+func (r *replica) markDown(err error) {
+	if r.healthy.Swap(false) {
+		log.Printf("read replica %d is down: %v", r.index, err)
+	}
+}
+
+// isConnectionError tells whether err means the connection to the database was lost,
+// rather than that the query failed by itself.
+func isConnectionError(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		// Class 08 holds the connection exceptions, 57P01 to 57P03 a server shutting down.
+		return pqErr.Code.Class() == "08" || pqErr.Code == "57P01" || pqErr.Code == "57P02" || pqErr.Code == "57P03"
+	}
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, &netErr)
+}
+
+func (rs *ReplicaSet) Close() error {
+	for _, r := range rs.replicas {
+		if err := r.db.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
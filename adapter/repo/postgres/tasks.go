@@ -5,21 +5,46 @@ import (
 	"api/domain"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 )
 
-const traceNameTasksRepo = "TasksRepo"
+const (
+	traceNameTasksRepo    = "TasksRepo"
+	defaultSearchLanguage = "english"
+)
 
 type TasksRepo struct {
-	querier gen.Querier
+	db             *sql.DB
+	tx             *sql.Tx
+	querier        *gen.Queries
+	searchLanguage string
+	replicas       *ReplicaSet
+}
+
+func NewTasksRepo(db *sql.DB) *TasksRepo {
+	return &TasksRepo{db: db, querier: gen.New(db), searchLanguage: defaultSearchLanguage}
 }
 
-func NewTasksRepo(querier gen.Querier) *TasksRepo {
-	return &TasksRepo{querier: querier}
+// WithSearchLanguage returns a copy of the repo which indexes and searches tasks using
+// the given Postgres text search configuration, e.g. "english" or "simple". Tasks are
+// re-indexed in the repo's language whenever they are saved.
+func (tr TasksRepo) WithSearchLanguage(language string) *TasksRepo {
+	tr.searchLanguage = language
+	return &tr
+}
+
+// WithReplicas returns a copy of the repo which reads tasks by id and lists them from
+// the replicas, unless ctx asks to read its own writes. Everything else, and every read
+// while all replicas are down, goes to the primary.
+func (tr TasksRepo) WithReplicas(replicas *ReplicaSet) *TasksRepo {
+	tr.replicas = replicas
+	return &tr
 }
 
 func (tr TasksRepo) GetTaskById(ctx context.Context, id uuid.UUID) (domain.Task, error) {
@@ -27,27 +52,60 @@ func (tr TasksRepo) GetTaskById(ctx context.Context, id uuid.UUID) (domain.Task,
 	span.SetAttributes(attribute.String("task_id", id.String()))
 	defer span.End()
 
-	task, err := tr.querier.GetTaskById(ctx, id)
+	var task gen.Task
+	err := tr.withReadTx(ctx, func(q *gen.Queries) error {
+		var err error
+		task, err = q.GetTaskById(ctx, id)
+		return err
+	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return domain.Task{}, fmt.Errorf("task not found in db %s: %v", id, err)
+			return domain.Task{}, fmt.Errorf("task not found in db %s: %w", id, domain.ErrTaskNotFound)
 		}
-		return domain.Task{}, fmt.Errorf("failed to get task %s: %v", id, err)
+		return domain.Task{}, fmt.Errorf("failed to get task %s: %w", id, err)
 	}
 
 	return task.ToDomain(), nil
 }
 
-func (tr TasksRepo) GetTasks(ctx context.Context) ([]domain.Task, error) {
+// GetTaskByIdForUpdate reads the task from the primary and locks its row until the
+// transaction of the repo ends, so concurrent updates of the task wait for it.
+func (tr TasksRepo) GetTaskByIdForUpdate(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".GetTaskByIdForUpdate")
+	span.SetAttributes(attribute.String("task_id", id.String()))
+	defer span.End()
+
+	var task gen.Task
+	err := tr.withTx(ctx, func(q *gen.Queries) error {
+		var err error
+		task, err = q.GetTaskByIdForUpdate(ctx, id)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Task{}, fmt.Errorf("task not found in db %s: %w", id, domain.ErrTaskNotFound)
+		}
+		return domain.Task{}, fmt.Errorf("failed to lock task %s: %w", id, err)
+	}
+
+	return task.ToDomain(), nil
+}
+
+func (tr TasksRepo) GetTasks(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
 	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".GetTasks")
 	defer span.End()
 
-	data, err := tr.querier.GetTasks(ctx)
+	var data []gen.Task
+	err := tr.withReadTx(ctx, func(q *gen.Queries) error {
+		var err error
+		data, err = q.GetTasks(ctx, sql.NullString{String: filter.Status, Valid: filter.Status != ""})
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to find all tasks: %v", err)
+		return nil, fmt.Errorf("failed to find all tasks: %w", err)
 	}
 
-	var tasks []domain.Task
+	tasks := []domain.Task{}
 	for _, currentTask := range data {
 		tasks = append(tasks, currentTask.ToDomain())
 	}
@@ -55,20 +113,414 @@ func (tr TasksRepo) GetTasks(ctx context.Context) ([]domain.Task, error) {
 	return tasks, nil
 }
 
+// GetTasksPage reads only the page itself, using the order of the export, so that the
+// cost of a page does not grow with the number of tasks before it.
+func (tr TasksRepo) GetTasksPage(ctx context.Context, filter domain.TaskFilter, after *domain.TaskCursor, limit int) ([]domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".GetTasksPage")
+	span.SetAttributes(attribute.Int("limit", limit))
+	defer span.End()
+
+	params := gen.GetTasksPageParams{
+		Status:     sql.NullString{String: filter.Status, Valid: filter.Status != ""},
+		MaxResults: int32(limit),
+	}
+	if after != nil {
+		// The driver reads created_at as UTC, so the cursor has to be sent as UTC as well,
+		// as the offset of a timestamp without time zone is ignored.
+		params.AfterCreatedAt = sql.NullTime{Time: after.CreatedAt.UTC(), Valid: true}
+		params.AfterID = uuid.NullUUID{UUID: after.ID, Valid: true}
+	}
+
+	var data []gen.Task
+	err := tr.withReadTx(ctx, func(q *gen.Queries) error {
+		var err error
+		data, err = q.GetTasksPage(ctx, params)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find page of tasks: %w", err)
+	}
+
+	tasks := make([]domain.Task, len(data))
+	for i, currentTask := range data {
+		tasks[i] = currentTask.ToDomain()
+	}
+	return tasks, nil
+}
+
+func (tr TasksRepo) CountTasks(ctx context.Context, filter domain.TaskFilter) (int, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".CountTasks")
+	defer span.End()
+
+	var count int64
+	err := tr.withReadTx(ctx, func(q *gen.Queries) error {
+		var err error
+		count, err = q.CountTasks(ctx, sql.NullString{String: filter.Status, Valid: filter.Status != ""})
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count tasks: %w", err)
+	}
+	return int(count), nil
+}
+
+// GetTasksByIds returns the live tasks among ids in a single query. Unknown ids are
+// left out, so the result may be shorter than ids and is in no particular order.
+func (tr TasksRepo) GetTasksByIds(ctx context.Context, ids []uuid.UUID) ([]domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".GetTasksByIds")
+	span.SetAttributes(attribute.Int("task_count", len(ids)))
+	defer span.End()
+
+	var data []gen.Task
+	err := tr.withTx(ctx, func(q *gen.Queries) error {
+		var err error
+		data, err = q.GetTasksByIds(ctx, ids)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks by ids: %w", err)
+	}
+
+	tasks := make([]domain.Task, len(data))
+	for i, currentTask := range data {
+		tasks[i] = currentTask.ToDomain()
+	}
+	return tasks, nil
+}
+
+// GetTaskEvents returns the outbox events of the tasks with the given ids in a single
+// query, oldest first.
+func (tr TasksRepo) GetTaskEvents(ctx context.Context, taskIDs []uuid.UUID) ([]domain.Event, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".GetTaskEvents")
+	span.SetAttributes(attribute.Int("task_count", len(taskIDs)))
+	defer span.End()
+
+	var data []gen.Outbox
+	err := tr.withTx(ctx, func(q *gen.Queries) error {
+		var err error
+		data, err = q.GetOutboxEventsByAggregateIds(ctx, taskIDs)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task events: %w", err)
+	}
+
+	events := make([]domain.Event, len(data))
+	for i, event := range data {
+		events[i] = event.ToDomain()
+	}
+	return events, nil
+}
+
 func (tr TasksRepo) CreateTask(ctx context.Context, data domain.Task) (domain.Task, error) {
 	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".CreateTask")
 	defer span.End()
 
-	task, err := tr.querier.SaveTask(ctx, gen.SaveTaskParams{
-		ID:          data.ID,
-		Title:       data.Title,
-		Description: data.Description,
-		Status:      data.Status,
-		DueDate:     data.DueDate,
+	var task gen.Task
+	err := tr.withTx(ctx, func(q *gen.Queries) error {
+		var err error
+		task, err = q.SaveTask(ctx, gen.SaveTaskParams{
+			ID:             data.ID,
+			Title:          data.Title,
+			Description:    data.Description,
+			Status:         data.Status,
+			DueDate:        data.DueDate,
+			Rrule:          data.RRule,
+			Timezone:       data.Timezone,
+			SearchLanguage: tr.searchLanguage,
+		})
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" {
+				return fmt.Errorf("task %s: %w", data.ID, domain.ErrTaskExists)
+			}
+			return fmt.Errorf("failed to save task: %w", err)
+		}
+		return saveEvent(ctx, q, domain.EventTaskCreated, task.ToDomain())
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	return task.ToDomain(), nil
+}
+
+func (tr TasksRepo) UpdateTask(ctx context.Context, data domain.Task) (domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".UpdateTask")
+	span.SetAttributes(attribute.String("task_id", data.ID.String()))
+	defer span.End()
+
+	var task gen.Task
+	err := tr.withTx(ctx, func(q *gen.Queries) error {
+		var err error
+		task, err = q.UpdateTask(ctx, gen.UpdateTaskParams{
+			ID:             data.ID,
+			Title:          data.Title,
+			Description:    data.Description,
+			Status:         data.Status,
+			DueDate:        data.DueDate,
+			Rrule:          data.RRule,
+			Timezone:       data.Timezone,
+			SearchLanguage: tr.searchLanguage,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("task not found in db %s: %w", data.ID, domain.ErrTaskNotFound)
+			}
+			return fmt.Errorf("failed to update task %s: %w", data.ID, err)
+		}
+		return saveEvent(ctx, q, domain.EventTaskUpdated, task.ToDomain())
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	return task.ToDomain(), nil
+}
+
+// DeleteTask moves the task to the trash, from which it can be restored until it is
+// purged.
+func (tr TasksRepo) DeleteTask(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".DeleteTask")
+	span.SetAttributes(attribute.String("task_id", id.String()))
+	defer span.End()
+
+	var task gen.Task
+	err := tr.withTx(ctx, func(q *gen.Queries) error {
+		var err error
+		task, err = q.DeleteTask(ctx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("task not found in db %s: %w", id, domain.ErrTaskNotFound)
+			}
+			return fmt.Errorf("failed to delete task %s: %w", id, err)
+		}
+		return saveEvent(ctx, q, domain.EventTaskDeleted, task.ToDomain())
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	return task.ToDomain(), nil
+}
+
+func (tr TasksRepo) GetDeletedTasks(ctx context.Context) ([]domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".GetDeletedTasks")
+	defer span.End()
+
+	var data []gen.Task
+	err := tr.withTx(ctx, func(q *gen.Queries) error {
+		var err error
+		data, err = q.GetDeletedTasks(ctx)
+		return err
 	})
 	if err != nil {
-		return domain.Task{}, fmt.Errorf("failed to save task: %v", err)
+		return nil, fmt.Errorf("failed to find deleted tasks: %w", err)
+	}
+
+	tasks := []domain.Task{}
+	for _, currentTask := range data {
+		tasks = append(tasks, currentTask.ToDomain())
+	}
+
+	return tasks, nil
+}
+
+func (tr TasksRepo) RestoreTask(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".RestoreTask")
+	span.SetAttributes(attribute.String("task_id", id.String()))
+	defer span.End()
+
+	var task gen.Task
+	err := tr.withTx(ctx, func(q *gen.Queries) error {
+		var err error
+		task, err = q.RestoreTask(ctx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("task not found in trash %s: %w", id, domain.ErrTaskNotFound)
+			}
+			return fmt.Errorf("failed to restore task %s: %w", id, err)
+		}
+		return saveEvent(ctx, q, domain.EventTaskRestored, task.ToDomain())
+	})
+	if err != nil {
+		return domain.Task{}, err
 	}
 
 	return task.ToDomain(), nil
 }
+
+// PurgeTask permanently deletes a task from the trash. Consumers of the outbox were
+// already told about the delete when the task was moved to the trash.
+func (tr TasksRepo) PurgeTask(ctx context.Context, id uuid.UUID) error {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".PurgeTask")
+	span.SetAttributes(attribute.String("task_id", id.String()))
+	defer span.End()
+
+	var count int64
+	err := tr.withTx(ctx, func(q *gen.Queries) error {
+		var err error
+		count, err = q.PurgeTask(ctx, id)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to purge task %s: %w", id, err)
+	}
+	if count == 0 {
+		return fmt.Errorf("task not found in trash %s: %w", id, domain.ErrTaskNotFound)
+	}
+	return nil
+}
+
+// SearchTasks returns the tasks matching the websearch-style query (quoted phrases, OR
+// and -negation are supported), best matches first.
+func (tr TasksRepo) SearchTasks(ctx context.Context, query string, limit int) ([]domain.TaskSearchResult, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".SearchTasks")
+	span.SetAttributes(attribute.String("query", query))
+	defer span.End()
+
+	var data []gen.SearchTasksRow
+	err := tr.withTx(ctx, func(q *gen.Queries) error {
+		var err error
+		data, err = q.SearchTasks(ctx, gen.SearchTasksParams{
+			Language:   tr.searchLanguage,
+			Query:      query,
+			MaxResults: int32(limit),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tasks: %w", err)
+	}
+
+	results := []domain.TaskSearchResult{}
+	for _, row := range data {
+		results = append(results, domain.TaskSearchResult{
+			Task:    row.Task.ToDomain(),
+			Rank:    row.Rank,
+			Snippet: row.Snippet,
+			Match:   domain.MatchFullText,
+		})
+	}
+
+	return results, nil
+}
+
+// SearchTasksBySimilarity returns the tasks whose title or description contain words
+// similar to query, which tolerates typos that full-text search does not.
+func (tr TasksRepo) SearchTasksBySimilarity(ctx context.Context, query string, limit int) ([]domain.TaskSearchResult, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".SearchTasksBySimilarity")
+	span.SetAttributes(attribute.String("query", query))
+	defer span.End()
+
+	var data []gen.SearchTasksBySimilarityRow
+	err := tr.withTx(ctx, func(q *gen.Queries) error {
+		var err error
+		data, err = q.SearchTasksBySimilarity(ctx, gen.SearchTasksBySimilarityParams{
+			Query:      query,
+			MaxResults: int32(limit),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tasks by similarity: %w", err)
+	}
+
+	results := []domain.TaskSearchResult{}
+	for _, row := range data {
+		results = append(results, domain.TaskSearchResult{
+			Task:    row.Task.ToDomain(),
+			Rank:    row.Rank,
+			Snippet: row.Snippet,
+			Match:   domain.MatchSimilarity,
+		})
+	}
+
+	return results, nil
+}
+
+// InTx runs fn with a copy of the repo bound to a new transaction of the organization
+// of ctx. A repo which is already bound to a transaction runs fn within it.
+func (tr TasksRepo) InTx(ctx context.Context, fn func(repo domain.TasksRepo) error) error {
+	if tr.tx != nil {
+		return fn(tr)
+	}
+
+	tx, err := beginTenantTx(ctx, tr.db, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	txRepo := tr
+	txRepo.tx = tx
+	txRepo.querier = tr.querier.WithTx(tx)
+	if err := fn(txRepo); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// withTx runs fn inside a single transaction, so that a task mutation and its outbox
+// record are either both committed or both rolled back. Every query of the repo runs
+// in one, as only a transaction of the organization of ctx can see its tasks.
+func (tr TasksRepo) withTx(ctx context.Context, fn func(q *gen.Queries) error) error {
+	if tr.tx != nil {
+		return fn(tr.querier)
+	}
+	return inTenantTx(ctx, tr.db, tr.querier, fn)
+}
+
+// withReadTx runs the read-only fn on a replica when there is one to run it on, and
+// otherwise like withTx. A replica which cannot begin a transaction, or loses its
+// connection while fn runs, is left for the primary until it passes a health check
+// again, and fn is run once more on the primary.
+func (tr TasksRepo) withReadTx(ctx context.Context, fn func(q *gen.Queries) error) error {
+	if tr.tx != nil || domain.ReadYourWritesFromContext(ctx) {
+		return tr.withTx(ctx, fn)
+	}
+	r := tr.replicas.pick()
+	if r == nil {
+		return tr.withTx(ctx, fn)
+	}
+
+	tx, err := beginTenantTx(ctx, r.db, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		if errors.Is(err, domain.ErrNoOrganization) {
+			return err
+		}
+		r.markDown(err)
+		return tr.withTx(ctx, fn)
+	}
+	defer tx.Rollback()
+
+	err = fn(tr.querier.WithTx(tx))
+	if err != nil && ctx.Err() == nil && isConnectionError(err) {
+		_ = tx.Rollback()
+		r.markDown(err)
+		return tr.withTx(ctx, fn)
+	}
+	return err
+}
+
+func saveEvent(ctx context.Context, q *gen.Queries, eventType domain.EventType, task domain.Task) error {
+	payload, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+
+	err = q.SaveOutboxEvent(ctx, gen.SaveOutboxEventParams{
+		EventID:     uuid.New(),
+		EventType:   string(eventType),
+		AggregateID: task.ID,
+		Payload:     payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save %s event: %w", eventType, err)
+	}
+	return nil
+}
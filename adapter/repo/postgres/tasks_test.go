@@ -1,9 +1,7 @@
 package repo
 
 import (
-	"api/adapter/repo/postgres/gen"
 	"api/domain"
-	"context"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -18,9 +16,9 @@ func TestGetTaskById_Success(t *testing.T) {
 	db := getIsolatedDatabase(t)
 	assert.NotEqual(t, nil, db)
 
-	repo := NewTasksRepo(gen.New(db))
+	repo := NewTasksRepo(db)
 
-	_, err := repo.CreateTask(context.Background(), domain.Task{
+	_, err := repo.CreateTask(orgContext(), domain.Task{
 		ID:          id,
 		Title:       "Do unit tests",
 		Description: "Create extensive unit tests for all layers",
@@ -30,7 +28,7 @@ func TestGetTaskById_Success(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	task, err := repo.GetTaskById(context.Background(), id)
+	task, err := repo.GetTaskById(orgContext(), id)
 	require.NoError(t, err)
 	require.Equal(t, id, task.ID)
 }
@@ -42,10 +40,10 @@ func TestGetTaskById_NotFound(t *testing.T) {
 	db := getIsolatedDatabase(t)
 	assert.NotEqual(t, nil, db)
 
-	repo := NewTasksRepo(gen.New(db))
+	repo := NewTasksRepo(db)
 
-	_, err := repo.GetTaskById(context.Background(), id)
-	require.Error(t, err, "not found")
+	_, err := repo.GetTaskById(orgContext(), id)
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
 }
 
 func TestGetTasks_Success(t *testing.T) {
@@ -56,9 +54,9 @@ func TestGetTasks_Success(t *testing.T) {
 	db := getIsolatedDatabase(t)
 	assert.NotEqual(t, nil, db)
 
-	repo := NewTasksRepo(gen.New(db))
+	repo := NewTasksRepo(db)
 
-	_, err := repo.CreateTask(context.Background(), domain.Task{
+	_, err := repo.CreateTask(orgContext(), domain.Task{
 		ID:          id1,
 		Title:       "Do unit tests",
 		Description: "Create extensive unit tests for all layers",
@@ -68,7 +66,7 @@ func TestGetTasks_Success(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	_, err = repo.CreateTask(context.Background(), domain.Task{
+	_, err = repo.CreateTask(orgContext(), domain.Task{
 		ID:          id2,
 		Title:       "Do tests",
 		Description: "Create tests for all layers",
@@ -78,20 +76,94 @@ func TestGetTasks_Success(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	tasks, err := repo.GetTasks(context.Background())
+	tasks, err := repo.GetTasks(orgContext(), domain.TaskFilter{})
 	require.NoError(t, err)
 	require.Equal(t, 2, len(tasks))
 }
 
+// Pages must follow each other whatever the TimeZone of the session, as created_at has
+// no time zone.
+func TestGetTasksPage_SessionTimeZone(t *testing.T) {
+	t.Parallel()
+	id1 := uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce")
+	id2 := uuid.MustParse("9d373cde-0ba2-45ba-b3dc-61bcfe2faadb")
+	id3 := uuid.MustParse("c3a1c8a4-5b1e-4f63-9d8e-2f4f7f0b6a11")
+
+	db := getIsolatedDatabase(t)
+	// A single connection keeps the session setting for every query of the repo.
+	db.SetMaxOpenConns(1)
+	_, err := db.Exec("SET TIME ZONE 'America/New_York'")
+	require.NoError(t, err)
+
+	repo := NewTasksRepo(db)
+	for _, id := range []uuid.UUID{id1, id2, id3} {
+		_, err := repo.CreateTask(orgContext(), domain.Task{
+			ID:      id,
+			Title:   "Do unit tests",
+			Status:  "PENDING",
+			DueDate: time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC),
+		})
+		require.NoError(t, err)
+	}
+
+	var ids []uuid.UUID
+	var after *domain.TaskCursor
+	for range 4 {
+		tasks, err := repo.GetTasksPage(orgContext(), domain.TaskFilter{}, after, 1)
+		require.NoError(t, err)
+		if len(tasks) == 0 {
+			break
+		}
+		ids = append(ids, tasks[0].ID)
+		cursor := domain.CursorOf(tasks[0])
+		after = &cursor
+	}
+	require.Equal(t, []uuid.UUID{id1, id2, id3}, ids)
+}
+
+func TestGetTasks_FilterByStatus(t *testing.T) {
+	t.Parallel()
+	id1 := uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce")
+	id2 := uuid.MustParse("9d373cde-0ba2-45ba-b3dc-61bcfe2faadb")
+
+	db := getIsolatedDatabase(t)
+	assert.NotEqual(t, nil, db)
+
+	repo := NewTasksRepo(db)
+
+	_, err := repo.CreateTask(orgContext(), domain.Task{
+		ID:          id1,
+		Title:       "Do unit tests",
+		Description: "Create extensive unit tests for all layers",
+		Status:      "PENDING",
+		DueDate:     time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	_, err = repo.CreateTask(orgContext(), domain.Task{
+		ID:          id2,
+		Title:       "Do tests",
+		Description: "Create tests for all layers",
+		Status:      "DONE",
+		DueDate:     time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	tasks, err := repo.GetTasks(orgContext(), domain.TaskFilter{Status: "DONE"})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(tasks))
+	require.Equal(t, id2, tasks[0].ID)
+}
+
 func TestGetTasks_EmptyDB(t *testing.T) {
 	t.Parallel()
 
 	db := getIsolatedDatabase(t)
 	assert.NotEqual(t, nil, db)
 
-	repo := NewTasksRepo(gen.New(db))
+	repo := NewTasksRepo(db)
 
-	tasks, err := repo.GetTasks(context.Background())
+	tasks, err := repo.GetTasks(orgContext(), domain.TaskFilter{})
 	require.NoError(t, err)
 	require.Equal(t, 0, len(tasks))
 }
@@ -103,9 +175,9 @@ func TestCreateTask_Success(t *testing.T) {
 	db := getIsolatedDatabase(t)
 	assert.NotEqual(t, nil, db)
 
-	repo := NewTasksRepo(gen.New(db))
+	repo := NewTasksRepo(db)
 
-	createdTask, err := repo.CreateTask(context.Background(), domain.Task{
+	createdTask, err := repo.CreateTask(orgContext(), domain.Task{
 		ID:          id,
 		Title:       "Do unit tests",
 		Description: "Create extensive unit tests for all layers",
@@ -116,7 +188,7 @@ func TestCreateTask_Success(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, id, createdTask.ID)
 
-	task, err := repo.GetTaskById(context.Background(), id)
+	task, err := repo.GetTaskById(orgContext(), id)
 	require.NoError(t, err)
 	require.Equal(t, createdTask.ID, task.ID)
 }
@@ -128,9 +200,9 @@ func TestCreateTask_Conflict(t *testing.T) {
 	db := getIsolatedDatabase(t)
 	assert.NotEqual(t, nil, db)
 
-	repo := NewTasksRepo(gen.New(db))
+	repo := NewTasksRepo(db)
 
-	createdTask, err := repo.CreateTask(context.Background(), domain.Task{
+	createdTask, err := repo.CreateTask(orgContext(), domain.Task{
 		ID:          id,
 		Title:       "Do unit tests",
 		Description: "Create extensive unit tests for all layers",
@@ -141,7 +213,7 @@ func TestCreateTask_Conflict(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, id, createdTask.ID)
 
-	_, err = repo.CreateTask(context.Background(), domain.Task{
+	_, err = repo.CreateTask(orgContext(), domain.Task{
 		ID:          id,
 		Title:       "Do unit tests",
 		Description: "Create extensive unit tests for all layers",
@@ -151,3 +223,358 @@ func TestCreateTask_Conflict(t *testing.T) {
 	})
 	require.Error(t, err)
 }
+
+func TestUpdateTask_Success(t *testing.T) {
+	t.Parallel()
+	id := uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce")
+
+	db := getIsolatedDatabase(t)
+	assert.NotEqual(t, nil, db)
+
+	repo := NewTasksRepo(db)
+
+	_, err := repo.CreateTask(orgContext(), domain.Task{
+		ID:          id,
+		Title:       "Do unit tests",
+		Description: "Create extensive unit tests for all layers",
+		Status:      "PENDING",
+		DueDate:     time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	updatedTask, err := repo.UpdateTask(orgContext(), domain.Task{
+		ID:          id,
+		Title:       "Do unit tests",
+		Description: "Create extensive unit tests for all layers",
+		Status:      "DONE",
+		DueDate:     time.Date(2025, 4, 5, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "DONE", updatedTask.Status)
+
+	task, err := repo.GetTaskById(orgContext(), id)
+	require.NoError(t, err)
+	require.Equal(t, "DONE", task.Status)
+	require.Equal(t, time.Date(2025, 4, 5, 0, 0, 0, 0, time.UTC), task.DueDate)
+}
+
+func TestUpdateTask_NotFound(t *testing.T) {
+	t.Parallel()
+	id := uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce")
+
+	db := getIsolatedDatabase(t)
+	assert.NotEqual(t, nil, db)
+
+	repo := NewTasksRepo(db)
+
+	_, err := repo.UpdateTask(orgContext(), domain.Task{ID: id, Title: "Do unit tests", Status: "DONE"})
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+}
+
+func TestCreateTask_Recurring(t *testing.T) {
+	t.Parallel()
+	id := uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce")
+
+	db := getIsolatedDatabase(t)
+	assert.NotEqual(t, nil, db)
+
+	repo := NewTasksRepo(db)
+
+	_, err := repo.CreateTask(orgContext(), domain.Task{
+		ID:          id,
+		Title:       "Send invoices",
+		Description: "Monthly invoices for all customers",
+		Status:      "PENDING",
+		DueDate:     time.Date(2025, 4, 30, 7, 0, 0, 0, time.UTC),
+		RRule:       "FREQ=MONTHLY;BYMONTHDAY=-1",
+		Timezone:    "Europe/Sofia",
+	})
+	require.NoError(t, err)
+
+	task, err := repo.GetTaskById(orgContext(), id)
+	require.NoError(t, err)
+	require.Equal(t, "FREQ=MONTHLY;BYMONTHDAY=-1", task.RRule)
+	require.Equal(t, "Europe/Sofia", task.Timezone)
+}
+
+func createSearchTasks(t *testing.T, repo *TasksRepo) {
+	t.Helper()
+	for _, task := range []domain.Task{
+		{Title: "Write quarterly report", Description: "Summarise the running projects for the board"},
+		{Title: "Book flights", Description: "Flights to the conference in Lisbon"},
+		{Title: "Review report drafts", Description: "Comments on the marketing drafts"},
+	} {
+		task.ID = uuid.New()
+		task.Status = "PENDING"
+		task.DueDate = time.Now().UTC()
+		_, err := repo.CreateTask(orgContext(), task)
+		require.NoError(t, err)
+	}
+}
+
+func TestSearchTasks_Success(t *testing.T) {
+	t.Parallel()
+
+	db := getIsolatedDatabase(t)
+	assert.NotEqual(t, nil, db)
+
+	repo := NewTasksRepo(db)
+	createSearchTasks(t, repo)
+
+	results, err := repo.SearchTasks(orgContext(), "report", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, domain.MatchFullText, results[0].Match)
+	require.Greater(t, results[0].Rank, 0.0)
+
+	// Stemming matches "run" against "running".
+	results, err = repo.SearchTasks(orgContext(), "run board", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "Write quarterly report", results[0].Task.Title)
+	require.Contains(t, results[0].Snippet, "<mark>running</mark>")
+
+	results, err = repo.SearchTasks(orgContext(), "report -quarterly", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "Review report drafts", results[0].Task.Title)
+
+	results, err = repo.SearchTasks(orgContext(), "report", 1)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+}
+
+func TestSearchTasks_NoHits(t *testing.T) {
+	t.Parallel()
+
+	db := getIsolatedDatabase(t)
+	assert.NotEqual(t, nil, db)
+
+	repo := NewTasksRepo(db)
+	createSearchTasks(t, repo)
+
+	results, err := repo.SearchTasks(orgContext(), "conferense", 10)
+	require.NoError(t, err)
+	require.Empty(t, results)
+}
+
+func TestSearchTasks_Language(t *testing.T) {
+	t.Parallel()
+
+	db := getIsolatedDatabase(t)
+	assert.NotEqual(t, nil, db)
+
+	// The simple configuration does not stem, so "run" no longer matches "running".
+	repo := NewTasksRepo(db).WithSearchLanguage("simple")
+	createSearchTasks(t, repo)
+
+	results, err := repo.SearchTasks(orgContext(), "run", 10)
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	results, err = repo.SearchTasks(orgContext(), "running", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+}
+
+func TestSearchTasksBySimilarity_Success(t *testing.T) {
+	t.Parallel()
+
+	db := getIsolatedDatabase(t)
+	assert.NotEqual(t, nil, db)
+
+	repo := NewTasksRepo(db)
+	createSearchTasks(t, repo)
+
+	results, err := repo.SearchTasksBySimilarity(orgContext(), "conferense", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "Book flights", results[0].Task.Title)
+	require.Equal(t, domain.MatchSimilarity, results[0].Match)
+}
+
+func TestDeleteTask_MovesToTrash(t *testing.T) {
+	t.Parallel()
+	id := uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce")
+
+	db := getIsolatedDatabase(t)
+	assert.NotEqual(t, nil, db)
+
+	repo := NewTasksRepo(db)
+	createTasks(t, repo, id)
+
+	deleted, err := repo.DeleteTask(orgContext(), id)
+	require.NoError(t, err)
+	require.NotNil(t, deleted.DeletedAt)
+
+	_, err = repo.GetTaskById(orgContext(), id)
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+
+	tasks, err := repo.GetTasks(orgContext(), domain.TaskFilter{})
+	require.NoError(t, err)
+	require.Empty(t, tasks)
+
+	_, err = repo.UpdateTask(orgContext(), domain.Task{ID: id, Title: "Do unit tests", Status: "DONE"})
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+
+	trash, err := repo.GetDeletedTasks(orgContext())
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	require.Equal(t, id, trash[0].ID)
+
+	_, err = repo.DeleteTask(orgContext(), id)
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+}
+
+func TestRestoreTask_Success(t *testing.T) {
+	t.Parallel()
+	id := uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce")
+
+	db := getIsolatedDatabase(t)
+	assert.NotEqual(t, nil, db)
+
+	repo := NewTasksRepo(db)
+	createTasks(t, repo, id)
+
+	_, err := repo.DeleteTask(orgContext(), id)
+	require.NoError(t, err)
+
+	restored, err := repo.RestoreTask(orgContext(), id)
+	require.NoError(t, err)
+	require.Nil(t, restored.DeletedAt)
+
+	task, err := repo.GetTaskById(orgContext(), id)
+	require.NoError(t, err)
+	require.Equal(t, "Do unit tests", task.Title)
+
+	_, err = repo.RestoreTask(orgContext(), id)
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+}
+
+func TestPurgeTask_Success(t *testing.T) {
+	t.Parallel()
+	id := uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce")
+
+	db := getIsolatedDatabase(t)
+	assert.NotEqual(t, nil, db)
+
+	repo := NewTasksRepo(db)
+	createTasks(t, repo, id)
+
+	// Only tasks in the trash can be purged.
+	err := repo.PurgeTask(orgContext(), id)
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+
+	_, err = repo.DeleteTask(orgContext(), id)
+	require.NoError(t, err)
+
+	err = repo.PurgeTask(orgContext(), id)
+	require.NoError(t, err)
+
+	trash, err := repo.GetDeletedTasks(orgContext())
+	require.NoError(t, err)
+	require.Empty(t, trash)
+}
+
+func TestInTx_Commit(t *testing.T) {
+	t.Parallel()
+	id := uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce")
+
+	db := getIsolatedDatabase(t)
+	assert.NotEqual(t, nil, db)
+
+	repo := NewTasksRepo(db)
+
+	err := repo.InTx(orgContext(), func(txRepo domain.TasksRepo) error {
+		_, err := txRepo.CreateTask(orgContext(), domain.Task{ID: id, Title: "Do unit tests", Status: "PENDING", DueDate: time.Now().UTC()})
+		if err != nil {
+			return err
+		}
+		_, err = txRepo.UpdateTask(orgContext(), domain.Task{ID: id, Title: "Do unit tests", Status: "DONE", DueDate: time.Now().UTC()})
+		return err
+	})
+	require.NoError(t, err)
+
+	task, err := repo.GetTaskById(orgContext(), id)
+	require.NoError(t, err)
+	require.Equal(t, "DONE", task.Status)
+}
+
+func TestInTx_Rollback(t *testing.T) {
+	t.Parallel()
+	id := uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce")
+
+	db := getIsolatedDatabase(t)
+	assert.NotEqual(t, nil, db)
+
+	repo := NewTasksRepo(db)
+
+	err := repo.InTx(orgContext(), func(txRepo domain.TasksRepo) error {
+		_, err := txRepo.CreateTask(orgContext(), domain.Task{ID: id, Title: "Do unit tests", Status: "PENDING", DueDate: time.Now().UTC()})
+		require.NoError(t, err)
+
+		// The task is visible within the transaction only.
+		_, err = txRepo.GetTaskById(orgContext(), id)
+		require.NoError(t, err)
+
+		_, err = txRepo.DeleteTask(orgContext(), uuid.New())
+		return err
+	})
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+
+	_, err = repo.GetTaskById(orgContext(), id)
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+
+	var pending int
+	err = db.QueryRow("SELECT COUNT(*) FROM outbox").Scan(&pending)
+	require.NoError(t, err)
+	require.Zero(t, pending)
+}
+
+func TestGetTasksByIds_SkipsUnknownAndDeleted(t *testing.T) {
+	t.Parallel()
+	ids := []uuid.UUID{
+		uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3c0"),
+		uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3c1"),
+		uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3c2"),
+	}
+
+	db := getIsolatedDatabase(t)
+	assert.NotEqual(t, nil, db)
+
+	repo := NewTasksRepo(db)
+	createTasks(t, repo, ids[0], ids[1])
+	_, err := repo.DeleteTask(orgContext(), ids[1])
+	require.NoError(t, err)
+
+	tasks, err := repo.GetTasksByIds(orgContext(), ids)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	require.Equal(t, ids[0], tasks[0].ID)
+}
+
+func TestGetTaskEvents_Success(t *testing.T) {
+	t.Parallel()
+	ids := []uuid.UUID{
+		uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3c0"),
+		uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3c1"),
+		uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3c2"),
+	}
+
+	db := getIsolatedDatabase(t)
+	assert.NotEqual(t, nil, db)
+
+	repo := NewTasksRepo(db)
+	createTasks(t, repo, ids...)
+	_, err := repo.DeleteTask(orgContext(), ids[0])
+	require.NoError(t, err)
+
+	events, err := repo.GetTaskEvents(orgContext(), ids[:2])
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	require.Equal(t, ids[0], events[0].AggregateID)
+	require.Equal(t, domain.EventTaskCreated, events[0].Type)
+	require.Equal(t, ids[1], events[1].AggregateID)
+	require.Equal(t, ids[0], events[2].AggregateID)
+	require.Equal(t, domain.EventTaskDeleted, events[2].Type)
+}
@@ -0,0 +1,45 @@
+package repo
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestTrashRetention_PurgeExpired(t *testing.T) {
+	t.Parallel()
+
+	db := getIsolatedDatabase(t)
+	repo := NewTasksRepo(db)
+
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+	createTasks(t, repo, ids...)
+	for _, id := range ids[:4] {
+		_, err := repo.DeleteTask(orgContext(), id)
+		require.NoError(t, err)
+	}
+
+	// Three tasks have been in the trash for longer than the retention period.
+	_, err := db.Exec(`UPDATE tasks SET deleted_at = now() - INTERVAL '31 days' WHERE id = ANY($1::UUID[])`,
+		"{"+ids[0].String()+","+ids[1].String()+","+ids[2].String()+"}")
+	require.NoError(t, err)
+
+	retention := NewTrashRetention(db, 30*24*time.Hour, time.Hour, 2)
+	count, err := retention.PurgeExpired(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+
+	trash, err := repo.GetDeletedTasks(orgContext())
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	require.Equal(t, ids[3], trash[0].ID)
+
+	_, err = repo.GetTaskById(orgContext(), ids[4])
+	require.NoError(t, err)
+
+	count, err = retention.PurgeExpired(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+}
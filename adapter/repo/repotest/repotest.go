@@ -0,0 +1,780 @@
+// Package repotest holds the behaviour every storage adapter has to share, as a suite
+// the test of each adapter runs against its own repos.
+package repotest
+
+import (
+	"api/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"io"
+	"testing"
+	"time"
+)
+
+// Repos are the repos of a single storage adapter, all backed by the same empty storage
+// holding only the default organization.
+type Repos struct {
+	Tasks         domain.TasksRepo
+	CalendarFeeds domain.CalendarFeedsRepo
+	Organizations domain.OrganizationsRepo
+}
+
+// Run runs the suite, calling newRepos once for every test.
+func Run(t *testing.T, newRepos func(t *testing.T) Repos) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, repos Repos)
+	}{
+		{"GetTaskById", testGetTaskById},
+		{"GetTaskByIdForUpdate", testGetTaskByIdForUpdate},
+		{"GetTasks", testGetTasks},
+		{"GetTasksPage", testGetTasksPage},
+		{"CountTasks", testCountTasks},
+		{"GetTasksByIds", testGetTasksByIds},
+		{"CreateTask", testCreateTask},
+		{"UpdateTask", testUpdateTask},
+		{"Trash", testTrash},
+		{"GetTaskEvents", testGetTaskEvents},
+		{"InTx", testInTx},
+		{"ImportTasks", testImportTasks},
+		{"ExportTasks", testExportTasks},
+		{"SearchTasks", testSearchTasks},
+		{"SearchTasksBySimilarity", testSearchTasksBySimilarity},
+		{"SearchSnippetsEscaped", testSearchSnippetsEscaped},
+		{"CalendarFeeds", testCalendarFeeds},
+		{"Organizations", testOrganizations},
+		{"TenantIsolation", testTenantIsolation},
+		{"NoOrganization", testNoOrganization},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tt.test(t, newRepos(t))
+		})
+	}
+}
+
+var (
+	id1 = uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3c0")
+	id2 = uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3c1")
+	id3 = uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3c2")
+)
+
+// orgContext scopes the repos to the default organization.
+func orgContext() context.Context {
+	return domain.WithOrganization(context.Background(), domain.DefaultOrganizationID)
+}
+
+func newTask(id uuid.UUID, title, status string) domain.Task {
+	return domain.Task{
+		ID:          id,
+		Title:       title,
+		Description: "Create extensive unit tests for all layers",
+		Status:      status,
+		DueDate:     time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func createTasks(t *testing.T, repo domain.TasksRepo, tasks ...domain.Task) {
+	t.Helper()
+	for _, task := range tasks {
+		_, err := repo.CreateTask(orgContext(), task)
+		require.NoError(t, err)
+	}
+}
+
+func taskIDs(tasks []domain.Task) []uuid.UUID {
+	ids := make([]uuid.UUID, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+func testGetTaskById(t *testing.T, repos Repos) {
+	task := newTask(id1, "Do unit tests", domain.StatusPending)
+	task.RRule = "FREQ=MONTHLY;BYMONTHDAY=-1"
+	task.Timezone = "Europe/Sofia"
+	createTasks(t, repos.Tasks, task)
+
+	got, err := repos.Tasks.GetTaskById(orgContext(), id1)
+	require.NoError(t, err)
+	require.Equal(t, id1, got.ID)
+	require.Equal(t, "Do unit tests", got.Title)
+	require.Equal(t, task.Description, got.Description)
+	require.Equal(t, domain.StatusPending, got.Status)
+	require.True(t, task.DueDate.Equal(got.DueDate))
+	require.Equal(t, task.RRule, got.RRule)
+	require.Equal(t, task.Timezone, got.Timezone)
+	require.Nil(t, got.DeletedAt)
+
+	_, err = repos.Tasks.GetTaskById(orgContext(), id2)
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+}
+
+func testGetTaskByIdForUpdate(t *testing.T, repos Repos) {
+	createTasks(t, repos.Tasks, newTask(id1, "Do unit tests", domain.StatusPending))
+
+	err := repos.Tasks.InTx(orgContext(), func(txRepo domain.TasksRepo) error {
+		got, err := txRepo.GetTaskByIdForUpdate(orgContext(), id1)
+		require.NoError(t, err)
+		require.Equal(t, "Do unit tests", got.Title)
+
+		_, err = txRepo.GetTaskByIdForUpdate(orgContext(), id2)
+		require.ErrorIs(t, err, domain.ErrTaskNotFound)
+		return nil
+	})
+	require.NoError(t, err)
+
+	_, err = repos.Tasks.DeleteTask(orgContext(), id1)
+	require.NoError(t, err)
+	_, err = repos.Tasks.GetTaskByIdForUpdate(orgContext(), id1)
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+}
+
+func testGetTasks(t *testing.T, repos Repos) {
+	tasks, err := repos.Tasks.GetTasks(orgContext(), domain.TaskFilter{})
+	require.NoError(t, err)
+	require.NotNil(t, tasks)
+	require.Empty(t, tasks)
+
+	createTasks(t, repos.Tasks,
+		newTask(id1, "Do unit tests", domain.StatusPending),
+		newTask(id2, "Do tests", domain.StatusDone),
+		newTask(id3, "Write docs", domain.StatusPending),
+	)
+	_, err = repos.Tasks.DeleteTask(orgContext(), id3)
+	require.NoError(t, err)
+
+	tasks, err = repos.Tasks.GetTasks(orgContext(), domain.TaskFilter{})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uuid.UUID{id1, id2}, taskIDs(tasks))
+
+	tasks, err = repos.Tasks.GetTasks(orgContext(), domain.TaskFilter{Status: domain.StatusDone})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{id2}, taskIDs(tasks))
+}
+
+func testGetTasksPage(t *testing.T, repos Repos) {
+	tasks, err := repos.Tasks.GetTasksPage(orgContext(), domain.TaskFilter{}, nil, 2)
+	require.NoError(t, err)
+	require.NotNil(t, tasks)
+	require.Empty(t, tasks)
+
+	// Imported tasks share their creation time, so they are ordered by id.
+	_, err = repos.Tasks.ImportTasks(orgContext(), taskIterator(
+		newTask(id3, "Write docs", domain.StatusPending),
+		newTask(id1, "Do unit tests", domain.StatusPending),
+		newTask(id2, "Do tests", domain.StatusDone),
+	), false)
+	require.NoError(t, err)
+	id4 := uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3b0")
+	createTasks(t, repos.Tasks, newTask(id4, "Review tests", domain.StatusPending))
+
+	tasks, err = repos.Tasks.GetTasksPage(orgContext(), domain.TaskFilter{}, nil, 2)
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{id1, id2}, taskIDs(tasks))
+
+	after := domain.CursorOf(tasks[1])
+	tasks, err = repos.Tasks.GetTasksPage(orgContext(), domain.TaskFilter{}, &after, 2)
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{id3, id4}, taskIDs(tasks))
+
+	after = domain.CursorOf(tasks[1])
+	tasks, err = repos.Tasks.GetTasksPage(orgContext(), domain.TaskFilter{}, &after, 2)
+	require.NoError(t, err)
+	require.Empty(t, tasks)
+
+	// A cursor stays valid when its task is deleted.
+	deleted, err := repos.Tasks.DeleteTask(orgContext(), id1)
+	require.NoError(t, err)
+	after = domain.CursorOf(deleted)
+	tasks, err = repos.Tasks.GetTasksPage(orgContext(), domain.TaskFilter{Status: domain.StatusPending}, &after, 10)
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{id3, id4}, taskIDs(tasks))
+}
+
+func testCountTasks(t *testing.T, repos Repos) {
+	count, err := repos.Tasks.CountTasks(orgContext(), domain.TaskFilter{})
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	createTasks(t, repos.Tasks,
+		newTask(id1, "Do unit tests", domain.StatusPending),
+		newTask(id2, "Do tests", domain.StatusDone),
+		newTask(id3, "Write docs", domain.StatusPending),
+	)
+	_, err = repos.Tasks.DeleteTask(orgContext(), id3)
+	require.NoError(t, err)
+
+	count, err = repos.Tasks.CountTasks(orgContext(), domain.TaskFilter{})
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	count, err = repos.Tasks.CountTasks(orgContext(), domain.TaskFilter{Status: domain.StatusDone})
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
+func testGetTasksByIds(t *testing.T, repos Repos) {
+	createTasks(t, repos.Tasks,
+		newTask(id1, "Do unit tests", domain.StatusPending),
+		newTask(id2, "Do tests", domain.StatusPending),
+	)
+	_, err := repos.Tasks.DeleteTask(orgContext(), id2)
+	require.NoError(t, err)
+
+	tasks, err := repos.Tasks.GetTasksByIds(orgContext(), []uuid.UUID{id1, id2, id3})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{id1}, taskIDs(tasks))
+
+	tasks, err = repos.Tasks.GetTasksByIds(orgContext(), nil)
+	require.NoError(t, err)
+	require.Empty(t, tasks)
+}
+
+func testCreateTask(t *testing.T, repos Repos) {
+	before := time.Now().Add(-time.Minute)
+	created, err := repos.Tasks.CreateTask(orgContext(), newTask(id1, "Do unit tests", domain.StatusPending))
+	require.NoError(t, err)
+	require.Equal(t, id1, created.ID)
+	require.Equal(t, "Do unit tests", created.Title)
+	// The creation time is set by the storage, whatever the task says.
+	require.True(t, created.CreatedAt.After(before))
+
+	task, err := repos.Tasks.GetTaskById(orgContext(), id1)
+	require.NoError(t, err)
+	require.True(t, created.CreatedAt.Equal(task.CreatedAt))
+
+	_, err = repos.Tasks.CreateTask(orgContext(), newTask(id1, "Do it again", domain.StatusPending))
+	require.ErrorIs(t, err, domain.ErrTaskExists)
+
+	events, err := repos.Tasks.GetTaskEvents(orgContext(), []uuid.UUID{id1})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+}
+
+func testUpdateTask(t *testing.T, repos Repos) {
+	createTasks(t, repos.Tasks, newTask(id1, "Do unit tests", domain.StatusPending))
+
+	update := newTask(id1, "Do more unit tests", domain.StatusDone)
+	update.DueDate = time.Date(2025, 4, 5, 0, 0, 0, 0, time.UTC)
+	updated, err := repos.Tasks.UpdateTask(orgContext(), update)
+	require.NoError(t, err)
+	require.Equal(t, domain.StatusDone, updated.Status)
+	require.False(t, updated.CreatedAt.IsZero())
+
+	task, err := repos.Tasks.GetTaskById(orgContext(), id1)
+	require.NoError(t, err)
+	require.Equal(t, "Do more unit tests", task.Title)
+	require.Equal(t, domain.StatusDone, task.Status)
+	require.True(t, update.DueDate.Equal(task.DueDate))
+
+	_, err = repos.Tasks.UpdateTask(orgContext(), newTask(id2, "Do unit tests", domain.StatusDone))
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+}
+
+func testTrash(t *testing.T, repos Repos) {
+	createTasks(t, repos.Tasks,
+		newTask(id1, "Do unit tests", domain.StatusPending),
+		newTask(id2, "Do tests", domain.StatusPending),
+	)
+
+	// Only tasks in the trash can be restored and purged.
+	_, err := repos.Tasks.RestoreTask(orgContext(), id1)
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+	require.ErrorIs(t, repos.Tasks.PurgeTask(orgContext(), id1), domain.ErrTaskNotFound)
+
+	deleted, err := repos.Tasks.DeleteTask(orgContext(), id1)
+	require.NoError(t, err)
+	require.NotNil(t, deleted.DeletedAt)
+	_, err = repos.Tasks.DeleteTask(orgContext(), id2)
+	require.NoError(t, err)
+
+	_, err = repos.Tasks.GetTaskById(orgContext(), id1)
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+	_, err = repos.Tasks.UpdateTask(orgContext(), newTask(id1, "Do unit tests", domain.StatusDone))
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+	_, err = repos.Tasks.DeleteTask(orgContext(), id1)
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+	// The id of a task in the trash stays taken.
+	_, err = repos.Tasks.CreateTask(orgContext(), newTask(id1, "Do unit tests", domain.StatusPending))
+	require.ErrorIs(t, err, domain.ErrTaskExists)
+
+	// The most recently deleted task comes first.
+	trash, err := repos.Tasks.GetDeletedTasks(orgContext())
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{id2, id1}, taskIDs(trash))
+
+	restored, err := repos.Tasks.RestoreTask(orgContext(), id1)
+	require.NoError(t, err)
+	require.Nil(t, restored.DeletedAt)
+	task, err := repos.Tasks.GetTaskById(orgContext(), id1)
+	require.NoError(t, err)
+	require.Equal(t, "Do unit tests", task.Title)
+
+	require.NoError(t, repos.Tasks.PurgeTask(orgContext(), id2))
+	require.ErrorIs(t, repos.Tasks.PurgeTask(orgContext(), id2), domain.ErrTaskNotFound)
+	_, err = repos.Tasks.RestoreTask(orgContext(), id2)
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+
+	trash, err = repos.Tasks.GetDeletedTasks(orgContext())
+	require.NoError(t, err)
+	require.NotNil(t, trash)
+	require.Empty(t, trash)
+}
+
+func testGetTaskEvents(t *testing.T, repos Repos) {
+	createTasks(t, repos.Tasks,
+		newTask(id1, "Do unit tests", domain.StatusPending),
+		newTask(id2, "Do tests", domain.StatusPending),
+		newTask(id3, "Write docs", domain.StatusPending),
+	)
+	_, err := repos.Tasks.UpdateTask(orgContext(), newTask(id1, "Do unit tests", domain.StatusDone))
+	require.NoError(t, err)
+	_, err = repos.Tasks.DeleteTask(orgContext(), id1)
+	require.NoError(t, err)
+	_, err = repos.Tasks.RestoreTask(orgContext(), id1)
+	require.NoError(t, err)
+
+	events, err := repos.Tasks.GetTaskEvents(orgContext(), []uuid.UUID{id1, id2})
+	require.NoError(t, err)
+	require.Len(t, events, 5)
+
+	expected := []struct {
+		eventType domain.EventType
+		id        uuid.UUID
+	}{
+		{domain.EventTaskCreated, id1},
+		{domain.EventTaskCreated, id2},
+		{domain.EventTaskUpdated, id1},
+		{domain.EventTaskDeleted, id1},
+		{domain.EventTaskRestored, id1},
+	}
+	for i, event := range events {
+		require.Equal(t, expected[i].eventType, event.Type)
+		require.Equal(t, expected[i].id, event.AggregateID)
+		require.NotEqual(t, uuid.Nil, event.ID)
+		require.False(t, event.OccurredAt.IsZero())
+
+		// The payload is the task as it was after the change.
+		var task domain.Task
+		require.NoError(t, json.Unmarshal(event.Payload, &task))
+		require.Equal(t, expected[i].id, task.ID)
+	}
+
+	var deleted domain.Task
+	require.NoError(t, json.Unmarshal(events[3].Payload, &deleted))
+	require.NotNil(t, deleted.DeletedAt)
+	require.Equal(t, domain.StatusDone, deleted.Status)
+
+	events, err = repos.Tasks.GetTaskEvents(orgContext(), []uuid.UUID{uuid.New()})
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+func testInTx(t *testing.T, repos Repos) {
+	err := repos.Tasks.InTx(orgContext(), func(txRepo domain.TasksRepo) error {
+		_, err := txRepo.CreateTask(orgContext(), newTask(id1, "Do unit tests", domain.StatusPending))
+		if err != nil {
+			return err
+		}
+		// A nested transaction joins the outer one.
+		return txRepo.InTx(orgContext(), func(txRepo domain.TasksRepo) error {
+			_, err := txRepo.UpdateTask(orgContext(), newTask(id1, "Do unit tests", domain.StatusDone))
+			return err
+		})
+	})
+	require.NoError(t, err)
+
+	task, err := repos.Tasks.GetTaskById(orgContext(), id1)
+	require.NoError(t, err)
+	require.Equal(t, domain.StatusDone, task.Status)
+
+	err = repos.Tasks.InTx(orgContext(), func(txRepo domain.TasksRepo) error {
+		_, err := txRepo.CreateTask(orgContext(), newTask(id2, "Do tests", domain.StatusPending))
+		require.NoError(t, err)
+		_, err = txRepo.UpdateTask(orgContext(), newTask(id1, "Taken over", domain.StatusPending))
+		require.NoError(t, err)
+
+		// The changes are visible within the transaction only.
+		_, err = txRepo.GetTaskById(orgContext(), id2)
+		require.NoError(t, err)
+
+		_, err = txRepo.DeleteTask(orgContext(), id3)
+		return err
+	})
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+
+	_, err = repos.Tasks.GetTaskById(orgContext(), id2)
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+	task, err = repos.Tasks.GetTaskById(orgContext(), id1)
+	require.NoError(t, err)
+	require.Equal(t, "Do unit tests", task.Title)
+
+	// Neither are the events of the rolled back changes kept.
+	events, err := repos.Tasks.GetTaskEvents(orgContext(), []uuid.UUID{id1, id2})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+}
+
+func taskIterator(tasks ...domain.Task) func() (domain.Task, error) {
+	return func() (domain.Task, error) {
+		if len(tasks) == 0 {
+			return domain.Task{}, io.EOF
+		}
+		task := tasks[0]
+		tasks = tasks[1:]
+		return task, nil
+	}
+}
+
+func testImportTasks(t *testing.T, repos Repos) {
+	createTasks(t, repos.Tasks, newTask(id3, "Do unit tests", domain.StatusPending))
+
+	// The duplicate of the first task and the existing task are skipped.
+	tasks := []domain.Task{
+		newTask(id1, "Imported task", domain.StatusPending),
+		newTask(id2, "Imported task", domain.StatusDone),
+		newTask(id3, "Imported task", domain.StatusPending),
+		newTask(id1, "Imported twice", domain.StatusPending),
+	}
+
+	imported, err := repos.Tasks.ImportTasks(orgContext(), taskIterator(tasks...), true)
+	require.NoError(t, err)
+	require.Equal(t, 2, imported)
+	_, err = repos.Tasks.GetTaskById(orgContext(), id1)
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+
+	imported, err = repos.Tasks.ImportTasks(orgContext(), taskIterator(tasks...), false)
+	require.NoError(t, err)
+	require.Equal(t, 2, imported)
+
+	task, err := repos.Tasks.GetTaskById(orgContext(), id1)
+	require.NoError(t, err)
+	require.Equal(t, "Imported task", task.Title)
+	require.False(t, task.CreatedAt.IsZero())
+	task, err = repos.Tasks.GetTaskById(orgContext(), id3)
+	require.NoError(t, err)
+	require.Equal(t, "Do unit tests", task.Title)
+
+	events, err := repos.Tasks.GetTaskEvents(orgContext(), []uuid.UUID{id1, id2})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, id1, events[0].AggregateID)
+	require.Equal(t, domain.EventTaskCreated, events[0].Type)
+	require.Equal(t, id2, events[1].AggregateID)
+
+	// A failing source imports nothing.
+	sourceErr := errors.New("broken source")
+	next := taskIterator(newTask(uuid.New(), "Imported task", domain.StatusPending))
+	imported, err = repos.Tasks.ImportTasks(orgContext(), func() (domain.Task, error) {
+		if task, err := next(); err == nil {
+			return task, nil
+		}
+		return domain.Task{}, sourceErr
+	}, false)
+	require.ErrorIs(t, err, sourceErr)
+	require.Zero(t, imported)
+
+	all, err := repos.Tasks.GetTasks(orgContext(), domain.TaskFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+}
+
+func testExportTasks(t *testing.T, repos Repos) {
+	createTasks(t, repos.Tasks,
+		newTask(id1, "Do unit tests", domain.StatusPending),
+		newTask(id2, "Do tests", domain.StatusDone),
+		newTask(id3, "Write docs", domain.StatusPending),
+	)
+	_, err := repos.Tasks.DeleteTask(orgContext(), id2)
+	require.NoError(t, err)
+
+	// Tasks are exported oldest first.
+	var exported []uuid.UUID
+	err = repos.Tasks.ExportTasks(orgContext(), domain.TaskFilter{}, func(task domain.Task) error {
+		exported = append(exported, task.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{id1, id3}, exported)
+
+	exported = nil
+	err = repos.Tasks.ExportTasks(orgContext(), domain.TaskFilter{Status: domain.StatusDone}, func(task domain.Task) error {
+		exported = append(exported, task.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Empty(t, exported)
+
+	callbackErr := errors.New("client went away")
+	calls := 0
+	err = repos.Tasks.ExportTasks(orgContext(), domain.TaskFilter{}, func(task domain.Task) error {
+		calls++
+		return callbackErr
+	})
+	require.ErrorIs(t, err, callbackErr)
+	require.Equal(t, 1, calls)
+}
+
+func createSearchTasks(t *testing.T, repo domain.TasksRepo) {
+	t.Helper()
+	for _, task := range []domain.Task{
+		{Title: "Write quarterly report", Description: "Summarise the running projects for the board"},
+		{Title: "Book flights", Description: "Flights to the conference in Lisbon"},
+		{Title: "Review report drafts", Description: "Comments on the marketing drafts"},
+	} {
+		task.ID = uuid.New()
+		task.Status = domain.StatusPending
+		task.DueDate = time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)
+		createTasks(t, repo, task)
+	}
+}
+
+func testSearchTasks(t *testing.T, repos Repos) {
+	createSearchTasks(t, repos.Tasks)
+
+	results, err := repos.Tasks.SearchTasks(orgContext(), "report", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	for _, result := range results {
+		require.Equal(t, domain.MatchFullText, result.Match)
+		require.Greater(t, result.Rank, 0.0)
+	}
+
+	// Stemming matches "run" against "running".
+	results, err = repos.Tasks.SearchTasks(orgContext(), "run board", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "Write quarterly report", results[0].Task.Title)
+	require.Contains(t, results[0].Snippet, "<mark>running</mark>")
+
+	results, err = repos.Tasks.SearchTasks(orgContext(), "report -quarterly", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "Review report drafts", results[0].Task.Title)
+
+	results, err = repos.Tasks.SearchTasks(orgContext(), "lisbon or marketing", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	results, err = repos.Tasks.SearchTasks(orgContext(), `"marketing drafts"`, 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "Review report drafts", results[0].Task.Title)
+
+	results, err = repos.Tasks.SearchTasks(orgContext(), "report", 1)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	// Full-text search does not tolerate typos.
+	results, err = repos.Tasks.SearchTasks(orgContext(), "conferense", 10)
+	require.NoError(t, err)
+	require.NotNil(t, results)
+	require.Empty(t, results)
+}
+
+func testSearchTasksBySimilarity(t *testing.T, repos Repos) {
+	createSearchTasks(t, repos.Tasks)
+
+	results, err := repos.Tasks.SearchTasksBySimilarity(orgContext(), "conferense", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "Book flights", results[0].Task.Title)
+	require.Equal(t, domain.MatchSimilarity, results[0].Match)
+	require.Greater(t, results[0].Rank, 0.0)
+	require.Equal(t, "Flights to the conference in Lisbon", results[0].Snippet)
+
+	results, err = repos.Tasks.SearchTasksBySimilarity(orgContext(), "xylophone", 10)
+	require.NoError(t, err)
+	require.NotNil(t, results)
+	require.Empty(t, results)
+}
+
+// Snippets are rendered as HTML, so the markup of a description must not get into them.
+func testSearchSnippetsEscaped(t *testing.T, repos Repos) {
+	createTasks(t, repos.Tasks, domain.Task{
+		ID:          uuid.New(),
+		Title:       "Fix the invoice page",
+		Description: `The <script>alert("invoice")</script> tag runs on the invoice page`,
+		Status:      domain.StatusPending,
+		DueDate:     time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC),
+	})
+
+	results, err := repos.Tasks.SearchTasks(orgContext(), "invoice", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.NotContains(t, results[0].Snippet, "</script>")
+	require.Contains(t, results[0].Snippet, "&lt;/script&gt;")
+	require.Contains(t, results[0].Snippet, "<mark>invoice</mark>")
+
+	results, err = repos.Tasks.SearchTasksBySimilarity(orgContext(), "invoise", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "The &lt;script&gt;alert(&#34;invoice&#34;)&lt;/script&gt; tag runs on the invoice page", results[0].Snippet)
+}
+
+func testCalendarFeeds(t *testing.T, repos Repos) {
+	repo := repos.CalendarFeeds
+	created, err := repo.CreateFeed(orgContext(), domain.CalendarFeed{ID: uuid.New(), Name: "My tasks", Status: domain.StatusPending}, "hash-1")
+	require.NoError(t, err)
+	require.Equal(t, "My tasks", created.Name)
+	require.Equal(t, domain.StatusPending, created.Status)
+	require.False(t, created.CreatedAt.IsZero())
+	require.Nil(t, created.RotatedAt)
+
+	feed, err := repo.GetFeedByTokenHash(orgContext(), "hash-1")
+	require.NoError(t, err)
+	require.Equal(t, created.ID, feed.ID)
+
+	rotated, err := repo.RotateFeedToken(orgContext(), "hash-1", "hash-2")
+	require.NoError(t, err)
+	require.Equal(t, created.ID, rotated.ID)
+	require.NotNil(t, rotated.RotatedAt)
+
+	_, err = repo.GetFeedByTokenHash(orgContext(), "hash-1")
+	require.ErrorIs(t, err, domain.ErrCalendarFeedNotFound)
+	_, err = repo.RotateFeedToken(orgContext(), "hash-1", "hash-3")
+	require.ErrorIs(t, err, domain.ErrCalendarFeedNotFound)
+
+	require.NoError(t, repo.DeleteFeed(orgContext(), "hash-2"))
+	require.ErrorIs(t, repo.DeleteFeed(orgContext(), "hash-2"), domain.ErrCalendarFeedNotFound)
+	_, err = repo.GetFeedByTokenHash(orgContext(), "hash-2")
+	require.ErrorIs(t, err, domain.ErrCalendarFeedNotFound)
+}
+
+func testOrganizations(t *testing.T, repos Repos) {
+	repo := repos.Organizations
+	created, err := repo.CreateOrganization(context.Background(), domain.Organization{ID: uuid.New(), Slug: "acme", Name: "Acme"})
+	require.NoError(t, err)
+	require.Equal(t, "acme", created.Slug)
+	require.Equal(t, "Acme", created.Name)
+	require.False(t, created.CreatedAt.IsZero())
+
+	org, err := repo.GetOrganizationBySlug(context.Background(), "acme")
+	require.NoError(t, err)
+	require.Equal(t, created.ID, org.ID)
+	require.Equal(t, created.Name, org.Name)
+
+	_, err = repo.CreateOrganization(context.Background(), domain.Organization{ID: uuid.New(), Slug: "acme", Name: "Acme again"})
+	require.ErrorIs(t, err, domain.ErrOrganizationExists)
+
+	_, err = repo.GetOrganizationBySlug(context.Background(), "globex")
+	require.ErrorIs(t, err, domain.ErrOrganizationNotFound)
+
+	org, err = repo.GetOrganizationBySlug(context.Background(), "default")
+	require.NoError(t, err)
+	require.Equal(t, domain.DefaultOrganizationID, org.ID)
+}
+
+func createOrganization(t *testing.T, repo domain.OrganizationsRepo, slug string) context.Context {
+	t.Helper()
+	org, err := repo.CreateOrganization(context.Background(), domain.Organization{ID: uuid.New(), Slug: slug, Name: slug})
+	require.NoError(t, err)
+	return domain.WithOrganization(context.Background(), org.ID)
+}
+
+// testTenantIsolation checks that nothing of one organization can be read nor changed
+// through the repos from another one.
+func testTenantIsolation(t *testing.T, repos Repos) {
+	acme := createOrganization(t, repos.Organizations, "acme")
+	globex := createOrganization(t, repos.Organizations, "globex")
+	tasks := repos.Tasks
+	feeds := repos.CalendarFeeds
+
+	acmeTask := newTask(id1, "Acme roadmap", domain.StatusPending)
+	_, err := tasks.CreateTask(acme, acmeTask)
+	require.NoError(t, err)
+	_, err = tasks.CreateTask(globex, newTask(id2, "Globex budget", domain.StatusPending))
+	require.NoError(t, err)
+	_, err = tasks.CreateTask(acme, newTask(id3, "Acme trash", domain.StatusPending))
+	require.NoError(t, err)
+	_, err = tasks.DeleteTask(acme, id3)
+	require.NoError(t, err)
+	_, err = feeds.CreateFeed(acme, domain.CalendarFeed{ID: uuid.New(), Name: "Acme"}, "acme-hash")
+	require.NoError(t, err)
+
+	_, err = tasks.GetTaskById(globex, id1)
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+
+	list, err := tasks.GetTasks(globex, domain.TaskFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{id2}, taskIDs(list))
+
+	list, err = tasks.GetTasksByIds(globex, []uuid.UUID{id1, id2})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{id2}, taskIDs(list))
+
+	list, err = tasks.GetDeletedTasks(globex)
+	require.NoError(t, err)
+	require.Empty(t, list)
+
+	results, err := tasks.SearchTasks(globex, "roadmap", 10)
+	require.NoError(t, err)
+	require.Empty(t, results)
+	results, err = tasks.SearchTasksBySimilarity(globex, "Acme roadmap", 10)
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	events, err := tasks.GetTaskEvents(globex, []uuid.UUID{id1})
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	var exported []uuid.UUID
+	err = tasks.ExportTasks(globex, domain.TaskFilter{}, func(task domain.Task) error {
+		exported = append(exported, task.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{id2}, exported)
+
+	_, err = tasks.UpdateTask(globex, newTask(id1, "Taken over", domain.StatusDone))
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+	_, err = tasks.DeleteTask(globex, id1)
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+	_, err = tasks.RestoreTask(globex, id3)
+	require.ErrorIs(t, err, domain.ErrTaskNotFound)
+	require.ErrorIs(t, tasks.PurgeTask(globex, id3), domain.ErrTaskNotFound)
+
+	// Ids are unique across organizations, so an import cannot take a task over either.
+	imported, err := tasks.ImportTasks(globex, taskIterator(newTask(id1, "Taken over", domain.StatusDone)), false)
+	require.NoError(t, err)
+	require.Zero(t, imported)
+
+	_, err = feeds.GetFeedByTokenHash(globex, "acme-hash")
+	require.ErrorIs(t, err, domain.ErrCalendarFeedNotFound)
+	_, err = feeds.RotateFeedToken(globex, "acme-hash", "globex-hash")
+	require.ErrorIs(t, err, domain.ErrCalendarFeedNotFound)
+	require.ErrorIs(t, feeds.DeleteFeed(globex, "acme-hash"), domain.ErrCalendarFeedNotFound)
+
+	// Acme still sees its tasks and feed untouched.
+	task, err := tasks.GetTaskById(acme, id1)
+	require.NoError(t, err)
+	require.Equal(t, "Acme roadmap", task.Title)
+	list, err = tasks.GetDeletedTasks(acme)
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{id3}, taskIDs(list))
+	_, err = feeds.GetFeedByTokenHash(acme, "acme-hash")
+	require.NoError(t, err)
+}
+
+// testNoOrganization checks that the repos refuse contexts without an organization. The
+// error is not always wrapped with %w, so only its message is compared.
+func testNoOrganization(t *testing.T, repos Repos) {
+	_, err := repos.Tasks.GetTasks(context.Background(), domain.TaskFilter{})
+	require.ErrorContains(t, err, domain.ErrNoOrganization.Error())
+	_, err = repos.Tasks.CreateTask(context.Background(), newTask(id1, "Nowhere", domain.StatusPending))
+	require.ErrorContains(t, err, domain.ErrNoOrganization.Error())
+	err = repos.Tasks.InTx(context.Background(), func(domain.TasksRepo) error { return nil })
+	require.ErrorContains(t, err, domain.ErrNoOrganization.Error())
+	_, err = repos.Tasks.ImportTasks(context.Background(), taskIterator(newTask(id1, "Nowhere", domain.StatusPending)), false)
+	require.ErrorContains(t, err, domain.ErrNoOrganization.Error())
+	err = repos.Tasks.ExportTasks(context.Background(), domain.TaskFilter{}, func(domain.Task) error { return nil })
+	require.ErrorContains(t, err, domain.ErrNoOrganization.Error())
+	_, err = repos.CalendarFeeds.GetFeedByTokenHash(context.Background(), "hash")
+	require.ErrorContains(t, err, domain.ErrNoOrganization.Error())
+}
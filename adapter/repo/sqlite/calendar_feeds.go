@@ -0,0 +1,117 @@
+package sqlite
+
+import (
+	"api/adapter/repo/sqlite/gen"
+	"api/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/otel"
+)
+
+const traceNameCalendarFeedsRepo = "CalendarFeedsRepo"
+
+// CalendarFeedsRepo filters every query by the organization of ctx, so that a feed
+// token only ever opens the tasks of the organization it was created in.
+type CalendarFeedsRepo struct {
+	querier *gen.Queries
+}
+
+func NewCalendarFeedsRepo(db *sql.DB) *CalendarFeedsRepo {
+	return &CalendarFeedsRepo{querier: gen.New(db)}
+}
+
+func (cr CalendarFeedsRepo) CreateFeed(ctx context.Context, data domain.CalendarFeed, tokenHash string) (domain.CalendarFeed, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameCalendarFeedsRepo).Start(ctx, traceNameCalendarFeedsRepo+".CreateFeed")
+	defer span.End()
+
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return domain.CalendarFeed{}, err
+	}
+
+	feed, err := cr.querier.SaveCalendarFeed(ctx, gen.SaveCalendarFeedParams{
+		ID:        data.ID,
+		OrgID:     orgID,
+		Name:      data.Name,
+		Status:    data.Status,
+		TokenHash: tokenHash,
+		CreatedAt: now(),
+	})
+	if err != nil {
+		return domain.CalendarFeed{}, fmt.Errorf("failed to save calendar feed: %v", err)
+	}
+
+	return feed.ToDomain(), nil
+}
+
+func (cr CalendarFeedsRepo) GetFeedByTokenHash(ctx context.Context, tokenHash string) (domain.CalendarFeed, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameCalendarFeedsRepo).Start(ctx, traceNameCalendarFeedsRepo+".GetFeedByTokenHash")
+	defer span.End()
+
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return domain.CalendarFeed{}, err
+	}
+
+	feed, err := cr.querier.GetCalendarFeedByTokenHash(ctx, gen.GetCalendarFeedByTokenHashParams{
+		TokenHash: tokenHash,
+		OrgID:     orgID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.CalendarFeed{}, domain.ErrCalendarFeedNotFound
+		}
+		return domain.CalendarFeed{}, fmt.Errorf("failed to get calendar feed: %v", err)
+	}
+
+	return feed.ToDomain(), nil
+}
+
+func (cr CalendarFeedsRepo) RotateFeedToken(ctx context.Context, tokenHash, newTokenHash string) (domain.CalendarFeed, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameCalendarFeedsRepo).Start(ctx, traceNameCalendarFeedsRepo+".RotateFeedToken")
+	defer span.End()
+
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return domain.CalendarFeed{}, err
+	}
+
+	feed, err := cr.querier.RotateCalendarFeedToken(ctx, gen.RotateCalendarFeedTokenParams{
+		NewTokenHash: newTokenHash,
+		RotatedAt:    sql.NullTime{Time: now(), Valid: true},
+		TokenHash:    tokenHash,
+		OrgID:        orgID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.CalendarFeed{}, domain.ErrCalendarFeedNotFound
+		}
+		return domain.CalendarFeed{}, fmt.Errorf("failed to rotate calendar feed token: %v", err)
+	}
+
+	return feed.ToDomain(), nil
+}
+
+func (cr CalendarFeedsRepo) DeleteFeed(ctx context.Context, tokenHash string) error {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameCalendarFeedsRepo).Start(ctx, traceNameCalendarFeedsRepo+".DeleteFeed")
+	defer span.End()
+
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	count, err := cr.querier.DeleteCalendarFeed(ctx, gen.DeleteCalendarFeedParams{
+		TokenHash: tokenHash,
+		OrgID:     orgID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete calendar feed: %v", err)
+	}
+	if count == 0 {
+		return domain.ErrCalendarFeedNotFound
+	}
+	return nil
+}
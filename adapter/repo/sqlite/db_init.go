@@ -0,0 +1,67 @@
+package sqlite
+
+import (
+	"api/config"
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	migratesqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"log"
+	_ "modernc.org/sqlite"
+)
+
+// The migrations are part of the binary, so that a single file is all a deployment
+// needs besides the database.
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// connectionParams turn on write-ahead logging, which lets readers run concurrently
+// with the single writer, and make every transaction take the write lock when it
+// begins. A transaction upgrading from a read to a write lock could fail on a
+// concurrent write, while one waiting for the lock up front only waits for the busy
+// timeout. Times are written in a format which sorts as text.
+const connectionParams = "_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)" +
+	"&_txlock=immediate&_time_format=sqlite"
+
+func NewSQLiteClient(ctx context.Context, conf config.Config) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", "file:"+conf.SqlitePath+"?"+connectionParams)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = db.PingContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func RunMigrations(db *sql.DB) error {
+	source, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		return err
+	}
+	driver, err := migratesqlite.WithInstance(db, &migratesqlite.Config{})
+	if err != nil {
+		return err
+	}
+	m, err := migrate.NewWithInstance("iofs", source, "sqlite", driver)
+	if err != nil {
+		return err
+	}
+	err = m.Up()
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to migrate: %v", err)
+	}
+	if errors.Is(err, migrate.ErrNoChange) {
+		log.Println("no new migrations")
+		return nil
+	}
+	log.Println("migrations run successfully")
+	return nil
+}
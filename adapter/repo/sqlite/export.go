@@ -0,0 +1,73 @@
+package sqlite
+
+import (
+	"api/adapter/repo/sqlite/gen"
+	"api/domain"
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+// taskColumns are the columns of gen.Task, in the order scanTask reads them, for the
+// queries sqlc cannot generate.
+const taskColumns = `t.seq, t.id, t.org_id, t.title, t.description, t.status, t.due_date, t.rrule, t.timezone,
+       t.created_at, t.deleted_at`
+
+// sqlc reads every row of a query before returning, so the export query is kept here.
+const exportTasks = `
+SELECT ` + taskColumns + `
+FROM tasks AS t
+WHERE t.org_id = ?
+  AND t.deleted_at IS NULL
+  AND (? IS NULL OR t.status = ?)
+ORDER BY t.created_at, t.id`
+
+// ExportTasks calls fn for every task matching filter, oldest first. The tasks are read
+// row by row from a single query, so only one task is held in memory however many
+// there are, and the export sees a consistent snapshot however long it takes.
+func (tr TasksRepo) ExportTasks(ctx context.Context, filter domain.TaskFilter, fn func(task domain.Task) error) error {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".ExportTasks")
+	defer span.End()
+
+	return tr.read(ctx, func(_ *gen.Queries, orgID uuid.UUID) error {
+		status := sql.NullString{String: filter.Status, Valid: filter.Status != ""}
+		rows, err := tr.conn().QueryContext(ctx, exportTasks, orgID, status, status)
+		if err != nil {
+			return fmt.Errorf("failed to query exported tasks: %v", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			task, err := scanTask(rows)
+			if err != nil {
+				return fmt.Errorf("failed to scan exported task: %v", err)
+			}
+			if err := fn(task.ToDomain()); err != nil {
+				return err
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to read exported tasks: %v", err)
+		}
+		return nil
+	})
+}
+
+// scanTask reads the taskColumns of the current row, followed by extra columns.
+func scanTask(rows *sql.Rows, extra ...any) (gen.Task, error) {
+	var t gen.Task
+	dest := []any{&t.Seq, &t.ID, &t.OrgID, &t.Title, &t.Description, &t.Status, &t.DueDate, &t.Rrule,
+		&t.Timezone, &t.CreatedAt, &t.DeletedAt}
+	err := rows.Scan(append(dest, extra...)...)
+	return t, err
+}
+
+// conn is the transaction the repo is bound to, or the database outside of one.
+func (tr TasksRepo) conn() gen.DBTX {
+	if tr.tx != nil {
+		return tr.tx.Tx
+	}
+	return tr.db
+}
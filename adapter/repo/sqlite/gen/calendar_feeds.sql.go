@@ -0,0 +1,144 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: calendar_feeds.sql
+
+package gen
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const deleteCalendarFeed = `-- name: DeleteCalendarFeed :execrows
+DELETE
+FROM calendar_feeds
+WHERE token_hash = ?1
+  AND org_id = ?2
+`
+
+type DeleteCalendarFeedParams struct {
+	TokenHash string    `json:"token_hash"`
+	OrgID     uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) DeleteCalendarFeed(ctx context.Context, arg DeleteCalendarFeedParams) (int64, error) {
+	result, err := q.exec(ctx, q.deleteCalendarFeedStmt, deleteCalendarFeed, arg.TokenHash, arg.OrgID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getCalendarFeedByTokenHash = `-- name: GetCalendarFeedByTokenHash :one
+SELECT id, org_id, name, status, token_hash, created_at, rotated_at
+FROM calendar_feeds
+WHERE token_hash = ?1
+  AND org_id = ?2
+`
+
+type GetCalendarFeedByTokenHashParams struct {
+	TokenHash string    `json:"token_hash"`
+	OrgID     uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) GetCalendarFeedByTokenHash(ctx context.Context, arg GetCalendarFeedByTokenHashParams) (CalendarFeed, error) {
+	row := q.queryRow(ctx, q.getCalendarFeedByTokenHashStmt, getCalendarFeedByTokenHash, arg.TokenHash, arg.OrgID)
+	var i CalendarFeed
+	err := row.Scan(
+		&i.ID,
+		&i.OrgID,
+		&i.Name,
+		&i.Status,
+		&i.TokenHash,
+		&i.CreatedAt,
+		&i.RotatedAt,
+	)
+	return i, err
+}
+
+const rotateCalendarFeedToken = `-- name: RotateCalendarFeedToken :one
+UPDATE calendar_feeds
+SET token_hash = ?1,
+    rotated_at = ?2
+WHERE token_hash = ?3
+  AND org_id = ?4
+RETURNING id, org_id, name, status, token_hash, created_at, rotated_at
+`
+
+type RotateCalendarFeedTokenParams struct {
+	NewTokenHash string       `json:"new_token_hash"`
+	RotatedAt    sql.NullTime `json:"rotated_at"`
+	TokenHash    string       `json:"token_hash"`
+	OrgID        uuid.UUID    `json:"org_id"`
+}
+
+func (q *Queries) RotateCalendarFeedToken(ctx context.Context, arg RotateCalendarFeedTokenParams) (CalendarFeed, error) {
+	row := q.queryRow(ctx, q.rotateCalendarFeedTokenStmt, rotateCalendarFeedToken,
+		arg.NewTokenHash,
+		arg.RotatedAt,
+		arg.TokenHash,
+		arg.OrgID,
+	)
+	var i CalendarFeed
+	err := row.Scan(
+		&i.ID,
+		&i.OrgID,
+		&i.Name,
+		&i.Status,
+		&i.TokenHash,
+		&i.CreatedAt,
+		&i.RotatedAt,
+	)
+	return i, err
+}
+
+const saveCalendarFeed = `-- name: SaveCalendarFeed :one
+INSERT INTO calendar_feeds (id,
+                            org_id,
+                            name,
+                            status,
+                            token_hash,
+                            created_at)
+VALUES (?1,
+        ?2,
+        ?3,
+        ?4,
+        ?5,
+        ?6)
+RETURNING id, org_id, name, status, token_hash, created_at, rotated_at
+`
+
+type SaveCalendarFeedParams struct {
+	ID        uuid.UUID `json:"id"`
+	OrgID     uuid.UUID `json:"org_id"`
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	TokenHash string    `json:"token_hash"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) SaveCalendarFeed(ctx context.Context, arg SaveCalendarFeedParams) (CalendarFeed, error) {
+	row := q.queryRow(ctx, q.saveCalendarFeedStmt, saveCalendarFeed,
+		arg.ID,
+		arg.OrgID,
+		arg.Name,
+		arg.Status,
+		arg.TokenHash,
+		arg.CreatedAt,
+	)
+	var i CalendarFeed
+	err := row.Scan(
+		&i.ID,
+		&i.OrgID,
+		&i.Name,
+		&i.Status,
+		&i.TokenHash,
+		&i.CreatedAt,
+		&i.RotatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,318 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+
+package gen
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type DBTX interface {
+	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+	PrepareContext(context.Context, string) (*sql.Stmt, error)
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
+}
+
+func New(db DBTX) *Queries {
+	return &Queries{db: db}
+}
+
+func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
+	q := Queries{db: db}
+	var err error
+	if q.countTasksStmt, err = db.PrepareContext(ctx, countTasks); err != nil {
+		return nil, fmt.Errorf("error preparing query CountTasks: %w", err)
+	}
+	if q.deleteCalendarFeedStmt, err = db.PrepareContext(ctx, deleteCalendarFeed); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteCalendarFeed: %w", err)
+	}
+	if q.deleteTaskStmt, err = db.PrepareContext(ctx, deleteTask); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteTask: %w", err)
+	}
+	if q.getCalendarFeedByTokenHashStmt, err = db.PrepareContext(ctx, getCalendarFeedByTokenHash); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCalendarFeedByTokenHash: %w", err)
+	}
+	if q.getDeletedTasksStmt, err = db.PrepareContext(ctx, getDeletedTasks); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDeletedTasks: %w", err)
+	}
+	if q.getOrganizationBySlugStmt, err = db.PrepareContext(ctx, getOrganizationBySlug); err != nil {
+		return nil, fmt.Errorf("error preparing query GetOrganizationBySlug: %w", err)
+	}
+	if q.getOutboxEventsByAggregateIdsStmt, err = db.PrepareContext(ctx, getOutboxEventsByAggregateIds); err != nil {
+		return nil, fmt.Errorf("error preparing query GetOutboxEventsByAggregateIds: %w", err)
+	}
+	if q.getOutboxLagStmt, err = db.PrepareContext(ctx, getOutboxLag); err != nil {
+		return nil, fmt.Errorf("error preparing query GetOutboxLag: %w", err)
+	}
+	if q.getPendingOutboxEventsStmt, err = db.PrepareContext(ctx, getPendingOutboxEvents); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPendingOutboxEvents: %w", err)
+	}
+	if q.getTaskByIdStmt, err = db.PrepareContext(ctx, getTaskById); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTaskById: %w", err)
+	}
+	if q.getTasksStmt, err = db.PrepareContext(ctx, getTasks); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTasks: %w", err)
+	}
+	if q.getTasksByIdsStmt, err = db.PrepareContext(ctx, getTasksByIds); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTasksByIds: %w", err)
+	}
+	if q.getTasksPageStmt, err = db.PrepareContext(ctx, getTasksPage); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTasksPage: %w", err)
+	}
+	if q.importTaskStmt, err = db.PrepareContext(ctx, importTask); err != nil {
+		return nil, fmt.Errorf("error preparing query ImportTask: %w", err)
+	}
+	if q.markOutboxEventsPublishedStmt, err = db.PrepareContext(ctx, markOutboxEventsPublished); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkOutboxEventsPublished: %w", err)
+	}
+	if q.purgeDeletedTasksStmt, err = db.PrepareContext(ctx, purgeDeletedTasks); err != nil {
+		return nil, fmt.Errorf("error preparing query PurgeDeletedTasks: %w", err)
+	}
+	if q.purgeTaskStmt, err = db.PrepareContext(ctx, purgeTask); err != nil {
+		return nil, fmt.Errorf("error preparing query PurgeTask: %w", err)
+	}
+	if q.restoreTaskStmt, err = db.PrepareContext(ctx, restoreTask); err != nil {
+		return nil, fmt.Errorf("error preparing query RestoreTask: %w", err)
+	}
+	if q.rotateCalendarFeedTokenStmt, err = db.PrepareContext(ctx, rotateCalendarFeedToken); err != nil {
+		return nil, fmt.Errorf("error preparing query RotateCalendarFeedToken: %w", err)
+	}
+	if q.saveCalendarFeedStmt, err = db.PrepareContext(ctx, saveCalendarFeed); err != nil {
+		return nil, fmt.Errorf("error preparing query SaveCalendarFeed: %w", err)
+	}
+	if q.saveOrganizationStmt, err = db.PrepareContext(ctx, saveOrganization); err != nil {
+		return nil, fmt.Errorf("error preparing query SaveOrganization: %w", err)
+	}
+	if q.saveOutboxEventStmt, err = db.PrepareContext(ctx, saveOutboxEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query SaveOutboxEvent: %w", err)
+	}
+	if q.saveTaskStmt, err = db.PrepareContext(ctx, saveTask); err != nil {
+		return nil, fmt.Errorf("error preparing query SaveTask: %w", err)
+	}
+	if q.updateTaskStmt, err = db.PrepareContext(ctx, updateTask); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateTask: %w", err)
+	}
+	return &q, nil
+}
+
+func (q *Queries) Close() error {
+	var err error
+	if q.countTasksStmt != nil {
+		if cerr := q.countTasksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countTasksStmt: %w", cerr)
+		}
+	}
+	if q.deleteCalendarFeedStmt != nil {
+		if cerr := q.deleteCalendarFeedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteCalendarFeedStmt: %w", cerr)
+		}
+	}
+	if q.deleteTaskStmt != nil {
+		if cerr := q.deleteTaskStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteTaskStmt: %w", cerr)
+		}
+	}
+	if q.getCalendarFeedByTokenHashStmt != nil {
+		if cerr := q.getCalendarFeedByTokenHashStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCalendarFeedByTokenHashStmt: %w", cerr)
+		}
+	}
+	if q.getDeletedTasksStmt != nil {
+		if cerr := q.getDeletedTasksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDeletedTasksStmt: %w", cerr)
+		}
+	}
+	if q.getOrganizationBySlugStmt != nil {
+		if cerr := q.getOrganizationBySlugStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getOrganizationBySlugStmt: %w", cerr)
+		}
+	}
+	if q.getOutboxEventsByAggregateIdsStmt != nil {
+		if cerr := q.getOutboxEventsByAggregateIdsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getOutboxEventsByAggregateIdsStmt: %w", cerr)
+		}
+	}
+	if q.getOutboxLagStmt != nil {
+		if cerr := q.getOutboxLagStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getOutboxLagStmt: %w", cerr)
+		}
+	}
+	if q.getPendingOutboxEventsStmt != nil {
+		if cerr := q.getPendingOutboxEventsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPendingOutboxEventsStmt: %w", cerr)
+		}
+	}
+	if q.getTaskByIdStmt != nil {
+		if cerr := q.getTaskByIdStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTaskByIdStmt: %w", cerr)
+		}
+	}
+	if q.getTasksStmt != nil {
+		if cerr := q.getTasksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTasksStmt: %w", cerr)
+		}
+	}
+	if q.getTasksByIdsStmt != nil {
+		if cerr := q.getTasksByIdsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTasksByIdsStmt: %w", cerr)
+		}
+	}
+	if q.getTasksPageStmt != nil {
+		if cerr := q.getTasksPageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTasksPageStmt: %w", cerr)
+		}
+	}
+	if q.importTaskStmt != nil {
+		if cerr := q.importTaskStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing importTaskStmt: %w", cerr)
+		}
+	}
+	if q.markOutboxEventsPublishedStmt != nil {
+		if cerr := q.markOutboxEventsPublishedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markOutboxEventsPublishedStmt: %w", cerr)
+		}
+	}
+	if q.purgeDeletedTasksStmt != nil {
+		if cerr := q.purgeDeletedTasksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing purgeDeletedTasksStmt: %w", cerr)
+		}
+	}
+	if q.purgeTaskStmt != nil {
+		if cerr := q.purgeTaskStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing purgeTaskStmt: %w", cerr)
+		}
+	}
+	if q.restoreTaskStmt != nil {
+		if cerr := q.restoreTaskStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing restoreTaskStmt: %w", cerr)
+		}
+	}
+	if q.rotateCalendarFeedTokenStmt != nil {
+		if cerr := q.rotateCalendarFeedTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing rotateCalendarFeedTokenStmt: %w", cerr)
+		}
+	}
+	if q.saveCalendarFeedStmt != nil {
+		if cerr := q.saveCalendarFeedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing saveCalendarFeedStmt: %w", cerr)
+		}
+	}
+	if q.saveOrganizationStmt != nil {
+		if cerr := q.saveOrganizationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing saveOrganizationStmt: %w", cerr)
+		}
+	}
+	if q.saveOutboxEventStmt != nil {
+		if cerr := q.saveOutboxEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing saveOutboxEventStmt: %w", cerr)
+		}
+	}
+	if q.saveTaskStmt != nil {
+		if cerr := q.saveTaskStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing saveTaskStmt: %w", cerr)
+		}
+	}
+	if q.updateTaskStmt != nil {
+		if cerr := q.updateTaskStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateTaskStmt: %w", cerr)
+		}
+	}
+	return err
+}
+
+func (q *Queries) exec(ctx context.Context, stmt *sql.Stmt, query string, args ...interface{}) (sql.Result, error) {
+	switch {
+	case stmt != nil && q.tx != nil:
+		return q.tx.StmtContext(ctx, stmt).ExecContext(ctx, args...)
+	case stmt != nil:
+		return stmt.ExecContext(ctx, args...)
+	default:
+		return q.db.ExecContext(ctx, query, args...)
+	}
+}
+
+func (q *Queries) query(ctx context.Context, stmt *sql.Stmt, query string, args ...interface{}) (*sql.Rows, error) {
+	switch {
+	case stmt != nil && q.tx != nil:
+		return q.tx.StmtContext(ctx, stmt).QueryContext(ctx, args...)
+	case stmt != nil:
+		return stmt.QueryContext(ctx, args...)
+	default:
+		return q.db.QueryContext(ctx, query, args...)
+	}
+}
+
+func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, args ...interface{}) *sql.Row {
+	switch {
+	case stmt != nil && q.tx != nil:
+		return q.tx.StmtContext(ctx, stmt).QueryRowContext(ctx, args...)
+	case stmt != nil:
+		return stmt.QueryRowContext(ctx, args...)
+	default:
+		return q.db.QueryRowContext(ctx, query, args...)
+	}
+}
+
+type Queries struct {
+	db                                DBTX
+	tx                                *sql.Tx
+	countTasksStmt                    *sql.Stmt
+	deleteCalendarFeedStmt            *sql.Stmt
+	deleteTaskStmt                    *sql.Stmt
+	getCalendarFeedByTokenHashStmt    *sql.Stmt
+	getDeletedTasksStmt               *sql.Stmt
+	getOrganizationBySlugStmt         *sql.Stmt
+	getOutboxEventsByAggregateIdsStmt *sql.Stmt
+	getOutboxLagStmt                  *sql.Stmt
+	getPendingOutboxEventsStmt        *sql.Stmt
+	getTaskByIdStmt                   *sql.Stmt
+	getTasksStmt                      *sql.Stmt
+	getTasksByIdsStmt                 *sql.Stmt
+	getTasksPageStmt                  *sql.Stmt
+	importTaskStmt                    *sql.Stmt
+	markOutboxEventsPublishedStmt     *sql.Stmt
+	purgeDeletedTasksStmt             *sql.Stmt
+	purgeTaskStmt                     *sql.Stmt
+	restoreTaskStmt                   *sql.Stmt
+	rotateCalendarFeedTokenStmt       *sql.Stmt
+	saveCalendarFeedStmt              *sql.Stmt
+	saveOrganizationStmt              *sql.Stmt
+	saveOutboxEventStmt               *sql.Stmt
+	saveTaskStmt                      *sql.Stmt
+	updateTaskStmt                    *sql.Stmt
+}
+
+func (q *Queries) WithTx(tx *sql.Tx) *Queries {
+	return &Queries{
+		db:                                tx,
+		tx:                                tx,
+		countTasksStmt:                    q.countTasksStmt,
+		deleteCalendarFeedStmt:            q.deleteCalendarFeedStmt,
+		deleteTaskStmt:                    q.deleteTaskStmt,
+		getCalendarFeedByTokenHashStmt:    q.getCalendarFeedByTokenHashStmt,
+		getDeletedTasksStmt:               q.getDeletedTasksStmt,
+		getOrganizationBySlugStmt:         q.getOrganizationBySlugStmt,
+		getOutboxEventsByAggregateIdsStmt: q.getOutboxEventsByAggregateIdsStmt,
+		getOutboxLagStmt:                  q.getOutboxLagStmt,
+		getPendingOutboxEventsStmt:        q.getPendingOutboxEventsStmt,
+		getTaskByIdStmt:                   q.getTaskByIdStmt,
+		getTasksStmt:                      q.getTasksStmt,
+		getTasksByIdsStmt:                 q.getTasksByIdsStmt,
+		getTasksPageStmt:                  q.getTasksPageStmt,
+		importTaskStmt:                    q.importTaskStmt,
+		markOutboxEventsPublishedStmt:     q.markOutboxEventsPublishedStmt,
+		purgeDeletedTasksStmt:             q.purgeDeletedTasksStmt,
+		purgeTaskStmt:                     q.purgeTaskStmt,
+		restoreTaskStmt:                   q.restoreTaskStmt,
+		rotateCalendarFeedTokenStmt:       q.rotateCalendarFeedTokenStmt,
+		saveCalendarFeedStmt:              q.saveCalendarFeedStmt,
+		saveOrganizationStmt:              q.saveOrganizationStmt,
+		saveOutboxEventStmt:               q.saveOutboxEventStmt,
+		saveTaskStmt:                      q.saveTaskStmt,
+		updateTaskStmt:                    q.updateTaskStmt,
+	}
+}
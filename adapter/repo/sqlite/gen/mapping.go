@@ -0,0 +1,56 @@
+// MAPPING FILE IS NOT GENERATED BY SQLC
+package gen
+
+import (
+	"api/domain"
+	"encoding/json"
+)
+
+func (t Task) ToDomain() domain.Task {
+	task := domain.Task{
+		ID:          t.ID,
+		Title:       t.Title,
+		Description: t.Description,
+		Status:      t.Status,
+		DueDate:     t.DueDate,
+		RRule:       t.Rrule,
+		Timezone:    t.Timezone,
+		CreatedAt:   t.CreatedAt,
+	}
+	if t.DeletedAt.Valid {
+		task.DeletedAt = &t.DeletedAt.Time
+	}
+	return task
+}
+
+func (o Outbox) ToDomain() domain.Event {
+	return domain.Event{
+		ID:          o.EventID,
+		Type:        domain.EventType(o.EventType),
+		AggregateID: o.AggregateID,
+		Payload:     json.RawMessage(o.Payload),
+		OccurredAt:  o.CreatedAt,
+	}
+}
+
+func (f CalendarFeed) ToDomain() domain.CalendarFeed {
+	feed := domain.CalendarFeed{
+		ID:        f.ID,
+		Name:      f.Name,
+		Status:    f.Status,
+		CreatedAt: f.CreatedAt,
+	}
+	if f.RotatedAt.Valid {
+		feed.RotatedAt = &f.RotatedAt.Time
+	}
+	return feed
+}
+
+func (o Organization) ToDomain() domain.Organization {
+	return domain.Organization{
+		ID:        o.ID,
+		Slug:      o.Slug,
+		Name:      o.Name,
+		CreatedAt: o.CreatedAt,
+	}
+}
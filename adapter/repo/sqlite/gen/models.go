@@ -0,0 +1,59 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+
+package gen
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CalendarFeed struct {
+	ID        uuid.UUID    `json:"id"`
+	OrgID     uuid.UUID    `json:"org_id"`
+	Name      string       `json:"name"`
+	Status    string       `json:"status"`
+	TokenHash string       `json:"token_hash"`
+	CreatedAt time.Time    `json:"created_at"`
+	RotatedAt sql.NullTime `json:"rotated_at"`
+}
+
+type Organization struct {
+	ID        uuid.UUID `json:"id"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Outbox struct {
+	ID          int64        `json:"id"`
+	EventID     uuid.UUID    `json:"event_id"`
+	EventType   string       `json:"event_type"`
+	AggregateID uuid.UUID    `json:"aggregate_id"`
+	OrgID       uuid.UUID    `json:"org_id"`
+	Payload     string       `json:"payload"`
+	CreatedAt   time.Time    `json:"created_at"`
+	PublishedAt sql.NullTime `json:"published_at"`
+}
+
+type Task struct {
+	Seq         int64        `json:"seq"`
+	ID          uuid.UUID    `json:"id"`
+	OrgID       uuid.UUID    `json:"org_id"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Status      string       `json:"status"`
+	DueDate     time.Time    `json:"due_date"`
+	Rrule       string       `json:"rrule"`
+	Timezone    string       `json:"timezone"`
+	CreatedAt   time.Time    `json:"created_at"`
+	DeletedAt   sql.NullTime `json:"deleted_at"`
+}
+
+type TasksSearch struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,193 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: outbox.sql
+
+package gen
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const getOutboxEventsByAggregateIds = `-- name: GetOutboxEventsByAggregateIds :many
+SELECT id, event_id, event_type, aggregate_id, org_id, payload, created_at, published_at
+FROM outbox
+WHERE org_id = ?1
+  AND aggregate_id IN (/*SLICE:aggregate_ids*/?)
+ORDER BY id
+`
+
+type GetOutboxEventsByAggregateIdsParams struct {
+	OrgID        uuid.UUID   `json:"org_id"`
+	AggregateIds []uuid.UUID `json:"aggregate_ids"`
+}
+
+func (q *Queries) GetOutboxEventsByAggregateIds(ctx context.Context, arg GetOutboxEventsByAggregateIdsParams) ([]Outbox, error) {
+	query := getOutboxEventsByAggregateIds
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.OrgID)
+	if len(arg.AggregateIds) > 0 {
+		for _, v := range arg.AggregateIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:aggregate_ids*/?", strings.Repeat(",?", len(arg.AggregateIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:aggregate_ids*/?", "NULL", 1)
+	}
+	rows, err := q.query(ctx, nil, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Outbox{}
+	for rows.Next() {
+		var i Outbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.EventType,
+			&i.AggregateID,
+			&i.OrgID,
+			&i.Payload,
+			&i.CreatedAt,
+			&i.PublishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOutboxLag = `-- name: GetOutboxLag :one
+SELECT COUNT(*)                                                                       AS pending,
+       CAST(COALESCE((julianday('now') - julianday(MIN(created_at))) * 86400, 0) AS REAL) AS lag_seconds
+FROM outbox
+WHERE published_at IS NULL
+`
+
+type GetOutboxLagRow struct {
+	Pending    int64   `json:"pending"`
+	LagSeconds float64 `json:"lag_seconds"`
+}
+
+func (q *Queries) GetOutboxLag(ctx context.Context) (GetOutboxLagRow, error) {
+	row := q.queryRow(ctx, q.getOutboxLagStmt, getOutboxLag)
+	var i GetOutboxLagRow
+	err := row.Scan(&i.Pending, &i.LagSeconds)
+	return i, err
+}
+
+const getPendingOutboxEvents = `-- name: GetPendingOutboxEvents :many
+SELECT id, event_id, event_type, aggregate_id, org_id, payload, created_at, published_at
+FROM outbox
+WHERE published_at IS NULL
+ORDER BY id
+LIMIT ?1
+`
+
+func (q *Queries) GetPendingOutboxEvents(ctx context.Context, batchSize int64) ([]Outbox, error) {
+	rows, err := q.query(ctx, q.getPendingOutboxEventsStmt, getPendingOutboxEvents, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Outbox{}
+	for rows.Next() {
+		var i Outbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.EventType,
+			&i.AggregateID,
+			&i.OrgID,
+			&i.Payload,
+			&i.CreatedAt,
+			&i.PublishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markOutboxEventsPublished = `-- name: MarkOutboxEventsPublished :exec
+UPDATE outbox
+SET published_at = ?1
+WHERE id IN (/*SLICE:ids*/?)
+`
+
+type MarkOutboxEventsPublishedParams struct {
+	PublishedAt sql.NullTime `json:"published_at"`
+	Ids         []int64      `json:"ids"`
+}
+
+func (q *Queries) MarkOutboxEventsPublished(ctx context.Context, arg MarkOutboxEventsPublishedParams) error {
+	query := markOutboxEventsPublished
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.PublishedAt)
+	if len(arg.Ids) > 0 {
+		for _, v := range arg.Ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(arg.Ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	_, err := q.exec(ctx, nil, query, queryParams...)
+	return err
+}
+
+const saveOutboxEvent = `-- name: SaveOutboxEvent :exec
+INSERT INTO outbox (event_id,
+                    event_type,
+                    aggregate_id,
+                    org_id,
+                    payload,
+                    created_at)
+VALUES (?1,
+        ?2,
+        ?3,
+        ?4,
+        ?5,
+        ?6)
+`
+
+type SaveOutboxEventParams struct {
+	EventID     uuid.UUID `json:"event_id"`
+	EventType   string    `json:"event_type"`
+	AggregateID uuid.UUID `json:"aggregate_id"`
+	OrgID       uuid.UUID `json:"org_id"`
+	Payload     string    `json:"payload"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (q *Queries) SaveOutboxEvent(ctx context.Context, arg SaveOutboxEventParams) error {
+	_, err := q.exec(ctx, q.saveOutboxEventStmt, saveOutboxEvent,
+		arg.EventID,
+		arg.EventType,
+		arg.AggregateID,
+		arg.OrgID,
+		arg.Payload,
+		arg.CreatedAt,
+	)
+	return err
+}
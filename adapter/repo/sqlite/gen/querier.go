@@ -0,0 +1,43 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+
+package gen
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Querier interface {
+	CountTasks(ctx context.Context, arg CountTasksParams) (int64, error)
+	DeleteCalendarFeed(ctx context.Context, arg DeleteCalendarFeedParams) (int64, error)
+	DeleteTask(ctx context.Context, arg DeleteTaskParams) (Task, error)
+	GetCalendarFeedByTokenHash(ctx context.Context, arg GetCalendarFeedByTokenHashParams) (CalendarFeed, error)
+	GetDeletedTasks(ctx context.Context, orgID uuid.UUID) ([]Task, error)
+	GetOrganizationBySlug(ctx context.Context, slug string) (Organization, error)
+	GetOutboxEventsByAggregateIds(ctx context.Context, arg GetOutboxEventsByAggregateIdsParams) ([]Outbox, error)
+	GetOutboxLag(ctx context.Context) (GetOutboxLagRow, error)
+	GetPendingOutboxEvents(ctx context.Context, batchSize int64) ([]Outbox, error)
+	GetTaskById(ctx context.Context, arg GetTaskByIdParams) (Task, error)
+	GetTasks(ctx context.Context, arg GetTasksParams) ([]Task, error)
+	// sqlc numbers the parameters following a slice wrongly, so slices always come last.
+	GetTasksByIds(ctx context.Context, arg GetTasksByIdsParams) ([]Task, error)
+	GetTasksPage(ctx context.Context, arg GetTasksPageParams) ([]Task, error)
+	// Tasks whose id already exists, including an earlier row of the same import, are left
+	// untouched.
+	ImportTask(ctx context.Context, arg ImportTaskParams) (int64, error)
+	MarkOutboxEventsPublished(ctx context.Context, arg MarkOutboxEventsPublishedParams) error
+	PurgeDeletedTasks(ctx context.Context, arg PurgeDeletedTasksParams) (int64, error)
+	PurgeTask(ctx context.Context, arg PurgeTaskParams) (int64, error)
+	RestoreTask(ctx context.Context, arg RestoreTaskParams) (Task, error)
+	RotateCalendarFeedToken(ctx context.Context, arg RotateCalendarFeedTokenParams) (CalendarFeed, error)
+	SaveCalendarFeed(ctx context.Context, arg SaveCalendarFeedParams) (CalendarFeed, error)
+	SaveOrganization(ctx context.Context, arg SaveOrganizationParams) (Organization, error)
+	SaveOutboxEvent(ctx context.Context, arg SaveOutboxEventParams) error
+	SaveTask(ctx context.Context, arg SaveTaskParams) (Task, error)
+	UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, error)
+}
+
+var _ Querier = (*Queries)(nil)
@@ -0,0 +1,562 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: tasks.sql
+
+package gen
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const countTasks = `-- name: CountTasks :one
+SELECT COUNT(*)
+FROM tasks
+WHERE org_id = ?1
+  AND deleted_at IS NULL
+  AND (CAST(?2 AS TEXT) IS NULL OR status = ?2)
+`
+
+type CountTasksParams struct {
+	OrgID  uuid.UUID      `json:"org_id"`
+	Status sql.NullString `json:"status"`
+}
+
+func (q *Queries) CountTasks(ctx context.Context, arg CountTasksParams) (int64, error) {
+	row := q.queryRow(ctx, q.countTasksStmt, countTasks, arg.OrgID, arg.Status)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteTask = `-- name: DeleteTask :one
+UPDATE tasks
+SET deleted_at = ?1
+WHERE id = ?2
+  AND org_id = ?3
+  AND deleted_at IS NULL
+RETURNING seq, id, org_id, title, description, status, due_date, rrule, timezone, created_at, deleted_at
+`
+
+type DeleteTaskParams struct {
+	DeletedAt sql.NullTime `json:"deleted_at"`
+	ID        uuid.UUID    `json:"id"`
+	OrgID     uuid.UUID    `json:"org_id"`
+}
+
+func (q *Queries) DeleteTask(ctx context.Context, arg DeleteTaskParams) (Task, error) {
+	row := q.queryRow(ctx, q.deleteTaskStmt, deleteTask, arg.DeletedAt, arg.ID, arg.OrgID)
+	var i Task
+	err := row.Scan(
+		&i.Seq,
+		&i.ID,
+		&i.OrgID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.DueDate,
+		&i.Rrule,
+		&i.Timezone,
+		&i.CreatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getDeletedTasks = `-- name: GetDeletedTasks :many
+SELECT seq, id, org_id, title, description, status, due_date, rrule, timezone, created_at, deleted_at
+FROM tasks
+WHERE org_id = ?1
+  AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
+`
+
+func (q *Queries) GetDeletedTasks(ctx context.Context, orgID uuid.UUID) ([]Task, error) {
+	rows, err := q.query(ctx, q.getDeletedTasksStmt, getDeletedTasks, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.Seq,
+			&i.ID,
+			&i.OrgID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.DueDate,
+			&i.Rrule,
+			&i.Timezone,
+			&i.CreatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTaskById = `-- name: GetTaskById :one
+SELECT seq, id, org_id, title, description, status, due_date, rrule, timezone, created_at, deleted_at
+FROM tasks
+WHERE id = ?1
+  AND org_id = ?2
+  AND deleted_at IS NULL
+`
+
+type GetTaskByIdParams struct {
+	ID    uuid.UUID `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) GetTaskById(ctx context.Context, arg GetTaskByIdParams) (Task, error) {
+	row := q.queryRow(ctx, q.getTaskByIdStmt, getTaskById, arg.ID, arg.OrgID)
+	var i Task
+	err := row.Scan(
+		&i.Seq,
+		&i.ID,
+		&i.OrgID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.DueDate,
+		&i.Rrule,
+		&i.Timezone,
+		&i.CreatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getTasks = `-- name: GetTasks :many
+SELECT seq, id, org_id, title, description, status, due_date, rrule, timezone, created_at, deleted_at
+FROM tasks
+WHERE org_id = ?1
+  AND deleted_at IS NULL
+  AND (CAST(?2 AS TEXT) IS NULL OR status = ?2)
+ORDER BY seq
+`
+
+type GetTasksParams struct {
+	OrgID  uuid.UUID      `json:"org_id"`
+	Status sql.NullString `json:"status"`
+}
+
+func (q *Queries) GetTasks(ctx context.Context, arg GetTasksParams) ([]Task, error) {
+	rows, err := q.query(ctx, q.getTasksStmt, getTasks, arg.OrgID, arg.Status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.Seq,
+			&i.ID,
+			&i.OrgID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.DueDate,
+			&i.Rrule,
+			&i.Timezone,
+			&i.CreatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTasksByIds = `-- name: GetTasksByIds :many
+SELECT seq, id, org_id, title, description, status, due_date, rrule, timezone, created_at, deleted_at
+FROM tasks
+WHERE org_id = ?1
+  AND deleted_at IS NULL
+  AND id IN (/*SLICE:ids*/?)
+`
+
+type GetTasksByIdsParams struct {
+	OrgID uuid.UUID   `json:"org_id"`
+	Ids   []uuid.UUID `json:"ids"`
+}
+
+// sqlc numbers the parameters following a slice wrongly, so slices always come last.
+func (q *Queries) GetTasksByIds(ctx context.Context, arg GetTasksByIdsParams) ([]Task, error) {
+	query := getTasksByIds
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.OrgID)
+	if len(arg.Ids) > 0 {
+		for _, v := range arg.Ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(arg.Ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.query(ctx, nil, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.Seq,
+			&i.ID,
+			&i.OrgID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.DueDate,
+			&i.Rrule,
+			&i.Timezone,
+			&i.CreatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTasksPage = `-- name: GetTasksPage :many
+SELECT seq, id, org_id, title, description, status, due_date, rrule, timezone, created_at, deleted_at
+FROM tasks
+WHERE org_id = ?1
+  AND deleted_at IS NULL
+  AND (CAST(?2 AS TEXT) IS NULL OR status = ?2)
+  AND (CAST(?3 AS TIMESTAMP) IS NULL
+    OR created_at > ?3
+    OR (created_at = ?3 AND id > ?4))
+ORDER BY created_at, id
+LIMIT ?5
+`
+
+type GetTasksPageParams struct {
+	OrgID          uuid.UUID      `json:"org_id"`
+	Status         sql.NullString `json:"status"`
+	AfterCreatedAt sql.NullTime   `json:"after_created_at"`
+	AfterID        interface{}    `json:"after_id"`
+	MaxResults     int64          `json:"max_results"`
+}
+
+func (q *Queries) GetTasksPage(ctx context.Context, arg GetTasksPageParams) ([]Task, error) {
+	rows, err := q.query(ctx, q.getTasksPageStmt, getTasksPage,
+		arg.OrgID,
+		arg.Status,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.Seq,
+			&i.ID,
+			&i.OrgID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.DueDate,
+			&i.Rrule,
+			&i.Timezone,
+			&i.CreatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const importTask = `-- name: ImportTask :execrows
+INSERT INTO tasks (id,
+                   org_id,
+                   title,
+                   description,
+                   status,
+                   due_date,
+                   rrule,
+                   timezone,
+                   created_at)
+VALUES (?1,
+        ?2,
+        ?3,
+        ?4,
+        ?5,
+        ?6,
+        ?7,
+        ?8,
+        ?9)
+ON CONFLICT (id) DO NOTHING
+`
+
+type ImportTaskParams struct {
+	ID          uuid.UUID `json:"id"`
+	OrgID       uuid.UUID `json:"org_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Status      string    `json:"status"`
+	DueDate     time.Time `json:"due_date"`
+	Rrule       string    `json:"rrule"`
+	Timezone    string    `json:"timezone"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Tasks whose id already exists, including an earlier row of the same import, are left
+// untouched.
+func (q *Queries) ImportTask(ctx context.Context, arg ImportTaskParams) (int64, error) {
+	result, err := q.exec(ctx, q.importTaskStmt, importTask,
+		arg.ID,
+		arg.OrgID,
+		arg.Title,
+		arg.Description,
+		arg.Status,
+		arg.DueDate,
+		arg.Rrule,
+		arg.Timezone,
+		arg.CreatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const purgeDeletedTasks = `-- name: PurgeDeletedTasks :execrows
+DELETE
+FROM tasks
+WHERE seq IN (SELECT t.seq
+              FROM tasks AS t
+              WHERE t.deleted_at < ?1
+              ORDER BY t.deleted_at
+              LIMIT ?2)
+`
+
+type PurgeDeletedTasksParams struct {
+	DeletedBefore sql.NullTime `json:"deleted_before"`
+	BatchSize     int64        `json:"batch_size"`
+}
+
+func (q *Queries) PurgeDeletedTasks(ctx context.Context, arg PurgeDeletedTasksParams) (int64, error) {
+	result, err := q.exec(ctx, q.purgeDeletedTasksStmt, purgeDeletedTasks, arg.DeletedBefore, arg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const purgeTask = `-- name: PurgeTask :execrows
+DELETE
+FROM tasks
+WHERE id = ?1
+  AND org_id = ?2
+  AND deleted_at IS NOT NULL
+`
+
+type PurgeTaskParams struct {
+	ID    uuid.UUID `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) PurgeTask(ctx context.Context, arg PurgeTaskParams) (int64, error) {
+	result, err := q.exec(ctx, q.purgeTaskStmt, purgeTask, arg.ID, arg.OrgID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreTask = `-- name: RestoreTask :one
+UPDATE tasks
+SET deleted_at = NULL
+WHERE id = ?1
+  AND org_id = ?2
+  AND deleted_at IS NOT NULL
+RETURNING seq, id, org_id, title, description, status, due_date, rrule, timezone, created_at, deleted_at
+`
+
+type RestoreTaskParams struct {
+	ID    uuid.UUID `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) RestoreTask(ctx context.Context, arg RestoreTaskParams) (Task, error) {
+	row := q.queryRow(ctx, q.restoreTaskStmt, restoreTask, arg.ID, arg.OrgID)
+	var i Task
+	err := row.Scan(
+		&i.Seq,
+		&i.ID,
+		&i.OrgID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.DueDate,
+		&i.Rrule,
+		&i.Timezone,
+		&i.CreatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const saveTask = `-- name: SaveTask :one
+INSERT INTO tasks (id,
+                   org_id,
+                   title,
+                   description,
+                   status,
+                   due_date,
+                   rrule,
+                   timezone,
+                   created_at)
+VALUES (?1,
+        ?2,
+        ?3,
+        ?4,
+        ?5,
+        ?6,
+        ?7,
+        ?8,
+        ?9)
+RETURNING seq, id, org_id, title, description, status, due_date, rrule, timezone, created_at, deleted_at
+`
+
+type SaveTaskParams struct {
+	ID          uuid.UUID `json:"id"`
+	OrgID       uuid.UUID `json:"org_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Status      string    `json:"status"`
+	DueDate     time.Time `json:"due_date"`
+	Rrule       string    `json:"rrule"`
+	Timezone    string    `json:"timezone"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (q *Queries) SaveTask(ctx context.Context, arg SaveTaskParams) (Task, error) {
+	row := q.queryRow(ctx, q.saveTaskStmt, saveTask,
+		arg.ID,
+		arg.OrgID,
+		arg.Title,
+		arg.Description,
+		arg.Status,
+		arg.DueDate,
+		arg.Rrule,
+		arg.Timezone,
+		arg.CreatedAt,
+	)
+	var i Task
+	err := row.Scan(
+		&i.Seq,
+		&i.ID,
+		&i.OrgID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.DueDate,
+		&i.Rrule,
+		&i.Timezone,
+		&i.CreatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const updateTask = `-- name: UpdateTask :one
+UPDATE tasks
+SET title       = ?1,
+    description = ?2,
+    status      = ?3,
+    due_date    = ?4,
+    rrule       = ?5,
+    timezone    = ?6
+WHERE id = ?7
+  AND org_id = ?8
+  AND deleted_at IS NULL
+RETURNING seq, id, org_id, title, description, status, due_date, rrule, timezone, created_at, deleted_at
+`
+
+type UpdateTaskParams struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Status      string    `json:"status"`
+	DueDate     time.Time `json:"due_date"`
+	Rrule       string    `json:"rrule"`
+	Timezone    string    `json:"timezone"`
+	ID          uuid.UUID `json:"id"`
+	OrgID       uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, error) {
+	row := q.queryRow(ctx, q.updateTaskStmt, updateTask,
+		arg.Title,
+		arg.Description,
+		arg.Status,
+		arg.DueDate,
+		arg.Rrule,
+		arg.Timezone,
+		arg.ID,
+		arg.OrgID,
+	)
+	var i Task
+	err := row.Scan(
+		&i.Seq,
+		&i.ID,
+		&i.OrgID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.DueDate,
+		&i.Rrule,
+		&i.Timezone,
+		&i.CreatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"api/adapter/repo/sqlite/gen"
+	"api/domain"
+	"context"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"io"
+)
+
+// ImportTasks inserts the tasks returned by next one by one. Only the first task of every
+// id is imported and ids which already exist are left untouched. A TaskCreated event is
+// written for every inserted task, in input order. It always runs in its own
+// transaction, which a dry run rolls back once the tasks have been counted.
+func (tr TasksRepo) ImportTasks(ctx context.Context, next func() (domain.Task, error), dryRun bool) (int, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".ImportTasks")
+	span.SetAttributes(attribute.Bool("dry_run", dryRun))
+	defer span.End()
+
+	tx, err := beginTenantTx(ctx, tr.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	q := tr.querier.WithTx(tx.Tx)
+	createdAt := now()
+	imported := 0
+	for {
+		task, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		count, err := q.ImportTask(ctx, gen.ImportTaskParams{
+			ID:          task.ID,
+			OrgID:       tx.orgID,
+			Title:       task.Title,
+			Description: task.Description,
+			Status:      task.Status,
+			DueDate:     task.DueDate,
+			Rrule:       task.RRule,
+			Timezone:    task.Timezone,
+			CreatedAt:   createdAt,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert task %s: %v", task.ID, err)
+		}
+		if count == 0 {
+			continue
+		}
+
+		task.CreatedAt = createdAt
+		if err := tx.saveEvent(ctx, q, domain.EventTaskCreated, task); err != nil {
+			return 0, err
+		}
+		imported++
+	}
+	span.SetAttributes(attribute.Int("imported", imported))
+
+	if dryRun {
+		return imported, nil
+	}
+	if err := tr.notifier.commit(tx); err != nil {
+		return 0, err
+	}
+	return imported, nil
+}
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS organizations
+(
+    id         UUID      NOT NULL,
+    slug       TEXT      NOT NULL,
+    name       TEXT      NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    CONSTRAINT PK_ORGANIZATIONS PRIMARY KEY (id),
+    CONSTRAINT UQ_ORGANIZATIONS_SLUG UNIQUE (slug)
+);
+
+-- The default organization is the only one of single-tenant deployments. Timestamps are
+-- written in the format the driver writes time.Time values in, so that they sort as text.
+INSERT INTO organizations (id, slug, name, created_at)
+VALUES ('00000000-0000-0000-0000-000000000001', 'default', 'Default', strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+ON CONFLICT DO NOTHING;
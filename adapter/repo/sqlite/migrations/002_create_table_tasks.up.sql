@@ -0,0 +1,57 @@
+-- seq is the rowid of the table, which orders tasks by insertion and keys the full-text
+-- index. SQLite has no row-level security, so every query filters by org_id itself.
+CREATE TABLE IF NOT EXISTS tasks
+(
+    seq         INTEGER   NOT NULL PRIMARY KEY AUTOINCREMENT,
+    id          UUID      NOT NULL,
+    org_id      UUID      NOT NULL,
+    title       TEXT      NOT NULL,
+    description TEXT      NOT NULL,
+    status      TEXT      NOT NULL,
+    due_date    TIMESTAMP NOT NULL,
+    rrule       TEXT      NOT NULL DEFAULT '',
+    timezone    TEXT      NOT NULL DEFAULT '',
+    created_at  TIMESTAMP NOT NULL,
+    deleted_at  TIMESTAMP,
+
+    CONSTRAINT UQ_TASKS_ID UNIQUE (id),
+    CONSTRAINT FK_TASKS_ORG_ID FOREIGN KEY (org_id) REFERENCES organizations (id)
+);
+
+CREATE INDEX IF NOT EXISTS IDX_TASKS_ORG_ID ON tasks (org_id, created_at);
+CREATE INDEX IF NOT EXISTS IDX_TASKS_DELETED_AT ON tasks (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- The full-text index stems English words with the Porter stemmer and is kept in sync
+-- with the tasks by the triggers below.
+CREATE VIRTUAL TABLE IF NOT EXISTS tasks_search USING fts5
+(
+    title,
+    description,
+    content = 'tasks',
+    content_rowid = 'seq',
+    tokenize = 'porter unicode61'
+);
+
+CREATE TRIGGER IF NOT EXISTS tasks_search_insert
+    AFTER INSERT
+    ON tasks
+BEGIN
+    INSERT INTO tasks_search (rowid, title, description) VALUES (new.seq, new.title, new.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS tasks_search_delete
+    AFTER DELETE
+    ON tasks
+BEGIN
+    INSERT INTO tasks_search (tasks_search, rowid, title, description)
+    VALUES ('delete', old.seq, old.title, old.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS tasks_search_update
+    AFTER UPDATE OF title, description
+    ON tasks
+BEGIN
+    INSERT INTO tasks_search (tasks_search, rowid, title, description)
+    VALUES ('delete', old.seq, old.title, old.description);
+    INSERT INTO tasks_search (rowid, title, description) VALUES (new.seq, new.title, new.description);
+END;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS outbox
+(
+    id           INTEGER   NOT NULL PRIMARY KEY AUTOINCREMENT,
+    event_id     UUID      NOT NULL,
+    event_type   TEXT      NOT NULL,
+    aggregate_id UUID      NOT NULL,
+    org_id       UUID      NOT NULL,
+    payload      TEXT      NOT NULL,
+    created_at   TIMESTAMP NOT NULL,
+    published_at TIMESTAMP,
+
+    CONSTRAINT UQ_OUTBOX_EVENT_ID UNIQUE (event_id),
+    CONSTRAINT FK_OUTBOX_ORG_ID FOREIGN KEY (org_id) REFERENCES organizations (id)
+);
+
+CREATE INDEX IF NOT EXISTS IDX_OUTBOX_PENDING ON outbox (id) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS IDX_OUTBOX_AGGREGATE_ID ON outbox (org_id, aggregate_id, id);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS calendar_feeds
+(
+    id         UUID      NOT NULL,
+    org_id     UUID      NOT NULL,
+    name       TEXT      NOT NULL,
+    status     TEXT      NOT NULL DEFAULT '',
+    token_hash TEXT      NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    rotated_at TIMESTAMP,
+
+    CONSTRAINT PK_CALENDAR_FEEDS PRIMARY KEY (id),
+    CONSTRAINT UQ_CALENDAR_FEEDS_TOKEN_HASH UNIQUE (token_hash),
+    CONSTRAINT FK_CALENDAR_FEEDS_ORG_ID FOREIGN KEY (org_id) REFERENCES organizations (id)
+);
+
+CREATE INDEX IF NOT EXISTS IDX_CALENDAR_FEEDS_ORG_ID ON calendar_feeds (org_id);
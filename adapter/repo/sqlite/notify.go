@@ -0,0 +1,58 @@
+package sqlite
+
+import (
+	"api/domain"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// changeNotifier delivers the task changes of committed transactions within the
+// process, in place of the trigger and listener the Postgres storage needs to reach
+// every replica. A SQLite database belongs to a single process, so nothing else can
+// change it.
+type changeNotifier struct {
+	mu     sync.Mutex
+	seq    int64
+	handle func(change domain.TaskChange)
+}
+
+// OnChange registers handle to be called with every task change committed through the
+// repo or any copy of it, in commit order. It must not block, as commits wait for it.
+func (tr *TasksRepo) OnChange(handle func(change domain.TaskChange)) {
+	tr.notifier.mu.Lock()
+	defer tr.notifier.mu.Unlock()
+	tr.notifier.handle = handle
+}
+
+// commit commits tx and delivers its changes. SQLite only ever lets one transaction
+// write, so holding the lock across the commit keeps the changes in commit order.
+func (n *changeNotifier) commit(tx *tenantTx) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	for _, change := range tx.changes {
+		n.seq++
+		change.ID = strconv.FormatInt(n.seq, 10)
+		if n.handle != nil {
+			n.handle(change)
+		}
+	}
+	return nil
+}
+
+// addChange records the live notification of a task mutation. Like the Postgres trigger,
+// it announces a restored task as created and a task moved to the trash only with its
+// identity.
+func (tx *tenantTx) addChange(eventType domain.EventType, task domain.Task) {
+	switch eventType {
+	case domain.EventTaskRestored:
+		eventType = domain.EventTaskCreated
+	case domain.EventTaskDeleted:
+		task = domain.Task{ID: task.ID, Status: task.Status}
+	}
+	tx.changes = append(tx.changes, domain.TaskChange{Type: eventType, Task: task, OrgID: tx.orgID})
+}
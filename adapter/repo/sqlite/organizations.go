@@ -0,0 +1,97 @@
+package sqlite
+
+import (
+	"api/adapter/repo/sqlite/gen"
+	"api/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	sqlitedriver "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const traceNameOrganizationsRepo = "OrganizationsRepo"
+
+type OrganizationsRepo struct {
+	querier *gen.Queries
+}
+
+func NewOrganizationsRepo(db *sql.DB) *OrganizationsRepo {
+	return &OrganizationsRepo{querier: gen.New(db)}
+}
+
+func (or OrganizationsRepo) GetOrganizationBySlug(ctx context.Context, slug string) (domain.Organization, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameOrganizationsRepo).Start(ctx, traceNameOrganizationsRepo+".GetOrganizationBySlug")
+	defer span.End()
+
+	org, err := or.querier.GetOrganizationBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Organization{}, fmt.Errorf("organization %s: %w", slug, domain.ErrOrganizationNotFound)
+		}
+		return domain.Organization{}, fmt.Errorf("failed to get organization %s: %v", slug, err)
+	}
+
+	return org.ToDomain(), nil
+}
+
+func (or OrganizationsRepo) CreateOrganization(ctx context.Context, data domain.Organization) (domain.Organization, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameOrganizationsRepo).Start(ctx, traceNameOrganizationsRepo+".CreateOrganization")
+	defer span.End()
+
+	org, err := or.querier.SaveOrganization(ctx, gen.SaveOrganizationParams{
+		ID:        data.ID,
+		Slug:      data.Slug,
+		Name:      data.Name,
+		CreatedAt: now(),
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.Organization{}, fmt.Errorf("organization %s: %w", data.Slug, domain.ErrOrganizationExists)
+		}
+		return domain.Organization{}, fmt.Errorf("failed to save organization: %v", err)
+	}
+
+	return org.ToDomain(), nil
+}
+
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlitedriver.Error
+	return errors.As(err, &sqliteErr) &&
+		(sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY)
+}
+
+// tenantTx is a write transaction of a single organization together with the task
+// changes to deliver once it is committed.
+type tenantTx struct {
+	*sql.Tx
+	orgID   uuid.UUID
+	changes []domain.TaskChange
+}
+
+// tenant returns the organization of ctx, which every query of the package filters by.
+func tenant(ctx context.Context) (uuid.UUID, error) {
+	orgID, ok := domain.OrganizationFromContext(ctx)
+	if !ok {
+		return uuid.Nil, domain.ErrNoOrganization
+	}
+	return orgID, nil
+}
+
+// beginTenantTx begins a write transaction for the organization of ctx. Without an
+// organization no transaction is begun at all.
+func beginTenantTx(ctx context.Context, db *sql.DB) (*tenantTx, error) {
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	return &tenantTx{Tx: tx, orgID: orgID}, nil
+}
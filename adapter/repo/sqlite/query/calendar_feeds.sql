@@ -0,0 +1,34 @@
+-- name: SaveCalendarFeed :one
+INSERT INTO calendar_feeds (id,
+                            org_id,
+                            name,
+                            status,
+                            token_hash,
+                            created_at)
+VALUES (sqlc.arg(id),
+        sqlc.arg(org_id),
+        sqlc.arg(name),
+        sqlc.arg(status),
+        sqlc.arg(token_hash),
+        sqlc.arg(created_at))
+RETURNING *;
+
+-- name: GetCalendarFeedByTokenHash :one
+SELECT *
+FROM calendar_feeds
+WHERE token_hash = sqlc.arg(token_hash)
+  AND org_id = sqlc.arg(org_id);
+
+-- name: RotateCalendarFeedToken :one
+UPDATE calendar_feeds
+SET token_hash = sqlc.arg(new_token_hash),
+    rotated_at = sqlc.arg(rotated_at)
+WHERE token_hash = sqlc.arg(token_hash)
+  AND org_id = sqlc.arg(org_id)
+RETURNING *;
+
+-- name: DeleteCalendarFeed :execrows
+DELETE
+FROM calendar_feeds
+WHERE token_hash = sqlc.arg(token_hash)
+  AND org_id = sqlc.arg(org_id);
@@ -0,0 +1,15 @@
+-- name: GetOrganizationBySlug :one
+SELECT *
+FROM organizations
+WHERE slug = sqlc.arg(slug);
+
+-- name: SaveOrganization :one
+INSERT INTO organizations (id,
+                           slug,
+                           name,
+                           created_at)
+VALUES (sqlc.arg(id),
+        sqlc.arg(slug),
+        sqlc.arg(name),
+        sqlc.arg(created_at))
+RETURNING *;
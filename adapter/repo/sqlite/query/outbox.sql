@@ -0,0 +1,38 @@
+-- name: SaveOutboxEvent :exec
+INSERT INTO outbox (event_id,
+                    event_type,
+                    aggregate_id,
+                    org_id,
+                    payload,
+                    created_at)
+VALUES (sqlc.arg(event_id),
+        sqlc.arg(event_type),
+        sqlc.arg(aggregate_id),
+        sqlc.arg(org_id),
+        sqlc.arg(payload),
+        sqlc.arg(created_at));
+
+-- name: GetPendingOutboxEvents :many
+SELECT *
+FROM outbox
+WHERE published_at IS NULL
+ORDER BY id
+LIMIT sqlc.arg(batch_size);
+
+-- name: MarkOutboxEventsPublished :exec
+UPDATE outbox
+SET published_at = sqlc.arg(published_at)
+WHERE id IN (sqlc.slice(ids));
+
+-- name: GetOutboxLag :one
+SELECT COUNT(*)                                                                       AS pending,
+       CAST(COALESCE((julianday('now') - julianday(MIN(created_at))) * 86400, 0) AS REAL) AS lag_seconds
+FROM outbox
+WHERE published_at IS NULL;
+
+-- name: GetOutboxEventsByAggregateIds :many
+SELECT *
+FROM outbox
+WHERE org_id = sqlc.arg(org_id)
+  AND aggregate_id IN (sqlc.slice(aggregate_ids))
+ORDER BY id;
@@ -0,0 +1,138 @@
+-- name: GetTaskById :one
+SELECT *
+FROM tasks
+WHERE id = sqlc.arg(id)
+  AND org_id = sqlc.arg(org_id)
+  AND deleted_at IS NULL;
+
+-- name: GetTasks :many
+SELECT *
+FROM tasks
+WHERE org_id = sqlc.arg(org_id)
+  AND deleted_at IS NULL
+  AND (CAST(sqlc.narg(status) AS TEXT) IS NULL OR status = sqlc.narg(status))
+ORDER BY seq;
+
+-- name: GetTasksPage :many
+SELECT *
+FROM tasks
+WHERE org_id = sqlc.arg(org_id)
+  AND deleted_at IS NULL
+  AND (CAST(sqlc.narg(status) AS TEXT) IS NULL OR status = sqlc.narg(status))
+  AND (CAST(sqlc.narg(after_created_at) AS TIMESTAMP) IS NULL
+    OR created_at > sqlc.narg(after_created_at)
+    OR (created_at = sqlc.narg(after_created_at) AND id > sqlc.narg(after_id)))
+ORDER BY created_at, id
+LIMIT sqlc.arg(max_results);
+
+-- name: CountTasks :one
+SELECT COUNT(*)
+FROM tasks
+WHERE org_id = sqlc.arg(org_id)
+  AND deleted_at IS NULL
+  AND (CAST(sqlc.narg(status) AS TEXT) IS NULL OR status = sqlc.narg(status));
+
+-- name: GetTasksByIds :many
+-- sqlc numbers the parameters following a slice wrongly, so slices always come last.
+SELECT *
+FROM tasks
+WHERE org_id = sqlc.arg(org_id)
+  AND deleted_at IS NULL
+  AND id IN (sqlc.slice(ids));
+
+-- name: SaveTask :one
+INSERT INTO tasks (id,
+                   org_id,
+                   title,
+                   description,
+                   status,
+                   due_date,
+                   rrule,
+                   timezone,
+                   created_at)
+VALUES (sqlc.arg(id),
+        sqlc.arg(org_id),
+        sqlc.arg(title),
+        sqlc.arg(description),
+        sqlc.arg(status),
+        sqlc.arg(due_date),
+        sqlc.arg(rrule),
+        sqlc.arg(timezone),
+        sqlc.arg(created_at))
+RETURNING *;
+
+-- name: ImportTask :execrows
+-- Tasks whose id already exists, including an earlier row of the same import, are left
+-- untouched.
+INSERT INTO tasks (id,
+                   org_id,
+                   title,
+                   description,
+                   status,
+                   due_date,
+                   rrule,
+                   timezone,
+                   created_at)
+VALUES (sqlc.arg(id),
+        sqlc.arg(org_id),
+        sqlc.arg(title),
+        sqlc.arg(description),
+        sqlc.arg(status),
+        sqlc.arg(due_date),
+        sqlc.arg(rrule),
+        sqlc.arg(timezone),
+        sqlc.arg(created_at))
+ON CONFLICT (id) DO NOTHING;
+
+-- name: UpdateTask :one
+UPDATE tasks
+SET title       = sqlc.arg(title),
+    description = sqlc.arg(description),
+    status      = sqlc.arg(status),
+    due_date    = sqlc.arg(due_date),
+    rrule       = sqlc.arg(rrule),
+    timezone    = sqlc.arg(timezone)
+WHERE id = sqlc.arg(id)
+  AND org_id = sqlc.arg(org_id)
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteTask :one
+UPDATE tasks
+SET deleted_at = sqlc.arg(deleted_at)
+WHERE id = sqlc.arg(id)
+  AND org_id = sqlc.arg(org_id)
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: GetDeletedTasks :many
+SELECT *
+FROM tasks
+WHERE org_id = sqlc.arg(org_id)
+  AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: RestoreTask :one
+UPDATE tasks
+SET deleted_at = NULL
+WHERE id = sqlc.arg(id)
+  AND org_id = sqlc.arg(org_id)
+  AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PurgeTask :execrows
+DELETE
+FROM tasks
+WHERE id = sqlc.arg(id)
+  AND org_id = sqlc.arg(org_id)
+  AND deleted_at IS NOT NULL;
+
+-- name: PurgeDeletedTasks :execrows
+DELETE
+FROM tasks
+WHERE seq IN (SELECT t.seq
+              FROM tasks AS t
+              WHERE t.deleted_at < sqlc.arg(deleted_before)
+              ORDER BY t.deleted_at
+              LIMIT sqlc.arg(batch_size));
+
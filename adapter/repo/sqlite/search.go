@@ -0,0 +1,194 @@
+package sqlite
+
+import (
+	"api/adapter/repo/sqlite/gen"
+	"api/adapter/repo/trigram"
+	"api/domain"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const snippetMaxLength = 200
+
+// snippet() can only cut the snippet from the indexed description, which is not
+// escaped, so it marks the matches with control characters replaced once the snippet
+// is escaped.
+const (
+	snippetStartSel = "\x02"
+	snippetStopSel  = "\x03"
+)
+
+// sqlc does not know the FTS5 virtual table, so the search query is kept here. bm25 is
+// lower for better matches and weighs matches in the title over those in the
+// description.
+const searchTasks = `
+SELECT ` + taskColumns + `,
+       -bm25(tasks_search, 1.0, 0.4)                        AS score,
+       snippet(tasks_search, 1, char(2), char(3), '', 30) AS snippet
+FROM tasks_search
+         JOIN tasks AS t ON t.seq = tasks_search.rowid
+WHERE tasks_search MATCH ?
+  AND t.org_id = ?
+  AND t.deleted_at IS NULL
+ORDER BY score DESC, t.created_at DESC
+LIMIT ?`
+
+// SearchTasks returns the tasks matching the websearch-style query (quoted phrases, OR
+// and -negation are supported), best matches first. Words are reduced by the Porter
+// stemmer of the full-text index, which only suits English.
+func (tr TasksRepo) SearchTasks(ctx context.Context, query string, limit int) ([]domain.TaskSearchResult, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".SearchTasks")
+	span.SetAttributes(attribute.String("query", query))
+	defer span.End()
+
+	results := []domain.TaskSearchResult{}
+	match := toMatchExpression(query)
+	err := tr.read(ctx, func(_ *gen.Queries, orgID uuid.UUID) error {
+		if match == "" {
+			return nil
+		}
+		rows, err := tr.conn().QueryContext(ctx, searchTasks, match, orgID, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			result := domain.TaskSearchResult{Match: domain.MatchFullText}
+			task, err := scanTask(rows, &result.Rank, &result.Snippet)
+			if err != nil {
+				return err
+			}
+			result.Task = task.ToDomain()
+			result.Snippet = markSnippet(result.Snippet)
+			results = append(results, result)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tasks: %v", err)
+	}
+
+	return results, nil
+}
+
+// SearchTasksBySimilarity returns the tasks whose title or description contain words
+// similar to query, which tolerates typos that full-text search does not. SQLite has no
+// trigram similarity, so it is computed here over every live task of the organization.
+func (tr TasksRepo) SearchTasksBySimilarity(ctx context.Context, query string, limit int) ([]domain.TaskSearchResult, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".SearchTasksBySimilarity")
+	span.SetAttributes(attribute.String("query", query))
+	defer span.End()
+
+	var data []gen.Task
+	err := tr.read(ctx, func(q *gen.Queries, orgID uuid.UUID) error {
+		var err error
+		data, err = q.GetTasks(ctx, gen.GetTasksParams{OrgID: orgID})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tasks by similarity: %v", err)
+	}
+
+	results := []domain.TaskSearchResult{}
+	for _, task := range data {
+		rank := trigram.WordSimilarity(query, task.Title+" "+task.Description)
+		if rank < trigram.Threshold {
+			continue
+		}
+		snippet := []rune(task.Description)
+		if len(snippet) > snippetMaxLength {
+			snippet = snippet[:snippetMaxLength]
+		}
+		results = append(results, domain.TaskSearchResult{
+			Task:    task.ToDomain(),
+			Rank:    rank,
+			Snippet: html.EscapeString(string(snippet)),
+			Match:   domain.MatchSimilarity,
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return results[i].Task.CreatedAt.After(results[j].Task.CreatedAt)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// toMatchExpression turns a websearch-style query into an FTS5 query: the alternatives
+// separated by OR match when all their terms do, and a term with a leading dash must not
+// match. Every term is quoted, so nothing the user types is taken for FTS5 syntax. An
+// alternative made of negated terms only cannot be expressed in FTS5 and is left out.
+func toMatchExpression(query string) string {
+	type alternative struct {
+		terms   []string
+		negated []string
+	}
+	alternatives := []alternative{{}}
+	add := func(text string, negated bool) {
+		words := strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+		if len(words) == 0 {
+			return
+		}
+		term := `"` + strings.Join(words, " ") + `"`
+		last := &alternatives[len(alternatives)-1]
+		if negated {
+			last.negated = append(last.negated, term)
+		} else {
+			last.terms = append(last.terms, term)
+		}
+	}
+
+	// The odd parts between quotes are phrases, which a lone dash in front negates.
+	negatePhrase := false
+	for i, part := range strings.Split(query, `"`) {
+		if i%2 == 1 {
+			add(part, negatePhrase)
+			continue
+		}
+		negatePhrase = false
+		for _, field := range strings.Fields(part) {
+			negatePhrase = field == "-"
+			switch {
+			case strings.EqualFold(field, "or"):
+				alternatives = append(alternatives, alternative{})
+			case strings.HasPrefix(field, "-"):
+				add(field[1:], true)
+			default:
+				add(field, false)
+			}
+		}
+	}
+
+	var expressions []string
+	for _, alt := range alternatives {
+		if len(alt.terms) == 0 {
+			continue
+		}
+		expression := "(" + strings.Join(alt.terms, " AND ") + ")"
+		for _, term := range alt.negated {
+			expression += " NOT " + term
+		}
+		expressions = append(expressions, "("+expression+")")
+	}
+	return strings.Join(expressions, " OR ")
+}
+
+// markSnippet escapes the snippet as HTML and puts its matches in <mark> tags.
+func markSnippet(snippet string) string {
+	snippet = html.EscapeString(snippet)
+	snippet = strings.ReplaceAll(snippet, snippetStartSel, "<mark>")
+	return strings.ReplaceAll(snippet, snippetStopSel, "</mark>")
+}
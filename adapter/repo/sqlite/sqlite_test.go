@@ -0,0 +1,254 @@
+package sqlite
+
+import (
+	"api/adapter/repo/repotest"
+	"api/config"
+	"api/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repos {
+		db := newTestDB(t)
+		return repotest.Repos{
+			Tasks:         NewTasksRepo(db),
+			CalendarFeeds: NewCalendarFeedsRepo(db),
+			Organizations: NewOrganizationsRepo(db),
+		}
+	})
+}
+
+// newTestDB returns a migrated database in a file of its own.
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db := openTestDB(t, filepath.Join(t.TempDir(), "tasks.db"))
+	require.NoError(t, RunMigrations(db))
+	return db
+}
+
+func openTestDB(t *testing.T, path string) *sql.DB {
+	t.Helper()
+	db, err := NewSQLiteClient(context.Background(), config.Config{SqlitePath: path})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// orgContext scopes the repos to the default organization.
+func orgContext() context.Context {
+	return domain.WithOrganization(context.Background(), domain.DefaultOrganizationID)
+}
+
+func newTask(id uuid.UUID) domain.Task {
+	return domain.Task{
+		ID:          id,
+		Title:       "Do unit tests",
+		Description: "Create extensive unit tests for all layers",
+		Status:      domain.StatusPending,
+		DueDate:     time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestNewSQLiteClient(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.db")
+	db := openTestDB(t, path)
+	require.NoError(t, RunMigrations(db))
+
+	var journalMode string
+	require.NoError(t, db.QueryRow("PRAGMA journal_mode").Scan(&journalMode))
+	require.Equal(t, "wal", journalMode)
+
+	id := uuid.New()
+	_, err := NewTasksRepo(db).CreateTask(orgContext(), newTask(id))
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	// The tasks survive a restart, which migrates the database again.
+	db = openTestDB(t, path)
+	require.NoError(t, RunMigrations(db))
+	task, err := NewTasksRepo(db).GetTaskById(orgContext(), id)
+	require.NoError(t, err)
+	require.Equal(t, "Do unit tests", task.Title)
+}
+
+func TestTasksRepo_OnChange(t *testing.T) {
+	repo := NewTasksRepo(newTestDB(t))
+	var changes []domain.TaskChange
+	repo.OnChange(func(change domain.TaskChange) {
+		changes = append(changes, change)
+	})
+	id := uuid.New()
+
+	_, err := repo.CreateTask(orgContext(), newTask(id))
+	require.NoError(t, err)
+	_, err = repo.DeleteTask(orgContext(), id)
+	require.NoError(t, err)
+	_, err = repo.RestoreTask(orgContext(), id)
+	require.NoError(t, err)
+
+	// Rolled back changes are never announced.
+	err = repo.InTx(orgContext(), func(txRepo domain.TasksRepo) error {
+		_, err := txRepo.UpdateTask(orgContext(), newTask(id))
+		require.NoError(t, err)
+		return errors.New("rolled back")
+	})
+	require.Error(t, err)
+
+	require.Len(t, changes, 3)
+	require.Equal(t, domain.EventTaskCreated, changes[0].Type)
+	require.Equal(t, "Do unit tests", changes[0].Task.Title)
+	require.Equal(t, domain.DefaultOrganizationID, changes[0].OrgID)
+	require.Equal(t, "1", changes[0].ID)
+	require.Equal(t, domain.EventTaskDeleted, changes[1].Type)
+	require.Equal(t, domain.Task{ID: id, Status: domain.StatusPending}, changes[1].Task)
+	require.Equal(t, domain.EventTaskCreated, changes[2].Type)
+	require.Equal(t, "3", changes[2].ID)
+}
+
+type fakePublisher struct {
+	events  []domain.Event
+	failAt  int
+	failErr error
+}
+
+func (fp *fakePublisher) Publish(_ context.Context, event domain.Event) error {
+	if fp.failErr != nil && len(fp.events) == fp.failAt {
+		return fp.failErr
+	}
+	fp.events = append(fp.events, event)
+	return nil
+}
+
+func (fp *fakePublisher) Close() error {
+	return nil
+}
+
+func TestOutboxRelay_PublishPending(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewTasksRepo(db)
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	for _, id := range ids {
+		_, err := repo.CreateTask(orgContext(), newTask(id))
+		require.NoError(t, err)
+	}
+
+	// The second event fails, so only the first one is marked as published.
+	publisher := &fakePublisher{failAt: 1, failErr: errors.New("nats is down")}
+	relay, err := NewOutboxRelay(db, publisher, time.Second, 2)
+	require.NoError(t, err)
+	count, err := relay.PublishPending(context.Background())
+	require.Error(t, err)
+	require.Equal(t, 1, count)
+
+	publisher.failErr = nil
+	count, err = relay.PublishPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	count, err = relay.PublishPending(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	require.Len(t, publisher.events, 3)
+	for i, event := range publisher.events {
+		require.Equal(t, ids[i], event.AggregateID)
+		require.Equal(t, domain.EventTaskCreated, event.Type)
+	}
+}
+
+func TestTrashRetention_PurgeExpired(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewTasksRepo(db)
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	for _, id := range ids {
+		_, err := repo.CreateTask(orgContext(), newTask(id))
+		require.NoError(t, err)
+	}
+	for _, id := range ids[:2] {
+		_, err := repo.DeleteTask(orgContext(), id)
+		require.NoError(t, err)
+	}
+
+	// Only the first task has been in the trash for longer than the retention period.
+	_, err := db.Exec("UPDATE tasks SET deleted_at = ? WHERE id = ?", now().Add(-31*24*time.Hour), ids[0])
+	require.NoError(t, err)
+
+	retention := NewTrashRetention(db, 30*24*time.Hour, time.Hour, 1)
+	count, err := retention.PurgeExpired(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	count, err = retention.PurgeExpired(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	trash, err := repo.GetDeletedTasks(orgContext())
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	require.Equal(t, ids[1], trash[0].ID)
+
+	// The purged task is gone from the full-text index too.
+	results, err := repo.SearchTasks(orgContext(), "unit tests", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, ids[2], results[0].Task.ID)
+}
+
+// Every write waits for the single write lock of the database, so concurrent writers
+// never fail on each other.
+func TestTasksRepo_Concurrently(t *testing.T) {
+	repo := NewTasksRepo(newTestDB(t))
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := uuid.New()
+			err := repo.InTx(orgContext(), func(txRepo domain.TasksRepo) error {
+				if _, err := txRepo.CreateTask(orgContext(), newTask(id)); err != nil {
+					return err
+				}
+				_, err := txRepo.DeleteTask(orgContext(), id)
+				return err
+			})
+			require.NoError(t, err)
+			_, err = repo.RestoreTask(orgContext(), id)
+			require.NoError(t, err)
+			_, err = repo.GetTasks(orgContext(), domain.TaskFilter{})
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	tasks, err := repo.GetTasks(orgContext(), domain.TaskFilter{})
+	require.NoError(t, err)
+	require.Len(t, tasks, 50)
+}
+
+func TestToMatchExpression(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "report", want: `(("report"))`},
+		{query: "run board", want: `(("run" AND "board"))`},
+		{query: "report -quarterly", want: `(("report") NOT "quarterly")`},
+		{query: "lisbon or marketing", want: `(("lisbon")) OR (("marketing"))`},
+		{query: `"marketing drafts" - "board"`, want: `(("marketing drafts") NOT "board")`},
+		{query: `report* AND (x`, want: `(("report" AND "AND" AND "x"))`},
+		{query: "-quarterly", want: ""},
+		{query: "  ", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			require.Equal(t, tt.want, toMatchExpression(tt.query))
+		})
+	}
+}
@@ -0,0 +1,452 @@
+package sqlite
+
+import (
+	"api/adapter/repo/sqlite/gen"
+	"api/domain"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"time"
+)
+
+const traceNameTasksRepo = "TasksRepo"
+
+// TasksRepo stores tasks in a SQLite database. SQLite has no row-level security, so
+// every query of the repo filters by the organization of ctx itself.
+type TasksRepo struct {
+	db       *sql.DB
+	tx       *tenantTx
+	querier  *gen.Queries
+	notifier *changeNotifier
+}
+
+func NewTasksRepo(db *sql.DB) *TasksRepo {
+	return &TasksRepo{db: db, querier: gen.New(db), notifier: &changeNotifier{}}
+}
+
+func (tr TasksRepo) GetTaskById(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".GetTaskById")
+	span.SetAttributes(attribute.String("task_id", id.String()))
+	defer span.End()
+
+	var task gen.Task
+	err := tr.read(ctx, func(q *gen.Queries, orgID uuid.UUID) error {
+		var err error
+		task, err = q.GetTaskById(ctx, gen.GetTaskByIdParams{ID: id, OrgID: orgID})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Task{}, fmt.Errorf("task not found in db %s: %w", id, domain.ErrTaskNotFound)
+		}
+		return domain.Task{}, fmt.Errorf("failed to get task %s: %v", id, err)
+	}
+
+	return task.ToDomain(), nil
+}
+
+// GetTaskByIdForUpdate needs no lock of its own: transactions take the write lock of
+// the database as they begin, so no other one can change the task before they end.
+func (tr TasksRepo) GetTaskByIdForUpdate(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	return tr.GetTaskById(ctx, id)
+}
+
+func (tr TasksRepo) GetTasks(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".GetTasks")
+	defer span.End()
+
+	var data []gen.Task
+	err := tr.read(ctx, func(q *gen.Queries, orgID uuid.UUID) error {
+		var err error
+		data, err = q.GetTasks(ctx, gen.GetTasksParams{
+			OrgID:  orgID,
+			Status: sql.NullString{String: filter.Status, Valid: filter.Status != ""},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find all tasks: %v", err)
+	}
+
+	tasks := []domain.Task{}
+	for _, currentTask := range data {
+		tasks = append(tasks, currentTask.ToDomain())
+	}
+
+	return tasks, nil
+}
+
+// GetTasksPage reads only the page itself, using the order of the export, so that the
+// cost of a page does not grow with the number of tasks before it.
+func (tr TasksRepo) GetTasksPage(ctx context.Context, filter domain.TaskFilter, after *domain.TaskCursor, limit int) ([]domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".GetTasksPage")
+	span.SetAttributes(attribute.Int("limit", limit))
+	defer span.End()
+
+	var data []gen.Task
+	err := tr.read(ctx, func(q *gen.Queries, orgID uuid.UUID) error {
+		params := gen.GetTasksPageParams{
+			OrgID:      orgID,
+			Status:     sql.NullString{String: filter.Status, Valid: filter.Status != ""},
+			MaxResults: int64(limit),
+		}
+		if after != nil {
+			// Timestamps are stored as text in UTC, which orders like the times themselves.
+			params.AfterCreatedAt = sql.NullTime{Time: after.CreatedAt.UTC(), Valid: true}
+			params.AfterID = after.ID
+		}
+		var err error
+		data, err = q.GetTasksPage(ctx, params)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find page of tasks: %v", err)
+	}
+
+	tasks := make([]domain.Task, len(data))
+	for i, currentTask := range data {
+		tasks[i] = currentTask.ToDomain()
+	}
+	return tasks, nil
+}
+
+func (tr TasksRepo) CountTasks(ctx context.Context, filter domain.TaskFilter) (int, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".CountTasks")
+	defer span.End()
+
+	var count int64
+	err := tr.read(ctx, func(q *gen.Queries, orgID uuid.UUID) error {
+		var err error
+		count, err = q.CountTasks(ctx, gen.CountTasksParams{
+			OrgID:  orgID,
+			Status: sql.NullString{String: filter.Status, Valid: filter.Status != ""},
+		})
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count tasks: %v", err)
+	}
+	return int(count), nil
+}
+
+// GetTasksByIds returns the live tasks among ids in a single query. Unknown ids are
+// left out, so the result may be shorter than ids and is in no particular order.
+func (tr TasksRepo) GetTasksByIds(ctx context.Context, ids []uuid.UUID) ([]domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".GetTasksByIds")
+	span.SetAttributes(attribute.Int("task_count", len(ids)))
+	defer span.End()
+
+	var data []gen.Task
+	err := tr.read(ctx, func(q *gen.Queries, orgID uuid.UUID) error {
+		var err error
+		data, err = q.GetTasksByIds(ctx, gen.GetTasksByIdsParams{Ids: ids, OrgID: orgID})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks by ids: %v", err)
+	}
+
+	tasks := make([]domain.Task, len(data))
+	for i, currentTask := range data {
+		tasks[i] = currentTask.ToDomain()
+	}
+	return tasks, nil
+}
+
+// GetTaskEvents returns the outbox events of the tasks with the given ids in a single
+// query, oldest first.
+func (tr TasksRepo) GetTaskEvents(ctx context.Context, taskIDs []uuid.UUID) ([]domain.Event, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".GetTaskEvents")
+	span.SetAttributes(attribute.Int("task_count", len(taskIDs)))
+	defer span.End()
+
+	var data []gen.Outbox
+	err := tr.read(ctx, func(q *gen.Queries, orgID uuid.UUID) error {
+		var err error
+		data, err = q.GetOutboxEventsByAggregateIds(ctx, gen.GetOutboxEventsByAggregateIdsParams{
+			AggregateIds: taskIDs,
+			OrgID:        orgID,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task events: %v", err)
+	}
+
+	events := make([]domain.Event, len(data))
+	for i, event := range data {
+		events[i] = event.ToDomain()
+	}
+	return events, nil
+}
+
+func (tr TasksRepo) CreateTask(ctx context.Context, data domain.Task) (domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".CreateTask")
+	defer span.End()
+
+	var task gen.Task
+	err := tr.write(ctx, func(q *gen.Queries, tx *tenantTx) error {
+		var err error
+		task, err = q.SaveTask(ctx, gen.SaveTaskParams{
+			ID:          data.ID,
+			OrgID:       tx.orgID,
+			Title:       data.Title,
+			Description: data.Description,
+			Status:      data.Status,
+			DueDate:     data.DueDate,
+			Rrule:       data.RRule,
+			Timezone:    data.Timezone,
+			CreatedAt:   now(),
+		})
+		if err != nil {
+			if isUniqueViolation(err) {
+				return fmt.Errorf("task %s: %w", data.ID, domain.ErrTaskExists)
+			}
+			return fmt.Errorf("failed to save task: %v", err)
+		}
+		return tx.saveEvent(ctx, q, domain.EventTaskCreated, task.ToDomain())
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	return task.ToDomain(), nil
+}
+
+func (tr TasksRepo) UpdateTask(ctx context.Context, data domain.Task) (domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".UpdateTask")
+	span.SetAttributes(attribute.String("task_id", data.ID.String()))
+	defer span.End()
+
+	var task gen.Task
+	err := tr.write(ctx, func(q *gen.Queries, tx *tenantTx) error {
+		// The transaction holds the write lock, so the task cannot change in between.
+		previous, err := q.GetTaskById(ctx, gen.GetTaskByIdParams{ID: data.ID, OrgID: tx.orgID})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("task not found in db %s: %w", data.ID, domain.ErrTaskNotFound)
+			}
+			return fmt.Errorf("failed to update task %s: %v", data.ID, err)
+		}
+
+		task, err = q.UpdateTask(ctx, gen.UpdateTaskParams{
+			ID:          data.ID,
+			OrgID:       tx.orgID,
+			Title:       data.Title,
+			Description: data.Description,
+			Status:      data.Status,
+			DueDate:     data.DueDate,
+			Rrule:       data.RRule,
+			Timezone:    data.Timezone,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("task not found in db %s: %w", data.ID, domain.ErrTaskNotFound)
+			}
+			return fmt.Errorf("failed to update task %s: %v", data.ID, err)
+		}
+		if err := tx.saveEvent(ctx, q, domain.EventTaskUpdated, task.ToDomain()); err != nil {
+			return err
+		}
+		tx.changes[len(tx.changes)-1].PreviousStatus = previous.Status
+		return nil
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	return task.ToDomain(), nil
+}
+
+// DeleteTask moves the task to the trash, from which it can be restored until it is
+// purged.
+func (tr TasksRepo) DeleteTask(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".DeleteTask")
+	span.SetAttributes(attribute.String("task_id", id.String()))
+	defer span.End()
+
+	var task gen.Task
+	err := tr.write(ctx, func(q *gen.Queries, tx *tenantTx) error {
+		var err error
+		task, err = q.DeleteTask(ctx, gen.DeleteTaskParams{
+			ID:        id,
+			OrgID:     tx.orgID,
+			DeletedAt: sql.NullTime{Time: now(), Valid: true},
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("task not found in db %s: %w", id, domain.ErrTaskNotFound)
+			}
+			return fmt.Errorf("failed to delete task %s: %v", id, err)
+		}
+		return tx.saveEvent(ctx, q, domain.EventTaskDeleted, task.ToDomain())
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	return task.ToDomain(), nil
+}
+
+func (tr TasksRepo) GetDeletedTasks(ctx context.Context) ([]domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".GetDeletedTasks")
+	defer span.End()
+
+	var data []gen.Task
+	err := tr.read(ctx, func(q *gen.Queries, orgID uuid.UUID) error {
+		var err error
+		data, err = q.GetDeletedTasks(ctx, orgID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find deleted tasks: %v", err)
+	}
+
+	tasks := []domain.Task{}
+	for _, currentTask := range data {
+		tasks = append(tasks, currentTask.ToDomain())
+	}
+
+	return tasks, nil
+}
+
+func (tr TasksRepo) RestoreTask(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".RestoreTask")
+	span.SetAttributes(attribute.String("task_id", id.String()))
+	defer span.End()
+
+	var task gen.Task
+	err := tr.write(ctx, func(q *gen.Queries, tx *tenantTx) error {
+		var err error
+		task, err = q.RestoreTask(ctx, gen.RestoreTaskParams{ID: id, OrgID: tx.orgID})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("task not found in trash %s: %w", id, domain.ErrTaskNotFound)
+			}
+			return fmt.Errorf("failed to restore task %s: %v", id, err)
+		}
+		return tx.saveEvent(ctx, q, domain.EventTaskRestored, task.ToDomain())
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	return task.ToDomain(), nil
+}
+
+// PurgeTask permanently deletes a task from the trash. Consumers of the outbox were
+// already told about the delete when the task was moved to the trash.
+func (tr TasksRepo) PurgeTask(ctx context.Context, id uuid.UUID) error {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTasksRepo).Start(ctx, traceNameTasksRepo+".PurgeTask")
+	span.SetAttributes(attribute.String("task_id", id.String()))
+	defer span.End()
+
+	var count int64
+	err := tr.write(ctx, func(q *gen.Queries, tx *tenantTx) error {
+		var err error
+		count, err = q.PurgeTask(ctx, gen.PurgeTaskParams{ID: id, OrgID: tx.orgID})
+		if err != nil {
+			return fmt.Errorf("failed to purge task %s: %v", id, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("task not found in trash %s: %w", id, domain.ErrTaskNotFound)
+	}
+	return nil
+}
+
+// InTx runs fn with a copy of the repo bound to a new transaction of the organization
+// of ctx. A repo which is already bound to a transaction runs fn within it.
+func (tr TasksRepo) InTx(ctx context.Context, fn func(repo domain.TasksRepo) error) error {
+	if tr.tx != nil {
+		return fn(tr)
+	}
+
+	tx, err := beginTenantTx(ctx, tr.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	txRepo := tr
+	txRepo.tx = tx
+	txRepo.querier = tr.querier.WithTx(tx.Tx)
+	if err := fn(txRepo); err != nil {
+		return err
+	}
+
+	return tr.notifier.commit(tx)
+}
+
+// read runs fn with the organization of ctx, within the transaction of the repo if it
+// is bound to one. Reads need no transaction of their own: a single query always sees a
+// consistent snapshot.
+func (tr TasksRepo) read(ctx context.Context, fn func(q *gen.Queries, orgID uuid.UUID) error) error {
+	if tr.tx != nil {
+		return fn(tr.querier, tr.tx.orgID)
+	}
+
+	orgID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+	return fn(tr.querier, orgID)
+}
+
+// write runs fn inside a single transaction, so that a task mutation and its outbox
+// record are either both committed or both rolled back.
+func (tr TasksRepo) write(ctx context.Context, fn func(q *gen.Queries, tx *tenantTx) error) error {
+	if tr.tx != nil {
+		return fn(tr.querier, tr.tx)
+	}
+
+	tx, err := beginTenantTx(ctx, tr.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tr.querier.WithTx(tx.Tx), tx); err != nil {
+		return err
+	}
+	return tr.notifier.commit(tx)
+}
+
+// saveEvent writes the outbox record of a task mutation and records its live change.
+func (tx *tenantTx) saveEvent(ctx context.Context, q *gen.Queries, eventType domain.EventType, task domain.Task) error {
+	payload, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %v", eventType, err)
+	}
+
+	err = q.SaveOutboxEvent(ctx, gen.SaveOutboxEventParams{
+		EventID:     uuid.New(),
+		EventType:   string(eventType),
+		AggregateID: task.ID,
+		OrgID:       tx.orgID,
+		Payload:     string(payload),
+		CreatedAt:   now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save %s event: %v", eventType, err)
+	}
+
+	tx.addChange(eventType, task)
+	return nil
+}
+
+// now is the time every row of the package is stamped with, at the precision of
+// Postgres timestamps. The driver writes it as text, which only sorts correctly as long
+// as every time is in UTC.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"api/adapter/repo/sqlite/gen"
+	"context"
+	"database/sql"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"log"
+	"time"
+)
+
+const traceNameTrashRetention = "TrashRetention"
+
+// TrashRetention permanently deletes tasks which have been in the trash for longer than
+// the retention period. Every batch is a separate statement, so the purge never holds
+// the write lock of the database for long.
+type TrashRetention struct {
+	querier   *gen.Queries
+	retention time.Duration
+	interval  time.Duration
+	batchSize int64
+}
+
+func NewTrashRetention(db *sql.DB, retention, interval time.Duration, batchSize int) *TrashRetention {
+	return &TrashRetention{
+		querier:   gen.New(db),
+		retention: retention,
+		interval:  interval,
+		batchSize: int64(batchSize),
+	}
+}
+
+// Run purges expired tasks every interval until ctx is cancelled.
+func (tr *TrashRetention) Run(ctx context.Context) {
+	ticker := time.NewTicker(tr.interval)
+	defer ticker.Stop()
+
+	for {
+		count, err := tr.PurgeExpired(ctx)
+		if err != nil {
+			log.Printf("error while purging expired tasks from trash: %v", err)
+		} else if count > 0 {
+			log.Printf("purged %d expired tasks from trash", count)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeExpired deletes expired tasks batch by batch until none are left and returns
+// how many were deleted.
+func (tr *TrashRetention) PurgeExpired(ctx context.Context) (int, error) {
+	ctx, span := otel.GetTracerProvider().Tracer(traceNameTrashRetention).Start(ctx, traceNameTrashRetention+".PurgeExpired")
+	defer span.End()
+
+	deletedBefore := now().Add(-tr.retention)
+	total := 0
+	for {
+		count, err := tr.querier.PurgeDeletedTasks(ctx, gen.PurgeDeletedTasksParams{
+			DeletedBefore: sql.NullTime{Time: deletedBefore, Valid: true},
+			BatchSize:     tr.batchSize,
+		})
+		total += int(count)
+		if err != nil {
+			return total, fmt.Errorf("failed to purge deleted tasks: %v", err)
+		}
+		if count < tr.batchSize {
+			span.SetAttributes(attribute.Int("purged", total))
+			return total, nil
+		}
+	}
+}
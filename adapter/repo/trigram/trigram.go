@@ -0,0 +1,55 @@
+// Package trigram measures how similar words are the way the pg_trgm extension of
+// Postgres does, for the storages which have to compute it themselves.
+package trigram
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Threshold is the default pg_trgm.word_similarity_threshold.
+const Threshold = 0.6
+
+// WordSimilarity is the greatest share of the trigrams of query found in any run of
+// consecutive words of text, which is close to what word_similarity of pg_trgm returns.
+func WordSimilarity(query, text string) float64 {
+	queryWords := words(query)
+	queryTrigrams := trigrams(queryWords)
+	if len(queryTrigrams) == 0 {
+		return 0
+	}
+	textWords := words(text)
+
+	best := 0.0
+	for start := range textWords {
+		for end := start + 1; end <= len(textWords) && end-start <= len(queryWords)+1; end++ {
+			common := 0
+			for trigram := range trigrams(textWords[start:end]) {
+				if queryTrigrams[trigram] {
+					common++
+				}
+			}
+			best = max(best, float64(common)/float64(len(queryTrigrams)))
+		}
+	}
+	return best
+}
+
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// trigrams returns the trigrams of words padded like pg_trgm pads them, with two spaces
+// in front and one behind.
+func trigrams(words []string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range words {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+	return set
+}
@@ -0,0 +1,28 @@
+package trigram
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWordSimilarity(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		text    string
+		similar bool
+	}{
+		{name: "exact word", query: "conference", text: "Flights to the conference in Lisbon", similar: true},
+		{name: "typo", query: "conferense", text: "Flights to the conference in Lisbon", similar: true},
+		{name: "case", query: "LISBON", text: "Flights to the conference in Lisbon", similar: true},
+		{name: "unrelated", query: "xylophone", text: "Flights to the conference in Lisbon", similar: false},
+		{name: "empty query", query: " - ", text: "Flights to the conference in Lisbon", similar: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			similarity := WordSimilarity(tt.query, tt.text)
+			require.Equal(t, tt.similar, similarity >= Threshold, "similarity %f", similarity)
+		})
+	}
+	require.Equal(t, 1.0, WordSimilarity("report", "Write quarterly report"))
+}
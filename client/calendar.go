@@ -0,0 +1,40 @@
+package client
+
+import (
+	"api/domain"
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// CalendarFeed is a calendar feed along with its secret token and subscription URL.
+// The API shows the token only when it is created or rotated.
+type CalendarFeed struct {
+	domain.CalendarFeed
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+// CreateCalendarFeed creates a feed publishing the tasks with the given status, or all
+// tasks when status is empty.
+func (c *Client) CreateCalendarFeed(ctx context.Context, name, status string) (CalendarFeed, error) {
+	var feed CalendarFeed
+	err := c.doJSON(ctx, request{
+		method: http.MethodPost,
+		path:   "/api/calendar/feeds",
+		body:   map[string]string{"name": name, "status": status},
+	}, &feed)
+	return feed, err
+}
+
+// RotateCalendarFeed replaces the token of a feed, so the old subscription URL stops
+// working.
+func (c *Client) RotateCalendarFeed(ctx context.Context, token string) (CalendarFeed, error) {
+	var feed CalendarFeed
+	err := c.doJSON(ctx, request{method: http.MethodPost, path: "/api/calendar/feeds/" + url.PathEscape(token) + "/rotate"}, &feed)
+	return feed, err
+}
+
+func (c *Client) DeleteCalendarFeed(ctx context.Context, token string) error {
+	return c.doJSON(ctx, request{method: http.MethodDelete, path: "/api/calendar/feeds/" + url.PathEscape(token)}, nil)
+}
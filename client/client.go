@@ -0,0 +1,247 @@
+// Package client is a Go client for the tasks API. It speaks the same JSON as the
+// server and returns the domain types, so services calling the API do not need to
+// write their own HTTP code.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const userAgent = "tasks-api-go-client"
+
+// Authenticator adds credentials to every request before it is sent.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// AuthenticatorFunc turns a function into an Authenticator.
+type AuthenticatorFunc func(req *http.Request) error
+
+func (f AuthenticatorFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+// BearerToken authenticates requests with a bearer token, such as the admin token
+// needed to purge tasks from the trash.
+func BearerToken(token string) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// RetryPolicy controls how idempotent calls (GET, PUT and DELETE) are retried after a
+// network error or a 429, 502, 503 or 504 response. The wait doubles after every
+// attempt, starting at MinBackoff and capped at MaxBackoff, unless the server asks
+// for a longer one with Retry-After.
+type RetryPolicy struct {
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+}
+
+// DefaultRetryPolicy tries idempotent calls up to three times.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, MinBackoff: 100 * time.Millisecond, MaxBackoff: 2 * time.Second}
+
+// NoRetry sends every call only once.
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	auth       Authenticator
+	retry      RetryPolicy
+}
+
+// NewClient returns a client for the API served at baseURL, e.g. http://localhost:8080.
+func NewClient(baseURL string) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url: %v", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid base url %q: scheme and host are required", baseURL)
+	}
+	return &Client{baseURL: u, httpClient: http.DefaultClient, retry: DefaultRetryPolicy}, nil
+}
+
+func (c Client) WithHTTPClient(httpClient *http.Client) *Client {
+	c.httpClient = httpClient
+	return &c
+}
+
+func (c Client) WithAuth(auth Authenticator) *Client {
+	c.auth = auth
+	return &c
+}
+
+func (c Client) WithRetryPolicy(retry RetryPolicy) *Client {
+	c.retry = retry
+	return &c
+}
+
+// request describes a call to path, or to url when the server handed out a link. A
+// JSON body is kept in memory so it can be sent again on retries, while an upload is
+// streamed once.
+type request struct {
+	method      string
+	path        string
+	url         *url.URL
+	query       url.Values
+	body        any
+	upload      io.Reader
+	contentType string
+}
+
+// do sends req and returns the response of a successful call, leaving it to the caller
+// to close its body. Error responses are returned as an *APIError.
+func (c *Client) do(ctx context.Context, req request) (*http.Response, error) {
+	var payload []byte
+	if req.body != nil {
+		var err error
+		if payload, err = json.Marshal(req.body); err != nil {
+			return nil, fmt.Errorf("error encoding request body: %v", err)
+		}
+	}
+
+	attempts := 1
+	if req.upload == nil && isIdempotent(req.method) {
+		attempts = max(c.retry.MaxAttempts, 1)
+	}
+
+	backoff := c.retry.MinBackoff
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, req, payload)
+		if attempt == attempts || !shouldRetry(ctx, resp, err) {
+			if err != nil {
+				return nil, err
+			}
+			if resp.StatusCode >= http.StatusBadRequest {
+				return nil, newAPIError(resp)
+			}
+			return resp, nil
+		}
+
+		wait := backoff
+		if resp != nil {
+			wait = max(wait, retryAfter(resp))
+			drain(resp)
+		}
+		backoff = min(backoff*2, c.retry.MaxBackoff)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, req request, payload []byte) (*http.Response, error) {
+	u := req.url
+	if u == nil {
+		u = c.baseURL.JoinPath(req.path)
+		u.RawQuery = req.query.Encode()
+	}
+
+	var body io.Reader
+	switch {
+	case req.upload != nil:
+		body = req.upload
+	case payload != nil:
+		body = bytes.NewReader(payload)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", userAgent)
+	switch {
+	case req.contentType != "":
+		httpReq.Header.Set("Content-Type", req.contentType)
+	case payload != nil:
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if c.auth != nil {
+		if err := c.auth.Authenticate(httpReq); err != nil {
+			return nil, fmt.Errorf("error authenticating request: %v", err)
+		}
+	}
+
+	return c.httpClient.Do(httpReq)
+}
+
+// doJSON sends req and decodes the JSON response into out, unless out is nil.
+func (c *Client) doJSON(ctx context.Context, req request, out any) error {
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return err
+	}
+	if out == nil {
+		drain(resp)
+		return nil
+	}
+	return decodeJSON(resp, out)
+}
+
+func decodeJSON(resp *http.Response, out any) error {
+	defer drain(resp)
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding response: %v", err)
+	}
+	return nil
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		// Requests which could not even be built or authenticated fail the same way again.
+		var urlErr *url.Error
+		return errors.As(err, &urlErr)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfter reads a Retry-After header given in seconds.
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// drain reads what is left of the body so the connection can be reused.
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
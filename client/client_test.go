@@ -0,0 +1,243 @@
+package client
+
+import (
+	"api/domain"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testRetryPolicy = RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c, err := NewClient(server.URL)
+	require.NoError(t, err)
+	return c.WithRetryPolicy(testRetryPolicy)
+}
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		baseURL     string
+		expectedErr bool
+	}{
+		{baseURL: "http://localhost:8080"},
+		{baseURL: "https://tasks.example.com/prefix"},
+		{baseURL: "localhost:8080", expectedErr: true},
+		{baseURL: "/api", expectedErr: true},
+		{baseURL: "http://%zz", expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.baseURL, func(t *testing.T) {
+			_, err := NewClient(tt.baseURL)
+			require.Equal(t, tt.expectedErr, err != nil, err)
+		})
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	tests := []struct {
+		name             string
+		call             func(c *Client) error
+		statuses         []int
+		expectedAttempts int32
+		expectedErr      error
+	}{
+		{
+			name: "idempotent call is retried until it succeeds",
+			call: func(c *Client) error {
+				_, err := c.GetTrash(context.Background())
+				return err
+			},
+			statuses:         []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			expectedAttempts: 3,
+		},
+		{
+			name: "idempotent call gives up after the last attempt",
+			call: func(c *Client) error {
+				_, err := c.GetTrash(context.Background())
+				return err
+			},
+			statuses:         []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK},
+			expectedAttempts: 3,
+			expectedErr:      &APIError{StatusCode: http.StatusTooManyRequests, Message: "slow down"},
+		},
+		{
+			name: "client errors are not retried",
+			call: func(c *Client) error {
+				_, err := c.GetTrash(context.Background())
+				return err
+			},
+			statuses:         []int{http.StatusNotFound, http.StatusOK},
+			expectedAttempts: 1,
+			expectedErr:      &APIError{StatusCode: http.StatusNotFound, Message: "not found"},
+		},
+		{
+			name: "non idempotent call is not retried",
+			call: func(c *Client) error {
+				_, err := c.CreateTask(context.Background(), domain.Task{Title: "Do unit tests"})
+				return err
+			},
+			statuses:         []int{http.StatusServiceUnavailable, http.StatusOK},
+			expectedAttempts: 1,
+			expectedErr:      &APIError{StatusCode: http.StatusServiceUnavailable, Message: "unavailable"},
+		},
+	}
+
+	messages := map[int]string{
+		http.StatusServiceUnavailable: "unavailable",
+		http.StatusBadGateway:         "bad gateway",
+		http.StatusTooManyRequests:    "slow down",
+		http.StatusNotFound:           "not found",
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[attempts.Add(1)-1]
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				if status == http.StatusOK {
+					fmt.Fprint(w, `[]`)
+					return
+				}
+				fmt.Fprintf(w, `{"code":%d,"message":%q}`, status, messages[status])
+			})
+
+			err := tt.call(c)
+			require.Equal(t, tt.expectedAttempts, attempts.Load())
+			if tt.expectedErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
+
+func TestClient_RetryStopsWithContext(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.GetTrash(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestClient_TypedErrors(t *testing.T) {
+	tests := []struct {
+		status   int
+		body     string
+		expected error
+		message  string
+	}{
+		{status: http.StatusNotFound, body: `{"code":404,"message":"task not found"}`, expected: ErrNotFound, message: "task not found"},
+		{status: http.StatusConflict, body: `{"code":409,"message":"conflict"}`, expected: ErrConflict, message: "conflict"},
+		{status: http.StatusPreconditionFailed, body: ``, expected: ErrConflict, message: "Precondition Failed"},
+		{status: http.StatusUnprocessableEntity, body: `{"code":422,"message":"invalid rrule"}`, expected: ErrUnprocessable, message: "invalid rrule"},
+		{status: http.StatusInternalServerError, body: `<html>oops</html>`, message: "Internal Server Error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			})
+
+			_, err := c.RestoreTask(context.Background(), uuid.Nil)
+
+			var apiErr *APIError
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, tt.status, apiErr.StatusCode)
+			require.Equal(t, tt.message, apiErr.Message)
+			for _, sentinel := range []error{ErrNotFound, ErrConflict, ErrUnprocessable} {
+				require.Equal(t, sentinel == tt.expected, errors.Is(err, sentinel), sentinel)
+			}
+		})
+	}
+}
+
+func TestClient_Auth(t *testing.T) {
+	var authorization atomic.Value
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		authorization.Store(r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	require.NoError(t, c.WithAuth(BearerToken("secret")).PurgeTask(context.Background(), uuid.Nil))
+	require.Equal(t, "Bearer secret", authorization.Load())
+
+	failing := AuthenticatorFunc(func(req *http.Request) error { return errors.New("token expired") })
+	err := c.WithAuth(failing).PurgeTask(context.Background(), uuid.Nil)
+	require.ErrorContains(t, err, "token expired")
+}
+
+func TestClient_ListTasksFollowsPages(t *testing.T) {
+	var requests []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RequestURI())
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			w.Header().Set("Link", `</api/tasks?cursor=b&limit=100&status=PENDING>; rel="next"`)
+			fmt.Fprint(w, `[{"title":"first"},{"title":"second"}]`)
+		case "b":
+			w.Header().Set("Link", `</api/tasks?cursor=c&limit=100&status=PENDING>; rel="next"`)
+			fmt.Fprint(w, `[{"title":"third"}]`)
+		default:
+			fmt.Fprint(w, `[{"title":"fourth"}]`)
+		}
+	})
+
+	var titles []string
+	for task, err := range c.ListTasks(context.Background(), domain.TaskFilter{Status: "PENDING"}) {
+		require.NoError(t, err)
+		titles = append(titles, task.Title)
+	}
+	require.Equal(t, []string{"first", "second", "third", "fourth"}, titles)
+	require.Equal(t, []string{
+		"/api/tasks?limit=100&status=PENDING",
+		"/api/tasks?cursor=b&limit=100&status=PENDING",
+		"/api/tasks?cursor=c&limit=100&status=PENDING",
+	}, requests)
+
+	// Stopping early does not fetch the following pages.
+	requests = nil
+	for task := range c.ListTasks(context.Background(), domain.TaskFilter{Status: "PENDING"}) {
+		if task.Title == "second" {
+			break
+		}
+	}
+	require.Equal(t, []string{"/api/tasks?limit=100&status=PENDING"}, requests)
+}
+
+func TestClient_ListTasksError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	var errs []error
+	for _, err := range c.ListTasks(context.Background(), domain.TaskFilter{}) {
+		errs = append(errs, err)
+	}
+	require.Len(t, errs, 1)
+	require.Equal(t, &APIError{StatusCode: http.StatusInternalServerError, Message: "Internal Server Error"}, errs[0])
+}
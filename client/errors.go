@@ -0,0 +1,55 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrConflict      = errors.New("conflict")
+	ErrUnprocessable = errors.New("unprocessable entity")
+)
+
+// APIError is returned for every error response of the API. Use errors.Is with
+// ErrNotFound, ErrConflict or ErrUnprocessable to tell the common cases apart.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error %d: %s", e.StatusCode, e.Message)
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict || e.StatusCode == http.StatusPreconditionFailed
+	case ErrUnprocessable:
+		return e.StatusCode == http.StatusUnprocessableEntity
+	default:
+		return false
+	}
+}
+
+// newAPIError reads the error response of the API, falling back to the status text
+// for responses that do not carry one, e.g. from a proxy.
+func newAPIError(resp *http.Response) *APIError {
+	defer drain(resp)
+
+	apiErr := &APIError{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+	var body struct {
+		Message string `json:"message"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err := json.Unmarshal(data, &body); err == nil && body.Message != "" {
+		apiErr.Message = body.Message
+	}
+	return apiErr
+}
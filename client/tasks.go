@@ -0,0 +1,237 @@
+package client
+
+import (
+	"api/domain"
+	"context"
+	"github.com/google/uuid"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatCSV      = "csv"
+	FormatNDJSON   = "ndjson"
+	FormatMarkdown = "md"
+	FormatXLSX     = "xlsx"
+)
+
+const listTasksPageSize = 100
+
+var importContentTypes = map[string]string{
+	FormatCSV:    "text/csv",
+	FormatNDJSON: "application/x-ndjson",
+}
+
+// BulkItemResult is the outcome of the operation at Index of a bulk request. Status
+// is the HTTP status the operation would have had as a single request.
+type BulkItemResult struct {
+	Index  int          `json:"index"`
+	Status int          `json:"status"`
+	Task   *domain.Task `json:"task,omitempty"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// ImportOptions are the optional params of an import. Mapping maps task fields to the
+// CSV columns holding them, when the columns are named differently.
+type ImportOptions struct {
+	DryRun  bool
+	Mapping map[string]string
+}
+
+func (c *Client) GetTask(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	var task domain.Task
+	err := c.doJSON(ctx, request{method: http.MethodGet, path: "/api/task/" + id.String()}, &task)
+	return task, err
+}
+
+// ListTasks iterates over the tasks matching filter, oldest first. The tasks are fetched
+// in pages of listTasksPageSize, each one as the iteration reaches the Link header of
+// the page before pointing to it, so the iteration can be stopped early without
+// loading every task.
+func (c *Client) ListTasks(ctx context.Context, filter domain.TaskFilter) iter.Seq2[domain.Task, error] {
+	return func(yield func(domain.Task, error) bool) {
+		req := request{method: http.MethodGet, path: "/api/tasks", query: limitQuery(filterQuery(filter), listTasksPageSize)}
+		for {
+			resp, err := c.do(ctx, req)
+			if err != nil {
+				yield(domain.Task{}, err)
+				return
+			}
+
+			var tasks []domain.Task
+			err = decodeJSON(resp, &tasks)
+			next := nextPage(resp)
+			if err != nil {
+				yield(domain.Task{}, err)
+				return
+			}
+			for _, task := range tasks {
+				if !yield(task, nil) {
+					return
+				}
+			}
+
+			if next == nil {
+				return
+			}
+			req = request{method: http.MethodGet, url: next}
+		}
+	}
+}
+
+func (c *Client) CreateTask(ctx context.Context, task domain.Task) (domain.Task, error) {
+	var created domain.Task
+	err := c.doJSON(ctx, request{method: http.MethodPost, path: "/api/task", body: task}, &created)
+	return created, err
+}
+
+func (c *Client) UpdateTask(ctx context.Context, task domain.Task) (domain.Task, error) {
+	var updated domain.Task
+	err := c.doJSON(ctx, request{method: http.MethodPut, path: "/api/task/" + task.ID.String(), body: task}, &updated)
+	return updated, err
+}
+
+// DeleteTask moves a task to the trash.
+func (c *Client) DeleteTask(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	var task domain.Task
+	err := c.doJSON(ctx, request{method: http.MethodDelete, path: "/api/task/" + id.String()}, &task)
+	return task, err
+}
+
+// GetOccurrences returns the due dates of the next occurrences of a recurring task,
+// none for a task that does not recur. It fails with ErrUnprocessable when the
+// recurrence rule of the task cannot be expanded.
+func (c *Client) GetOccurrences(ctx context.Context, id uuid.UUID, limit int) ([]time.Time, error) {
+	var occurrences []time.Time
+	err := c.doJSON(ctx, request{
+		method: http.MethodGet,
+		path:   "/api/task/" + id.String() + "/occurrences",
+		query:  limitQuery(nil, limit),
+	}, &occurrences)
+	return occurrences, err
+}
+
+func (c *Client) SearchTasks(ctx context.Context, query string, limit int) ([]domain.TaskSearchResult, error) {
+	var results []domain.TaskSearchResult
+	err := c.doJSON(ctx, request{
+		method: http.MethodGet,
+		path:   "/api/tasks/search",
+		query:  limitQuery(url.Values{"q": {query}}, limit),
+	}, &results)
+	return results, err
+}
+
+func (c *Client) GetTrash(ctx context.Context) ([]domain.Task, error) {
+	var tasks []domain.Task
+	err := c.doJSON(ctx, request{method: http.MethodGet, path: "/api/trash"}, &tasks)
+	return tasks, err
+}
+
+func (c *Client) RestoreTask(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	var task domain.Task
+	err := c.doJSON(ctx, request{method: http.MethodPost, path: "/api/task/" + id.String() + "/restore"}, &task)
+	return task, err
+}
+
+// PurgeTask deletes a task from the trash for good. It needs the admin token, see
+// BearerToken.
+func (c *Client) PurgeTask(ctx context.Context, id uuid.UUID) error {
+	return c.doJSON(ctx, request{method: http.MethodDelete, path: "/api/trash/" + id.String()}, nil)
+}
+
+// BulkTasks applies several operations in one request. Failed operations are reported
+// in their result rather than as an error, unless the whole request fails.
+func (c *Client) BulkTasks(ctx context.Context, ops []domain.BulkOperation, atomic bool) ([]BulkItemResult, error) {
+	var results []BulkItemResult
+	err := c.doJSON(ctx, request{
+		method: http.MethodPost,
+		path:   "/api/tasks/bulk",
+		query:  url.Values{"atomic": {strconv.FormatBool(atomic)}},
+		body:   ops,
+	}, &results)
+	return results, err
+}
+
+// ImportTasks uploads tasks in the csv or ndjson format. The upload is streamed, so
+// it is never retried.
+func (c *Client) ImportTasks(ctx context.Context, format string, data io.Reader, opts ImportOptions) (domain.ImportReport, error) {
+	query := url.Values{"format": {format}, "dry_run": {strconv.FormatBool(opts.DryRun)}}
+	for field, column := range opts.Mapping {
+		query.Add("mapping", field+":"+column)
+	}
+
+	var report domain.ImportReport
+	err := c.doJSON(ctx, request{
+		method:      http.MethodPost,
+		path:        "/api/tasks/import",
+		query:       query,
+		upload:      data,
+		contentType: importContentTypes[format],
+	}, &report)
+	return report, err
+}
+
+// ExportTasks downloads the tasks matching filter in the given format. The caller has
+// to close the returned reader.
+func (c *Client) ExportTasks(ctx context.Context, format string, filter domain.TaskFilter) (io.ReadCloser, error) {
+	query := filterQuery(filter)
+	query.Set("format", format)
+
+	resp, err := c.do(ctx, request{method: http.MethodGet, path: "/api/tasks/export", query: query})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func filterQuery(filter domain.TaskFilter) url.Values {
+	query := url.Values{}
+	if filter.Status != "" {
+		query.Set("status", filter.Status)
+	}
+	return query
+}
+
+func limitQuery(query url.Values, limit int) url.Values {
+	if query == nil {
+		query = url.Values{}
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	return query
+}
+
+// nextPage returns the target of the rel="next" link of resp, resolved against the
+// URL of the request.
+func nextPage(resp *http.Response) *url.URL {
+	for _, header := range resp.Header.Values("Link") {
+		for _, link := range strings.Split(header, ",") {
+			target, params, ok := strings.Cut(strings.TrimSpace(link), ";")
+			if !ok || !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			isNext := false
+			for _, param := range strings.Split(params, ";") {
+				name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if strings.EqualFold(name, "rel") && strings.Trim(value, `"`) == "next" {
+					isNext = true
+				}
+			}
+			if !isNext {
+				continue
+			}
+			u, err := resp.Request.URL.Parse(strings.Trim(target, "<>"))
+			if err != nil {
+				return nil
+			}
+			return u
+		}
+	}
+	return nil
+}
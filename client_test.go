@@ -0,0 +1,204 @@
+package main
+
+import (
+	"api/client"
+	"api/config"
+	"api/domain"
+	mock "api/mocks/mock_domain"
+	"api/uc"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newRouterClient serves the router built by createRouter on top of mocked repos, so
+// the client goes through the real validation, handlers and use cases.
+func newRouterClient(t *testing.T, conf config.Config) (*client.Client, *mock.MockTasksRepo, *mock.MockCalendarFeedsRepo) {
+	ctrl := gomock.NewController(t)
+	tasksRepo := mock.NewMockTasksRepo(ctrl)
+	feedsRepo := mock.NewMockCalendarFeedsRepo(ctrl)
+
+	router, err := createRouter(tasksRepo, feedsRepo, nil, uc.NewTaskChangesHub(0), nil, nil, conf)
+	require.NoError(t, err)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	c, err := client.NewClient(server.URL)
+	require.NoError(t, err)
+	return c.WithRetryPolicy(client.NoRetry), tasksRepo, feedsRepo
+}
+
+func getClientTask() domain.Task {
+	return domain.Task{
+		ID:          uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce"),
+		Title:       "Do unit tests",
+		Description: "Create extensive unit tests for all layers",
+		Status:      "PENDING",
+		DueDate:     time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC),
+		CreatedAt:   time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestClient_Tasks(t *testing.T) {
+	ctx := context.Background()
+	task := getClientTask()
+	c, tasksRepo, _ := newRouterClient(t, config.Config{})
+
+	tasksRepo.EXPECT().CreateTask(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, data domain.Task) (domain.Task, error) {
+		data.ID = task.ID
+		data.CreatedAt = task.CreatedAt
+		return data, nil
+	})
+	created, err := c.CreateTask(ctx, domain.Task{Title: task.Title, Description: task.Description, Status: task.Status, DueDate: task.DueDate})
+	require.NoError(t, err)
+	require.Equal(t, task, created)
+
+	tasksRepo.EXPECT().GetTaskById(gomock.Any(), task.ID).Return(task, nil)
+	fetched, err := c.GetTask(ctx, task.ID)
+	require.NoError(t, err)
+	require.Equal(t, task, fetched)
+
+	done := task
+	done.Status = domain.StatusDone
+	tasksRepo.EXPECT().UpdateTask(gomock.Any(), gomock.Eq(done)).Return(done, nil)
+	updated, err := c.UpdateTask(ctx, done)
+	require.NoError(t, err)
+	require.Equal(t, done, updated)
+
+	tasksRepo.EXPECT().GetTasksPage(gomock.Any(), domain.TaskFilter{Status: domain.StatusDone}, nil, 101).Return([]domain.Task{done}, nil)
+	var listed []domain.Task
+	for task, err := range c.ListTasks(ctx, domain.TaskFilter{Status: domain.StatusDone}) {
+		require.NoError(t, err)
+		listed = append(listed, task)
+	}
+	require.Equal(t, []domain.Task{done}, listed)
+
+	tasksRepo.EXPECT().SearchTasks(gomock.Any(), "unit tests", 5).Return([]domain.TaskSearchResult{{Task: task, Rank: 0.5, Match: "fulltext"}}, nil)
+	results, err := c.SearchTasks(ctx, "unit tests", 5)
+	require.NoError(t, err)
+	require.Equal(t, []domain.TaskSearchResult{{Task: task, Rank: 0.5, Match: "fulltext"}}, results)
+}
+
+func TestClient_Trash(t *testing.T) {
+	ctx := context.Background()
+	task := getClientTask()
+	deletedAt := time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC)
+	deleted := task
+	deleted.DeletedAt = &deletedAt
+	c, tasksRepo, _ := newRouterClient(t, config.Config{AdminToken: "secret"})
+
+	tasksRepo.EXPECT().DeleteTask(gomock.Any(), task.ID).Return(deleted, nil)
+	result, err := c.DeleteTask(ctx, task.ID)
+	require.NoError(t, err)
+	require.Equal(t, deleted, result)
+
+	tasksRepo.EXPECT().GetDeletedTasks(gomock.Any()).Return([]domain.Task{deleted}, nil)
+	trash, err := c.GetTrash(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []domain.Task{deleted}, trash)
+
+	tasksRepo.EXPECT().RestoreTask(gomock.Any(), task.ID).Return(task, nil)
+	restored, err := c.RestoreTask(ctx, task.ID)
+	require.NoError(t, err)
+	require.Equal(t, task, restored)
+
+	err = c.PurgeTask(ctx, task.ID)
+	require.Equal(t, &client.APIError{StatusCode: 401, Message: "admin token required"}, err)
+
+	tasksRepo.EXPECT().PurgeTask(gomock.Any(), task.ID).Return(nil)
+	require.NoError(t, c.WithAuth(client.BearerToken("secret")).PurgeTask(ctx, task.ID))
+}
+
+func TestClient_Errors(t *testing.T) {
+	ctx := context.Background()
+	task := getClientTask()
+	c, tasksRepo, _ := newRouterClient(t, config.Config{})
+
+	tasksRepo.EXPECT().GetTaskById(gomock.Any(), task.ID).Return(domain.Task{}, domain.ErrTaskNotFound)
+	_, err := c.GetTask(ctx, task.ID)
+	require.ErrorIs(t, err, client.ErrNotFound)
+
+	invalid := task
+	invalid.RRule = "FREQ=SOMETIMES"
+	tasksRepo.EXPECT().GetTaskById(gomock.Any(), task.ID).Return(invalid, nil)
+	_, err = c.GetOccurrences(ctx, task.ID, 3)
+	require.ErrorIs(t, err, client.ErrUnprocessable)
+
+	// Rejected by the OpenAPI validation before reaching the handler.
+	_, err = c.SearchTasks(ctx, "", 0)
+	var apiErr *client.APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, 400, apiErr.StatusCode)
+}
+
+func TestClient_BulkImportExport(t *testing.T) {
+	ctx := context.Background()
+	task := getClientTask()
+	c, tasksRepo, _ := newRouterClient(t, config.Config{})
+
+	missingID := uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3cf")
+	tasksRepo.EXPECT().DeleteTask(gomock.Any(), task.ID).Return(task, nil)
+	tasksRepo.EXPECT().DeleteTask(gomock.Any(), missingID).Return(domain.Task{}, domain.ErrTaskNotFound)
+	results, err := c.BulkTasks(ctx, []domain.BulkOperation{
+		{Op: domain.BulkOpDelete, ID: task.ID},
+		{Op: domain.BulkOpDelete, ID: missingID},
+	}, false)
+	require.NoError(t, err)
+	require.Equal(t, []client.BulkItemResult{
+		{Index: 0, Status: 200, Task: &task},
+		{Index: 1, Status: 404, Error: domain.ErrTaskNotFound.Error()},
+	}, results)
+
+	tasksRepo.EXPECT().ImportTasks(gomock.Any(), gomock.Any(), true).DoAndReturn(func(ctx context.Context, next func() (domain.Task, error), dryRun bool) (int, error) {
+		count := 0
+		for {
+			if _, err := next(); errors.Is(err, io.EOF) {
+				return count, nil
+			}
+			count++
+		}
+	})
+	report, err := c.ImportTasks(ctx, client.FormatCSV, strings.NewReader("Name,status\nDo unit tests,PENDING\n"), client.ImportOptions{
+		DryRun:  true,
+		Mapping: map[string]string{"title": "Name"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, domain.ImportReport{DryRun: true, Total: 1, Imported: 1, Errors: []domain.ImportError{}}, report)
+
+	tasksRepo.EXPECT().ExportTasks(gomock.Any(), domain.TaskFilter{}, gomock.Any()).DoAndReturn(func(ctx context.Context, filter domain.TaskFilter, fn func(task domain.Task) error) error {
+		return fn(task)
+	})
+	export, err := c.ExportTasks(ctx, client.FormatNDJSON, domain.TaskFilter{})
+	require.NoError(t, err)
+	defer export.Close()
+	data, err := io.ReadAll(export)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"title":"Do unit tests"`)
+}
+
+func TestClient_CalendarFeeds(t *testing.T) {
+	ctx := context.Background()
+	c, _, feedsRepo := newRouterClient(t, config.Config{})
+
+	feed := domain.CalendarFeed{
+		ID:        uuid.MustParse("5e0c1f53-5d0a-4c39-9d7e-1b1a8f0a7c11"),
+		Name:      "My tasks",
+		CreatedAt: time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC),
+	}
+	feedsRepo.EXPECT().CreateFeed(gomock.Any(), gomock.Any(), gomock.Any()).Return(feed, nil)
+	created, err := c.CreateCalendarFeed(ctx, "My tasks", "")
+	require.NoError(t, err)
+	require.Equal(t, feed, created.CalendarFeed)
+	require.NotEmpty(t, created.Token)
+	require.Contains(t, created.URL, "/api/calendar.ics?token="+created.Token)
+
+	feedsRepo.EXPECT().DeleteFeed(gomock.Any(), gomock.Any()).Return(domain.ErrCalendarFeedNotFound)
+	require.ErrorIs(t, c.DeleteCalendarFeed(ctx, created.Token), client.ErrNotFound)
+}
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"api/client"
+	"api/domain"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+type cli struct {
+	now        func() time.Time
+	configPath string
+	server     string
+	token      string
+	output     string
+	json       bool
+}
+
+func newRootCmd(now func() time.Time) *cobra.Command {
+	c := &cli{now: now}
+
+	root := &cobra.Command{
+		Use:   "tasks",
+		Short: "Manage tasks from the terminal",
+		Long: `Manage tasks from the terminal through the tasks HTTP API.
+
+The server URL and token are read from ~/.tasks.yaml (or the file named by
+TASKS_CONFIG). The TASKS_SERVER and TASKS_TOKEN env vars and the --server and
+--token flags take precedence over it.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		// Checked up front so that a typo does not fail only after a task was changed.
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if !slices.Contains(outputFormats, c.outputFormat()) {
+				return fmt.Errorf("unknown output format %q, use one of %v", c.outputFormat(), outputFormats)
+			}
+			return nil
+		},
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&c.configPath, "config", defaultConfigPath(), "config file")
+	flags.StringVar(&c.server, "server", "", "API server URL (default "+defaultServer+")")
+	flags.StringVar(&c.token, "token", "", "bearer token sent to the server")
+	flags.StringVarP(&c.output, "output", "o", outputTable, "output format: table, json or yaml")
+	flags.BoolVar(&c.json, "json", false, "shorthand for --output json")
+	_ = root.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(outputFormats, cobra.ShellCompDirectiveNoFileComp))
+	_ = root.MarkPersistentFlagFilename("config", "yaml", "yml")
+
+	root.AddCommand(c.addCmd(), c.lsCmd(), c.showCmd(), c.doneCmd(), c.configCmd())
+	return root
+}
+
+// connect builds an API client from the flags, the env and the config file, in that
+// order of precedence.
+func (c *cli) connect() (*client.Client, error) {
+	conf, err := loadConfig(c.configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	server := firstNonEmpty(c.server, os.Getenv("TASKS_SERVER"), conf.Server, defaultServer)
+	token := firstNonEmpty(c.token, os.Getenv("TASKS_TOKEN"), conf.Token)
+
+	api, err := client.NewClient(server)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		api = api.WithAuth(client.BearerToken(token))
+	}
+	return api, nil
+}
+
+func (c *cli) outputFormat() string {
+	if c.json {
+		return outputJSON
+	}
+	return c.output
+}
+
+func (c *cli) addCmd() *cobra.Command {
+	var (
+		due         string
+		description string
+		status      string
+		repeat      string
+		timezone    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add <title>",
+		Short: "Add a task",
+		Example: `  tasks add "Do unit tests" --due tomorrow
+  tasks add Water the plants --due "next monday" --repeat FREQ=WEEKLY`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			task := domain.Task{
+				Title:       strings.Join(args, " "),
+				Description: description,
+				Status:      status,
+				RRule:       repeat,
+				Timezone:    timezone,
+			}
+			if due != "" {
+				dueDate, err := parseDate(due, c.now())
+				if err != nil {
+					return err
+				}
+				task.DueDate = dueDate
+			}
+
+			api, err := c.connect()
+			if err != nil {
+				return err
+			}
+			created, err := api.CreateTask(cmd.Context(), task)
+			if err != nil {
+				return err
+			}
+			return printTask(cmd.OutOrStdout(), c.outputFormat(), created, c.now())
+		},
+	}
+
+	cmd.Flags().StringVar(&due, "due", "", `due date, e.g. today, tomorrow, friday, "in 3 days" or 2025-05-12`)
+	cmd.Flags().StringVarP(&description, "description", "d", "", "description")
+	cmd.Flags().StringVar(&status, "status", domain.StatusPending, "status")
+	cmd.Flags().StringVar(&repeat, "repeat", "", "RFC 5545 recurrence rule, e.g. FREQ=WEEKLY;BYDAY=MO")
+	cmd.Flags().StringVar(&timezone, "timezone", "", "IANA time zone the task repeats in, e.g. Europe/Sofia")
+	_ = cmd.RegisterFlagCompletionFunc("due", cobra.FixedCompletions([]string{"today", "tomorrow", "monday", "friday", "in 1 week"}, cobra.ShellCompDirectiveNoFileComp))
+	_ = cmd.RegisterFlagCompletionFunc("status", completeStatus)
+	return cmd
+}
+
+func (c *cli) lsCmd() *cobra.Command {
+	var status string
+
+	cmd := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List tasks",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			api, err := c.connect()
+			if err != nil {
+				return err
+			}
+
+			tasks := []domain.Task{}
+			for task, err := range api.ListTasks(cmd.Context(), domain.TaskFilter{Status: status}) {
+				if err != nil {
+					return err
+				}
+				tasks = append(tasks, task)
+			}
+			return printTasks(cmd.OutOrStdout(), c.outputFormat(), tasks, c.now())
+		},
+	}
+
+	cmd.Flags().StringVar(&status, "status", "", "only tasks with this status")
+	_ = cmd.RegisterFlagCompletionFunc("status", completeStatus)
+	return cmd
+}
+
+func (c *cli) showCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "show <id>",
+		Short:             "Show a task",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: c.completeTaskID,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseTaskID(args[0])
+			if err != nil {
+				return err
+			}
+			api, err := c.connect()
+			if err != nil {
+				return err
+			}
+			task, err := api.GetTask(cmd.Context(), id)
+			if err != nil {
+				return taskError(id, err)
+			}
+			return printTask(cmd.OutOrStdout(), c.outputFormat(), task, c.now())
+		},
+	}
+}
+
+func (c *cli) doneCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "done <id>",
+		Short:             "Mark a task as done",
+		Long:              "Mark a task as done. Completing a repeating task creates the task for its next occurrence.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: c.completeTaskID,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseTaskID(args[0])
+			if err != nil {
+				return err
+			}
+			api, err := c.connect()
+			if err != nil {
+				return err
+			}
+
+			// The API replaces the whole task on update.
+			task, err := api.GetTask(cmd.Context(), id)
+			if err != nil {
+				return taskError(id, err)
+			}
+			task.Status = domain.StatusDone
+			task, err = api.UpdateTask(cmd.Context(), task)
+			if err != nil {
+				return taskError(id, err)
+			}
+
+			if c.outputFormat() != outputTable {
+				return printTask(cmd.OutOrStdout(), c.outputFormat(), task, c.now())
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Done: %s\n", oneLine(task.Title))
+			return nil
+		},
+	}
+}
+
+func (c *cli) configCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Read and write the config file",
+		Long: `Read and write the config file. Keys:
+  server  URL of the API server, e.g. https://tasks.example.com
+  token   bearer token sent to the server`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:       "get <key>",
+		Short:     "Print a config value",
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: configKeys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conf, err := loadConfig(c.configPath)
+			if err != nil {
+				return err
+			}
+			value, err := conf.get(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), value)
+			return nil
+		},
+	}, &cobra.Command{
+		Use:       "set <key> <value>",
+		Short:     "Set a config value",
+		Args:      cobra.ExactArgs(2),
+		ValidArgs: configKeys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conf, err := loadConfig(c.configPath)
+			if err != nil {
+				return err
+			}
+			if err := conf.set(args[0], args[1]); err != nil {
+				return err
+			}
+			return saveConfig(c.configPath, conf)
+		},
+	})
+	return cmd
+}
+
+// completeTaskID completes task ids, with their titles as descriptions.
+func (c *cli) completeTaskID(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	api, err := c.connect()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var completions []string
+	for task, err := range api.ListTasks(cmd.Context(), domain.TaskFilter{}) {
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		if id := task.ID.String(); strings.HasPrefix(id, toComplete) {
+			completions = append(completions, id+"\t"+oneLine(task.Title))
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeStatus(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return []string{domain.StatusPending, domain.StatusDone}, cobra.ShellCompDirectiveNoFileComp
+}
+
+func parseTaskID(arg string) (uuid.UUID, error) {
+	id, err := uuid.Parse(arg)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid task id %q", arg)
+	}
+	return id, nil
+}
+
+func taskError(id uuid.UUID, err error) error {
+	if errors.Is(err, client.ErrNotFound) {
+		return fmt.Errorf("task %s not found", id)
+	}
+	return err
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
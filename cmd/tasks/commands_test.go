@@ -0,0 +1,254 @@
+package main
+
+import (
+	"api/domain"
+	"bytes"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2025, 4, 2, 15, 30, 0, 0, time.UTC)
+
+func getTestTask() domain.Task {
+	return domain.Task{
+		ID:          uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce"),
+		Title:       "Do unit tests",
+		Description: "Create extensive unit tests for all layers",
+		Status:      domain.StatusPending,
+		DueDate:     time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC),
+		CreatedAt:   time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// fakeAPI keeps tasks in memory and serves the few routes the CLI calls.
+type fakeAPI struct {
+	mu            sync.Mutex
+	tasks         map[uuid.UUID]domain.Task
+	authorization string
+}
+
+func newFakeAPI(t *testing.T, tasks ...domain.Task) (*fakeAPI, string) {
+	api := &fakeAPI{tasks: map[uuid.UUID]domain.Task{}}
+	for _, task := range tasks {
+		api.tasks[task.ID] = task
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/tasks", func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		api.authorization = r.Header.Get("Authorization")
+		list := []domain.Task{}
+		for _, task := range api.tasks {
+			if status := r.URL.Query().Get("status"); status == "" || task.Status == status {
+				list = append(list, task)
+			}
+		}
+		writeJSON(w, http.StatusOK, list)
+	})
+	mux.HandleFunc("POST /api/task", func(w http.ResponseWriter, r *http.Request) {
+		var task domain.Task
+		_ = json.NewDecoder(r.Body).Decode(&task)
+		task.ID = uuid.MustParse("5e0c1f53-5d0a-4c39-9d7e-1b1a8f0a7c11")
+		task.CreatedAt = testNow
+		api.mu.Lock()
+		api.tasks[task.ID] = task
+		api.mu.Unlock()
+		writeJSON(w, http.StatusOK, task)
+	})
+	mux.HandleFunc("GET /api/task/{id}", func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		task, ok := api.tasks[uuid.MustParse(r.PathValue("id"))]
+		api.mu.Unlock()
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]any{"code": 404, "message": "task not found"})
+			return
+		}
+		writeJSON(w, http.StatusOK, task)
+	})
+	mux.HandleFunc("PUT /api/task/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var task domain.Task
+		_ = json.NewDecoder(r.Body).Decode(&task)
+		api.mu.Lock()
+		api.tasks[task.ID] = task
+		api.mu.Unlock()
+		writeJSON(w, http.StatusOK, task)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return api, server.URL
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// runCLI runs the CLI with args against the server, using a config file in a temp dir.
+func runCLI(t *testing.T, server string, args ...string) (string, error) {
+	t.Setenv("TASKS_SERVER", server)
+	t.Setenv("TASKS_TOKEN", "")
+
+	var out bytes.Buffer
+	cmd := newRootCmd(func() time.Time { return testNow })
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(append([]string{"--config", filepath.Join(t.TempDir(), ".tasks.yaml")}, args...))
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestAdd(t *testing.T) {
+	api, server := newFakeAPI(t)
+
+	out, err := runCLI(t, server, "add", "Water", "the", "plants", "--due", "tomorrow", "-d", "All of them")
+	require.NoError(t, err)
+	require.Equal(t, `ID:       5e0c1f53-5d0a-4c39-9d7e-1b1a8f0a7c11
+Title:    Water the plants
+Status:   PENDING
+Due:      tomorrow
+Created:  today 15:30
+
+All of them
+`, out)
+
+	created := api.tasks[uuid.MustParse("5e0c1f53-5d0a-4c39-9d7e-1b1a8f0a7c11")]
+	require.Equal(t, "Water the plants", created.Title)
+	require.True(t, time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC).Equal(created.DueDate))
+}
+
+func TestAdd_InvalidDue(t *testing.T) {
+	api, server := newFakeAPI(t)
+
+	_, err := runCLI(t, server, "add", "Water the plants", "--due", "someday")
+	require.ErrorContains(t, err, `unknown date "someday"`)
+	require.Empty(t, api.tasks)
+}
+
+func TestLs(t *testing.T) {
+	done := getTestTask()
+	done.ID = uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3cf")
+	done.Status = domain.StatusDone
+	_, server := newFakeAPI(t, getTestTask(), done)
+
+	out, err := runCLI(t, server, "ls", "--status", "PENDING")
+	require.NoError(t, err)
+	require.Equal(t, `ID                                    STATUS   DUE       TITLE
+1461ec84-ccff-4f3c-af34-65d0856ac3ce  PENDING  tomorrow  Do unit tests
+`, out)
+
+	out, err = runCLI(t, server, "ls", "--status", "PENDING", "--json")
+	require.NoError(t, err)
+	var tasks []domain.Task
+	require.NoError(t, json.Unmarshal([]byte(out), &tasks))
+	require.Equal(t, []domain.Task{getTestTask()}, tasks)
+
+	out, err = runCLI(t, server, "ls", "--status", "DONE", "-o", "yaml")
+	require.NoError(t, err)
+	require.Equal(t, `- id: 1461ec84-ccff-4f3c-af34-65d0856ac3cf
+  title: Do unit tests
+  description: Create extensive unit tests for all layers
+  status: DONE
+  due_date: "2025-04-03T00:00:00Z"
+  created_at: "2025-04-01T00:00:00Z"
+`, out)
+
+	out, err = runCLI(t, server, "ls", "--status", "ARCHIVED", "--json")
+	require.NoError(t, err)
+	require.Equal(t, "[]\n", out)
+}
+
+func TestShowAndDone(t *testing.T) {
+	api, server := newFakeAPI(t, getTestTask())
+	id := getTestTask().ID.String()
+
+	out, err := runCLI(t, server, "show", id, "--json")
+	require.NoError(t, err)
+	var task domain.Task
+	require.NoError(t, json.Unmarshal([]byte(out), &task))
+	require.Equal(t, getTestTask(), task)
+
+	out, err = runCLI(t, server, "done", id)
+	require.NoError(t, err)
+	require.Equal(t, "Done: Do unit tests\n", out)
+	require.Equal(t, domain.StatusDone, api.tasks[getTestTask().ID].Status)
+	require.Equal(t, getTestTask().Description, api.tasks[getTestTask().ID].Description)
+
+	_, err = runCLI(t, server, "done", "1461ec84-ccff-4f3c-af34-65d0856ac3cf")
+	require.EqualError(t, err, "task 1461ec84-ccff-4f3c-af34-65d0856ac3cf not found")
+
+	_, err = runCLI(t, server, "show", "42")
+	require.EqualError(t, err, `invalid task id "42"`)
+}
+
+func TestOutputFormatCheckedFirst(t *testing.T) {
+	api, server := newFakeAPI(t)
+
+	_, err := runCLI(t, server, "add", "Water the plants", "-o", "xml")
+	require.ErrorContains(t, err, `unknown output format "xml"`)
+	require.Empty(t, api.tasks)
+}
+
+func TestConfig(t *testing.T) {
+	api, server := newFakeAPI(t)
+	path := filepath.Join(t.TempDir(), ".tasks.yaml")
+	t.Setenv("TASKS_SERVER", "")
+	t.Setenv("TASKS_TOKEN", "")
+
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		cmd := newRootCmd(func() time.Time { return testNow })
+		cmd.SetOut(&out)
+		cmd.SetArgs(append([]string{"--config", path}, args...))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	_, err := run("config", "set", "server", server)
+	require.NoError(t, err)
+	_, err = run("config", "set", "token", "secret")
+	require.NoError(t, err)
+	_, err = run("config", "set", "color", "blue")
+	require.ErrorContains(t, err, `unknown config key "color"`)
+
+	out, err := run("config", "get", "server")
+	require.NoError(t, err)
+	require.Equal(t, server+"\n", out)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// The server and token come from the config file.
+	_, err = run("ls")
+	require.NoError(t, err)
+	require.Equal(t, "Bearer secret", api.authorization)
+
+	// The flag wins over the config file.
+	_, err = run("ls", "--token", "other")
+	require.NoError(t, err)
+	require.Equal(t, "Bearer other", api.authorization)
+}
+
+func TestCompletion(t *testing.T) {
+	_, server := newFakeAPI(t, getTestTask())
+
+	out, err := runCLI(t, server, "__complete", "show", "1461")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(out, "1461ec84-ccff-4f3c-af34-65d0856ac3ce\tDo unit tests\n"), out)
+
+	out, err = runCLI(t, server, "completion", "zsh")
+	require.NoError(t, err)
+	require.Contains(t, out, "#compdef tasks")
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	defaultServer = "http://localhost:8080"
+	configFile    = ".tasks.yaml"
+)
+
+// cliConfig is kept in ~/.tasks.yaml. The TASKS_SERVER and TASKS_TOKEN env vars and
+// the --server and --token flags take precedence over it.
+type cliConfig struct {
+	Server string `yaml:"server,omitempty"`
+	Token  string `yaml:"token,omitempty"`
+}
+
+var configKeys = []string{"server", "token"}
+
+func defaultConfigPath() string {
+	if path := os.Getenv("TASKS_CONFIG"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return configFile
+	}
+	return filepath.Join(home, configFile)
+}
+
+// loadConfig reads the config at path. A missing file is not an error.
+func loadConfig(path string) (cliConfig, error) {
+	var conf cliConfig
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return conf, nil
+	}
+	if err != nil {
+		return conf, fmt.Errorf("error reading config: %v", err)
+	}
+	if err := yaml.Unmarshal(data, &conf); err != nil {
+		return conf, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	return conf, nil
+}
+
+// saveConfig writes the config readable by the user only, as it may hold a token.
+func saveConfig(path string, conf cliConfig) error {
+	data, err := yaml.Marshal(conf)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("error writing config: %v", err)
+	}
+	return nil
+}
+
+func (c *cliConfig) get(key string) (string, error) {
+	switch key {
+	case "server":
+		return c.Server, nil
+	case "token":
+		return c.Token, nil
+	default:
+		return "", fmt.Errorf("unknown config key %q, use one of %v", key, configKeys)
+	}
+}
+
+func (c *cliConfig) set(key, value string) error {
+	switch key {
+	case "server":
+		c.Server = value
+	case "token":
+		c.Token = value
+	default:
+		return fmt.Errorf("unknown config key %q, use one of %v", key, configKeys)
+	}
+	return nil
+}
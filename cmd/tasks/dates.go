@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parseDate reads a due date given either as a date ("2025-05-12", "2025-05-12 15:04",
+// RFC 3339) or relative to now: "today", "tomorrow", "yesterday", weekday names
+// ("friday", "next friday"), "in 3 days", "+2w" and the like. Dates without a time
+// of day fall on midnight in the zone of now.
+func parseDate(value string, now time.Time) (time.Time, error) {
+	input := strings.ToLower(strings.Join(strings.Fields(value), " "))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch input {
+	case "":
+		return time.Time{}, fmt.Errorf("empty date")
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	if weekday, ok := parseWeekday(strings.TrimPrefix(input, "next ")); ok {
+		days := (int(weekday) - int(today.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return today.AddDate(0, 0, days), nil
+	}
+
+	if n, unit, ok := parseOffset(input); ok {
+		switch unit {
+		case "h":
+			return now.Add(time.Duration(n) * time.Hour), nil
+		case "d":
+			return today.AddDate(0, 0, n), nil
+		case "w":
+			return today.AddDate(0, 0, 7*n), nil
+		case "m":
+			return today.AddDate(0, n, 0), nil
+		case "y":
+			return today.AddDate(n, 0, 0), nil
+		}
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, strings.ToUpper(input), now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown date %q, use e.g. today, tomorrow, friday, in 3 days or 2025-05-12", value)
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+func parseWeekday(input string) (time.Weekday, bool) {
+	weekday, ok := weekdays[input]
+	return weekday, ok
+}
+
+var offsetUnits = map[string]string{
+	"h": "h", "hour": "h", "hours": "h",
+	"d": "d", "day": "d", "days": "d",
+	"w": "w", "week": "w", "weeks": "w",
+	"m": "m", "month": "m", "months": "m",
+	"y": "y", "year": "y", "years": "y",
+}
+
+// parseOffset reads "in 3 days", "3 days ago", "+3d" and "-1w".
+func parseOffset(input string) (int, string, bool) {
+	sign := 1
+	switch {
+	case strings.HasPrefix(input, "in "):
+		input = strings.TrimPrefix(input, "in ")
+	case strings.HasSuffix(input, " ago"):
+		input = strings.TrimSuffix(input, " ago")
+		sign = -1
+	case strings.HasPrefix(input, "+"):
+		input = input[1:]
+	case strings.HasPrefix(input, "-"):
+		input = input[1:]
+		sign = -1
+	default:
+		return 0, "", false
+	}
+
+	digits := strings.TrimRight(input, "abcdefghijklmnopqrstuvwxyz ")
+	n, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0, "", false
+	}
+	unit, ok := offsetUnits[strings.TrimSpace(input[len(digits):])]
+	if !ok {
+		return 0, "", false
+	}
+	return sign * n, unit, true
+}
+
+// formatDate shows dates close to now relative to it and others as a plain date.
+func formatDate(t, now time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	t = t.In(now.Location())
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, now.Location())
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	days := int(day.Sub(today).Round(24*time.Hour) / (24 * time.Hour))
+
+	suffix := ""
+	if t.Hour() != 0 || t.Minute() != 0 {
+		suffix = " " + t.Format("15:04")
+	}
+	switch {
+	case days == 0:
+		return "today" + suffix
+	case days == 1:
+		return "tomorrow" + suffix
+	case days == -1:
+		return "yesterday" + suffix
+	case days > 1 && days < 7:
+		return "in " + strconv.Itoa(days) + " days"
+	case days < -1 && days > -7:
+		return strconv.Itoa(-days) + " days ago"
+	default:
+		return t.Format("2006-01-02")
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	sofia, err := time.LoadLocation("Europe/Sofia")
+	require.NoError(t, err)
+	// A Wednesday afternoon.
+	now := time.Date(2025, 4, 2, 15, 30, 0, 0, sofia)
+
+	tests := []struct {
+		input    string
+		expected time.Time
+	}{
+		{input: "today", expected: time.Date(2025, 4, 2, 0, 0, 0, 0, sofia)},
+		{input: "Tomorrow", expected: time.Date(2025, 4, 3, 0, 0, 0, 0, sofia)},
+		{input: "yesterday", expected: time.Date(2025, 4, 1, 0, 0, 0, 0, sofia)},
+		{input: "friday", expected: time.Date(2025, 4, 4, 0, 0, 0, 0, sofia)},
+		{input: "next mon", expected: time.Date(2025, 4, 7, 0, 0, 0, 0, sofia)},
+		{input: "wednesday", expected: time.Date(2025, 4, 9, 0, 0, 0, 0, sofia)},
+		{input: "in 3 days", expected: time.Date(2025, 4, 5, 0, 0, 0, 0, sofia)},
+		{input: "in  1 week", expected: time.Date(2025, 4, 9, 0, 0, 0, 0, sofia)},
+		{input: "+2w", expected: time.Date(2025, 4, 16, 0, 0, 0, 0, sofia)},
+		{input: "+1m", expected: time.Date(2025, 5, 2, 0, 0, 0, 0, sofia)},
+		{input: "2 days ago", expected: time.Date(2025, 3, 31, 0, 0, 0, 0, sofia)},
+		{input: "-1d", expected: time.Date(2025, 4, 1, 0, 0, 0, 0, sofia)},
+		{input: "in 2 hours", expected: time.Date(2025, 4, 2, 17, 30, 0, 0, sofia)},
+		{input: "2025-05-12", expected: time.Date(2025, 5, 12, 0, 0, 0, 0, sofia)},
+		{input: "2025-05-12 09:15", expected: time.Date(2025, 5, 12, 9, 15, 0, 0, sofia)},
+		{input: "2025-05-12T09:15:00Z", expected: time.Date(2025, 5, 12, 9, 15, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			actual, err := parseDate(tt.input, now)
+			require.NoError(t, err)
+			require.True(t, tt.expected.Equal(actual), "expected %v, got %v", tt.expected, actual)
+		})
+	}
+}
+
+func TestParseDate_Invalid(t *testing.T) {
+	now := time.Date(2025, 4, 2, 15, 30, 0, 0, time.UTC)
+	for _, input := range []string{"", "someday", "in three days", "+3x", "2025-13-01", "next"} {
+		t.Run(input, func(t *testing.T) {
+			_, err := parseDate(input, now)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	now := time.Date(2025, 4, 2, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		date     time.Time
+		expected string
+	}{
+		{date: time.Time{}, expected: "-"},
+		{date: time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC), expected: "today"},
+		{date: time.Date(2025, 4, 3, 9, 15, 0, 0, time.UTC), expected: "tomorrow 09:15"},
+		{date: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), expected: "yesterday"},
+		{date: time.Date(2025, 4, 6, 0, 0, 0, 0, time.UTC), expected: "in 4 days"},
+		{date: time.Date(2025, 3, 29, 0, 0, 0, 0, time.UTC), expected: "4 days ago"},
+		{date: time.Date(2025, 5, 12, 0, 0, 0, 0, time.UTC), expected: "2025-05-12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			require.Equal(t, tt.expected, formatDate(tt.date, now))
+		})
+	}
+}
@@ -0,0 +1,23 @@
+// Command tasks manages tasks from the terminal through the HTTP API.
+//
+//	tasks add "Do unit tests" --due tomorrow
+//	tasks ls --status PENDING --json
+//	tasks show <id>
+//	tasks done <id>
+//
+// The server URL and token are read from ~/.tasks.yaml, see "tasks config --help".
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+func main() {
+	cmd := newRootCmd(time.Now)
+	if err := cmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
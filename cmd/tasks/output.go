@@ -0,0 +1,108 @@
+package main
+
+import (
+	"api/domain"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+var outputFormats = []string{outputTable, outputJSON, outputYAML}
+
+// printTasks writes tasks in the given format. JSON and YAML carry the tasks exactly as
+// the API returns them, while the table is meant for people and shows dates relative
+// to now.
+func printTasks(w io.Writer, format string, tasks []domain.Task, now time.Time) error {
+	switch format {
+	case outputJSON:
+		return printJSON(w, tasks)
+	case outputYAML:
+		return printYAML(w, tasks)
+	case outputTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tSTATUS\tDUE\tTITLE")
+		for _, task := range tasks {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", task.ID, task.Status, formatDate(task.DueDate, now), oneLine(task.Title))
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q, use one of %v", format, outputFormats)
+	}
+}
+
+// printTask writes a single task, as a list of its fields in the table format.
+func printTask(w io.Writer, format string, task domain.Task, now time.Time) error {
+	switch format {
+	case outputJSON:
+		return printJSON(w, task)
+	case outputYAML:
+		return printYAML(w, task)
+	case outputTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "ID:\t%s\n", task.ID)
+		fmt.Fprintf(tw, "Title:\t%s\n", oneLine(task.Title))
+		fmt.Fprintf(tw, "Status:\t%s\n", task.Status)
+		fmt.Fprintf(tw, "Due:\t%s\n", formatDate(task.DueDate, now))
+		if task.RRule != "" {
+			fmt.Fprintf(tw, "Repeats:\t%s\n", task.RRule)
+		}
+		fmt.Fprintf(tw, "Created:\t%s\n", formatDate(task.CreatedAt, now))
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		if task.Description != "" {
+			fmt.Fprintf(w, "\n%s\n", task.Description)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q, use one of %v", format, outputFormats)
+	}
+}
+
+func printJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// printYAML converts the JSON of v, so that YAML uses the same field names and order.
+func printYAML(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// blockStyle drops the flow style and quotes the JSON was parsed with.
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -3,7 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type builder struct {
@@ -28,6 +30,90 @@ func (cb *builder) getString(name string, defaultValue ...string) string {
 	return envVar
 }
 
+// getStrings reads a comma separated list, dropping empty entries.
+func (cb *builder) getStrings(name string, defaultValue ...string) []string {
+	var values []string
+	for _, value := range strings.Split(cb.getString(name, defaultValue...), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func (cb *builder) getInt(name string, defaultValue ...int) int {
+	envVar := os.Getenv(name)
+	if envVar == "" {
+		if len(defaultValue) == 0 {
+			cb.errors = append(cb.errors, fmt.Sprintf("Missing value for %s", name))
+			return 0
+		}
+		return defaultValue[0]
+	}
+	value, err := strconv.Atoi(envVar)
+	if err != nil {
+		cb.errors = append(cb.errors, fmt.Sprintf("Invalid integer for %s: %s", name, envVar))
+		return 0
+	}
+	return value
+}
+
+func (cb *builder) getBool(name string, defaultValue ...bool) bool {
+	envVar := os.Getenv(name)
+	if envVar == "" {
+		if len(defaultValue) == 0 {
+			cb.errors = append(cb.errors, fmt.Sprintf("Missing value for %s", name))
+			return false
+		}
+		return defaultValue[0]
+	}
+	value, err := strconv.ParseBool(envVar)
+	if err != nil {
+		cb.errors = append(cb.errors, fmt.Sprintf("Invalid boolean for %s: %s", name, envVar))
+		return false
+	}
+	return value
+}
+
+func (cb *builder) getDuration(name string, defaultValue ...time.Duration) time.Duration {
+	envVar := os.Getenv(name)
+	if envVar == "" {
+		if len(defaultValue) == 0 {
+			cb.errors = append(cb.errors, fmt.Sprintf("Missing value for %s", name))
+			return 0
+		}
+		return defaultValue[0]
+	}
+	value, err := time.ParseDuration(envVar)
+	if err != nil {
+		cb.errors = append(cb.errors, fmt.Sprintf("Invalid duration for %s: %s", name, envVar))
+		return 0
+	}
+	return value
+}
+
+// getPositiveInt is getInt for settings which are meaningless, or worse, when zero or
+// negative.
+func (cb *builder) getPositiveInt(name string, defaultValue ...int) int {
+	errorCount := len(cb.errors)
+	value := cb.getInt(name, defaultValue...)
+	if value <= 0 && len(cb.errors) == errorCount {
+		cb.errors = append(cb.errors, fmt.Sprintf("Invalid value for %s: must be positive", name))
+	}
+	return value
+}
+
+// getPositiveDuration is getDuration for settings which are meaningless, or worse, when
+// zero or negative.
+func (cb *builder) getPositiveDuration(name string, defaultValue ...time.Duration) time.Duration {
+	errorCount := len(cb.errors)
+	value := cb.getDuration(name, defaultValue...)
+	if value <= 0 && len(cb.errors) == errorCount {
+		cb.errors = append(cb.errors, fmt.Sprintf("Invalid value for %s: must be positive", name))
+	}
+	return value
+}
+
 func (cb *builder) getError() error {
 	if len(cb.errors) == 0 {
 		return nil
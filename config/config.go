@@ -1,16 +1,169 @@
 package config
 
+import "time"
+
 type Config struct {
 	ApiPort         string
+	GrpcPort        string
 	DbConnectionUrl string
+	DevMode         bool
+
+	DbConnectTimeout    time.Duration
+	DbConnectBackoff    time.Duration
+	DbConnectMaxBackoff time.Duration
+	DbMaxOpenConns      int
+	DbMaxIdleConns      int
+	DbConnMaxLifetime   time.Duration
+	DbConnMaxIdleTime   time.Duration
+	DbStatementTimeout  time.Duration
+	DbBreakerFailures   int
+	DbBreakerCooldown   time.Duration
+
+	DbReplicaUrls           []string
+	DbReplicaHealthInterval time.Duration
+	ReadYourWritesWindow    time.Duration
+
+	Storage        string
+	StorageFixture string
+	SqlitePath     string
+
+	TaskCache     string
+	TaskCacheSize int
+	TaskCacheTTL  time.Duration
+	RedisUrl      string
+
+	OutboxPublisher    string
+	OutboxFilePath     string
+	OutboxPollInterval time.Duration
+	OutboxBatchSize    int
+	NatsUrl            string
+	NatsSubject        string
+
+	StreamHeartbeatInterval time.Duration
+	StreamReplaySize        int
+
+	SearchLanguage string
+
+	AdminToken          string
+	TrashRetention      time.Duration
+	TrashPurgeInterval  time.Duration
+	TrashPurgeBatchSize int
+
+	GraphQLMaxDepth      int
+	GraphQLMaxComplexity int
+
+	RateLimitStore         string
+	RateLimitPolicies      string
+	RateLimitIPGuard       string
+	RateLimitSweepInterval time.Duration
+
+	CorsAllowedOrigins   []string
+	CorsAllowedMethods   []string
+	CorsAllowedHeaders   []string
+	CorsAllowCredentials bool
+	CorsMaxAge           time.Duration
+
+	HstsMaxAge            time.Duration
+	ContentSecurityPolicy string
+	MaxBodySize           int64
+	MaxImportBodySize     int64
+
+	CompressionMinSize int
+
+	TenantResolution   []string
+	TenantBaseDomain   string
+	TenantTokenSecret  string
+	TenantTrustedProxy bool
 }
 
+// defaultContentSecurityPolicy fits the Swagger UI and GraphiQL pages, which load their
+// scripts and styles from CDNs and start with an inline script.
+const defaultContentSecurityPolicy = "default-src 'self'; " +
+	"script-src 'self' 'unsafe-inline' https://unpkg.com https://cdn.jsdelivr.net; " +
+	"style-src 'self' 'unsafe-inline' https://unpkg.com https://cdn.jsdelivr.net; " +
+	"img-src 'self' data: https:; font-src 'self' data: https://cdn.jsdelivr.net; " +
+	"connect-src 'self'; frame-ancestors 'none'; base-uri 'self'; form-action 'self'"
+
 func FromEnv() (*Config, error) {
 	var conf Config
 	cb := newBuilder()
 
 	conf.ApiPort = cb.getString("API_PORT")
-	conf.DbConnectionUrl = cb.getString("DB_CONNECTION_URL")
+	conf.GrpcPort = cb.getString("GRPC_PORT", "")
+	conf.DevMode = cb.getBool("DEV_MODE", false)
+
+	conf.Storage = cb.getString("STORAGE", "postgres")
+	conf.StorageFixture = cb.getString("STORAGE_FIXTURE", "")
+	conf.SqlitePath = cb.getString("SQLITE_PATH", "tasks.db")
+	// Only Postgres needs a connection, the other storages run within the process.
+	if conf.Storage == "postgres" {
+		conf.DbConnectionUrl = cb.getString("DB_CONNECTION_URL")
+	} else {
+		conf.DbConnectionUrl = cb.getString("DB_CONNECTION_URL", "")
+	}
+
+	conf.DbConnectTimeout = cb.getDuration("DB_CONNECT_TIMEOUT", time.Minute)
+	conf.DbConnectBackoff = cb.getDuration("DB_CONNECT_BACKOFF", 500*time.Millisecond)
+	conf.DbConnectMaxBackoff = cb.getDuration("DB_CONNECT_MAX_BACKOFF", 10*time.Second)
+	conf.DbMaxOpenConns = cb.getInt("DB_MAX_OPEN_CONNS", 25)
+	conf.DbMaxIdleConns = cb.getInt("DB_MAX_IDLE_CONNS", 25)
+	conf.DbConnMaxLifetime = cb.getDuration("DB_CONN_MAX_LIFETIME", 30*time.Minute)
+	conf.DbConnMaxIdleTime = cb.getDuration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute)
+	conf.DbStatementTimeout = cb.getDuration("DB_STATEMENT_TIMEOUT", 30*time.Second)
+	conf.DbBreakerFailures = cb.getPositiveInt("DB_BREAKER_FAILURES", 5)
+	conf.DbBreakerCooldown = cb.getPositiveDuration("DB_BREAKER_COOLDOWN", 10*time.Second)
+
+	conf.DbReplicaUrls = cb.getStrings("DB_REPLICA_URLS", "")
+	conf.DbReplicaHealthInterval = cb.getPositiveDuration("DB_REPLICA_HEALTH_INTERVAL", 5*time.Second)
+	conf.ReadYourWritesWindow = cb.getDuration("READ_YOUR_WRITES_WINDOW", 5*time.Second)
+
+	conf.TaskCache = cb.getString("TASK_CACHE", "none")
+	conf.TaskCacheSize = cb.getInt("TASK_CACHE_SIZE", 10000)
+	conf.TaskCacheTTL = cb.getDuration("TASK_CACHE_TTL", time.Minute)
+	conf.RedisUrl = cb.getString("REDIS_URL", "redis://localhost:6379/0")
+
+	conf.OutboxPublisher = cb.getString("OUTBOX_PUBLISHER", "none")
+	conf.OutboxFilePath = cb.getString("OUTBOX_FILE_PATH", "events.ndjson")
+	conf.OutboxPollInterval = cb.getPositiveDuration("OUTBOX_POLL_INTERVAL", time.Second)
+	conf.OutboxBatchSize = cb.getPositiveInt("OUTBOX_BATCH_SIZE", 100)
+	conf.NatsUrl = cb.getString("NATS_URL", "nats://localhost:4222")
+	conf.NatsSubject = cb.getString("NATS_SUBJECT", "tasks.events")
+
+	conf.StreamHeartbeatInterval = cb.getPositiveDuration("STREAM_HEARTBEAT_INTERVAL", 15*time.Second)
+	conf.StreamReplaySize = cb.getInt("STREAM_REPLAY_SIZE", 256)
+
+	conf.SearchLanguage = cb.getString("SEARCH_LANGUAGE", "english")
+
+	conf.AdminToken = cb.getString("ADMIN_TOKEN", "")
+	conf.TrashRetention = cb.getDuration("TRASH_RETENTION", 30*24*time.Hour)
+	conf.TrashPurgeInterval = cb.getPositiveDuration("TRASH_PURGE_INTERVAL", time.Hour)
+	conf.TrashPurgeBatchSize = cb.getPositiveInt("TRASH_PURGE_BATCH_SIZE", 500)
+
+	conf.GraphQLMaxDepth = cb.getInt("GRAPHQL_MAX_DEPTH", 10)
+	conf.GraphQLMaxComplexity = cb.getInt("GRAPHQL_MAX_COMPLEXITY", 1000)
+
+	conf.RateLimitStore = cb.getString("RATE_LIMIT_STORE", "memory")
+	conf.RateLimitPolicies = cb.getString("RATE_LIMIT_POLICIES", "GET /api/tasks 60/1m ip, /api/* 600/1m ip")
+	conf.RateLimitIPGuard = cb.getString("RATE_LIMIT_IP_GUARD", "1200/1m")
+	conf.RateLimitSweepInterval = cb.getPositiveDuration("RATE_LIMIT_SWEEP_INTERVAL", time.Minute)
+
+	conf.CorsAllowedOrigins = cb.getStrings("CORS_ALLOWED_ORIGINS", "")
+	conf.CorsAllowedMethods = cb.getStrings("CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE")
+	conf.CorsAllowedHeaders = cb.getStrings("CORS_ALLOWED_HEADERS", "Accept,Authorization,Content-Type,Last-Event-ID,X-API-Key")
+	conf.CorsAllowCredentials = cb.getBool("CORS_ALLOW_CREDENTIALS", false)
+	conf.CorsMaxAge = cb.getDuration("CORS_MAX_AGE", 10*time.Minute)
+
+	conf.HstsMaxAge = cb.getDuration("HSTS_MAX_AGE", 365*24*time.Hour)
+	conf.ContentSecurityPolicy = cb.getString("CONTENT_SECURITY_POLICY", defaultContentSecurityPolicy)
+	conf.MaxBodySize = int64(cb.getInt("MAX_BODY_SIZE", 1<<20))
+	conf.MaxImportBodySize = int64(cb.getInt("MAX_IMPORT_BODY_SIZE", 32<<20))
+
+	conf.CompressionMinSize = cb.getInt("COMPRESSION_MIN_SIZE", 1024)
+
+	conf.TenantResolution = cb.getStrings("TENANT_RESOLUTION", "")
+	conf.TenantBaseDomain = cb.getString("TENANT_BASE_DOMAIN", "")
+	conf.TenantTokenSecret = cb.getString("TENANT_TOKEN_SECRET", "")
+	conf.TenantTrustedProxy = cb.getBool("TENANT_TRUSTED_PROXY", false)
 
 	cbError := cb.getError()
 	if cbError != nil {
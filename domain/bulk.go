@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidBulkOperation = errors.New("invalid bulk operation")
+	// ErrBulkRolledBack marks the operations of an atomic batch which were undone or
+	// not attempted because another operation of the batch failed.
+	ErrBulkRolledBack = errors.New("rolled back because another operation failed")
+)
+
+const (
+	BulkOpCreate = "create"
+	BulkOpUpdate = "update"
+	BulkOpDelete = "delete"
+	BulkOpStatus = "status"
+)
+
+// BulkOperation is a single item of a bulk request. Create and update carry the full
+// task, delete only its ID and status changes the ID and the new status.
+type BulkOperation struct {
+	Op     string    `json:"op"`
+	ID     uuid.UUID `json:"id"`
+	Task   *Task     `json:"task,omitempty"`
+	Status string    `json:"status,omitempty"`
+}
+
+// BulkResult is the outcome of the operation at the same index of a bulk request.
+type BulkResult struct {
+	Task *Task
+	Err  error
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+var ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+
+// CalendarFeedsRepo stores calendar feeds by the hash of their secret token; the token
+// itself is only ever known to the feed's owner.
+type CalendarFeedsRepo interface {
+	CreateFeed(ctx context.Context, feed CalendarFeed, tokenHash string) (CalendarFeed, error)
+	GetFeedByTokenHash(ctx context.Context, tokenHash string) (CalendarFeed, error)
+	RotateFeedToken(ctx context.Context, tokenHash, newTokenHash string) (CalendarFeed, error)
+	DeleteFeed(ctx context.Context, tokenHash string) error
+}
+
+// CalendarFeed is a personal calendar subscription to the tasks matching its filter.
+type CalendarFeed struct {
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	Status    string     `json:"status,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
+}
+
+func (f CalendarFeed) Filter() TaskFilter {
+	return TaskFilter{Status: f.Status}
+}
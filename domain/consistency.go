@@ -0,0 +1,18 @@
+package domain
+
+import "context"
+
+type readYourWritesCtxKey struct{}
+
+// WithReadYourWrites returns a copy of ctx whose reads have to see every write committed
+// before, so they are not served by a read replica which may lag behind.
+func WithReadYourWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readYourWritesCtxKey{}, true)
+}
+
+// ReadYourWritesFromContext tells whether the reads of ctx have to see every write
+// committed before.
+func ReadYourWritesFromContext(ctx context.Context) bool {
+	readYourWrites, _ := ctx.Value(readYourWritesCtxKey{}).(bool)
+	return readYourWrites
+}
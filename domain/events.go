@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventTaskCreated  EventType = "TaskCreated"
+	EventTaskUpdated  EventType = "TaskUpdated"
+	EventTaskDeleted  EventType = "TaskDeleted"
+	EventTaskRestored EventType = "TaskRestored"
+)
+
+// Publisher delivers outbox events to an external stream. Delivery is at-least-once,
+// so consumers are expected to deduplicate on Event.ID.
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+	Close() error
+}
+
+type Event struct {
+	ID          uuid.UUID       `json:"id"`
+	Type        EventType       `json:"type"`
+	AggregateID uuid.UUID       `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+}
+
+// TaskChange is a live notification about a committed task mutation. ID is ordered
+// consistently across API replicas and is used to resume a stream. Changes are only
+// delivered to subscribers of the organization in OrgID. PreviousStatus is the status
+// an updated task had before, so that subscribers filtering by status learn about the
+// tasks leaving their filter.
+type TaskChange struct {
+	ID             string    `json:"id"`
+	Type           EventType `json:"type"`
+	Task           Task      `json:"task"`
+	OrgID          uuid.UUID `json:"-"`
+	PreviousStatus string    `json:"-"`
+}
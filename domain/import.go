@@ -0,0 +1,37 @@
+package domain
+
+import "errors"
+
+var (
+	// ErrInvalidImport is returned for input which cannot be read any further.
+	ErrInvalidImport = errors.New("invalid import")
+	// ErrInvalidImportRow is returned for a single row which cannot be parsed; the rows
+	// after it are still read.
+	ErrInvalidImportRow = errors.New("invalid row")
+)
+
+// TaskSource yields the tasks of an import one at a time, so that the input never has
+// to be held in memory as a whole.
+type TaskSource interface {
+	// Next returns the next task together with the line it was read from. It returns
+	// io.EOF after the last task and an error wrapping ErrInvalidImportRow for a row
+	// which cannot be parsed.
+	Next() (line int, task Task, err error)
+}
+
+type ImportError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// ImportReport summarises an import. Rows whose task id already exists, in the
+// database or earlier in the input, are skipped. Errors lists at most the first
+// thousand invalid rows; Failed counts all of them.
+type ImportReport struct {
+	DryRun   bool          `json:"dry_run"`
+	Total    int           `json:"total"`
+	Imported int           `json:"imported"`
+	Skipped  int           `json:"skipped"`
+	Failed   int           `json:"failed"`
+	Errors   []ImportError `json:"errors"`
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrOrganizationExists   = errors.New("organization already exists")
+	ErrInvalidOrganization  = errors.New("invalid organization")
+	// ErrNoOrganization is returned by the repos for contexts without an organization,
+	// which would otherwise see no rows at all.
+	ErrNoOrganization = errors.New("no organization in context")
+)
+
+// DefaultOrganizationID is the organization of single-tenant deployments. Tasks created
+// before organizations existed belong to it as well.
+var DefaultOrganizationID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// organizationSlugPattern keeps slugs usable as subdomains.
+var organizationSlugPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// OrganizationsRepo looks up organizations outside of any tenant, as it is used to find
+// the tenant of a request in the first place.
+type OrganizationsRepo interface {
+	GetOrganizationBySlug(ctx context.Context, slug string) (Organization, error)
+	CreateOrganization(ctx context.Context, org Organization) (Organization, error)
+}
+
+// Organization is a tenant. Its tasks, events and calendar feeds are invisible to every
+// other organization.
+type Organization struct {
+	ID        uuid.UUID `json:"id"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (o Organization) Validate() error {
+	if !organizationSlugPattern.MatchString(o.Slug) {
+		return fmt.Errorf("%w: slug must be lower case letters, digits and dashes, at most 63 characters", ErrInvalidOrganization)
+	}
+	if strings.TrimSpace(o.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidOrganization)
+	}
+	return nil
+}
+
+type organizationCtxKey struct{}
+
+// WithOrganization returns a copy of ctx scoped to the organization with the given id.
+func WithOrganization(ctx context.Context, orgID uuid.UUID) context.Context {
+	return context.WithValue(ctx, organizationCtxKey{}, orgID)
+}
+
+// OrganizationFromContext returns the organization ctx is scoped to.
+func OrganizationFromContext(ctx context.Context) (uuid.UUID, bool) {
+	orgID, ok := ctx.Value(organizationCtxKey{}).(uuid.UUID)
+	return orgID, ok
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestOrganization_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		org   Organization
+		valid bool
+	}{
+		{name: "valid", org: Organization{Slug: "acme", Name: "Acme"}, valid: true},
+		{name: "digits and dashes", org: Organization{Slug: "acme-2", Name: "Acme"}, valid: true},
+		{name: "single character", org: Organization{Slug: "a", Name: "A"}, valid: true},
+		{name: "longest subdomain", org: Organization{Slug: strings.Repeat("a", 63), Name: "Acme"}, valid: true},
+		{name: "too long", org: Organization{Slug: strings.Repeat("a", 64), Name: "Acme"}},
+		{name: "upper case", org: Organization{Slug: "Acme", Name: "Acme"}},
+		{name: "leading dash", org: Organization{Slug: "-acme", Name: "Acme"}},
+		{name: "trailing dash", org: Organization{Slug: "acme-", Name: "Acme"}},
+		{name: "dot", org: Organization{Slug: "acme.inc", Name: "Acme"}},
+		{name: "empty slug", org: Organization{Name: "Acme"}},
+		{name: "blank name", org: Organization{Slug: "acme", Name: "  "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.org.Validate()
+			if tt.valid {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrInvalidOrganization)
+		})
+	}
+}
+
+func TestOrganizationFromContext(t *testing.T) {
+	_, ok := OrganizationFromContext(context.Background())
+	require.False(t, ok)
+
+	orgID, ok := OrganizationFromContext(WithOrganization(context.Background(), DefaultOrganizationID))
+	require.True(t, ok)
+	require.Equal(t, DefaultOrganizationID, orgID)
+}
@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRateLimitPolicy = errors.New("invalid rate limit policy")
+
+// RateLimitStore keeps a token bucket per key. Take has to be atomic for a key, as the
+// concurrent requests of a client race for the same tokens.
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, policy RateLimitPolicy, now time.Time) (RateLimitResult, error)
+}
+
+// RateLimitIdentity selects what a policy counts requests by. Requests without an API
+// key or a bearer token are counted by their IP.
+type RateLimitIdentity string
+
+const (
+	IdentityIP     RateLimitIdentity = "ip"
+	IdentityAPIKey RateLimitIdentity = "api_key"
+	IdentityUser   RateLimitIdentity = "user"
+)
+
+// RateLimitPolicy lets every client make Limit requests per Window to the routes it
+// matches. Unused requests accumulate up to Limit, so a client can burst after being
+// idle. Pattern uses the syntax of the chi routes: {param} matches a single segment
+// and a trailing * matches the rest of the path.
+type RateLimitPolicy struct {
+	Method   string
+	Pattern  string
+	Limit    int
+	Window   time.Duration
+	Identity RateLimitIdentity
+}
+
+// TokenBucket is the state of a client under a policy.
+type TokenBucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long the bucket takes to be full again.
+	Reset time.Duration
+	// RetryAfter is how long a rejected client has to wait for the next token.
+	RetryAfter time.Duration
+}
+
+// ParseRateLimitPolicies parses a comma separated list of policies, each written as
+// "[METHOD] PATTERN LIMIT/WINDOW [IDENTITY]", e.g. "GET /api/tasks 60/1m ip". The
+// identity defaults to ip.
+func ParseRateLimitPolicies(spec string) ([]RateLimitPolicy, error) {
+	var policies []RateLimitPolicy
+	for _, entry := range strings.Split(spec, ",") {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 {
+			continue
+		}
+
+		policy := RateLimitPolicy{Identity: IdentityIP}
+		if !strings.HasPrefix(fields[0], "/") {
+			policy.Method = strings.ToUpper(fields[0])
+			fields = fields[1:]
+		}
+		if len(fields) < 2 || len(fields) > 3 || !strings.HasPrefix(fields[0], "/") {
+			return nil, fmt.Errorf("%w: %q is not [METHOD] PATTERN LIMIT/WINDOW [IDENTITY]", ErrInvalidRateLimitPolicy, strings.TrimSpace(entry))
+		}
+		policy.Pattern = fields[0]
+
+		var err error
+		if policy.Limit, policy.Window, err = parseRate(fields[1]); err != nil {
+			return nil, err
+		}
+
+		if len(fields) == 3 {
+			policy.Identity = RateLimitIdentity(fields[2])
+			switch policy.Identity {
+			case IdentityIP, IdentityAPIKey, IdentityUser:
+			default:
+				return nil, fmt.Errorf("%w: unknown identity %q", ErrInvalidRateLimitPolicy, fields[2])
+			}
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+// ParseRateLimitGuard parses the policy every request carrying an API key or a bearer
+// token is counted by its IP with, written as "LIMIT/WINDOW", e.g. "1200/1m". An empty
+// spec or "none" disables the guard and returns nil.
+func ParseRateLimitGuard(spec string) (*RateLimitPolicy, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" || spec == "none" {
+		return nil, nil
+	}
+	limit, window, err := parseRate(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &RateLimitPolicy{Pattern: "/*", Limit: limit, Window: window, Identity: IdentityIP}, nil
+}
+
+func parseRate(rate string) (int, time.Duration, error) {
+	limitSpec, windowSpec, ok := strings.Cut(rate, "/")
+	limit, err := strconv.Atoi(limitSpec)
+	if !ok || err != nil || limit < 1 {
+		return 0, 0, fmt.Errorf("%w: invalid limit %q", ErrInvalidRateLimitPolicy, rate)
+	}
+	window, err := time.ParseDuration(windowSpec)
+	if err != nil || window <= 0 {
+		return 0, 0, fmt.Errorf("%w: invalid window %q", ErrInvalidRateLimitPolicy, rate)
+	}
+	return limit, window, nil
+}
+
+func (p RateLimitPolicy) String() string {
+	method := p.Method
+	if method == "" {
+		method = "*"
+	}
+	return fmt.Sprintf("%s %s %d/%s %s", method, p.Pattern, p.Limit, p.Window, p.Identity)
+}
+
+// Matches reports whether the policy applies to a request.
+func (p RateLimitPolicy) Matches(method, path string) bool {
+	if p.Method != "" && p.Method != "*" && p.Method != method {
+		return false
+	}
+
+	patternSegments := strings.Split(strings.Trim(p.Pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range patternSegments {
+		if segment == "*" && i == len(patternSegments)-1 {
+			return len(pathSegments) > i
+		}
+		if i >= len(pathSegments) {
+			return false
+		}
+		isParam := strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+		if !isParam && segment != pathSegments[i] {
+			return false
+		}
+	}
+	return len(patternSegments) == len(pathSegments)
+}
+
+// Take refills the bucket for the time passed since it was last used and takes a
+// token from it, if there is one. A nil bucket is a client's first request.
+func (p RateLimitPolicy) Take(bucket *TokenBucket, now time.Time) (TokenBucket, RateLimitResult) {
+	capacity := float64(p.Limit)
+	perSecond := capacity / p.Window.Seconds()
+
+	tokens := capacity
+	if bucket != nil {
+		elapsed := max(now.Sub(bucket.UpdatedAt).Seconds(), 0)
+		tokens = min(capacity, bucket.Tokens+elapsed*perSecond)
+	}
+
+	result := RateLimitResult{Limit: p.Limit}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - tokens) / perSecond)
+	}
+	result.Remaining = int(math.Floor(tokens))
+	result.Reset = seconds((capacity - tokens) / perSecond)
+
+	return TokenBucket{Tokens: tokens, UpdatedAt: now}, result
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
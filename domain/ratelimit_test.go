@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimitPolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		expected []RateLimitPolicy
+		err      bool
+	}{
+		{name: "empty", spec: ""},
+		{
+			name: "several",
+			spec: "GET /api/tasks 60/1m, post /api/task 10/1s api_key,/api/* 600/1m user",
+			expected: []RateLimitPolicy{
+				{Method: "GET", Pattern: "/api/tasks", Limit: 60, Window: time.Minute, Identity: IdentityIP},
+				{Method: "POST", Pattern: "/api/task", Limit: 10, Window: time.Second, Identity: IdentityAPIKey},
+				{Pattern: "/api/*", Limit: 600, Window: time.Minute, Identity: IdentityUser},
+			},
+		},
+		{name: "missing rate", spec: "GET /api/tasks", err: true},
+		{name: "missing pattern", spec: "GET 60/1m", err: true},
+		{name: "zero limit", spec: "/api/* 0/1m", err: true},
+		{name: "invalid window", spec: "/api/* 60/minute", err: true},
+		{name: "unknown identity", spec: "/api/* 60/1m session", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policies, err := ParseRateLimitPolicies(tt.spec)
+			if tt.err {
+				require.ErrorIs(t, err, ErrInvalidRateLimitPolicy)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, policies)
+		})
+	}
+}
+
+func TestParseRateLimitGuard(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		expected *RateLimitPolicy
+		err      bool
+	}{
+		{name: "empty", spec: ""},
+		{name: "none", spec: "none"},
+		{
+			name:     "rate",
+			spec:     " 1200/1m ",
+			expected: &RateLimitPolicy{Pattern: "/*", Limit: 1200, Window: time.Minute, Identity: IdentityIP},
+		},
+		{name: "missing window", spec: "1200", err: true},
+		{name: "zero limit", spec: "0/1m", err: true},
+		{name: "invalid window", spec: "1200/minute", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard, err := ParseRateLimitGuard(tt.spec)
+			if tt.err {
+				require.ErrorIs(t, err, ErrInvalidRateLimitPolicy)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, guard)
+		})
+	}
+}
+
+func TestRateLimitPolicy_Matches(t *testing.T) {
+	tests := []struct {
+		policy   RateLimitPolicy
+		method   string
+		path     string
+		expected bool
+	}{
+		{policy: RateLimitPolicy{Method: "GET", Pattern: "/api/tasks"}, method: "GET", path: "/api/tasks", expected: true},
+		{policy: RateLimitPolicy{Method: "GET", Pattern: "/api/tasks"}, method: "POST", path: "/api/tasks"},
+		{policy: RateLimitPolicy{Method: "GET", Pattern: "/api/tasks"}, method: "GET", path: "/api/tasks/search"},
+		{policy: RateLimitPolicy{Pattern: "/api/task/{id}"}, method: "PUT", path: "/api/task/1461ec84", expected: true},
+		{policy: RateLimitPolicy{Pattern: "/api/task/{id}"}, method: "GET", path: "/api/task/1461ec84/occurrences"},
+		{policy: RateLimitPolicy{Pattern: "/api/*"}, method: "GET", path: "/api/task/1461ec84/occurrences", expected: true},
+		{policy: RateLimitPolicy{Pattern: "/api/*"}, method: "GET", path: "/caldav/tasks/"},
+		{policy: RateLimitPolicy{Method: "*", Pattern: "/*"}, method: "PROPFIND", path: "/caldav/tasks/", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy.Method+" "+tt.policy.Pattern+" "+tt.method+" "+tt.path, func(t *testing.T) {
+			require.Equal(t, tt.expected, tt.policy.Matches(tt.method, tt.path))
+		})
+	}
+}
+
+func TestRateLimitPolicy_Take(t *testing.T) {
+	policy := RateLimitPolicy{Pattern: "/api/*", Limit: 3, Window: 3 * time.Second}
+	now := time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)
+
+	bucket, result := policy.Take(nil, now)
+	require.Equal(t, RateLimitResult{Allowed: true, Limit: 3, Remaining: 2, Reset: time.Second}, result)
+
+	bucket, result = policy.Take(&bucket, now)
+	require.True(t, result.Allowed)
+	bucket, result = policy.Take(&bucket, now)
+	require.Equal(t, RateLimitResult{Allowed: true, Limit: 3, Remaining: 0, Reset: 3 * time.Second}, result)
+
+	bucket, result = policy.Take(&bucket, now.Add(500*time.Millisecond))
+	require.Equal(t, RateLimitResult{Allowed: false, Limit: 3, Remaining: 0, Reset: 2500 * time.Millisecond, RetryAfter: 500 * time.Millisecond}, result)
+
+	// A rejected request does not cost a token.
+	bucket, result = policy.Take(&bucket, now.Add(time.Second))
+	require.Equal(t, RateLimitResult{Allowed: true, Limit: 3, Remaining: 0, Reset: 3 * time.Second}, result)
+
+	// Idle clients get at most Limit tokens back.
+	_, result = policy.Take(&bucket, now.Add(time.Hour))
+	require.Equal(t, RateLimitResult{Allowed: true, Limit: 3, Remaining: 2, Reset: time.Second}, result)
+}
@@ -0,0 +1,500 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRecurrence = errors.New("invalid recurrence")
+
+type Frequency string
+
+const (
+	FreqDaily   Frequency = "DAILY"
+	FreqWeekly  Frequency = "WEEKLY"
+	FreqMonthly Frequency = "MONTHLY"
+	FreqYearly  Frequency = "YEARLY"
+)
+
+// maxEmptyPeriods stops the expansion of rules that can never produce another
+// occurrence, e.g. FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30 starting in February.
+const maxEmptyPeriods = 1000
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// WeekdayNum is a BYDAY entry. N selects the Nth occurrence of the weekday within the
+// month or year (negative values count from the end); zero selects all of them.
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+type untilKind int
+
+const (
+	untilUTC untilKind = iota
+	untilLocal
+	untilDate
+)
+
+// RRule is the subset of an RFC 5545 recurrence rule supported for recurring tasks:
+// FREQ, INTERVAL, BYDAY, BYMONTHDAY, COUNT and UNTIL. Weeks start on Monday.
+type RRule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	Count      int
+	Until      time.Time
+
+	untilKind untilKind
+}
+
+func ParseRRule(value string) (RRule, error) {
+	rule := RRule{Interval: 1}
+	seen := make(map[string]bool)
+
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		key = strings.ToUpper(key)
+		if !ok || val == "" {
+			return RRule{}, fmt.Errorf("%w: malformed rule part %q", ErrInvalidRecurrence, part)
+		}
+		if seen[key] {
+			return RRule{}, fmt.Errorf("%w: %s is set more than once", ErrInvalidRecurrence, key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			rule.Freq = Frequency(strings.ToUpper(val))
+			switch rule.Freq {
+			case FreqDaily, FreqWeekly, FreqMonthly, FreqYearly:
+			default:
+				err = fmt.Errorf("unsupported FREQ %s", val)
+			}
+		case "INTERVAL":
+			rule.Interval, err = parsePositive(key, val)
+		case "COUNT":
+			rule.Count, err = parsePositive(key, val)
+		case "UNTIL":
+			rule.Until, rule.untilKind, err = parseUntil(val)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(val)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseByMonthDay(val)
+		default:
+			err = fmt.Errorf("unsupported rule part %s", key)
+		}
+		if err != nil {
+			return RRule{}, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+		}
+	}
+
+	if err := rule.validate(); err != nil {
+		return RRule{}, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+	return rule, nil
+}
+
+func (r RRule) validate() error {
+	if r.Freq == "" {
+		return errors.New("FREQ is required")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return errors.New("COUNT and UNTIL cannot be combined")
+	}
+	if r.Freq == FreqWeekly && len(r.ByMonthDay) > 0 {
+		return errors.New("BYMONTHDAY cannot be used with FREQ=WEEKLY")
+	}
+	for _, day := range r.ByDay {
+		if day.N != 0 && r.Freq != FreqMonthly && r.Freq != FreqYearly {
+			return fmt.Errorf("numbered BYDAY is only allowed with FREQ=MONTHLY or FREQ=YEARLY")
+		}
+		if day.N > 53 || day.N < -53 || (r.Freq == FreqMonthly && (day.N > 5 || day.N < -5)) {
+			return fmt.Errorf("BYDAY position %d is out of range", day.N)
+		}
+	}
+	return nil
+}
+
+func parsePositive(key, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%s must be a positive integer", key)
+	}
+	return n, nil
+}
+
+func parseUntil(value string) (time.Time, untilKind, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, untilUTC, nil
+	}
+	if t, err := time.Parse("20060102T150405", value); err == nil {
+		return t, untilLocal, nil
+	}
+	if t, err := time.Parse("20060102", value); err == nil {
+		return t, untilDate, nil
+	}
+	return time.Time{}, 0, fmt.Errorf("invalid UNTIL %s", value)
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, item := range strings.Split(strings.ToUpper(value), ",") {
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid BYDAY %s", item)
+		}
+		weekday, ok := weekdayCodes[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid BYDAY %s", item)
+		}
+		n := 0
+		if prefix := item[:len(item)-2]; prefix != "" {
+			var err error
+			n, err = strconv.Atoi(prefix)
+			if err != nil || n == 0 {
+				return nil, fmt.Errorf("invalid BYDAY %s", item)
+			}
+		}
+		days = append(days, WeekdayNum{Weekday: weekday, N: n})
+	}
+	return days, nil
+}
+
+func parseByMonthDay(value string) ([]int, error) {
+	var days []int
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(item)
+		if err != nil || n == 0 || n > 31 || n < -31 {
+			return nil, fmt.Errorf("invalid BYMONTHDAY %s", item)
+		}
+		days = append(days, n)
+	}
+	return days, nil
+}
+
+// String formats the rule in its canonical RFC 5545 form.
+func (r RRule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		var days []string
+		for _, day := range r.ByDay {
+			code := strings.ToUpper(day.Weekday.String()[:2])
+			if day.N != 0 {
+				code = strconv.Itoa(day.N) + code
+			}
+			days = append(days, code)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		var days []string
+		for _, day := range r.ByMonthDay {
+			days = append(days, strconv.Itoa(day))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		switch r.untilKind {
+		case untilLocal:
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102T150405"))
+		case untilDate:
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+		default:
+			parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+// Occurrences returns up to limit occurrences of the rule, starting with start itself
+// when it matches. Occurrences keep the wall-clock time of start in its location, so a
+// daily 09:00 task stays at 09:00 across DST changes. Months that do not have the
+// requested day are skipped, as RFC 5545 requires.
+func (r RRule) Occurrences(start time.Time, limit int) []time.Time {
+	var occurrences []time.Time
+	until := r.until(start.Location())
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	matched, empty := 0, 0
+	for period := 0; empty < maxEmptyPeriods; period++ {
+		candidates := r.expand(start, period*interval)
+		if len(candidates) == 0 {
+			empty++
+			continue
+		}
+		empty = 0
+
+		for _, candidate := range candidates {
+			if candidate.Before(start) {
+				continue
+			}
+			if !until.IsZero() && candidate.After(until) {
+				return occurrences
+			}
+			if r.Count > 0 && matched >= r.Count {
+				return occurrences
+			}
+			matched++
+			occurrences = append(occurrences, candidate)
+			if len(occurrences) >= limit {
+				return occurrences
+			}
+		}
+	}
+	return occurrences
+}
+
+// Next returns the first occurrence strictly after current, treating current as an
+// occurrence of the series. The rule returned alongside has its COUNT reduced by the
+// occurrence consumed by current.
+func (r RRule) Next(current time.Time) (time.Time, RRule, bool) {
+	if r.Count == 1 {
+		return time.Time{}, RRule{}, false
+	}
+	for _, occurrence := range r.Occurrences(current, 2) {
+		if occurrence.After(current) {
+			next := r
+			if next.Count > 0 {
+				next.Count--
+			}
+			return occurrence, next, true
+		}
+	}
+	return time.Time{}, RRule{}, false
+}
+
+func (r RRule) until(loc *time.Location) time.Time {
+	switch {
+	case r.Until.IsZero():
+		return time.Time{}
+	case r.untilKind == untilLocal:
+		return time.Date(r.Until.Year(), r.Until.Month(), r.Until.Day(), r.Until.Hour(), r.Until.Minute(), r.Until.Second(), 0, loc)
+	case r.untilKind == untilDate:
+		return time.Date(r.Until.Year(), r.Until.Month(), r.Until.Day(), 23, 59, 59, 0, loc)
+	default:
+		return r.Until
+	}
+}
+
+// expand returns the sorted candidate occurrences of the period that is offset
+// periods (days, weeks, months or years) after the one containing start.
+func (r RRule) expand(start time.Time, offset int) []time.Time {
+	year, month, day := start.Date()
+	var dates []time.Time
+
+	switch r.Freq {
+	case FreqDaily:
+		date := civilDate(year, month, day+offset)
+		if r.matchesWeekday(date) && r.matchesMonthDay(date) {
+			dates = append(dates, date)
+		}
+	case FreqWeekly:
+		weekStart := civilDate(year, month, day-mondayIndex(start.Weekday())+7*offset)
+		if len(r.ByDay) == 0 {
+			dates = append(dates, weekStart.AddDate(0, 0, mondayIndex(start.Weekday())))
+		}
+		for _, byDay := range r.ByDay {
+			dates = append(dates, weekStart.AddDate(0, 0, mondayIndex(byDay.Weekday)))
+		}
+	case FreqMonthly:
+		first := civilDate(year, month+time.Month(offset), 1)
+		dates = r.expandMonth(first.Year(), first.Month(), day)
+	case FreqYearly:
+		dates = r.expandYear(year+offset, month, day)
+	}
+
+	occurrences := make([]time.Time, 0, len(dates))
+	seen := make(map[time.Time]bool)
+	for _, date := range dates {
+		if seen[date] {
+			continue
+		}
+		seen[date] = true
+		occurrences = append(occurrences, atWallClock(date, start))
+	}
+	sort.Slice(occurrences, func(i, j int) bool { return occurrences[i].Before(occurrences[j]) })
+	return occurrences
+}
+
+func (r RRule) expandMonth(year int, month time.Month, startDay int) []time.Time {
+	daysInMonth := civilDate(year, month+1, 0).Day()
+
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		if startDay > daysInMonth {
+			return nil
+		}
+		return []time.Time{civilDate(year, month, startDay)}
+	}
+
+	var dates []time.Time
+	if len(r.ByMonthDay) > 0 {
+		for _, monthDay := range r.ByMonthDay {
+			if d := resolveMonthDay(monthDay, daysInMonth); d > 0 {
+				date := civilDate(year, month, d)
+				if r.matchesWeekdayInMonth(date) {
+					dates = append(dates, date)
+				}
+			}
+		}
+		return dates
+	}
+
+	first := civilDate(year, month, 1)
+	for _, byDay := range r.ByDay {
+		dates = append(dates, weekdaysIn(first, daysInMonth, byDay)...)
+	}
+	return dates
+}
+
+func (r RRule) expandYear(year int, startMonth time.Month, startDay int) []time.Time {
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		date := civilDate(year, startMonth, startDay)
+		// February 29th only exists in leap years.
+		if date.Month() != startMonth {
+			return nil
+		}
+		return []time.Time{date}
+	}
+
+	var dates []time.Time
+	if len(r.ByMonthDay) > 0 {
+		for month := time.January; month <= time.December; month++ {
+			dates = append(dates, r.expandMonth(year, month, startDay)...)
+		}
+		return dates
+	}
+
+	first := civilDate(year, time.January, 1)
+	daysInYear := civilDate(year+1, time.January, 0).YearDay()
+	for _, byDay := range r.ByDay {
+		dates = append(dates, weekdaysIn(first, daysInYear, byDay)...)
+	}
+	return dates
+}
+
+func (r RRule) matchesWeekday(date time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, byDay := range r.ByDay {
+		if byDay.Weekday == date.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWeekdayInMonth applies BYDAY as a filter on BYMONTHDAY candidates, honouring
+// numbered entries such as 1FR within the candidate's month.
+func (r RRule) matchesWeekdayInMonth(date time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	daysInMonth := civilDate(date.Year(), date.Month()+1, 0).Day()
+	first := civilDate(date.Year(), date.Month(), 1)
+	for _, byDay := range r.ByDay {
+		for _, candidate := range weekdaysIn(first, daysInMonth, byDay) {
+			if candidate.Equal(date) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r RRule) matchesMonthDay(date time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	daysInMonth := civilDate(date.Year(), date.Month()+1, 0).Day()
+	for _, monthDay := range r.ByMonthDay {
+		if resolveMonthDay(monthDay, daysInMonth) == date.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+// weekdaysIn returns the days matching byDay within the span of days starting at first.
+func weekdaysIn(first time.Time, days int, byDay WeekdayNum) []time.Time {
+	var matches []time.Time
+	offset := (int(byDay.Weekday) - int(first.Weekday()) + 7) % 7
+	for d := offset; d < days; d += 7 {
+		matches = append(matches, first.AddDate(0, 0, d))
+	}
+
+	switch {
+	case byDay.N > 0 && byDay.N <= len(matches):
+		return matches[byDay.N-1 : byDay.N]
+	case byDay.N < 0 && -byDay.N <= len(matches):
+		return matches[len(matches)+byDay.N : len(matches)+byDay.N+1]
+	case byDay.N == 0:
+		return matches
+	default:
+		return nil
+	}
+}
+
+// atWallClock places the wall-clock time of start on date. As RFC 5545 prescribes, a
+// time falling into a DST gap is interpreted with the UTC offset in effect before the
+// gap, which moves it forward by the length of the gap, and an ambiguous time resolves
+// to its first occurrence.
+func atWallClock(date, start time.Time) time.Time {
+	loc := start.Location()
+	t := time.Date(date.Year(), date.Month(), date.Day(), start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), loc)
+	if t.Hour() == start.Hour() && t.Minute() == start.Minute() {
+		return t
+	}
+
+	naive := time.Date(date.Year(), date.Month(), date.Day(), start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), time.UTC)
+	_, offsetBefore := naive.Add(-24 * time.Hour).In(loc).Zone()
+	return naive.Add(-time.Duration(offsetBefore) * time.Second).In(loc)
+}
+
+func resolveMonthDay(monthDay, daysInMonth int) int {
+	if monthDay < 0 {
+		monthDay = daysInMonth + monthDay + 1
+	}
+	if monthDay < 1 || monthDay > daysInMonth {
+		return 0
+	}
+	return monthDay
+}
+
+func mondayIndex(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
+
+// civilDate works on calendar dates in UTC so that day arithmetic is not affected by
+// DST transitions; the wall-clock time is applied afterwards.
+func civilDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
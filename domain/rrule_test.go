@@ -0,0 +1,460 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	require.NoError(t, err)
+	return loc
+}
+
+func TestParseRRule(t *testing.T) {
+	tests := []struct {
+		name           string
+		value          string
+		expectedRule   RRule
+		expectedString string
+		expectedErr    bool
+	}{
+		{
+			name:           "daily",
+			value:          "FREQ=DAILY",
+			expectedRule:   RRule{Freq: FreqDaily, Interval: 1},
+			expectedString: "FREQ=DAILY",
+		},
+		{
+			name:           "rrule prefix and lower case",
+			value:          "RRULE:freq=weekly;interval=2;byday=mo,we",
+			expectedRule:   RRule{Freq: FreqWeekly, Interval: 2, ByDay: []WeekdayNum{{Weekday: time.Monday}, {Weekday: time.Wednesday}}},
+			expectedString: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE",
+		},
+		{
+			name:           "numbered weekdays",
+			value:          "FREQ=MONTHLY;BYDAY=2TU,-1FR,+1MO",
+			expectedRule:   RRule{Freq: FreqMonthly, Interval: 1, ByDay: []WeekdayNum{{Weekday: time.Tuesday, N: 2}, {Weekday: time.Friday, N: -1}, {Weekday: time.Monday, N: 1}}},
+			expectedString: "FREQ=MONTHLY;BYDAY=2TU,-1FR,1MO",
+		},
+		{
+			name:           "month days and count",
+			value:          "FREQ=MONTHLY;BYMONTHDAY=1,15,-1;COUNT=10",
+			expectedRule:   RRule{Freq: FreqMonthly, Interval: 1, ByMonthDay: []int{1, 15, -1}, Count: 10},
+			expectedString: "FREQ=MONTHLY;BYMONTHDAY=1,15,-1;COUNT=10",
+		},
+		{
+			name:           "until utc",
+			value:          "FREQ=DAILY;UNTIL=20250131T235959Z",
+			expectedRule:   RRule{Freq: FreqDaily, Interval: 1, Until: time.Date(2025, 1, 31, 23, 59, 59, 0, time.UTC)},
+			expectedString: "FREQ=DAILY;UNTIL=20250131T235959Z",
+		},
+		{
+			name:           "until local",
+			value:          "FREQ=DAILY;UNTIL=20250131T090000",
+			expectedRule:   RRule{Freq: FreqDaily, Interval: 1, Until: time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC), untilKind: untilLocal},
+			expectedString: "FREQ=DAILY;UNTIL=20250131T090000",
+		},
+		{
+			name:           "until date",
+			value:          "FREQ=DAILY;UNTIL=20250131",
+			expectedRule:   RRule{Freq: FreqDaily, Interval: 1, Until: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), untilKind: untilDate},
+			expectedString: "FREQ=DAILY;UNTIL=20250131",
+		},
+		{name: "empty", value: "", expectedErr: true},
+		{name: "missing freq", value: "INTERVAL=2", expectedErr: true},
+		{name: "unsupported freq", value: "FREQ=HOURLY", expectedErr: true},
+		{name: "unsupported part", value: "FREQ=DAILY;BYMONTH=1", expectedErr: true},
+		{name: "malformed part", value: "FREQ=DAILY;COUNT", expectedErr: true},
+		{name: "duplicate part", value: "FREQ=DAILY;FREQ=WEEKLY", expectedErr: true},
+		{name: "zero interval", value: "FREQ=DAILY;INTERVAL=0", expectedErr: true},
+		{name: "negative count", value: "FREQ=DAILY;COUNT=-1", expectedErr: true},
+		{name: "count and until", value: "FREQ=DAILY;COUNT=2;UNTIL=20250131", expectedErr: true},
+		{name: "invalid until", value: "FREQ=DAILY;UNTIL=2025-01-31", expectedErr: true},
+		{name: "invalid weekday", value: "FREQ=WEEKLY;BYDAY=XX", expectedErr: true},
+		{name: "zero weekday position", value: "FREQ=MONTHLY;BYDAY=0MO", expectedErr: true},
+		{name: "numbered weekday with weekly", value: "FREQ=WEEKLY;BYDAY=1MO", expectedErr: true},
+		{name: "weekday position out of month", value: "FREQ=MONTHLY;BYDAY=6MO", expectedErr: true},
+		{name: "month day out of range", value: "FREQ=MONTHLY;BYMONTHDAY=32", expectedErr: true},
+		{name: "zero month day", value: "FREQ=MONTHLY;BYMONTHDAY=0", expectedErr: true},
+		{name: "month day with weekly", value: "FREQ=WEEKLY;BYMONTHDAY=1", expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRRule(tt.value)
+			if tt.expectedErr {
+				require.ErrorIs(t, err, ErrInvalidRecurrence)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedRule, rule)
+			require.Equal(t, tt.expectedString, rule.String())
+		})
+	}
+}
+
+func TestRRule_Occurrences(t *testing.T) {
+	sofia := mustLoadLocation(t, "Europe/Sofia")
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	tests := []struct {
+		name     string
+		rule     string
+		start    time.Time
+		limit    int
+		expected []time.Time
+	}{
+		{
+			name:  "daily",
+			rule:  "FREQ=DAILY",
+			start: time.Date(2025, 1, 30, 9, 0, 0, 0, time.UTC),
+			limit: 3,
+			expected: []time.Time{
+				time.Date(2025, 1, 30, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "daily with interval and weekdays",
+			rule:  "FREQ=DAILY;INTERVAL=2;BYDAY=MO,TU,WE,TH,FR",
+			start: time.Date(2025, 4, 3, 9, 0, 0, 0, time.UTC), // Thursday
+			limit: 3,
+			expected: []time.Time{
+				time.Date(2025, 4, 3, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 4, 7, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 4, 9, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "daily on a month day",
+			rule:  "FREQ=DAILY;BYMONTHDAY=-1",
+			start: time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC),
+			limit: 3,
+			expected: []time.Time{
+				time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 3, 31, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "weekly on the start weekday",
+			rule:  "FREQ=WEEKLY",
+			start: time.Date(2025, 4, 3, 9, 0, 0, 0, time.UTC),
+			limit: 2,
+			expected: []time.Time{
+				time.Date(2025, 4, 3, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 4, 10, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "bi-weekly on several weekdays starting mid-week",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE,FR",
+			start: time.Date(2025, 4, 2, 9, 0, 0, 0, time.UTC), // Wednesday
+			limit: 5,
+			expected: []time.Time{
+				time.Date(2025, 4, 2, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 4, 4, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 4, 14, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 4, 16, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 4, 18, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "weekly on sunday belongs to the week starting monday",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=SU",
+			start: time.Date(2025, 4, 6, 9, 0, 0, 0, time.UTC), // Sunday
+			limit: 2,
+			expected: []time.Time{
+				time.Date(2025, 4, 6, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 4, 20, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "monthly on the 31st skips shorter months",
+			rule:  "FREQ=MONTHLY",
+			start: time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC),
+			limit: 4,
+			expected: []time.Time{
+				time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 3, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 5, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 7, 31, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "monthly on the last day",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start: time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC),
+			limit: 4,
+			expected: []time.Time{
+				time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, 4, 30, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "monthly on several month days",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=15,1",
+			start: time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC),
+			limit: 3,
+			expected: []time.Time{
+				time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 2, 15, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "monthly on the second tuesday",
+			rule:  "FREQ=MONTHLY;BYDAY=2TU",
+			start: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+			limit: 3,
+			expected: []time.Time{
+				time.Date(2025, 1, 14, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 2, 11, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 3, 11, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "monthly on the last friday",
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR",
+			start: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+			limit: 3,
+			expected: []time.Time{
+				time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 3, 28, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "monthly on the fifth monday skips months without one",
+			rule:  "FREQ=MONTHLY;BYDAY=5MO",
+			start: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+			limit: 2,
+			expected: []time.Time{
+				time.Date(2025, 3, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 6, 30, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "friday the 13th",
+			rule:  "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13",
+			start: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+			limit: 2,
+			expected: []time.Time{
+				time.Date(2025, 6, 13, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 2, 13, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "quarterly",
+			rule:  "FREQ=MONTHLY;INTERVAL=3",
+			start: time.Date(2025, 11, 30, 9, 0, 0, 0, time.UTC),
+			limit: 3,
+			expected: []time.Time{
+				time.Date(2025, 11, 30, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 5, 30, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 8, 30, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "yearly on a leap day",
+			rule:  "FREQ=YEARLY",
+			start: time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+			limit: 2,
+			expected: []time.Time{
+				time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+				time.Date(2028, 2, 29, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "yearly on month days expands to every month",
+			rule:  "FREQ=YEARLY;BYMONTHDAY=31",
+			start: time.Date(2025, 10, 1, 9, 0, 0, 0, time.UTC),
+			limit: 3,
+			expected: []time.Time{
+				time.Date(2025, 10, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 12, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "yearly on the last sunday of the year",
+			rule:  "FREQ=YEARLY;BYDAY=-1SU",
+			start: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+			limit: 2,
+			expected: []time.Time{
+				time.Date(2025, 12, 28, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 12, 27, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "count limits the series",
+			rule:  "FREQ=DAILY;COUNT=2",
+			start: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+			limit: 10,
+			expected: []time.Time{
+				time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "until is inclusive",
+			rule:  "FREQ=WEEKLY;UNTIL=20250115T090000Z",
+			start: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+			limit: 10,
+			expected: []time.Time{
+				time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 1, 8, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "until date covers the whole local day",
+			rule:  "FREQ=DAILY;UNTIL=20250102",
+			start: time.Date(2025, 1, 1, 23, 0, 0, 0, sofia),
+			limit: 10,
+			expected: []time.Time{
+				time.Date(2025, 1, 1, 23, 0, 0, 0, sofia),
+				time.Date(2025, 1, 2, 23, 0, 0, 0, sofia),
+			},
+		},
+		{
+			name:  "until local is interpreted in the start location",
+			rule:  "FREQ=DAILY;UNTIL=20250102T090000",
+			start: time.Date(2025, 1, 1, 9, 0, 0, 0, newYork),
+			limit: 10,
+			expected: []time.Time{
+				time.Date(2025, 1, 1, 9, 0, 0, 0, newYork),
+				time.Date(2025, 1, 2, 9, 0, 0, 0, newYork),
+			},
+		},
+		{
+			name:  "wall-clock time is kept across dst start",
+			rule:  "FREQ=DAILY",
+			start: time.Date(2025, 3, 29, 9, 0, 0, 0, sofia),
+			limit: 2,
+			expected: []time.Time{
+				time.Date(2025, 3, 29, 7, 0, 0, 0, time.UTC),
+				time.Date(2025, 3, 30, 6, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "wall-clock time is kept across dst end",
+			rule:  "FREQ=WEEKLY",
+			start: time.Date(2025, 10, 27, 9, 0, 0, 0, newYork),
+			limit: 2,
+			expected: []time.Time{
+				time.Date(2025, 10, 27, 13, 0, 0, 0, time.UTC),
+				time.Date(2025, 11, 3, 14, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "non-existent time in the dst gap moves forward",
+			rule:  "FREQ=DAILY",
+			start: time.Date(2025, 3, 8, 2, 30, 0, 0, newYork),
+			limit: 3,
+			expected: []time.Time{
+				time.Date(2025, 3, 8, 7, 30, 0, 0, time.UTC),
+				time.Date(2025, 3, 9, 7, 30, 0, 0, time.UTC), // 03:30 EDT
+				time.Date(2025, 3, 10, 6, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "ambiguous time resolves to the first occurrence",
+			rule:  "FREQ=DAILY",
+			start: time.Date(2025, 11, 1, 1, 30, 0, 0, newYork),
+			limit: 2,
+			expected: []time.Time{
+				time.Date(2025, 11, 1, 5, 30, 0, 0, time.UTC),
+				time.Date(2025, 11, 2, 5, 30, 0, 0, time.UTC), // 01:30 EDT
+			},
+		},
+		{
+			name:     "rule that never matches",
+			rule:     "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30",
+			start:    time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC),
+			limit:    1,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRRule(tt.rule)
+			require.NoError(t, err)
+
+			occurrences := rule.Occurrences(tt.start, tt.limit)
+			require.Equal(t, len(tt.expected), len(occurrences), "%v", occurrences)
+			for i := range tt.expected {
+				require.True(t, tt.expected[i].Equal(occurrences[i]), "occurrence %d: expected %v, got %v", i, tt.expected[i], occurrences[i])
+				require.Equal(t, tt.start.Location(), occurrences[i].Location())
+			}
+		})
+	}
+}
+
+func TestRRule_Next(t *testing.T) {
+	tests := []struct {
+		name         string
+		rule         string
+		current      time.Time
+		expectedNext time.Time
+		expectedRule string
+		expectedOk   bool
+	}{
+		{
+			name:         "next weekly occurrence",
+			rule:         "FREQ=WEEKLY;BYDAY=MO,TH",
+			current:      time.Date(2025, 4, 3, 9, 0, 0, 0, time.UTC),
+			expectedNext: time.Date(2025, 4, 7, 9, 0, 0, 0, time.UTC),
+			expectedRule: "FREQ=WEEKLY;BYDAY=MO,TH",
+			expectedOk:   true,
+		},
+		{
+			name:         "current date not matching the rule",
+			rule:         "FREQ=MONTHLY;BYMONTHDAY=1",
+			current:      time.Date(2025, 4, 3, 9, 0, 0, 0, time.UTC),
+			expectedNext: time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC),
+			expectedRule: "FREQ=MONTHLY;BYMONTHDAY=1",
+			expectedOk:   true,
+		},
+		{
+			name:         "count is decremented",
+			rule:         "FREQ=DAILY;COUNT=3",
+			current:      time.Date(2025, 4, 3, 9, 0, 0, 0, time.UTC),
+			expectedNext: time.Date(2025, 4, 4, 9, 0, 0, 0, time.UTC),
+			expectedRule: "FREQ=DAILY;COUNT=2",
+			expectedOk:   true,
+		},
+		{
+			name:       "last occurrence by count",
+			rule:       "FREQ=DAILY;COUNT=1",
+			current:    time.Date(2025, 4, 3, 9, 0, 0, 0, time.UTC),
+			expectedOk: false,
+		},
+		{
+			name:       "last occurrence by until",
+			rule:       "FREQ=DAILY;UNTIL=20250403T235959Z",
+			current:    time.Date(2025, 4, 3, 9, 0, 0, 0, time.UTC),
+			expectedOk: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRRule(tt.rule)
+			require.NoError(t, err)
+
+			next, nextRule, ok := rule.Next(tt.current)
+			require.Equal(t, tt.expectedOk, ok)
+			if ok {
+				require.True(t, tt.expectedNext.Equal(next), "expected %v, got %v", tt.expectedNext, next)
+				require.Equal(t, tt.expectedRule, nextRule.String())
+			}
+		})
+	}
+}
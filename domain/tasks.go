@@ -2,24 +2,201 @@ package domain
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
+	"reflect"
+	"strings"
 	"time"
 )
 
-var ErrTaskNotFound = errors.New("task not found")
+var (
+	ErrTaskNotFound = errors.New("task not found")
+	ErrInvalidTask  = errors.New("invalid task")
+	// ErrTaskExists is returned when creating a task with the id of another one, which
+	// may also be in the trash.
+	ErrTaskExists    = errors.New("task already exists")
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrStorageUnavailable is returned without even trying while the storage is known
+	// to be unreachable.
+	ErrStorageUnavailable = errors.New("storage unavailable")
+)
+
+const (
+	StatusPending = "PENDING"
+	StatusDone    = "DONE"
+)
 
 type TasksRepo interface {
 	GetTaskById(ctx context.Context, id uuid.UUID) (Task, error)
-	GetTasks(ctx context.Context) ([]Task, error)
+	// GetTaskByIdForUpdate is GetTaskById which, within InTx, also keeps other
+	// transactions from changing the task until the transaction ends.
+	GetTaskByIdForUpdate(ctx context.Context, id uuid.UUID) (Task, error)
+	GetTasks(ctx context.Context, filter TaskFilter) ([]Task, error)
+	// GetTasksPage returns up to limit tasks matching filter which come after the cursor,
+	// oldest first. A nil cursor starts with the oldest task.
+	GetTasksPage(ctx context.Context, filter TaskFilter, after *TaskCursor, limit int) ([]Task, error)
+	// CountTasks returns how many tasks match filter.
+	CountTasks(ctx context.Context, filter TaskFilter) (int, error)
+	// GetTasksByIds returns the live tasks among ids; unknown ids are left out.
+	GetTasksByIds(ctx context.Context, ids []uuid.UUID) ([]Task, error)
+	// GetTaskEvents returns the domain events recorded for the given tasks, oldest first.
+	GetTaskEvents(ctx context.Context, taskIDs []uuid.UUID) ([]Event, error)
 	CreateTask(ctx context.Context, data Task) (Task, error)
+	UpdateTask(ctx context.Context, data Task) (Task, error)
+	SearchTasks(ctx context.Context, query string, limit int) ([]TaskSearchResult, error)
+	SearchTasksBySimilarity(ctx context.Context, query string, limit int) ([]TaskSearchResult, error)
+	DeleteTask(ctx context.Context, id uuid.UUID) (Task, error)
+	GetDeletedTasks(ctx context.Context) ([]Task, error)
+	RestoreTask(ctx context.Context, id uuid.UUID) (Task, error)
+	PurgeTask(ctx context.Context, id uuid.UUID) error
+	// InTx runs fn with a repo whose operations all belong to a single transaction,
+	// which is committed only if fn returns nil.
+	InTx(ctx context.Context, fn func(repo TasksRepo) error) error
+	// ImportTasks inserts the tasks returned by next until it returns io.EOF and
+	// reports how many of them were new. A dry run rolls the import back at the end.
+	ImportTasks(ctx context.Context, next func() (Task, error), dryRun bool) (int, error)
+	// ExportTasks calls fn for every task matching filter and stops at the first error.
+	ExportTasks(ctx context.Context, filter TaskFilter, fn func(task Task) error) error
 }
 
 type Task struct {
-	ID          uuid.UUID `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Status      string    `json:"status"`
-	DueDate     time.Time `json:"due_date"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          uuid.UUID  `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	DueDate     time.Time  `json:"due_date"`
+	RRule       string     `json:"rrule,omitempty"`
+	Timezone    string     `json:"timezone,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+// Validate applies the rules every new task has to satisfy, however it is created.
+func (t Task) Validate() error {
+	if reflect.DeepEqual(t, Task{}) {
+		return ErrInvalidTask
+	}
+	if t.IsRecurring() {
+		if _, _, err := t.Recurrence(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t Task) IsRecurring() bool {
+	return t.RRule != ""
+}
+
+// Recurrence parses the task's RRULE together with the location in which its
+// occurrences are expanded; tasks without a timezone recur in UTC.
+func (t Task) Recurrence() (RRule, *time.Location, error) {
+	rule, err := ParseRRule(t.RRule)
+	if err != nil {
+		return RRule{}, nil, err
+	}
+	loc, err := time.LoadLocation(t.Timezone)
+	if err != nil {
+		return RRule{}, nil, fmt.Errorf("%w: unknown timezone %s", ErrInvalidRecurrence, t.Timezone)
+	}
+	return rule, loc, nil
+}
+
+// Occurrences returns up to limit occurrences of a recurring task following its due
+// date, and none for a task which does not repeat.
+func (t Task) Occurrences(limit int) ([]time.Time, error) {
+	occurrences := []time.Time{}
+	if !t.IsRecurring() {
+		return occurrences, nil
+	}
+
+	rule, loc, err := t.Recurrence()
+	if err != nil {
+		return nil, err
+	}
+
+	current := t.DueDate.In(loc)
+	for len(occurrences) < limit {
+		next, nextRule, ok := rule.Next(current)
+		if !ok {
+			break
+		}
+		occurrences = append(occurrences, next)
+		current, rule = next, nextRule
+	}
+	return occurrences, nil
+}
+
+const (
+	MatchFullText   = "fulltext"
+	MatchSimilarity = "similarity"
+)
+
+// TaskSearchResult is a task matching a search query together with its relevance and a
+// snippet of the description highlighting the matched terms. Match tells whether the
+// task was found by full-text search or by trigram similarity.
+type TaskSearchResult struct {
+	Task    Task    `json:"task"`
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+	Match   string  `json:"match"`
+}
+
+// TaskFilter narrows down task listings. Empty fields match every task.
+type TaskFilter struct {
+	Status string
+}
+
+func (f TaskFilter) Matches(task Task) bool {
+	return f.Status == "" || f.Status == task.Status
+}
+
+// TaskCursor is the position of a task among the tasks ordered oldest first. Tasks
+// created at the same time are ordered by id.
+type TaskCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+func CursorOf(task Task) TaskCursor {
+	return TaskCursor{CreatedAt: task.CreatedAt, ID: task.ID}
+}
+
+// String encodes the cursor for clients, which pass it back as it is.
+func (c TaskCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.CreatedAt.UTC().Format(time.RFC3339Nano) + "/" + c.ID.String()))
+}
+
+// ParseTaskCursor decodes a cursor encoded by TaskCursor.String.
+func ParseTaskCursor(cursor string) (TaskCursor, error) {
+	invalid := fmt.Errorf("%w %q", ErrInvalidCursor, cursor)
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return TaskCursor{}, invalid
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "/")
+	if !ok {
+		return TaskCursor{}, invalid
+	}
+
+	var position TaskCursor
+	if position.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return TaskCursor{}, invalid
+	}
+	if position.ID, err = uuid.Parse(id); err != nil {
+		return TaskCursor{}, invalid
+	}
+	return position, nil
+}
+
+// Precedes tells whether task comes after the cursor, so that it belongs to the tasks
+// following it.
+func (c TaskCursor) Precedes(task Task) bool {
+	if !task.CreatedAt.Equal(c.CreatedAt) {
+		return task.CreatedAt.After(c.CreatedAt)
+	}
+	return task.ID.String() > c.ID.String()
 }
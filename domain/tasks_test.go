@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestTaskValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		task     Task
+		expected error
+	}{
+		{name: "valid", task: Task{Title: "Do unit tests"}},
+		{name: "valid recurring", task: Task{Title: "Standup", RRule: "FREQ=DAILY", Timezone: "Europe/Sofia"}},
+		{name: "empty", task: Task{}, expected: ErrInvalidTask},
+		{name: "invalid rule", task: Task{Title: "Standup", RRule: "FREQ=HOURLY"}, expected: ErrInvalidRecurrence},
+		{name: "invalid timezone", task: Task{Title: "Standup", RRule: "FREQ=DAILY", Timezone: "Mars/Base"}, expected: ErrInvalidRecurrence},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.task.Validate()
+			if tt.expected == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"github.com/go-chi/render"
+	"net/http"
+	"strings"
+)
+
+// RequireAdmin lets through only requests carrying the admin token as a bearer token.
+// An empty token disables the wrapped endpoints altogether.
+func RequireAdmin(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token == "" {
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, ErrorResponse{
+					Code:    http.StatusForbidden,
+					Message: "admin endpoints are disabled",
+				})
+				return
+			}
+
+			provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+				render.Status(r, http.StatusUnauthorized)
+				render.JSON(w, r, ErrorResponse{
+					Code:    http.StatusUnauthorized,
+					Message: "admin token required",
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireAdmin(t *testing.T) {
+	tests := []struct {
+		name               string
+		token              string
+		authorization      string
+		expectedStatusCode int
+	}{
+		{
+			name:               "valid token",
+			token:              "secret",
+			authorization:      "Bearer secret",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "invalid token",
+			token:              "secret",
+			authorization:      "Bearer secrets",
+			expectedStatusCode: 401,
+		},
+		{
+			name:               "not a bearer token",
+			token:              "secret",
+			authorization:      "Basic secret",
+			expectedStatusCode: 401,
+		},
+		{
+			name:               "admin endpoints disabled",
+			authorization:      "Bearer ",
+			expectedStatusCode: 403,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", tt.authorization)
+
+			RequireAdmin(tt.token)(next).ServeHTTP(recorder, req)
+			require.Equal(t, tt.expectedStatusCode, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"github.com/go-chi/render"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// StorageBreaker tells whether the storage is known to be unreachable.
+type StorageBreaker interface {
+	// Open returns true while the storage is not even tried, and how long until it is
+	// tried again.
+	Open() (bool, time.Duration)
+}
+
+// FailFast answers with HTTP 503 while breaker is open, telling clients when to retry,
+// instead of letting every request fail on the storage.
+func FailFast(breaker StorageBreaker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			open, retryAfter := breaker.Open()
+			if !open {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Retry-After", strconv.Itoa(max(int(math.Ceil(retryAfter.Seconds())), 1)))
+			render.Status(r, http.StatusServiceUnavailable)
+			render.JSON(w, r, ErrorResponse{
+				Code:    http.StatusServiceUnavailable,
+				Message: "the database is unreachable",
+			})
+		})
+	}
+}
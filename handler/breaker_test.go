@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fakeBreaker struct {
+	open       bool
+	retryAfter time.Duration
+}
+
+func (fb *fakeBreaker) Open() (bool, time.Duration) {
+	return fb.open, fb.retryAfter
+}
+
+func TestFailFast(t *testing.T) {
+	breaker := &fakeBreaker{}
+	handler := FailFast(breaker)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/tasks", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	breaker.open, breaker.retryAfter = true, 2500*time.Millisecond
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/tasks", nil))
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	require.Equal(t, "3", recorder.Header().Get("Retry-After"))
+	require.JSONEq(t, `{"code":503,"message":"the database is unreachable"}`, recorder.Body.String())
+
+	// Once the cooldown has passed, requests make the trial connection.
+	breaker.open, breaker.retryAfter = false, 0
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/tasks", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"api/domain"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-chi/render"
+	"net/http"
+	"strconv"
+)
+
+const maxBulkOperations = 100
+
+// BulkItemResponse reports the outcome of the operation at Index of a bulk request.
+// Status is the HTTP status the operation would have had as a single request.
+type BulkItemResponse struct {
+	Index  int          `json:"index"`
+	Status int          `json:"status"`
+	Task   *domain.Task `json:"task,omitempty"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// BulkTasks applies a JSON array of operations. The response is HTTP 200 when all of
+// them succeeded and HTTP 207 with the status of every item otherwise.
+func (th TasksHandler) BulkTasks(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := getContextFromRequest(r)
+	defer cancel()
+
+	atomic := false
+	if raw := r.URL.Query().Get("atomic"); raw != "" {
+		var err error
+		if atomic, err = strconv.ParseBool(raw); err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "query param atomic must be a boolean",
+			})
+			return
+		}
+	}
+
+	var ops []domain.BulkOperation
+	if err := json.NewDecoder(r.Body).Decode(&ops); err != nil {
+		code := bodyErrorStatus(err)
+		render.Status(r, code)
+		render.JSON(w, r, ErrorResponse{
+			Code:    code,
+			Message: err.Error(),
+		})
+		return
+	}
+	if len(ops) == 0 || len(ops) > maxBulkOperations {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("between 1 and %d operations are allowed", maxBulkOperations),
+		})
+		return
+	}
+
+	results, err := th.tasksService.BulkTasks(ctx, ops, atomic)
+	if err != nil {
+		renderServerError(w, r, err)
+		return
+	}
+
+	status := http.StatusOK
+	response := make([]BulkItemResponse, len(results))
+	for i, result := range results {
+		response[i] = BulkItemResponse{Index: i, Status: http.StatusOK, Task: result.Task}
+		if result.Err != nil {
+			response[i].Status = bulkErrorStatus(result.Err)
+			response[i].Error = result.Err.Error()
+			status = http.StatusMultiStatus
+		}
+	}
+
+	render.Status(r, status)
+	render.JSON(w, r, response)
+}
+
+func bulkErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrTaskNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrTaskExists):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrInvalidBulkOperation), errors.Is(err, domain.ErrInvalidTask), errors.Is(err, domain.ErrInvalidRecurrence):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrBulkRolledBack):
+		return http.StatusFailedDependency
+	default:
+		return serverErrorStatus(err)
+	}
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"api/domain"
+	mock "api/mocks/mock_uc"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBulkTasks(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		body               string
+		ucMock             func(ucMock mock.MockTasksUC)
+		expectedStatusCode int
+		expectedItems      []int
+	}{
+		{
+			name: "happy path - OK",
+			body: `[{"op":"create","task":{"title":"Do unit tests"}},{"op":"delete","id":"1461ec84-ccff-4f3c-af34-65d0856ac3ce"}]`,
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().BulkTasks(gomock.Any(), gomock.Len(2), false).Return([]domain.BulkResult{
+					{Task: &domain.Task{Title: "Do unit tests"}},
+					{Task: &domain.Task{Title: "Deleted"}},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedItems:      []int{200, 200},
+		},
+		{
+			name:  "partial failure",
+			query: "?atomic=false",
+			body:  `[{"op":"create","task":{"title":"Do unit tests"}},{"op":"archive"},{"op":"delete","id":"1461ec84-ccff-4f3c-af34-65d0856ac3ce"}]`,
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().BulkTasks(gomock.Any(), gomock.Len(3), false).Return([]domain.BulkResult{
+					{Task: &domain.Task{Title: "Do unit tests"}},
+					{Err: domain.ErrInvalidBulkOperation},
+					{Err: domain.ErrTaskNotFound},
+				}, nil)
+			},
+			expectedStatusCode: 207,
+			expectedItems:      []int{200, 400, 404},
+		},
+		{
+			name: "create with a taken id",
+			body: `[{"op":"create","task":{"id":"1461ec84-ccff-4f3c-af34-65d0856ac3ce","title":"Do unit tests"}}]`,
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().BulkTasks(gomock.Any(), gomock.Len(1), false).Return([]domain.BulkResult{
+					{Err: domain.ErrTaskExists},
+				}, nil)
+			},
+			expectedStatusCode: 207,
+			expectedItems:      []int{409},
+		},
+		{
+			name:  "atomic rollback",
+			query: "?atomic=true",
+			body:  `[{"op":"create","task":{"title":"Do unit tests"}},{"op":"update","id":"1461ec84-ccff-4f3c-af34-65d0856ac3ce","task":{"title":"x"}}]`,
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().BulkTasks(gomock.Any(), gomock.Len(2), true).Return([]domain.BulkResult{
+					{Err: domain.ErrBulkRolledBack},
+					{Err: errors.New("db error")},
+				}, nil)
+			},
+			expectedStatusCode: 207,
+			expectedItems:      []int{424, 500},
+		},
+		{
+			name:  "atomic commit error",
+			query: "?atomic=true",
+			body:  `[{"op":"create","task":{"title":"Do unit tests"}}]`,
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().BulkTasks(gomock.Any(), gomock.Any(), true).Return(nil, errors.New("failed to commit transaction"))
+			},
+			expectedStatusCode: 500,
+		},
+		{
+			name:               "invalid atomic param",
+			query:              "?atomic=maybe",
+			body:               `[{"op":"delete","id":"1461ec84-ccff-4f3c-af34-65d0856ac3ce"}]`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "invalid body",
+			body:               `{"op":"delete"}`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "no operations",
+			body:               `[]`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "too many operations",
+			body:               "[" + strings.Repeat(`{"op":"delete"},`, maxBulkOperations) + `{"op":"delete"}]`,
+			expectedStatusCode: 400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			r := chi.NewRouter()
+			recorder := httptest.NewRecorder()
+			ucMock := mock.NewMockTasksUC(ctrl)
+			handler := NewTasksHandler(ucMock)
+
+			if tt.ucMock != nil {
+				tt.ucMock(*ucMock)
+			}
+
+			r.Post("/api/tasks/bulk", handler.BulkTasks)
+			req, err := http.NewRequest(http.MethodPost, "/api/tasks/bulk"+tt.query, bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+
+			r.ServeHTTP(recorder, req)
+			require.Equal(t, tt.expectedStatusCode, recorder.Code)
+
+			if tt.expectedItems != nil {
+				var body []BulkItemResponse
+				err = json.Unmarshal(recorder.Body.Bytes(), &body)
+				require.NoError(t, err)
+				require.Len(t, body, len(tt.expectedItems))
+				for i, status := range tt.expectedItems {
+					require.Equal(t, i, body[i].Index)
+					require.Equal(t, status, body[i].Status)
+					if status != http.StatusOK {
+						require.NotEmpty(t, body[i].Error)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,762 @@
+package handler
+
+import (
+	"api/domain"
+	"api/uc"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	davNamespace       = "DAV:"
+	caldavNamespace    = "urn:ietf:params:xml:ns:caldav"
+	calServerNamespace = "http://calendarserver.org/ns/"
+
+	caldavCalendarName = "tasks"
+	caldavMaxBodySize  = 1 << 20
+	caldavAllow        = "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT"
+)
+
+var xmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+
+var davPrefixes = map[string]string{
+	davNamespace:       "d",
+	caldavNamespace:    "c",
+	calServerNamespace: "cs",
+}
+
+// CalDAVHandler serves the tasks as a single CalDAV calendar holding one VTODO per
+// task, which lets calendar and reminder apps sync tasks both ways. The handler root is
+// the principal as well as its calendar home, and the calendar lives below it:
+//
+//	{prefix}/                 principal and calendar home
+//	{prefix}/tasks/           VTODO calendar collection
+//	{prefix}/tasks/{id}.ics   one task
+//
+// Resource ETags are derived from the task's iCalendar data, so they change with every
+// change to a task no matter whether it was made through CalDAV or the REST API.
+type CalDAVHandler struct {
+	tasksService uc.TasksUC
+	prefix       string
+}
+
+func NewCalDAVHandler(tasksService uc.TasksUC, prefix string) *CalDAVHandler {
+	return &CalDAVHandler{tasksService: tasksService, prefix: strings.TrimSuffix(prefix, "/")}
+}
+
+type davResourceKind int
+
+const (
+	davUnknown davResourceKind = iota
+	davPrincipal
+	davCalendar
+	davTask
+)
+
+type davResource struct {
+	kind   davResourceKind
+	taskID uuid.UUID
+}
+
+// WellKnown points clients doing service discovery (RFC 6764) to the principal.
+func (ch CalDAVHandler) WellKnown(w http.ResponseWriter, r *http.Request) {
+	http.Redirect(w, r, ch.principalHref(), http.StatusMovedPermanently)
+}
+
+func (ch CalDAVHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := getContextFromRequest(r)
+	defer cancel()
+	r = r.WithContext(ctx)
+
+	res := ch.resolve(r.URL.Path)
+	if res.kind == davUnknown && r.Method != http.MethodPut {
+		caldavError(w, r, http.StatusNotFound, "resource not found")
+		return
+	}
+
+	w.Header().Set("DAV", "1, 3, calendar-access")
+	switch r.Method {
+	case http.MethodOptions:
+		w.Header().Set("Allow", caldavAllow)
+		w.WriteHeader(http.StatusOK)
+	case "PROPFIND":
+		ch.propfind(w, r, res)
+	case "REPORT":
+		ch.report(w, r, res)
+	case http.MethodGet, http.MethodHead:
+		ch.getTask(w, r, res)
+	case http.MethodPut:
+		ch.putTask(w, r, res)
+	case http.MethodDelete:
+		ch.deleteTask(w, r, res)
+	default:
+		w.Header().Set("Allow", caldavAllow)
+		caldavError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (ch CalDAVHandler) resolve(path string) davResource {
+	path = strings.Trim(strings.TrimPrefix(path, ch.prefix), "/")
+	switch {
+	case path == "":
+		return davResource{kind: davPrincipal}
+	case path == caldavCalendarName:
+		return davResource{kind: davCalendar}
+	}
+
+	name, ok := strings.CutPrefix(path, caldavCalendarName+"/")
+	if !ok {
+		return davResource{}
+	}
+	name, ok = strings.CutSuffix(name, ".ics")
+	if !ok {
+		return davResource{}
+	}
+	id, err := uuid.Parse(name)
+	if err != nil {
+		return davResource{}
+	}
+	return davResource{kind: davTask, taskID: id}
+}
+
+func (ch CalDAVHandler) principalHref() string {
+	return ch.prefix + "/"
+}
+
+func (ch CalDAVHandler) calendarHref() string {
+	return ch.prefix + "/" + caldavCalendarName + "/"
+}
+
+func (ch CalDAVHandler) taskHref(id uuid.UUID) string {
+	return ch.calendarHref() + id.String() + ".ics"
+}
+
+// davPropRequest lists the properties asked for by a PROPFIND or REPORT body.
+type davPropRequest struct {
+	AllProp  *struct{} `xml:"DAV: allprop"`
+	PropName *struct{} `xml:"DAV: propname"`
+	Prop     struct {
+		Names []struct {
+			XMLName xml.Name
+		} `xml:",any"`
+	} `xml:"DAV: prop"`
+}
+
+type davPropfind struct {
+	XMLName xml.Name `xml:"DAV: propfind"`
+	davPropRequest
+}
+
+type davCalendarQuery struct {
+	XMLName xml.Name `xml:"urn:ietf:params:xml:ns:caldav calendar-query"`
+	davPropRequest
+	Filter struct {
+		CompFilter davCompFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+	} `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+type davCalendarMultiget struct {
+	XMLName xml.Name `xml:"urn:ietf:params:xml:ns:caldav calendar-multiget"`
+	davPropRequest
+	Hrefs []string `xml:"DAV: href"`
+}
+
+type davCompFilter struct {
+	Name         string          `xml:"name,attr"`
+	IsNotDefined *struct{}       `xml:"urn:ietf:params:xml:ns:caldav is-not-defined"`
+	TimeRange    *davTimeRange   `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+	CompFilters  []davCompFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+	PropFilters  []davPropFilter `xml:"urn:ietf:params:xml:ns:caldav prop-filter"`
+}
+
+type davPropFilter struct {
+	Name         string    `xml:"name,attr"`
+	IsNotDefined *struct{} `xml:"urn:ietf:params:xml:ns:caldav is-not-defined"`
+	TextMatch    *struct {
+		Negate string `xml:"negate-condition,attr"`
+		Text   string `xml:",chardata"`
+	} `xml:"urn:ietf:params:xml:ns:caldav text-match"`
+}
+
+type davTimeRange struct {
+	Start string `xml:"start,attr"`
+	End   string `xml:"end,attr"`
+}
+
+// davProp is a property together with its value as inner XML.
+type davProp struct {
+	name  xml.Name
+	value string
+}
+
+// davResponse is one response element of a multistatus. A response with a status
+// reports a resource that could not be found rather than its properties.
+type davResponse struct {
+	href    string
+	status  int
+	found   []davProp
+	missing []xml.Name
+}
+
+func (ch CalDAVHandler) propfind(w http.ResponseWriter, r *http.Request, res davResource) {
+	var body davPropfind
+	if err := decodeDAVBody(r, &body); err != nil {
+		caldavError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	depthOne := r.Header.Get("Depth") != "0"
+
+	var responses []davResponse
+	switch res.kind {
+	case davPrincipal:
+		responses = append(responses, body.selectProps(ch.principalHref(), ch.principalProps()))
+		if depthOne {
+			calendar, _, err := ch.calendar(r)
+			if err != nil {
+				caldavError(w, r, serverErrorStatus(err), err.Error())
+				return
+			}
+			responses = append(responses, body.selectProps(ch.calendarHref(), calendar))
+		}
+
+	case davCalendar:
+		calendar, tasks, err := ch.calendar(r)
+		if err != nil {
+			caldavError(w, r, serverErrorStatus(err), err.Error())
+			return
+		}
+		responses = append(responses, body.selectProps(ch.calendarHref(), calendar))
+		if depthOne {
+			for _, task := range tasks {
+				responses = append(responses, body.selectProps(ch.taskHref(task.ID), ch.taskProps(task)))
+			}
+		}
+
+	case davTask:
+		task, err := ch.tasksService.GetTaskById(r.Context(), res.taskID)
+		if err != nil {
+			caldavTaskError(w, r, err)
+			return
+		}
+		responses = append(responses, body.selectProps(ch.taskHref(task.ID), ch.taskProps(task)))
+	}
+
+	writeMultistatus(w, responses)
+}
+
+func (ch CalDAVHandler) report(w http.ResponseWriter, r *http.Request, res davResource) {
+	if res.kind != davCalendar {
+		caldavError(w, r, http.StatusForbidden, "reports are supported on the calendar collection only")
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, caldavMaxBodySize))
+	if err != nil {
+		caldavError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	var root struct {
+		XMLName xml.Name
+	}
+	if err := xml.Unmarshal(data, &root); err != nil {
+		caldavError(w, r, http.StatusBadRequest, "invalid report: "+err.Error())
+		return
+	}
+
+	var responses []davResponse
+	switch root.XMLName {
+	case xml.Name{Space: caldavNamespace, Local: "calendar-query"}:
+		var query davCalendarQuery
+		if err := xml.Unmarshal(data, &query); err != nil {
+			caldavError(w, r, http.StatusBadRequest, "invalid report: "+err.Error())
+			return
+		}
+		tasks, err := ch.tasksService.GetTasks(r.Context(), domain.TaskFilter{})
+		if err != nil {
+			caldavError(w, r, serverErrorStatus(err), err.Error())
+			return
+		}
+		for _, task := range tasks {
+			if query.Filter.CompFilter.matches(task) {
+				responses = append(responses, query.selectProps(ch.taskHref(task.ID), ch.taskProps(task)))
+			}
+		}
+
+	case xml.Name{Space: caldavNamespace, Local: "calendar-multiget"}:
+		var multiget davCalendarMultiget
+		if err := xml.Unmarshal(data, &multiget); err != nil {
+			caldavError(w, r, http.StatusBadRequest, "invalid report: "+err.Error())
+			return
+		}
+		for _, href := range multiget.Hrefs {
+			href = strings.TrimSpace(href)
+			target := davResource{}
+			if u, err := url.Parse(href); err == nil {
+				target = ch.resolve(u.Path)
+			}
+			if target.kind != davTask {
+				responses = append(responses, davResponse{href: href, status: http.StatusNotFound})
+				continue
+			}
+			task, err := ch.tasksService.GetTaskById(r.Context(), target.taskID)
+			if errors.Is(err, domain.ErrTaskNotFound) {
+				responses = append(responses, davResponse{href: href, status: http.StatusNotFound})
+				continue
+			}
+			if err != nil {
+				caldavError(w, r, serverErrorStatus(err), err.Error())
+				return
+			}
+			responses = append(responses, multiget.selectProps(href, ch.taskProps(task)))
+		}
+
+	default:
+		caldavError(w, r, http.StatusForbidden, "unsupported report "+root.XMLName.Local)
+		return
+	}
+
+	writeMultistatus(w, responses)
+}
+
+func (ch CalDAVHandler) getTask(w http.ResponseWriter, r *http.Request, res davResource) {
+	if res.kind != davTask {
+		caldavError(w, r, http.StatusMethodNotAllowed, "collections cannot be downloaded")
+		return
+	}
+
+	task, err := ch.tasksService.GetTaskById(r.Context(), res.taskID)
+	if err != nil {
+		caldavTaskError(w, r, err)
+		return
+	}
+
+	data := taskICS(task)
+	w.Header().Set("Content-Type", icsContentType)
+	w.Header().Set("ETag", taskETag(data))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+// putTask creates or updates the task named by the resource from the VTODO in the body.
+// Only the properties that map onto a task are taken over; the task's recurrence is
+// left as it is. The ETag of the saved task is returned, so that clients need not fetch
+// it again before their next change.
+func (ch CalDAVHandler) putTask(w http.ResponseWriter, r *http.Request, res davResource) {
+	if res.kind != davTask {
+		caldavError(w, r, http.StatusForbidden, "tasks must be stored in "+ch.calendarHref()+" as {uuid}.ics")
+		return
+	}
+
+	todo, err := parseICSTodo(http.MaxBytesReader(w, r.Body, caldavMaxBodySize))
+	if err != nil {
+		caldavError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if uid, err := uuid.Parse(todo.UID); err != nil || uid != res.taskID {
+		caldavError(w, r, http.StatusBadRequest, "UID must match the resource name")
+		return
+	}
+
+	existing, err := ch.tasksService.GetTaskById(r.Context(), res.taskID)
+	exists := err == nil
+	if err != nil && !errors.Is(err, domain.ErrTaskNotFound) {
+		caldavError(w, r, serverErrorStatus(err), err.Error())
+		return
+	}
+	if !checkDAVPreconditions(r, existing, exists) {
+		caldavError(w, r, http.StatusPreconditionFailed, "precondition failed")
+		return
+	}
+
+	task := existing
+	if !exists {
+		task = domain.Task{ID: res.taskID}
+	}
+	task.Title = todo.Summary
+	task.Description = todo.Description
+	task.DueDate = todo.Due
+	task.Status = taskStatusFromICS(todo.Status, existing.Status)
+
+	var saved domain.Task
+	if exists {
+		saved, err = ch.tasksService.UpdateTask(r.Context(), task)
+	} else {
+		saved, err = ch.tasksService.CreateTask(r.Context(), task)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidTask) || errors.Is(err, domain.ErrInvalidRecurrence):
+			caldavError(w, r, http.StatusBadRequest, err.Error())
+		// The id belongs to a task in the trash, which has to be restored instead.
+		case errors.Is(err, domain.ErrTaskExists) && r.Header.Get("If-None-Match") == "*":
+			caldavError(w, r, http.StatusPreconditionFailed, "precondition failed")
+		case errors.Is(err, domain.ErrTaskExists):
+			caldavError(w, r, http.StatusConflict, "a task in the trash has this UID")
+		default:
+			caldavTaskError(w, r, err)
+		}
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(taskICS(saved)))
+	if exists {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+// deleteTask moves the task to the trash, from where it can still be restored.
+func (ch CalDAVHandler) deleteTask(w http.ResponseWriter, r *http.Request, res davResource) {
+	if res.kind != davTask {
+		caldavError(w, r, http.StatusForbidden, "collections cannot be deleted")
+		return
+	}
+
+	task, err := ch.tasksService.GetTaskById(r.Context(), res.taskID)
+	if err != nil {
+		caldavTaskError(w, r, err)
+		return
+	}
+	if !checkDAVPreconditions(r, task, true) {
+		caldavError(w, r, http.StatusPreconditionFailed, "precondition failed")
+		return
+	}
+
+	if _, err := ch.tasksService.DeleteTask(r.Context(), res.taskID); err != nil {
+		caldavTaskError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// calendar returns the properties of the calendar collection along with its tasks,
+// which its CTag is computed from.
+func (ch CalDAVHandler) calendar(r *http.Request) ([]davProp, []domain.Task, error) {
+	tasks, err := ch.tasksService.GetTasks(r.Context(), domain.TaskFilter{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	etags := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		etags = append(etags, taskETag(taskICS(task)))
+	}
+	sort.Strings(etags)
+	ctag := taskETag([]byte(strings.Join(etags, "")))
+
+	props := []davProp{
+		{name: xml.Name{Space: davNamespace, Local: "resourcetype"}, value: "<d:collection/><c:calendar/>"},
+		{name: xml.Name{Space: davNamespace, Local: "displayname"}, value: "Tasks"},
+		{name: xml.Name{Space: davNamespace, Local: "current-user-principal"}, value: davHref(ch.principalHref())},
+		{name: xml.Name{Space: davNamespace, Local: "owner"}, value: davHref(ch.principalHref())},
+		{name: xml.Name{Space: davNamespace, Local: "current-user-privilege-set"}, value: davPrivileges},
+		{name: xml.Name{Space: davNamespace, Local: "supported-report-set"}, value: davSupportedReports},
+		{name: xml.Name{Space: caldavNamespace, Local: "supported-calendar-component-set"}, value: `<c:comp name="VTODO"/>`},
+		{name: xml.Name{Space: davNamespace, Local: "getetag"}, value: xmlEscape(ctag)},
+		{name: xml.Name{Space: calServerNamespace, Local: "getctag"}, value: xmlEscape(ctag)},
+	}
+	return props, tasks, nil
+}
+
+const (
+	davPrivileges = "<d:privilege><d:read/></d:privilege><d:privilege><d:write/></d:privilege>" +
+		"<d:privilege><d:write-content/></d:privilege><d:privilege><d:bind/></d:privilege><d:privilege><d:unbind/></d:privilege>"
+	davSupportedReports = "<d:supported-report><d:report><c:calendar-query/></d:report></d:supported-report>" +
+		"<d:supported-report><d:report><c:calendar-multiget/></d:report></d:supported-report>"
+)
+
+func (ch CalDAVHandler) principalProps() []davProp {
+	return []davProp{
+		{name: xml.Name{Space: davNamespace, Local: "resourcetype"}, value: "<d:collection/><d:principal/>"},
+		{name: xml.Name{Space: davNamespace, Local: "displayname"}, value: "Tasks"},
+		{name: xml.Name{Space: davNamespace, Local: "current-user-principal"}, value: davHref(ch.principalHref())},
+		{name: xml.Name{Space: davNamespace, Local: "principal-URL"}, value: davHref(ch.principalHref())},
+		{name: xml.Name{Space: caldavNamespace, Local: "calendar-home-set"}, value: davHref(ch.principalHref())},
+		{name: xml.Name{Space: caldavNamespace, Local: "calendar-user-address-set"}, value: ""},
+	}
+}
+
+func (ch CalDAVHandler) taskProps(task domain.Task) []davProp {
+	data := taskICS(task)
+	return []davProp{
+		{name: xml.Name{Space: davNamespace, Local: "resourcetype"}, value: ""},
+		{name: xml.Name{Space: davNamespace, Local: "getcontenttype"}, value: "text/calendar; charset=utf-8; component=VTODO"},
+		{name: xml.Name{Space: davNamespace, Local: "getetag"}, value: xmlEscape(taskETag(data))},
+		{name: xml.Name{Space: caldavNamespace, Local: "calendar-data"}, value: xmlEscape(string(data))},
+	}
+}
+
+// selectProps picks the requested properties out of available. calendar-data is not
+// part of allprop, as clients fetch it on purpose.
+func (req davPropRequest) selectProps(href string, available []davProp) davResponse {
+	response := davResponse{href: href}
+	if req.AllProp != nil || req.PropName != nil || len(req.Prop.Names) == 0 {
+		for _, prop := range available {
+			if prop.name.Local == "calendar-data" {
+				continue
+			}
+			if req.PropName != nil {
+				prop.value = ""
+			}
+			response.found = append(response.found, prop)
+		}
+		return response
+	}
+
+	for _, requested := range req.Prop.Names {
+		found := false
+		for _, prop := range available {
+			if prop.name == requested.XMLName {
+				response.found = append(response.found, prop)
+				found = true
+				break
+			}
+		}
+		if !found {
+			response.missing = append(response.missing, requested.XMLName)
+		}
+	}
+	return response
+}
+
+// matches applies a calendar-query filter rooted at VCALENDAR to the VTODO of task.
+func (f davCompFilter) matches(task domain.Task) bool {
+	if f.Name != "VCALENDAR" || f.IsNotDefined != nil {
+		return false
+	}
+	for _, comp := range f.CompFilters {
+		if comp.Name != "VTODO" {
+			return comp.IsNotDefined != nil
+		}
+		if comp.IsNotDefined != nil || !comp.matchesTodo(task) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f davCompFilter) matchesTodo(task domain.Task) bool {
+	if f.TimeRange != nil && !f.TimeRange.matches(task) {
+		return false
+	}
+	// A VTODO has no nested components other than VALARM, which tasks do not have.
+	for _, comp := range f.CompFilters {
+		if comp.IsNotDefined == nil {
+			return false
+		}
+	}
+
+	props := map[string]string{
+		"UID":         task.ID.String(),
+		"SUMMARY":     task.Title,
+		"DESCRIPTION": task.Description,
+		"STATUS":      icsStatus(calendarTypeTodo, task.Status),
+		"CREATED":     task.CreatedAt.UTC().Format(icsTimeFormat),
+		"DTSTAMP":     task.CreatedAt.UTC().Format(icsTimeFormat),
+	}
+	if !task.DueDate.IsZero() {
+		props["DUE"] = task.DueDate.UTC().Format(icsTimeFormat)
+	}
+	for _, filter := range f.PropFilters {
+		value, defined := props[strings.ToUpper(filter.Name)]
+		if filter.IsNotDefined != nil {
+			if defined {
+				return false
+			}
+			continue
+		}
+		if !defined {
+			return false
+		}
+		if filter.TextMatch != nil {
+			contains := strings.Contains(strings.ToLower(value), strings.ToLower(filter.TextMatch.Text))
+			if contains == (filter.TextMatch.Negate == "yes") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matches follows RFC 4791 section 9.9 for the VTODO properties tasks have: a task is
+// placed at its due date or, without one, at its creation.
+func (tr davTimeRange) matches(task domain.Task) bool {
+	start, err := time.Parse(icsTimeFormat, tr.Start)
+	if err != nil {
+		start = time.Time{}
+	}
+	end, err := time.Parse(icsTimeFormat, tr.End)
+	if err != nil {
+		end = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+	}
+
+	if task.DueDate.IsZero() {
+		return end.After(task.CreatedAt)
+	}
+	return !start.After(task.DueDate) && end.After(task.DueDate)
+}
+
+// checkDAVPreconditions evaluates If-Match and If-None-Match against the current state
+// of the task, so that clients do not overwrite changes they have not seen.
+func checkDAVPreconditions(r *http.Request, task domain.Task, exists bool) bool {
+	etag := ""
+	if exists {
+		etag = taskETag(taskICS(task))
+	}
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		if !exists || (ifMatch != "*" && !etagListContains(ifMatch, etag)) {
+			return false
+		}
+	}
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" && exists {
+		if ifNoneMatch == "*" || etagListContains(ifNoneMatch, etag) {
+			return false
+		}
+	}
+	return true
+}
+
+func etagListContains(list, etag string) bool {
+	for _, candidate := range strings.Split(list, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// taskStatusFromICS maps a VTODO status back onto a task status. IN-PROCESS is what
+// tasks with a status of their own are published as, so such a status is kept.
+func taskStatusFromICS(status, previous string) string {
+	switch status {
+	case "COMPLETED":
+		return domain.StatusDone
+	case "IN-PROCESS":
+		if previous != "" && previous != domain.StatusPending && previous != domain.StatusDone {
+			return previous
+		}
+	}
+	return domain.StatusPending
+}
+
+func taskICS(task domain.Task) []byte {
+	var buf bytes.Buffer
+	encoder := &icsEncoder{w: &buf, componentType: calendarTypeTodo}
+	// Writing to a bytes.Buffer does not fail.
+	_ = encoder.Begin()
+	_ = encoder.Encode(task)
+	_ = encoder.End()
+	return buf.Bytes()
+}
+
+func taskETag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// decodeDAVBody reads an optional XML request body; an empty body leaves v untouched.
+func decodeDAVBody(r *http.Request, v any) error {
+	data, err := io.ReadAll(io.LimitReader(r.Body, caldavMaxBodySize))
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	if err := xml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid request body: %v", err)
+	}
+	return nil
+}
+
+func writeMultistatus(w http.ResponseWriter, responses []davResponse) {
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<d:multistatus xmlns:d="DAV:" xmlns:c="` + caldavNamespace + `" xmlns:cs="` + calServerNamespace + `">`)
+	for _, response := range responses {
+		sb.WriteString("<d:response>" + davHref(response.href))
+		if response.status != 0 {
+			sb.WriteString(davStatus(response.status))
+		}
+		if len(response.found) > 0 {
+			sb.WriteString("<d:propstat><d:prop>")
+			for _, prop := range response.found {
+				sb.WriteString(davElement(prop.name, prop.value))
+			}
+			sb.WriteString("</d:prop>" + davStatus(http.StatusOK) + "</d:propstat>")
+		}
+		if len(response.missing) > 0 {
+			sb.WriteString("<d:propstat><d:prop>")
+			for _, name := range response.missing {
+				sb.WriteString(davElement(name, ""))
+			}
+			sb.WriteString("</d:prop>" + davStatus(http.StatusNotFound) + "</d:propstat>")
+		}
+		sb.WriteString("</d:response>")
+	}
+	sb.WriteString("</d:multistatus>\n")
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	_, _ = io.WriteString(w, sb.String())
+}
+
+// davElement writes an element with the given inner XML, declaring its namespace when
+// it is not one of the namespaces declared on the multistatus.
+func davElement(name xml.Name, inner string) string {
+	tag := name.Local
+	declaration := ""
+	if prefix, ok := davPrefixes[name.Space]; ok {
+		tag = prefix + ":" + name.Local
+	} else {
+		tag = "x:" + name.Local
+		declaration = ` xmlns:x="` + xmlEscape(name.Space) + `"`
+	}
+	if inner == "" {
+		return "<" + tag + declaration + "/>"
+	}
+	return "<" + tag + declaration + ">" + inner + "</" + tag + ">"
+}
+
+func davHref(href string) string {
+	return "<d:href>" + xmlEscape(href) + "</d:href>"
+}
+
+func davStatus(code int) string {
+	return fmt.Sprintf("<d:status>HTTP/1.1 %d %s</d:status>", code, http.StatusText(code))
+}
+
+// xmlEscape escapes character data. Carriage returns are written as references, as
+// XML parsers would otherwise drop them from the CRLF line endings of calendar data.
+func xmlEscape(s string) string {
+	return xmlEscaper.Replace(s)
+}
+
+func caldavTaskError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, domain.ErrTaskNotFound) {
+		caldavError(w, r, http.StatusNotFound, "task not found")
+		return
+	}
+	caldavError(w, r, serverErrorStatus(err), err.Error())
+}
+
+func caldavError(w http.ResponseWriter, r *http.Request, code int, message string) {
+	render.Status(r, code)
+	render.JSON(w, r, ErrorResponse{
+		Code:    code,
+		Message: message,
+	})
+}
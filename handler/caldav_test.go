@@ -0,0 +1,326 @@
+package handler
+
+import (
+	"api/domain"
+	mock "api/mocks/mock_uc"
+	"bufio"
+	"bytes"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// readRecordedRequest reads a request captured from a CalDAV client. The files hold
+// the request line and headers followed by the body, without a Content-Length.
+func readRecordedRequest(t *testing.T, name string) *http.Request {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "caldav", name))
+	require.NoError(t, err)
+
+	reader := bufio.NewReader(bytes.NewReader(data))
+	req, err := http.ReadRequest(reader)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	return req
+}
+
+// recordedResponse renders the parts of a response clients act upon.
+func recordedResponse(recorder *httptest.ResponseRecorder) []byte {
+	var out bytes.Buffer
+	out.WriteString("HTTP " + strconv.Itoa(recorder.Code) + "\n")
+	for _, header := range []string{"Allow", "Content-Type", "DAV", "ETag", "Location"} {
+		if value := recorder.Header().Get(header); value != "" {
+			out.WriteString(header + ": " + value + "\n")
+		}
+	}
+	out.WriteString("\n")
+	out.Write(recorder.Body.Bytes())
+	return out.Bytes()
+}
+
+func newCalDAVRouter(handler *CalDAVHandler) http.Handler {
+	chi.RegisterMethod("PROPFIND")
+	chi.RegisterMethod("REPORT")
+	r := chi.NewRouter()
+	r.Get("/.well-known/caldav", handler.WellKnown)
+	r.Handle("/caldav", handler)
+	r.Handle("/caldav/*", handler)
+	return r
+}
+
+func TestCalDAV_RecordedRequests(t *testing.T) {
+	tasks := getExportTasks()
+	newID := uuid.MustParse("0b5c7e1e-2f4e-4d3a-9a57-3c1f0d6c9e01")
+
+	tests := []struct {
+		request string
+		ucMock  func(ucMock mock.MockTasksUC)
+	}{
+		{
+			request: "options.http",
+		},
+		{
+			request: "propfind_principal.http",
+		},
+		{
+			request: "propfind_home.http",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTasks(gomock.Any(), domain.TaskFilter{}).Return(tasks, nil)
+			},
+		},
+		{
+			request: "propfind_calendar.http",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTasks(gomock.Any(), domain.TaskFilter{}).Return(tasks, nil)
+			},
+		},
+		{
+			request: "propfind_etags.http",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTasks(gomock.Any(), domain.TaskFilter{}).Return(tasks, nil)
+			},
+		},
+		{
+			request: "report_multiget.http",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTaskById(gomock.Any(), tasks[0].ID).Return(tasks[0], nil)
+				ucMock.EXPECT().GetTaskById(gomock.Any(), newID).Return(domain.Task{}, domain.ErrTaskNotFound)
+			},
+		},
+		{
+			request: "report_query.http",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTasks(gomock.Any(), domain.TaskFilter{}).Return(tasks, nil)
+			},
+		},
+		{
+			request: "get.http",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTaskById(gomock.Any(), tasks[0].ID).Return(tasks[0], nil)
+			},
+		},
+		{
+			request: "put_create.http",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTaskById(gomock.Any(), newID).Return(domain.Task{}, domain.ErrTaskNotFound)
+				created := domain.Task{
+					ID:          newID,
+					Title:       "Buy milk, eggs and bread",
+					Description: "From the shop on the corner\nbefore it closes. This line is long enough to be folded by the client.",
+					Status:      domain.StatusPending,
+					DueDate:     time.Date(2025, 4, 6, 15, 0, 0, 0, time.UTC),
+				}
+				ucMock.EXPECT().CreateTask(gomock.Any(), created).Return(created, nil)
+			},
+		},
+		{
+			request: "put_complete.http",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				completed := tasks[0]
+				completed.Status = domain.StatusDone
+				ucMock.EXPECT().GetTaskById(gomock.Any(), tasks[0].ID).Return(tasks[0], nil)
+				ucMock.EXPECT().UpdateTask(gomock.Any(), completed).Return(completed, nil)
+			},
+		},
+		{
+			request: "put_stale.http",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTaskById(gomock.Any(), tasks[0].ID).Return(tasks[0], nil)
+			},
+		},
+		{
+			request: "delete.http",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTaskById(gomock.Any(), tasks[0].ID).Return(tasks[0], nil)
+				ucMock.EXPECT().DeleteTask(gomock.Any(), tasks[0].ID).Return(tasks[0], nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.request, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			recorder := httptest.NewRecorder()
+			ucMock := mock.NewMockTasksUC(ctrl)
+			if tt.ucMock != nil {
+				tt.ucMock(*ucMock)
+			}
+
+			req := readRecordedRequest(t, tt.request)
+			newCalDAVRouter(NewCalDAVHandler(ucMock, "/caldav")).ServeHTTP(recorder, req)
+			requireGolden(t, filepath.Join("caldav", tt.request[:len(tt.request)-len(".http")]+".response"), recordedResponse(recorder))
+		})
+	}
+}
+
+func TestCalDAV(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		path               string
+		body               string
+		headers            map[string]string
+		ucMock             func(ucMock mock.MockTasksUC)
+		expectedStatusCode int
+	}{
+		{
+			name:               "well-known redirect",
+			method:             http.MethodGet,
+			path:               "/.well-known/caldav",
+			expectedStatusCode: 301,
+		},
+		{
+			name:               "unknown resource",
+			method:             "PROPFIND",
+			path:               "/caldav/other/",
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "task outside the calendar",
+			method:             http.MethodPut,
+			path:               "/caldav/tasks/not-a-uuid.ics",
+			body:               "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n",
+			expectedStatusCode: 403,
+		},
+		{
+			name:               "invalid propfind body",
+			method:             "PROPFIND",
+			path:               "/caldav/",
+			body:               "<propfind",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "unsupported report",
+			method:             "REPORT",
+			path:               "/caldav/tasks/",
+			body:               `<D:sync-collection xmlns:D="DAV:"><D:sync-token/></D:sync-collection>`,
+			expectedStatusCode: 403,
+		},
+		{
+			name:               "report outside the calendar",
+			method:             "REPORT",
+			path:               "/caldav/",
+			body:               `<C:calendar-query xmlns:C="urn:ietf:params:xml:ns:caldav"/>`,
+			expectedStatusCode: 403,
+		},
+		{
+			name:   "get missing task",
+			method: http.MethodGet,
+			path:   "/caldav/tasks/1461ec84-ccff-4f3c-af34-65d0856ac3ce.ics",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTaskById(gomock.Any(), gomock.Any()).Return(domain.Task{}, domain.ErrTaskNotFound)
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "put without VTODO",
+			method:             http.MethodPut,
+			path:               "/caldav/tasks/1461ec84-ccff-4f3c-af34-65d0856ac3ce.ics",
+			body:               "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nEND:VCALENDAR\r\n",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "put with a UID not matching the resource",
+			method:             http.MethodPut,
+			path:               "/caldav/tasks/1461ec84-ccff-4f3c-af34-65d0856ac3ce.ics",
+			body:               "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:other@example.com\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+			expectedStatusCode: 400,
+		},
+		{
+			name:    "put over an existing task with If-None-Match",
+			method:  http.MethodPut,
+			path:    "/caldav/tasks/1461ec84-ccff-4f3c-af34-65d0856ac3ce.ics",
+			body:    "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:1461ec84-ccff-4f3c-af34-65d0856ac3ce\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+			headers: map[string]string{"If-None-Match": "*"},
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTaskById(gomock.Any(), gomock.Any()).Return(getExportTasks()[0], nil)
+			},
+			expectedStatusCode: 412,
+		},
+		{
+			name:    "put with If-Match on a missing task",
+			method:  http.MethodPut,
+			path:    "/caldav/tasks/1461ec84-ccff-4f3c-af34-65d0856ac3ce.ics",
+			body:    "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:1461ec84-ccff-4f3c-af34-65d0856ac3ce\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+			headers: map[string]string{"If-Match": `"abc"`},
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTaskById(gomock.Any(), gomock.Any()).Return(domain.Task{}, domain.ErrTaskNotFound)
+			},
+			expectedStatusCode: 412,
+		},
+		{
+			name:   "put over a task in the trash",
+			method: http.MethodPut,
+			path:   "/caldav/tasks/1461ec84-ccff-4f3c-af34-65d0856ac3ce.ics",
+			body:   "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:1461ec84-ccff-4f3c-af34-65d0856ac3ce\r\nSUMMARY:Do unit tests\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTaskById(gomock.Any(), gomock.Any()).Return(domain.Task{}, domain.ErrTaskNotFound)
+				ucMock.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Return(domain.Task{}, domain.ErrTaskExists)
+			},
+			expectedStatusCode: 409,
+		},
+		{
+			name:    "put over a task in the trash with If-None-Match",
+			method:  http.MethodPut,
+			path:    "/caldav/tasks/1461ec84-ccff-4f3c-af34-65d0856ac3ce.ics",
+			body:    "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:1461ec84-ccff-4f3c-af34-65d0856ac3ce\r\nSUMMARY:Do unit tests\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+			headers: map[string]string{"If-None-Match": "*"},
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTaskById(gomock.Any(), gomock.Any()).Return(domain.Task{}, domain.ErrTaskNotFound)
+				ucMock.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Return(domain.Task{}, domain.ErrTaskExists)
+			},
+			expectedStatusCode: 412,
+		},
+		{
+			name:   "delete missing task",
+			method: http.MethodDelete,
+			path:   "/caldav/tasks/1461ec84-ccff-4f3c-af34-65d0856ac3ce.ics",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTaskById(gomock.Any(), gomock.Any()).Return(domain.Task{}, domain.ErrTaskNotFound)
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "delete the calendar",
+			method:             http.MethodDelete,
+			path:               "/caldav/tasks/",
+			expectedStatusCode: 403,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			recorder := httptest.NewRecorder()
+			ucMock := mock.NewMockTasksUC(ctrl)
+			if tt.ucMock != nil {
+				tt.ucMock(*ucMock)
+			}
+
+			req, err := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			newCalDAVRouter(NewCalDAVHandler(ucMock, "/caldav")).ServeHTTP(recorder, req)
+			require.Equal(t, tt.expectedStatusCode, recorder.Code)
+		})
+	}
+}
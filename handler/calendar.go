@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"api/domain"
+	"api/uc"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const (
+	calendarTypeTodo  = "todo"
+	calendarTypeEvent = "event"
+)
+
+type CalendarHandler struct {
+	calendarService uc.CalendarUC
+	tasksService    uc.TasksUC
+}
+
+func NewCalendarHandler(calendarService uc.CalendarUC, tasksService uc.TasksUC) *CalendarHandler {
+	return &CalendarHandler{calendarService: calendarService, tasksService: tasksService}
+}
+
+type CalendarFeedRequest struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// CalendarFeedResponse is returned whenever a feed gets a new token. The token is not
+// stored in clear, so this is the only time it is shown.
+type CalendarFeedResponse struct {
+	domain.CalendarFeed
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+func (ch CalendarHandler) CreateFeed(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := getContextFromRequest(r)
+	defer cancel()
+
+	var body CalendarFeedRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		code := bodyErrorStatus(err)
+		render.Status(r, code)
+		render.JSON(w, r, ErrorResponse{
+			Code:    code,
+			Message: "invalid calendar feed: " + err.Error(),
+		})
+		return
+	}
+	if body.Name == "" {
+		body.Name = "Tasks"
+	}
+
+	feed, token, err := ch.calendarService.CreateFeed(ctx, domain.CalendarFeed{Name: body.Name, Status: body.Status})
+	if err != nil {
+		renderServerError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, CalendarFeedResponse{CalendarFeed: feed, Token: token, URL: calendarFeedURL(r, token)})
+}
+
+func (ch CalendarHandler) RotateFeed(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := getContextFromRequest(r)
+	defer cancel()
+
+	feed, token, err := ch.calendarService.RotateFeed(ctx, chi.URLParam(r, "token"))
+	if err != nil {
+		calendarFeedError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, CalendarFeedResponse{CalendarFeed: feed, Token: token, URL: calendarFeedURL(r, token)})
+}
+
+func (ch CalendarHandler) DeleteFeed(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := getContextFromRequest(r)
+	defer cancel()
+
+	if err := ch.calendarService.DeleteFeed(ctx, chi.URLParam(r, "token")); err != nil {
+		calendarFeedError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetCalendar serves the tasks of the feed identified by the token query param as an
+// RFC 5545 calendar. Tasks are published as to-dos unless type=event is asked for,
+// which suits calendar apps that do not show to-dos. Tasks without a due date are left
+// out.
+func (ch CalendarHandler) GetCalendar(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := getContextFromRequest(r)
+	defer cancel()
+
+	componentType := r.URL.Query().Get("type")
+	if componentType == "" {
+		componentType = calendarTypeTodo
+	}
+	if componentType != calendarTypeTodo && componentType != calendarTypeEvent {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "query param type must be one of todo, event",
+		})
+		return
+	}
+
+	feed, err := ch.calendarService.GetFeed(ctx, r.URL.Query().Get("token"))
+	if err != nil {
+		calendarFeedError(w, r, err)
+		return
+	}
+
+	newEncoder := func(w io.Writer) taskEncoder {
+		return &icsEncoder{w: w, name: feed.Name, componentType: componentType}
+	}
+	streamTasks(w, r, icsContentType, "", newEncoder, func(fn func(task domain.Task) error) error {
+		return ch.tasksService.ExportTasks(ctx, feed.Filter(), func(task domain.Task) error {
+			if task.DueDate.IsZero() {
+				return nil
+			}
+			return fn(task)
+		})
+	})
+}
+
+func calendarFeedError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, domain.ErrCalendarFeedNotFound) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "calendar feed not found",
+		})
+		return
+	}
+
+	renderServerError(w, r, err)
+}
+
+// calendarFeedURL builds the subscription URL from the request, honouring the scheme
+// reported by a TLS terminating proxy.
+func calendarFeedURL(r *http.Request, token string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	feedURL := url.URL{Scheme: scheme, Host: r.Host, Path: "/api/calendar.ics", RawQuery: url.Values{"token": {token}}.Encode()}
+	return feedURL.String()
+}
@@ -0,0 +1,316 @@
+package handler
+
+import (
+	"api/domain"
+	mock "api/mocks/mock_uc"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func getCalendarFeed() domain.CalendarFeed {
+	return domain.CalendarFeed{
+		ID:        uuid.MustParse("5e0c1f53-5d0a-4c39-9d7e-1b1a8f0a7c11"),
+		Name:      "My tasks",
+		Status:    "PENDING",
+		CreatedAt: time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func getCalendarTasks() []domain.Task {
+	tasks := getExportTasks()
+	tasks[0].Description = "A description long enough to be folded with a multi-byte dash– at the fold"
+	return append(tasks, domain.Task{
+		ID:        uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ee"),
+		Title:     "No due date",
+		Status:    "IN_PROGRESS",
+		CreatedAt: time.Date(2025, 4, 2, 11, 0, 0, 0, time.UTC),
+	})
+}
+
+func TestGetCalendar_Golden(t *testing.T) {
+	tests := []struct {
+		query  string
+		tasks  []domain.Task
+		golden string
+	}{
+		{query: "?token=secret", tasks: getCalendarTasks(), golden: "calendar_todo.ics"},
+		{query: "?token=secret&type=event", tasks: getCalendarTasks(), golden: "calendar_event.ics"},
+		{query: "?token=secret", tasks: nil, golden: "calendar_empty.ics"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			r := chi.NewRouter()
+			recorder := httptest.NewRecorder()
+			calendarMock := mock.NewMockCalendarUC(ctrl)
+			tasksMock := mock.NewMockTasksUC(ctrl)
+			handler := NewCalendarHandler(calendarMock, tasksMock)
+
+			calendarMock.EXPECT().GetFeed(gomock.Any(), "secret").Return(getCalendarFeed(), nil)
+			tasksMock.EXPECT().ExportTasks(gomock.Any(), domain.TaskFilter{Status: "PENDING"}, gomock.Any()).DoAndReturn(exportTasks(tt.tasks))
+
+			r.Get("/api/calendar.ics", handler.GetCalendar)
+			req, err := http.NewRequest(http.MethodGet, "/api/calendar.ics"+tt.query, nil)
+			require.NoError(t, err)
+
+			r.ServeHTTP(recorder, req)
+			require.Equal(t, http.StatusOK, recorder.Code)
+			require.Equal(t, "text/calendar; charset=utf-8", recorder.Header().Get("Content-Type"))
+			requireGolden(t, tt.golden, recorder.Body.Bytes())
+
+			for _, line := range strings.Split(strings.TrimSuffix(recorder.Body.String(), "\r\n"), "\r\n") {
+				require.LessOrEqual(t, len(line), 75)
+			}
+		})
+	}
+}
+
+func TestGetCalendar(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		ucMock             func(calendarMock mock.MockCalendarUC, tasksMock mock.MockTasksUC)
+		expectedStatusCode int
+	}{
+		{
+			name:               "unknown type",
+			query:              "?token=secret&type=journal",
+			expectedStatusCode: 400,
+		},
+		{
+			name:  "unknown token",
+			query: "?token=secret",
+			ucMock: func(calendarMock mock.MockCalendarUC, tasksMock mock.MockTasksUC) {
+				calendarMock.EXPECT().GetFeed(gomock.Any(), "secret").Return(domain.CalendarFeed{}, domain.ErrCalendarFeedNotFound)
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name:  "feed error",
+			query: "?token=secret",
+			ucMock: func(calendarMock mock.MockCalendarUC, tasksMock mock.MockTasksUC) {
+				calendarMock.EXPECT().GetFeed(gomock.Any(), "secret").Return(domain.CalendarFeed{}, errors.New("db error"))
+			},
+			expectedStatusCode: 500,
+		},
+		{
+			name:  "tasks error",
+			query: "?token=secret",
+			ucMock: func(calendarMock mock.MockCalendarUC, tasksMock mock.MockTasksUC) {
+				calendarMock.EXPECT().GetFeed(gomock.Any(), "secret").Return(getCalendarFeed(), nil)
+				tasksMock.EXPECT().ExportTasks(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			r := chi.NewRouter()
+			recorder := httptest.NewRecorder()
+			calendarMock := mock.NewMockCalendarUC(ctrl)
+			tasksMock := mock.NewMockTasksUC(ctrl)
+			handler := NewCalendarHandler(calendarMock, tasksMock)
+
+			if tt.ucMock != nil {
+				tt.ucMock(*calendarMock, *tasksMock)
+			}
+
+			r.Get("/api/calendar.ics", handler.GetCalendar)
+			req, err := http.NewRequest(http.MethodGet, "/api/calendar.ics"+tt.query, nil)
+			require.NoError(t, err)
+
+			r.ServeHTTP(recorder, req)
+			require.Equal(t, tt.expectedStatusCode, recorder.Code)
+		})
+	}
+}
+
+func TestCreateFeed(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		headers            map[string]string
+		ucMock             func(calendarMock mock.MockCalendarUC)
+		expectedStatusCode int
+		expectedURL        string
+	}{
+		{
+			name:    "happy path - OK",
+			body:    `{"name":"My tasks","status":"PENDING"}`,
+			headers: map[string]string{"X-Forwarded-Proto": "https"},
+			ucMock: func(calendarMock mock.MockCalendarUC) {
+				calendarMock.EXPECT().CreateFeed(gomock.Any(), domain.CalendarFeed{Name: "My tasks", Status: "PENDING"}).Return(getCalendarFeed(), "secret", nil)
+			},
+			expectedStatusCode: 201,
+			expectedURL:        "https://tasks.example.com/api/calendar.ics?token=secret",
+		},
+		{
+			name: "empty body gets the default name",
+			ucMock: func(calendarMock mock.MockCalendarUC) {
+				calendarMock.EXPECT().CreateFeed(gomock.Any(), domain.CalendarFeed{Name: "Tasks"}).Return(getCalendarFeed(), "secret", nil)
+			},
+			expectedStatusCode: 201,
+			expectedURL:        "http://tasks.example.com/api/calendar.ics?token=secret",
+		},
+		{
+			name:               "invalid body",
+			body:               `{"name":`,
+			expectedStatusCode: 400,
+		},
+		{
+			name: "error",
+			body: `{"name":"My tasks"}`,
+			ucMock: func(calendarMock mock.MockCalendarUC) {
+				calendarMock.EXPECT().CreateFeed(gomock.Any(), gomock.Any()).Return(domain.CalendarFeed{}, "", errors.New("db error"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			r := chi.NewRouter()
+			recorder := httptest.NewRecorder()
+			calendarMock := mock.NewMockCalendarUC(ctrl)
+			handler := NewCalendarHandler(calendarMock, mock.NewMockTasksUC(ctrl))
+
+			if tt.ucMock != nil {
+				tt.ucMock(*calendarMock)
+			}
+
+			r.Post("/api/calendar/feeds", handler.CreateFeed)
+			req, err := http.NewRequest(http.MethodPost, "http://tasks.example.com/api/calendar/feeds", bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			r.ServeHTTP(recorder, req)
+			require.Equal(t, tt.expectedStatusCode, recorder.Code)
+
+			if tt.expectedURL != "" {
+				var response CalendarFeedResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, "secret", response.Token)
+				require.Equal(t, tt.expectedURL, response.URL)
+				require.Equal(t, getCalendarFeed().ID, response.ID)
+			}
+		})
+	}
+}
+
+func TestRotateFeed(t *testing.T) {
+	tests := []struct {
+		name               string
+		ucMock             func(calendarMock mock.MockCalendarUC)
+		expectedStatusCode int
+	}{
+		{
+			name: "happy path - OK",
+			ucMock: func(calendarMock mock.MockCalendarUC) {
+				calendarMock.EXPECT().RotateFeed(gomock.Any(), "secret").Return(getCalendarFeed(), "new-secret", nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name: "unknown token",
+			ucMock: func(calendarMock mock.MockCalendarUC) {
+				calendarMock.EXPECT().RotateFeed(gomock.Any(), "secret").Return(domain.CalendarFeed{}, "", domain.ErrCalendarFeedNotFound)
+			},
+			expectedStatusCode: 404,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			r := chi.NewRouter()
+			recorder := httptest.NewRecorder()
+			calendarMock := mock.NewMockCalendarUC(ctrl)
+			handler := NewCalendarHandler(calendarMock, mock.NewMockTasksUC(ctrl))
+			tt.ucMock(*calendarMock)
+
+			r.Post("/api/calendar/feeds/{token}/rotate", handler.RotateFeed)
+			req, err := http.NewRequest(http.MethodPost, "/api/calendar/feeds/secret/rotate", nil)
+			require.NoError(t, err)
+
+			r.ServeHTTP(recorder, req)
+			require.Equal(t, tt.expectedStatusCode, recorder.Code)
+			if recorder.Code == http.StatusOK {
+				require.Contains(t, recorder.Body.String(), "token=new-secret")
+			}
+		})
+	}
+}
+
+func TestDeleteFeed(t *testing.T) {
+	tests := []struct {
+		name               string
+		ucMock             func(calendarMock mock.MockCalendarUC)
+		expectedStatusCode int
+	}{
+		{
+			name: "happy path - OK",
+			ucMock: func(calendarMock mock.MockCalendarUC) {
+				calendarMock.EXPECT().DeleteFeed(gomock.Any(), "secret").Return(nil)
+			},
+			expectedStatusCode: 204,
+		},
+		{
+			name: "unknown token",
+			ucMock: func(calendarMock mock.MockCalendarUC) {
+				calendarMock.EXPECT().DeleteFeed(gomock.Any(), "secret").Return(domain.ErrCalendarFeedNotFound)
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name: "error",
+			ucMock: func(calendarMock mock.MockCalendarUC) {
+				calendarMock.EXPECT().DeleteFeed(gomock.Any(), "secret").Return(errors.New("db error"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			r := chi.NewRouter()
+			recorder := httptest.NewRecorder()
+			calendarMock := mock.NewMockCalendarUC(ctrl)
+			handler := NewCalendarHandler(calendarMock, mock.NewMockTasksUC(ctrl))
+			tt.ucMock(*calendarMock)
+
+			r.Delete("/api/calendar/feeds/{token}", handler.DeleteFeed)
+			req, err := http.NewRequest(http.MethodDelete, "/api/calendar/feeds/secret", nil)
+			require.NoError(t, err)
+
+			r.ServeHTTP(recorder, req)
+			require.Equal(t, tt.expectedStatusCode, recorder.Code)
+		})
+	}
+}
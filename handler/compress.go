@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"bufio"
+	"errors"
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"io"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// responseEncoder is implemented by the gzip, zstd and brotli writers, which are reset
+// and reused across responses.
+type responseEncoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// compressionEncodings are in the order of preference of the server, which settles ties
+// between encodings the client accepts equally.
+var compressionEncodings = []string{"zstd", "br", "gzip"}
+
+var encoderPools = map[string]*sync.Pool{
+	"zstd": {New: func() any {
+		encoder, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		return encoder
+	}},
+	"br": {New: func() any {
+		// Level 5 is about as fast as gzip while still compressing better.
+		return brotli.NewWriterLevel(nil, 5)
+	}},
+	"gzip": {New: func() any {
+		return gzip.NewWriter(nil)
+	}},
+}
+
+// Compress compresses the responses of at least minSize bytes with the encoding the
+// client prefers in its Accept-Encoding header. Smaller responses are not worth the
+// overhead, and responses flushed before reaching minSize, like the event stream, are
+// sent as they are. A negative minSize disables compression.
+func Compress(minSize int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if minSize < 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead || r.Header.Get("Upgrade") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, encoding: encoding, minSize: minSize}
+			defer cw.Close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding returns the supported encoding with the highest quality in
+// acceptEncoding, or "" when the response has to be sent as it is.
+func negotiateEncoding(acceptEncoding string) string {
+	qualities := map[string]float64{}
+	for _, entry := range parseQualities(acceptEncoding) {
+		qualities[entry.value] = entry.quality
+	}
+
+	best, bestQuality := "", 0.0
+	for _, encoding := range compressionEncodings {
+		quality, ok := qualities[encoding]
+		if !ok {
+			quality = qualities["*"]
+		}
+		if quality > bestQuality {
+			best, bestQuality = encoding, quality
+		}
+	}
+	return best
+}
+
+// compressWriter holds the body back until minSize bytes have been written, and then
+// decides whether to compress it. The status is held back with it, as the headers can
+// only be changed before it is sent.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	status  int
+	buf     []byte
+	started bool
+	encoder responseEncoder
+}
+
+func (cw *compressWriter) WriteHeader(statusCode int) {
+	if statusCode < 200 {
+		cw.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+	if cw.status != 0 {
+		return
+	}
+	cw.status = statusCode
+
+	// Whatever cannot be compressed is sent right away.
+	headers := cw.Header()
+	contentLength, err := strconv.Atoi(headers.Get("Content-Length"))
+	if headers.Get("Content-Encoding") != "" ||
+		statusCode == http.StatusNoContent || statusCode == http.StatusNotModified ||
+		(err == nil && contentLength < cw.minSize) ||
+		!compressible(headers.Get("Content-Type")) {
+		cw.start(false)
+	}
+}
+
+func (cw *compressWriter) Write(data []byte) (int, error) {
+	if cw.status == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.started {
+		if cw.encoder != nil {
+			return cw.encoder.Write(data)
+		}
+		return cw.ResponseWriter.Write(data)
+	}
+
+	cw.buf = append(cw.buf, data...)
+	if len(cw.buf) >= cw.minSize {
+		if cw.Header().Get("Content-Type") == "" {
+			cw.Header().Set("Content-Type", http.DetectContentType(cw.buf))
+		}
+		if err := cw.start(compressible(cw.Header().Get("Content-Type"))); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+// start sends the headers and the held back body, through the encoder when compress
+// is set.
+func (cw *compressWriter) start(compress bool) error {
+	cw.started = true
+	if compress {
+		cw.Header().Set("Content-Encoding", cw.encoding)
+		cw.Header().Del("Content-Length")
+		cw.encoder = encoderPools[cw.encoding].Get().(responseEncoder)
+		cw.encoder.Reset(cw.ResponseWriter)
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	if len(cw.buf) == 0 {
+		return nil
+	}
+	var err error
+	if cw.encoder != nil {
+		_, err = cw.encoder.Write(cw.buf)
+	} else {
+		_, err = cw.ResponseWriter.Write(cw.buf)
+	}
+	cw.buf = nil
+	return err
+}
+
+// Close sends what is still held back and finishes the compressed stream.
+func (cw *compressWriter) Close() {
+	if !cw.started {
+		if cw.status == 0 {
+			return
+		}
+		if err := cw.start(false); err != nil {
+			log.Printf("error while writing response: %v", err)
+		}
+		return
+	}
+	if cw.encoder == nil {
+		return
+	}
+
+	if err := cw.encoder.Close(); err != nil {
+		log.Printf("error while compressing response: %v", err)
+	}
+	cw.encoder.Reset(nil)
+	encoderPools[cw.encoding].Put(cw.encoder)
+	cw.encoder = nil
+}
+
+func (cw *compressWriter) Flush() {
+	if cw.status == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if !cw.started {
+		if err := cw.start(false); err != nil {
+			return
+		}
+	}
+	if cw.encoder != nil {
+		if err := cw.encoder.Flush(); err != nil {
+			return
+		}
+	}
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (cw *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := cw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response does not implement http.Hijacker")
+	}
+	return hijacker.Hijack()
+}
+
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// compressible tells apart the content types which are already compressed, so that
+// compressing them again would only cost time.
+func compressible(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "image/svg+xml":
+		return true
+	case strings.HasPrefix(mediaType, "image/"), strings.HasPrefix(mediaType, "audio/"), strings.HasPrefix(mediaType, "video/"):
+		return false
+	case mediaType == "application/zip", mediaType == "application/gzip", mediaType == "application/zstd", mediaType == "application/pdf":
+		return false
+	case strings.HasPrefix(mediaType, "application/vnd.openxmlformats-officedocument."):
+		return false
+	}
+	return true
+}
@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"api/domain"
+	mock "api/mocks/mock_uc"
+	"bytes"
+	"encoding/json"
+	"github.com/andybalholm/brotli"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func decompress(t *testing.T, encoding string, body []byte) []byte {
+	t.Helper()
+
+	var reader io.Reader
+	switch encoding {
+	case "":
+		return body
+	case "zstd":
+		decoder, err := zstd.NewReader(bytes.NewReader(body))
+		require.NoError(t, err)
+		defer decoder.Close()
+		reader = decoder
+	case "br":
+		reader = brotli.NewReader(bytes.NewReader(body))
+	case "gzip":
+		decoder, err := gzip.NewReader(bytes.NewReader(body))
+		require.NoError(t, err)
+		reader = decoder
+	default:
+		t.Fatalf("unexpected encoding %q", encoding)
+	}
+
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return data
+}
+
+func TestCompress_TaskEndpoints(t *testing.T) {
+	tasksList := make([]domain.Task, 20)
+	for i := range tasksList {
+		tasksList[i] = getExpectedBody()
+	}
+
+	endpoints := []struct {
+		name     string
+		target   string
+		ucMock   func(ucMock *mock.MockTasksUC)
+		expected any
+	}{
+		{
+			name:   "get tasks",
+			target: "/api/tasks",
+			ucMock: func(ucMock *mock.MockTasksUC) {
+				ucMock.EXPECT().GetTasks(gomock.Any(), gomock.Any()).Return(tasksList, nil)
+			},
+			expected: tasksList,
+		},
+		{
+			name:   "get task",
+			target: "/api/task/1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			ucMock: func(ucMock *mock.MockTasksUC) {
+				ucMock.EXPECT().GetTaskById(gomock.Any(), gomock.Any()).Return(getExpectedBody(), nil)
+			},
+			expected: getExpectedBody(),
+		},
+	}
+
+	encodings := []struct {
+		acceptEncoding   string
+		expectedEncoding string
+	}{
+		{acceptEncoding: "zstd", expectedEncoding: "zstd"},
+		{acceptEncoding: "br", expectedEncoding: "br"},
+		{acceptEncoding: "gzip", expectedEncoding: "gzip"},
+		{acceptEncoding: "gzip, deflate, br, zstd", expectedEncoding: "zstd"},
+		{acceptEncoding: "identity", expectedEncoding: ""},
+		{acceptEncoding: "", expectedEncoding: ""},
+	}
+
+	for _, endpoint := range endpoints {
+		for _, encoding := range encodings {
+			t.Run(endpoint.name+" - "+encoding.acceptEncoding, func(t *testing.T) {
+				ucMock := mock.NewMockTasksUC(gomock.NewController(t))
+				endpoint.ucMock(ucMock)
+				tasksHandler := NewTasksHandler(ucMock)
+
+				r := chi.NewRouter()
+				r.Use(Compress(64))
+				r.Use(Negotiate)
+				r.Get("/api/tasks", tasksHandler.GetTasks)
+				r.Get("/api/task/{id}", tasksHandler.GetTaskById)
+
+				req := httptest.NewRequest(http.MethodGet, endpoint.target, nil)
+				req.Header.Set("Accept-Encoding", encoding.acceptEncoding)
+				recorder := httptest.NewRecorder()
+				r.ServeHTTP(recorder, req)
+
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, encoding.expectedEncoding, recorder.Header().Get("Content-Encoding"))
+				require.Equal(t, []string{"Accept-Encoding", "Accept"}, recorder.Header().Values("Vary"))
+				require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+				expected, err := json.Marshal(endpoint.expected)
+				require.NoError(t, err)
+				require.JSONEq(t, string(expected), string(decompress(t, encoding.expectedEncoding, recorder.Body.Bytes())))
+			})
+		}
+	}
+}
+
+func TestCompress_NegotiatedFormat(t *testing.T) {
+	ucMock := mock.NewMockTasksUC(gomock.NewController(t))
+	ucMock.EXPECT().GetTasks(gomock.Any(), gomock.Any()).Return([]domain.Task{getExpectedBody(), getExpectedBody()}, nil)
+	handler := Compress(64)(Negotiate(http.HandlerFunc(NewTasksHandler(ucMock).GetTasks)))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+	req.Header.Set("Accept", "application/msgpack")
+	req.Header.Set("Accept-Encoding", "br")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "br", recorder.Header().Get("Content-Encoding"))
+	require.Equal(t, "application/msgpack", recorder.Header().Get("Content-Type"))
+	expected, err := json.Marshal([]domain.Task{getExpectedBody(), getExpectedBody()})
+	require.NoError(t, err)
+	body := decompress(t, "br", recorder.Body.Bytes())
+	require.JSONEq(t, string(expected), string(decodeAs(t, "application/msgpack", body)))
+}
+
+func TestCompress_SendsAsIs(t *testing.T) {
+	large := strings.Repeat("a", 2048)
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		body    string
+	}{
+		{name: "below the minimum size", method: http.MethodGet, headers: map[string]string{"Content-Type": "application/json"}, body: `{"code":404}`},
+		{name: "already encoded", method: http.MethodGet, headers: map[string]string{"Content-Encoding": "gzip"}, body: large},
+		{name: "compressed content type", method: http.MethodGet, headers: map[string]string{"Content-Type": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"}, body: large},
+		{name: "image", method: http.MethodGet, headers: map[string]string{"Content-Type": "image/png"}, body: large},
+		{name: "small content length", method: http.MethodGet, headers: map[string]string{"Content-Length": "12"}, body: `{"code":404}`},
+		{name: "head request", method: http.MethodHead, body: large},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/tasks", nil)
+			req.Header.Set("Accept-Encoding", "zstd, br, gzip")
+			recorder := httptest.NewRecorder()
+			Compress(1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for name, value := range tt.headers {
+					w.Header().Set(name, value)
+				}
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte(tt.body))
+			})).ServeHTTP(recorder, req)
+
+			require.Equal(t, http.StatusOK, recorder.Code)
+			require.Equal(t, tt.headers["Content-Encoding"], recorder.Header().Get("Content-Encoding"))
+			require.Equal(t, tt.body, recorder.Body.String())
+		})
+	}
+}
+
+func TestCompress_KeepsStatusAndFlushes(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/stream", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	Compress(1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(": connected\n\n"))
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte(strings.Repeat("data: {}\n\n", 200)))
+	})).ServeHTTP(recorder, req)
+
+	// Once flushed below the minimum size, the response is sent as it is.
+	require.True(t, recorder.Flushed)
+	require.Equal(t, http.StatusAccepted, recorder.Code)
+	require.Empty(t, recorder.Header().Get("Content-Encoding"))
+	require.True(t, strings.HasPrefix(recorder.Body.String(), ": connected\n\ndata: {}"))
+}
+
+func TestCompress_Disabled(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	Compress(-1)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("a", 2048)))
+	})).ServeHTTP(recorder, req)
+
+	require.Empty(t, recorder.Header().Get("Content-Encoding"))
+	require.Empty(t, recorder.Header().Get("Vary"))
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		acceptEncoding   string
+		expectedEncoding string
+	}{
+		{acceptEncoding: "gzip", expectedEncoding: "gzip"},
+		{acceptEncoding: "gzip, br", expectedEncoding: "br"},
+		{acceptEncoding: "zstd;q=0.5, gzip", expectedEncoding: "gzip"},
+		{acceptEncoding: "*", expectedEncoding: "zstd"},
+		{acceptEncoding: "*, zstd;q=0", expectedEncoding: "br"},
+		{acceptEncoding: "GZIP;Q=0.8, deflate", expectedEncoding: "gzip"},
+		{acceptEncoding: "deflate, identity", expectedEncoding: ""},
+		{acceptEncoding: "gzip;q=0", expectedEncoding: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			require.Equal(t, tt.expectedEncoding, negotiateEncoding(tt.acceptEncoding))
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"api/domain"
+	"net/http"
+	"time"
+)
+
+// readYourWritesCookie marks the clients which wrote recently, whose reads must not be
+// served by a read replica lagging behind their writes.
+const readYourWritesCookie = "read_your_writes"
+
+// ReadYourWrites sends the reads of a client to the primary database for window after
+// each of its writes, and the reads within a write, e.g. of the task being updated,
+// always. Any request which is not a GET, HEAD or OPTIONS is taken for a write.
+func ReadYourWrites(window time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				if _, err := r.Cookie(readYourWritesCookie); err != nil {
+					next.ServeHTTP(w, r)
+					return
+				}
+			default:
+				http.SetCookie(w, &http.Cookie{
+					Name:     readYourWritesCookie,
+					Value:    "1",
+					Path:     "/",
+					MaxAge:   int(window.Round(time.Second) / time.Second),
+					HttpOnly: true,
+					SameSite: http.SameSiteLaxMode,
+				})
+			}
+			next.ServeHTTP(w, r.WithContext(domain.WithReadYourWrites(r.Context())))
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"api/domain"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReadYourWrites(t *testing.T) {
+	var readYourWrites bool
+	handler := ReadYourWrites(5 * time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		readYourWrites = domain.ReadYourWritesFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// Reads go to the replicas until the client writes.
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/tasks", nil))
+	require.False(t, readYourWrites)
+	require.Empty(t, recorder.Result().Cookies())
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/task", nil))
+	require.True(t, readYourWrites)
+	cookies := recorder.Result().Cookies()
+	require.Len(t, cookies, 1)
+	require.Equal(t, readYourWritesCookie, cookies[0].Name)
+	require.Equal(t, 5, cookies[0].MaxAge)
+	require.True(t, cookies[0].HttpOnly)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+	req.AddCookie(cookies[0])
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	require.True(t, readYourWrites)
+	require.Empty(t, recorder.Result().Cookies())
+}
@@ -1,6 +1,32 @@
 package handler
 
+import (
+	"api/domain"
+	"errors"
+	"github.com/go-chi/render"
+	"net/http"
+)
+
 type ErrorResponse struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
 }
+
+// serverErrorStatus is the status of an error nothing more specific is known about:
+// HTTP 503 while the storage is unreachable, so that clients retry later, and HTTP 500
+// otherwise.
+func serverErrorStatus(err error) int {
+	if errors.Is(err, domain.ErrStorageUnavailable) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+func renderServerError(w http.ResponseWriter, r *http.Request, err error) {
+	code := serverErrorStatus(err)
+	render.Status(r, code)
+	render.JSON(w, r, ErrorResponse{
+		Code:    code,
+		Message: err.Error(),
+	})
+}
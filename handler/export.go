@@ -0,0 +1,311 @@
+package handler
+
+import (
+	"api/domain"
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"github.com/go-chi/render"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// exportColumns are the task fields written by the tabular formats, in order.
+var exportColumns = []string{"id", "title", "description", "status", "due_date", "rrule", "timezone", "created_at"}
+
+// taskEncoder writes tasks in one export format. Begin is called before the first
+// task and End after the last one, also when there are no tasks at all.
+type taskEncoder interface {
+	Begin() error
+	Encode(task domain.Task) error
+	End() error
+}
+
+type exportFormat struct {
+	contentType string
+	extension   string
+	newEncoder  func(w io.Writer) taskEncoder
+}
+
+var exportFormats = map[string]exportFormat{
+	"csv":    {contentType: "text/csv; charset=utf-8", extension: "csv", newEncoder: newCSVEncoder},
+	"ndjson": {contentType: "application/x-ndjson", extension: "ndjson", newEncoder: newNDJSONEncoder},
+	"md":     {contentType: "text/markdown; charset=utf-8", extension: "md", newEncoder: newMarkdownEncoder},
+	"xlsx":   {contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", extension: "xlsx", newEncoder: newXLSXEncoder},
+}
+
+// ExportTasks streams the tasks matching the listing filters in the requested format.
+func (th TasksHandler) ExportTasks(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := getContextFromRequest(r)
+	defer cancel()
+
+	formatName := r.URL.Query().Get("format")
+	format, ok := exportFormats[formatName]
+	if !ok {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "query param format must be one of csv, ndjson, md, xlsx",
+		})
+		return
+	}
+
+	disposition := fmt.Sprintf(`attachment; filename="tasks.%s"`, format.extension)
+	streamTasks(w, r, format.contentType, disposition, format.newEncoder, func(fn func(task domain.Task) error) error {
+		return th.tasksService.ExportTasks(ctx, taskFilterFromQuery(r), fn)
+	})
+}
+
+// streamTasks encodes the tasks passed to fn by export. Nothing is written before the
+// first task has been read, so a failing query is still answered with an error status;
+// a failure in the middle of the stream can only cut the download short.
+func streamTasks(w http.ResponseWriter, r *http.Request, contentType, disposition string, newEncoder func(w io.Writer) taskEncoder, export func(fn func(task domain.Task) error) error) {
+	bw := bufio.NewWriter(w)
+	encoder := newEncoder(bw)
+	started := false
+	begin := func() error {
+		started = true
+		w.Header().Set("Content-Type", contentType)
+		if disposition != "" {
+			w.Header().Set("Content-Disposition", disposition)
+		}
+		w.WriteHeader(http.StatusOK)
+		return encoder.Begin()
+	}
+
+	err := export(func(task domain.Task) error {
+		if !started {
+			if err := begin(); err != nil {
+				return err
+			}
+		}
+		return encoder.Encode(task)
+	})
+	if err == nil && !started {
+		err = begin()
+	}
+	if err == nil {
+		err = encoder.End()
+	}
+	if err == nil {
+		err = bw.Flush()
+	}
+
+	if err != nil {
+		if started {
+			log.Printf("error while streaming tasks: %v", err)
+			return
+		}
+		renderServerError(w, r, err)
+	}
+}
+
+func exportRecord(task domain.Task) []string {
+	return []string{
+		task.ID.String(),
+		task.Title,
+		task.Description,
+		task.Status,
+		task.DueDate.UTC().Format(time.RFC3339),
+		task.RRule,
+		task.Timezone,
+		task.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+type csvEncoder struct {
+	writer *csv.Writer
+}
+
+func newCSVEncoder(w io.Writer) taskEncoder {
+	return &csvEncoder{writer: csv.NewWriter(w)}
+}
+
+func (ce *csvEncoder) Begin() error {
+	return ce.writer.Write(exportColumns)
+}
+
+func (ce *csvEncoder) Encode(task domain.Task) error {
+	return ce.writer.Write(exportRecord(task))
+}
+
+func (ce *csvEncoder) End() error {
+	ce.writer.Flush()
+	return ce.writer.Error()
+}
+
+type ndjsonEncoder struct {
+	encoder *json.Encoder
+}
+
+func newNDJSONEncoder(w io.Writer) taskEncoder {
+	return &ndjsonEncoder{encoder: json.NewEncoder(w)}
+}
+
+func (ne *ndjsonEncoder) Begin() error {
+	return nil
+}
+
+func (ne *ndjsonEncoder) Encode(task domain.Task) error {
+	return ne.encoder.Encode(task)
+}
+
+func (ne *ndjsonEncoder) End() error {
+	return nil
+}
+
+// markdownEncoder writes a GitHub flavoured Markdown table.
+type markdownEncoder struct {
+	w io.Writer
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "|", `\|`, "<", "&lt;", ">", "&gt;", "\r\n", "<br>", "\n", "<br>", "\r", "<br>")
+
+func newMarkdownEncoder(w io.Writer) taskEncoder {
+	return &markdownEncoder{w: w}
+}
+
+func (me *markdownEncoder) Begin() error {
+	if err := me.writeRow(exportColumns); err != nil {
+		return err
+	}
+	separators := make([]string, len(exportColumns))
+	for i := range separators {
+		separators[i] = "---"
+	}
+	return me.writeRow(separators)
+}
+
+func (me *markdownEncoder) Encode(task domain.Task) error {
+	record := exportRecord(task)
+	for i, value := range record {
+		record[i] = markdownEscaper.Replace(value)
+	}
+	return me.writeRow(record)
+}
+
+func (me *markdownEncoder) End() error {
+	return nil
+}
+
+func (me *markdownEncoder) writeRow(cells []string) error {
+	_, err := io.WriteString(me.w, "| "+strings.Join(cells, " | ")+" |\n")
+	return err
+}
+
+// xlsxEncoder writes a single-sheet Office Open XML workbook. The package parts are
+// written up front and the worksheet last, so its rows can be streamed into the zip
+// archive without buffering the sheet.
+type xlsxEncoder struct {
+	archive *zip.Writer
+	sheet   io.Writer
+	row     int
+}
+
+// xlsxModified is the modification time of every part, which keeps exports of the
+// same tasks byte for byte identical.
+var xlsxModified = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{
+		name: "[Content_Types].xml",
+		content: xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`,
+	},
+	{
+		name: "_rels/.rels",
+		content: xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`,
+	},
+	{
+		name: "xl/workbook.xml",
+		content: xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Tasks" sheetId="1" r:id="rId1"/></sheets>` +
+			`</workbook>`,
+	},
+	{
+		name: "xl/_rels/workbook.xml.rels",
+		content: xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`,
+	},
+}
+
+func newXLSXEncoder(w io.Writer) taskEncoder {
+	return &xlsxEncoder{archive: zip.NewWriter(w)}
+}
+
+func (xe *xlsxEncoder) Begin() error {
+	for _, part := range xlsxParts {
+		pw, err := xe.createPart(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(pw, part.content); err != nil {
+			return err
+		}
+	}
+
+	sheet, err := xe.createPart("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	xe.sheet = sheet
+
+	_, err = io.WriteString(xe.sheet, xml.Header+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	if err != nil {
+		return err
+	}
+	return xe.writeRow(exportColumns)
+}
+
+func (xe *xlsxEncoder) Encode(task domain.Task) error {
+	return xe.writeRow(exportRecord(task))
+}
+
+func (xe *xlsxEncoder) End() error {
+	if _, err := io.WriteString(xe.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return xe.archive.Close()
+}
+
+func (xe *xlsxEncoder) createPart(name string) (io.Writer, error) {
+	return xe.archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: xlsxModified})
+}
+
+// writeRow writes cells as inline strings, which spares the shared strings table that
+// would otherwise have to be held in memory until the end.
+func (xe *xlsxEncoder) writeRow(cells []string) error {
+	xe.row++
+	row := strconv.Itoa(xe.row)
+
+	var sb strings.Builder
+	sb.WriteString(`<row r="` + row + `">`)
+	for i, cell := range cells {
+		sb.WriteString(`<c r="` + string(rune('A'+i)) + row + `" t="inlineStr"><is><t xml:space="preserve">`)
+		if err := xml.EscapeText(&sb, []byte(cell)); err != nil {
+			return err
+		}
+		sb.WriteString(`</t></is></c>`)
+	}
+	sb.WriteString(`</row>`)
+
+	_, err := io.WriteString(xe.sheet, sb.String())
+	return err
+}
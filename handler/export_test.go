@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"api/domain"
+	mock "api/mocks/mock_uc"
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+func getExportTasks() []domain.Task {
+	return []domain.Task{
+		{
+			ID:          uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce"),
+			Title:       "Do unit tests",
+			Description: "Create extensive unit tests for all layers",
+			Status:      "PENDING",
+			DueDate:     time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC),
+			CreatedAt:   time.Date(2025, 4, 1, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			ID:          uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3dd"),
+			Title:       `Escape "quotes", | pipes & <tags>`,
+			Description: "Line one\nLine two with a back\\slash – and ünicode",
+			Status:      "DONE",
+			DueDate:     time.Date(2025, 4, 30, 7, 0, 0, 0, time.UTC),
+			RRule:       "FREQ=MONTHLY;BYMONTHDAY=-1",
+			Timezone:    "Europe/Sofia",
+			CreatedAt:   time.Date(2025, 4, 2, 10, 0, 0, 0, time.UTC),
+		},
+	}
+}
+
+// exportTasks stands in for the use case and streams tasks to the handler.
+func exportTasks(tasks []domain.Task) func(ctx context.Context, filter domain.TaskFilter, fn func(task domain.Task) error) error {
+	return func(ctx context.Context, filter domain.TaskFilter, fn func(task domain.Task) error) error {
+		for _, task := range tasks {
+			if err := fn(task); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// xlsxContent lists the parts of an xlsx archive, so that golden files do not depend
+// on the output of the compressor.
+func xlsxContent(t *testing.T, data []byte) []byte {
+	t.Helper()
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	for _, file := range archive.File {
+		rc, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+
+		out.WriteString("== " + file.Name + " ==\n")
+		out.Write(content)
+		out.WriteString("\n")
+	}
+	return out.Bytes()
+}
+
+func requireGolden(t *testing.T, name string, actual []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(path, actual, 0o644))
+	}
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(actual))
+}
+
+func TestExportTasks_Golden(t *testing.T) {
+	tests := []struct {
+		format              string
+		tasks               []domain.Task
+		golden              string
+		expectedContentType string
+	}{
+		{format: "csv", tasks: getExportTasks(), golden: "export.csv", expectedContentType: "text/csv; charset=utf-8"},
+		{format: "ndjson", tasks: getExportTasks(), golden: "export.ndjson", expectedContentType: "application/x-ndjson"},
+		{format: "md", tasks: getExportTasks(), golden: "export.md", expectedContentType: "text/markdown; charset=utf-8"},
+		{format: "xlsx", tasks: getExportTasks(), golden: "export.xlsx.txt", expectedContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+		{format: "csv", tasks: nil, golden: "export_empty.csv", expectedContentType: "text/csv; charset=utf-8"},
+		{format: "xlsx", tasks: nil, golden: "export_empty.xlsx.txt", expectedContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			r := chi.NewRouter()
+			recorder := httptest.NewRecorder()
+			ucMock := mock.NewMockTasksUC(ctrl)
+			handler := NewTasksHandler(ucMock)
+
+			ucMock.EXPECT().ExportTasks(gomock.Any(), domain.TaskFilter{Status: "PENDING"}, gomock.Any()).DoAndReturn(exportTasks(tt.tasks))
+
+			r.Get("/api/tasks/export", handler.ExportTasks)
+			req, err := http.NewRequest(http.MethodGet, "/api/tasks/export?status=PENDING&format="+tt.format, nil)
+			require.NoError(t, err)
+
+			r.ServeHTTP(recorder, req)
+			require.Equal(t, http.StatusOK, recorder.Code)
+			require.Equal(t, tt.expectedContentType, recorder.Header().Get("Content-Type"))
+			require.Equal(t, `attachment; filename="tasks.`+tt.format+`"`, recorder.Header().Get("Content-Disposition"))
+
+			body := recorder.Body.Bytes()
+			if tt.format == "xlsx" {
+				body = xlsxContent(t, body)
+			}
+			requireGolden(t, tt.golden, body)
+		})
+	}
+}
+
+func TestExportTasks(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		ucMock             func(ucMock mock.MockTasksUC)
+		expectedStatusCode int
+	}{
+		{
+			name:               "missing format",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "unknown format",
+			query:              "?format=pdf",
+			expectedStatusCode: 400,
+		},
+		{
+			name:  "query error",
+			query: "?format=csv",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().ExportTasks(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+			},
+			expectedStatusCode: 500,
+		},
+		{
+			name:  "error after the first task cuts the export short",
+			query: "?format=ndjson",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().ExportTasks(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, filter domain.TaskFilter, fn func(task domain.Task) error) error {
+						if err := fn(getExportTasks()[0]); err != nil {
+							return err
+						}
+						return errors.New("connection lost")
+					})
+			},
+			expectedStatusCode: 200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			r := chi.NewRouter()
+			recorder := httptest.NewRecorder()
+			ucMock := mock.NewMockTasksUC(ctrl)
+			handler := NewTasksHandler(ucMock)
+
+			if tt.ucMock != nil {
+				tt.ucMock(*ucMock)
+			}
+
+			r.Get("/api/tasks/export", handler.ExportTasks)
+			req, err := http.NewRequest(http.MethodGet, "/api/tasks/export"+tt.query, nil)
+			require.NoError(t, err)
+
+			r.ServeHTTP(recorder, req)
+			require.Equal(t, tt.expectedStatusCode, recorder.Code)
+		})
+	}
+}
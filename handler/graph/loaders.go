@@ -0,0 +1,94 @@
+package graph
+
+import (
+	"api/domain"
+	"api/uc"
+	"context"
+	"github.com/google/uuid"
+	"github.com/graph-gophers/dataloader/v7"
+	"net/http"
+	"time"
+)
+
+// loaderWait is how long a loader collects keys before it runs a batch. The fields of
+// a list are resolved concurrently, so they all reach the loader well within it.
+const loaderWait = time.Millisecond
+
+type loadersKey struct{}
+
+// loaders batch the lookups of a single request, so that a list of n tasks costs one
+// query for their history instead of n. They also cache, so they must never outlive
+// the request.
+type loaders struct {
+	tasks  *dataloader.Loader[uuid.UUID, *domain.Task]
+	events *dataloader.Loader[uuid.UUID, []domain.Event]
+}
+
+func newLoaders(tasksService uc.TasksUC) *loaders {
+	return &loaders{
+		tasks:  dataloader.NewBatchedLoader(batchTasks(tasksService), dataloader.WithWait[uuid.UUID, *domain.Task](loaderWait)),
+		events: dataloader.NewBatchedLoader(batchEvents(tasksService), dataloader.WithWait[uuid.UUID, []domain.Event](loaderWait)),
+	}
+}
+
+// withLoaders gives every request its own set of loaders.
+func withLoaders(tasksService uc.TasksUC, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), loadersKey{}, newLoaders(tasksService))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func loadersFor(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// batchTasks loads tasks by id with one query. Unknown ids resolve to nil.
+func batchTasks(tasksService uc.TasksUC) dataloader.BatchFunc[uuid.UUID, *domain.Task] {
+	return func(ctx context.Context, ids []uuid.UUID) []*dataloader.Result[*domain.Task] {
+		results := make([]*dataloader.Result[*domain.Task], len(ids))
+		tasks, err := tasksService.GetTasksByIds(ctx, ids)
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[*domain.Task]{Error: err}
+			}
+			return results
+		}
+
+		byID := make(map[uuid.UUID]*domain.Task, len(tasks))
+		for i := range tasks {
+			byID[tasks[i].ID] = &tasks[i]
+		}
+		for i, id := range ids {
+			results[i] = &dataloader.Result[*domain.Task]{Data: byID[id]}
+		}
+		return results
+	}
+}
+
+// batchEvents loads the history of several tasks with one query.
+func batchEvents(tasksService uc.TasksUC) dataloader.BatchFunc[uuid.UUID, []domain.Event] {
+	return func(ctx context.Context, ids []uuid.UUID) []*dataloader.Result[[]domain.Event] {
+		results := make([]*dataloader.Result[[]domain.Event], len(ids))
+		events, err := tasksService.GetTaskEvents(ctx, ids)
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[[]domain.Event]{Error: err}
+			}
+			return results
+		}
+
+		byTask := make(map[uuid.UUID][]domain.Event, len(ids))
+		for _, event := range events {
+			byTask[event.AggregateID] = append(byTask[event.AggregateID], event)
+		}
+		for i, id := range ids {
+			history := byTask[id]
+			if history == nil {
+				history = []domain.Event{}
+			}
+			results[i] = &dataloader.Result[[]domain.Event]{Data: history}
+		}
+		return results
+	}
+}
@@ -0,0 +1,40 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package graph
+
+import (
+	"api/domain"
+	"time"
+)
+
+type CreateTaskInput struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	DueDate     *time.Time `json:"dueDate,omitempty"`
+	Rrule       string     `json:"rrule"`
+	Timezone    string     `json:"timezone"`
+}
+
+type Mutation struct {
+}
+
+type PageInfo struct {
+	HasNextPage bool    `json:"hasNextPage"`
+	EndCursor   *string `json:"endCursor,omitempty"`
+}
+
+type Query struct {
+}
+
+type TaskConnection struct {
+	Edges    []TaskEdge `json:"edges"`
+	PageInfo *PageInfo  `json:"pageInfo"`
+	// The number of tasks matching the filter, on all pages.
+	TotalCount int `json:"totalCount"`
+}
+
+type TaskEdge struct {
+	Cursor string       `json:"cursor"`
+	Node   *domain.Task `json:"node"`
+}
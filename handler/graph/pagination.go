@@ -0,0 +1,41 @@
+package graph
+
+import (
+	"api/domain"
+	"errors"
+	"fmt"
+)
+
+var errInvalidArgument = errors.New("invalid argument")
+
+// newTaskConnection returns the page of the first tasks, which are oldest first. The
+// repo is asked for one task more than the page holds, which tells whether there is a
+// next page without counting the tasks. A cursor holds the creation time and id of a
+// task rather than its position, so pages stay consistent when tasks before the cursor
+// are created or deleted.
+func newTaskConnection(tasks []domain.Task, first int) *TaskConnection {
+	end := min(first, len(tasks))
+	connection := &TaskConnection{
+		Edges:    make([]TaskEdge, 0, end),
+		PageInfo: &PageInfo{HasNextPage: len(tasks) > first},
+	}
+	for i := range end {
+		connection.Edges = append(connection.Edges, TaskEdge{Cursor: encodeCursor(tasks[i]), Node: &tasks[i]})
+	}
+	if len(connection.Edges) > 0 {
+		connection.PageInfo.EndCursor = &connection.Edges[len(connection.Edges)-1].Cursor
+	}
+	return connection
+}
+
+func encodeCursor(task domain.Task) string {
+	return domain.CursorOf(task).String()
+}
+
+func decodeCursor(cursor string) (domain.TaskCursor, error) {
+	position, err := domain.ParseTaskCursor(cursor)
+	if err != nil {
+		return domain.TaskCursor{}, fmt.Errorf("%w: %v", errInvalidArgument, err)
+	}
+	return position, nil
+}
@@ -0,0 +1,9 @@
+package graph
+
+import "api/uc"
+
+// Resolver resolves the GraphQL schema in schema.graphqls. The generated code in
+// generated.go and models_gen.go is updated with 'go tool gqlgen generate'.
+type Resolver struct {
+	tasksService uc.TasksUC
+}
@@ -0,0 +1,112 @@
+package graph
+
+import (
+	"api/domain"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/uuid"
+)
+
+// CreateTask is the resolver for the createTask field.
+func (r *mutationResolver) CreateTask(ctx context.Context, input CreateTaskInput) (*domain.Task, error) {
+	task := domain.Task{
+		ID:          uuid.New(),
+		Title:       input.Title,
+		Description: input.Description,
+		Status:      input.Status,
+		RRule:       input.Rrule,
+		Timezone:    input.Timezone,
+	}
+	if input.DueDate != nil {
+		task.DueDate = *input.DueDate
+	}
+
+	created, err := r.tasksService.CreateTask(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// Task is the resolver for the task field.
+func (r *queryResolver) Task(ctx context.Context, id uuid.UUID) (*domain.Task, error) {
+	return loadersFor(ctx).tasks.Load(ctx, id)()
+}
+
+// Tasks is the resolver for the tasks field.
+func (r *queryResolver) Tasks(ctx context.Context, filter *domain.TaskFilter, first int, after *string) (*TaskConnection, error) {
+	if first < 1 || first > maxTasksPage {
+		return nil, fmt.Errorf("%w: first must be between 1 and %d", errInvalidArgument, maxTasksPage)
+	}
+	var taskFilter domain.TaskFilter
+	if filter != nil {
+		taskFilter = *filter
+	}
+
+	var cursor *domain.TaskCursor
+	if after != nil {
+		position, err := decodeCursor(*after)
+		if err != nil {
+			return nil, err
+		}
+		cursor = &position
+	}
+
+	tasks, err := r.tasksService.GetTasksPage(ctx, taskFilter, cursor, first+1)
+	if err != nil {
+		return nil, err
+	}
+	connection := newTaskConnection(tasks, first)
+	// Counting reads every matching task, so it is left out unless asked for.
+	if slices.Contains(graphql.CollectAllFields(ctx), "totalCount") {
+		if connection.TotalCount, err = r.tasksService.CountTasks(ctx, taskFilter); err != nil {
+			return nil, err
+		}
+	}
+	return connection, nil
+}
+
+// Occurrences is the resolver for the occurrences field.
+func (r *taskResolver) Occurrences(ctx context.Context, obj *domain.Task, limit int) ([]time.Time, error) {
+	if limit < 1 || limit > maxOccurrences {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", errInvalidArgument, maxOccurrences)
+	}
+	// Computed from the task itself, so that a list of tasks needs no further queries.
+	return obj.Occurrences(limit)
+}
+
+// History is the resolver for the history field.
+func (r *taskResolver) History(ctx context.Context, obj *domain.Task) ([]domain.Event, error) {
+	return loadersFor(ctx).events.Load(ctx, obj.ID)()
+}
+
+// Type is the resolver for the type field.
+func (r *taskEventResolver) Type(ctx context.Context, obj *domain.Event) (string, error) {
+	return string(obj.Type), nil
+}
+
+// Payload is the resolver for the payload field.
+func (r *taskEventResolver) Payload(ctx context.Context, obj *domain.Event) (string, error) {
+	return string(obj.Payload), nil
+}
+
+// Mutation returns MutationResolver implementation.
+func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+// Task returns TaskResolver implementation.
+func (r *Resolver) Task() TaskResolver { return &taskResolver{r} }
+
+// TaskEvent returns TaskEventResolver implementation.
+func (r *Resolver) TaskEvent() TaskEventResolver { return &taskEventResolver{r} }
+
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+type taskResolver struct{ *Resolver }
+type taskEventResolver struct{ *Resolver }
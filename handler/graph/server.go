@@ -0,0 +1,126 @@
+package graph
+
+import (
+	"api/domain"
+	"api/uc"
+	"context"
+	"errors"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"net/http"
+	"strings"
+)
+
+const (
+	maxTasksPage      = 100
+	maxOccurrences    = 100
+	errDepthLimit     = "DEPTH_LIMIT_EXCEEDED"
+	errNotFound       = "NOT_FOUND"
+	errBadUserInput   = "BAD_USER_INPUT"
+	errInternalServer = "INTERNAL_SERVER_ERROR"
+	errUnavailable    = "SERVICE_UNAVAILABLE"
+)
+
+// NewServer returns the GraphQL endpoint. Operations deeper than maxDepth or more
+// complex than maxComplexity are rejected before any resolver runs. Introspection is
+// needed by GraphiQL but is otherwise left off.
+func NewServer(tasksService uc.TasksUC, maxDepth, maxComplexity int, introspection bool) http.Handler {
+	config := Config{Resolvers: &Resolver{tasksService: tasksService}}
+	// Lists cost their fields once per requested item.
+	config.Complexity.Query.Tasks = func(childComplexity int, filter *domain.TaskFilter, first int, after *string) int {
+		return 1 + childComplexity*max(first, 1)
+	}
+	config.Complexity.Task.Occurrences = func(childComplexity int, limit int) int {
+		return 1 + max(limit, 1)
+	}
+
+	server := handler.New(NewExecutableSchema(config))
+	server.AddTransport(transport.POST{})
+	server.Use(depthLimit{max: maxDepth})
+	server.Use(extension.FixedComplexityLimit(maxComplexity))
+	if introspection {
+		server.Use(extension.Introspection{})
+	}
+	server.SetErrorPresenter(presentError)
+
+	return withLoaders(tasksService, server)
+}
+
+// depthLimit rejects operations nesting more than max fields. Introspection fields do
+// not count, as GraphiQL's schema query is deeper than any query on tasks needs to be.
+type depthLimit struct {
+	max int
+}
+
+var _ interface {
+	graphql.OperationContextMutator
+	graphql.HandlerExtension
+} = depthLimit{}
+
+func (dl depthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (dl depthLimit) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (dl depthLimit) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	if depth := selectionDepth(opCtx.Operation.SelectionSet); depth > dl.max {
+		err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, dl.max)
+		errcode.Set(err, errDepthLimit)
+		return err
+	}
+	return nil
+}
+
+// selectionDepth follows fragments, which have been checked for cycles by the time
+// operation context mutators run.
+func selectionDepth(selections ast.SelectionSet) int {
+	depth := 0
+	for _, selection := range selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if !strings.HasPrefix(selection.Name, "__") {
+				depth = max(depth, 1+selectionDepth(selection.SelectionSet))
+			}
+		case *ast.InlineFragment:
+			depth = max(depth, selectionDepth(selection.SelectionSet))
+		case *ast.FragmentSpread:
+			if selection.Definition != nil {
+				depth = max(depth, selectionDepth(selection.Definition.SelectionSet))
+			}
+		}
+	}
+	return depth
+}
+
+// presentError adds a code to the errors of resolvers, in the same spirit as the HTTP
+// status codes of the REST API. Introspection while it is disabled is the client's
+// mistake rather than ours.
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	presented := graphql.DefaultErrorPresenter(ctx, err)
+	if _, ok := presented.Extensions["code"]; ok {
+		return presented
+	}
+
+	fieldCtx := graphql.GetFieldContext(ctx)
+	switch {
+	case fieldCtx != nil && strings.HasPrefix(fieldCtx.Field.Name, "__"):
+		errcode.Set(presented, errBadUserInput)
+	case errors.Is(err, domain.ErrTaskNotFound):
+		errcode.Set(presented, errNotFound)
+	case errors.Is(err, domain.ErrInvalidTask), errors.Is(err, domain.ErrInvalidRecurrence), errors.Is(err, errInvalidArgument):
+		errcode.Set(presented, errBadUserInput)
+	case errors.Is(err, domain.ErrStorageUnavailable):
+		errcode.Set(presented, errUnavailable)
+	default:
+		errcode.Set(presented, errInternalServer)
+	}
+	return presented
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"api/handler/graph"
+	"api/uc"
+	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/go-chi/render"
+	"net/http"
+)
+
+// GraphQLHandler serves the GraphQL schema of handler/graph, which lets clients fetch
+// tasks together with their related data in one round trip.
+type GraphQLHandler struct {
+	server     http.Handler
+	playground http.Handler
+	devMode    bool
+}
+
+// NewGraphQLHandler limits the depth and complexity of operations. Dev mode turns on
+// introspection and the GraphiQL playground.
+func NewGraphQLHandler(tasksService uc.TasksUC, maxDepth, maxComplexity int, devMode bool) *GraphQLHandler {
+	return &GraphQLHandler{
+		server:     graph.NewServer(tasksService, maxDepth, maxComplexity, devMode),
+		playground: playground.Handler("Tasks API", "/api/graphql"),
+		devMode:    devMode,
+	}
+}
+
+func (gh GraphQLHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	gh.server.ServeHTTP(w, r)
+}
+
+// Playground serves GraphiQL in dev mode. The page loads its assets from a CDN.
+func (gh GraphQLHandler) Playground(w http.ResponseWriter, r *http.Request) {
+	if !gh.devMode {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "the playground is only available in dev mode",
+		})
+		return
+	}
+	gh.playground.ServeHTTP(w, r)
+}
@@ -0,0 +1,327 @@
+package handler
+
+import (
+	"api/domain"
+	mock "api/mocks/mock_uc"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Path       []any          `json:"path"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func postGraphQL(t *testing.T, handler *GraphQLHandler, query string, variables map[string]any) (int, graphQLResponse) {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	handler.Serve(recorder, req)
+
+	var response graphQLResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response), recorder.Body.String())
+	return recorder.Code, response
+}
+
+func getGraphQLTestTasks() []domain.Task {
+	tasks := make([]domain.Task, 3)
+	for i := range tasks {
+		tasks[i] = getExpectedBody()
+		tasks[i].ID = uuid.MustParse(fmt.Sprintf("1461ec84-ccff-4f3c-af34-65d0856ac3c%d", i))
+		tasks[i].Title = fmt.Sprintf("Task %d", i)
+		tasks[i].CreatedAt = time.Date(2025, 4, 1+i, 0, 0, 0, 0, time.UTC)
+	}
+	// Not in creation order, as the repo does not sort.
+	return []domain.Task{tasks[2], tasks[0], tasks[1]}
+}
+
+// getGraphQLTestTasksPage pages through the test tasks like the repo does.
+func getGraphQLTestTasksPage(_ context.Context, _ domain.TaskFilter, after *domain.TaskCursor, limit int) ([]domain.Task, error) {
+	tasks := getGraphQLTestTasks()
+	slices.SortFunc(tasks, func(a, b domain.Task) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	page := []domain.Task{}
+	for _, task := range tasks {
+		if after == nil || after.Precedes(task) {
+			page = append(page, task)
+		}
+	}
+	return page[:min(limit, len(page))], nil
+}
+
+func TestGraphQL_TasksBatchesHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ucMock := mock.NewMockTasksUC(ctrl)
+	tasks := getGraphQLTestTasks()
+
+	ucMock.EXPECT().GetTasksPage(gomock.Any(), gomock.Eq(domain.TaskFilter{Status: "PENDING"}), gomock.Nil(), 21).
+		DoAndReturn(getGraphQLTestTasksPage)
+	ucMock.EXPECT().CountTasks(gomock.Any(), gomock.Eq(domain.TaskFilter{Status: "PENDING"})).Return(3, nil)
+	ucMock.EXPECT().GetTaskEvents(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, ids []uuid.UUID) ([]domain.Event, error) {
+			require.ElementsMatch(t, []uuid.UUID{tasks[0].ID, tasks[1].ID, tasks[2].ID}, ids)
+			return []domain.Event{
+				{ID: uuid.New(), Type: domain.EventTaskCreated, AggregateID: tasks[1].ID, Payload: json.RawMessage(`{"title":"Task 0"}`)},
+				{ID: uuid.New(), Type: domain.EventTaskCreated, AggregateID: tasks[2].ID, Payload: json.RawMessage(`{}`)},
+				{ID: uuid.New(), Type: domain.EventTaskUpdated, AggregateID: tasks[1].ID, Payload: json.RawMessage(`{}`)},
+			}, nil
+		}).Times(1)
+
+	status, response := postGraphQL(t, NewGraphQLHandler(ucMock, 10, 1000, false),
+		`{ tasks(filter: {status: "PENDING"}) { totalCount edges { node { title history { type payload } } } } }`, nil)
+	require.Equal(t, http.StatusOK, status)
+	require.Empty(t, response.Errors)
+	require.JSONEq(t, `{"tasks": {"totalCount": 3, "edges": [
+		{"node": {"title": "Task 0", "history": [{"type": "TaskCreated", "payload": "{\"title\":\"Task 0\"}"}, {"type": "TaskUpdated", "payload": "{}"}]}},
+		{"node": {"title": "Task 1", "history": [{"type": "TaskCreated", "payload": "{}"}]}},
+		{"node": {"title": "Task 2", "history": []}}
+	]}}`, string(response.Data))
+}
+
+func TestGraphQL_TaskBatchesLookups(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ucMock := mock.NewMockTasksUC(ctrl)
+	tasks := getGraphQLTestTasks()
+	unknown := uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3cf")
+
+	ucMock.EXPECT().GetTasksByIds(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, ids []uuid.UUID) ([]domain.Task, error) {
+			require.ElementsMatch(t, []uuid.UUID{tasks[0].ID, tasks[1].ID, unknown}, ids)
+			return tasks[:2], nil
+		}).Times(1)
+
+	status, response := postGraphQL(t, NewGraphQLHandler(ucMock, 10, 1000, false),
+		`query($a: ID!, $b: ID!, $c: ID!) { a: task(id: $a) { title } b: task(id: $b) { title } c: task(id: $c) { title } again: task(id: $a) { id } }`,
+		map[string]any{"a": tasks[0].ID, "b": tasks[1].ID, "c": unknown})
+	require.Equal(t, http.StatusOK, status)
+	require.Empty(t, response.Errors)
+	require.JSONEq(t, fmt.Sprintf(`{"a": {"title": "Task 2"}, "b": {"title": "Task 0"}, "c": null, "again": {"id": %q}}`, tasks[0].ID), string(response.Data))
+}
+
+func TestGraphQL_TasksPagination(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ucMock := mock.NewMockTasksUC(ctrl)
+	handler := NewGraphQLHandler(ucMock, 10, 1000, false)
+	// Only the page is read, and the tasks are not counted unless totalCount is selected.
+	ucMock.EXPECT().GetTasksPage(gomock.Any(), gomock.Any(), gomock.Any(), 3).DoAndReturn(getGraphQLTestTasksPage).Times(2)
+
+	query := `query($after: String) { tasks(first: 2, after: $after) { edges { node { title } } pageInfo { hasNextPage endCursor } } }`
+	type page struct {
+		Tasks struct {
+			Edges []struct {
+				Node struct{ Title string }
+			}
+			PageInfo struct {
+				HasNextPage bool
+				EndCursor   *string
+			}
+		}
+	}
+
+	status, response := postGraphQL(t, handler, query, nil)
+	require.Equal(t, http.StatusOK, status)
+	require.Empty(t, response.Errors)
+	var first page
+	require.NoError(t, json.Unmarshal(response.Data, &first))
+	require.Len(t, first.Tasks.Edges, 2)
+	require.Equal(t, "Task 0", first.Tasks.Edges[0].Node.Title)
+	require.Equal(t, "Task 1", first.Tasks.Edges[1].Node.Title)
+	require.True(t, first.Tasks.PageInfo.HasNextPage)
+
+	_, response = postGraphQL(t, handler, query, map[string]any{"after": *first.Tasks.PageInfo.EndCursor})
+	require.Empty(t, response.Errors)
+	var second page
+	require.NoError(t, json.Unmarshal(response.Data, &second))
+	require.Len(t, second.Tasks.Edges, 1)
+	require.Equal(t, "Task 2", second.Tasks.Edges[0].Node.Title)
+	require.False(t, second.Tasks.PageInfo.HasNextPage)
+
+	_, response = postGraphQL(t, handler, query, map[string]any{"after": "bm90IGEgY3Vyc29y"})
+	require.Len(t, response.Errors, 1)
+	require.Equal(t, "BAD_USER_INPUT", response.Errors[0].Extensions["code"])
+
+	_, response = postGraphQL(t, handler, `{ tasks(first: 0) { totalCount } }`, nil)
+	require.Len(t, response.Errors, 1)
+	require.Equal(t, "BAD_USER_INPUT", response.Errors[0].Extensions["code"])
+}
+
+func TestGraphQL_Occurrences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ucMock := mock.NewMockTasksUC(ctrl)
+	task := getExpectedBody()
+	task.RRule = "FREQ=DAILY"
+	ucMock.EXPECT().GetTasksByIds(gomock.Any(), gomock.Any()).Return([]domain.Task{task}, nil)
+
+	status, response := postGraphQL(t, NewGraphQLHandler(ucMock, 10, 1000, false),
+		`query($id: ID!) { task(id: $id) { occurrences(limit: 2) } }`, map[string]any{"id": task.ID})
+	require.Equal(t, http.StatusOK, status)
+	require.Empty(t, response.Errors)
+	require.JSONEq(t, `{"task": {"occurrences": ["2025-04-04T00:00:00Z", "2025-04-05T00:00:00Z"]}}`, string(response.Data))
+}
+
+func TestGraphQL_CreateTask(t *testing.T) {
+	tests := []struct {
+		name         string
+		ucMock       func(ucMock mock.MockTasksUC)
+		expectedData string
+		expectedCode string
+	}{
+		{
+			name: "happy path - OK",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().CreateTask(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ any, task domain.Task) (domain.Task, error) {
+						require.NotEqual(t, uuid.Nil, task.ID)
+						require.Equal(t, "Do unit tests", task.Title)
+						require.Equal(t, domain.StatusPending, task.Status)
+						require.True(t, time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC).Equal(task.DueDate))
+						return getExpectedBody(), nil
+					})
+			},
+			expectedData: `{"createTask": {"id": "1461ec84-ccff-4f3c-af34-65d0856ac3ce", "status": "PENDING", "dueDate": "2025-04-03T00:00:00Z"}}`,
+		},
+		{
+			name: "invalid recurrence",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Return(domain.Task{}, fmt.Errorf("%w: unknown FREQ", domain.ErrInvalidRecurrence))
+			},
+			expectedData: `null`,
+			expectedCode: "BAD_USER_INPUT",
+		},
+		{
+			name: "internal server error",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Return(domain.Task{}, errors.New("connection refused"))
+			},
+			expectedData: `null`,
+			expectedCode: "INTERNAL_SERVER_ERROR",
+		},
+		{
+			name: "database unreachable",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Return(domain.Task{}, fmt.Errorf("error creating task: %w", domain.ErrStorageUnavailable))
+			},
+			expectedData: `null`,
+			expectedCode: "SERVICE_UNAVAILABLE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ucMock := mock.NewMockTasksUC(ctrl)
+			tt.ucMock(*ucMock)
+
+			status, response := postGraphQL(t, NewGraphQLHandler(ucMock, 10, 1000, false),
+				`mutation { createTask(input: {title: "Do unit tests", dueDate: "2025-04-03T00:00:00Z"}) { id status dueDate } }`, nil)
+			require.Equal(t, http.StatusOK, status)
+			require.JSONEq(t, tt.expectedData, string(response.Data))
+			if tt.expectedCode == "" {
+				require.Empty(t, response.Errors)
+				return
+			}
+			require.Len(t, response.Errors, 1)
+			require.Equal(t, tt.expectedCode, response.Errors[0].Extensions["code"])
+		})
+	}
+}
+
+func TestGraphQL_Limits(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxDepth      int
+		maxComplexity int
+		query         string
+		expectedData  string
+		expectedCode  string
+	}{
+		{
+			name:          "depth",
+			maxDepth:      4,
+			maxComplexity: 1000,
+			query:         `{ tasks { edges { node { history { type } } } } }`,
+			expectedCode:  "DEPTH_LIMIT_EXCEEDED",
+		},
+		{
+			name:          "depth through fragments",
+			maxDepth:      4,
+			maxComplexity: 1000,
+			query:         `{ tasks { ...edges } } fragment edges on TaskConnection { edges { ... on TaskEdge { node { history { type } } } } }`,
+			expectedCode:  "DEPTH_LIMIT_EXCEEDED",
+		},
+		{
+			name:          "complexity",
+			maxDepth:      10,
+			maxComplexity: 50,
+			query:         `{ tasks(first: 100) { edges { node { id title } } } }`,
+			expectedCode:  "COMPLEXITY_LIMIT_EXCEEDED",
+		},
+		{
+			name:          "complexity of occurrences",
+			maxDepth:      10,
+			maxComplexity: 50,
+			query:         `{ a: task(id: "1461ec84-ccff-4f3c-af34-65d0856ac3ce") { occurrences(limit: 100) } }`,
+			expectedCode:  "COMPLEXITY_LIMIT_EXCEEDED",
+		},
+		{
+			name:          "introspection is off outside dev mode",
+			maxDepth:      10,
+			maxComplexity: 1000,
+			query:         `{ __schema { types { name } } }`,
+			expectedData:  `{"__schema": null}`,
+			expectedCode:  "BAD_USER_INPUT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ucMock := mock.NewMockTasksUC(gomock.NewController(t))
+
+			_, response := postGraphQL(t, NewGraphQLHandler(ucMock, tt.maxDepth, tt.maxComplexity, false), tt.query, nil)
+			expectedData := tt.expectedData
+			if expectedData == "" {
+				expectedData = `null`
+			}
+			require.JSONEq(t, expectedData, string(response.Data))
+			require.Len(t, response.Errors, 1)
+			require.Equal(t, tt.expectedCode, response.Errors[0].Extensions["code"], response.Errors[0].Message)
+		})
+	}
+}
+
+func TestGraphQL_DevMode(t *testing.T) {
+	ucMock := mock.NewMockTasksUC(gomock.NewController(t))
+
+	// The introspection query of GraphiQL is deeper than the depth limit.
+	status, response := postGraphQL(t, NewGraphQLHandler(ucMock, 3, 1000, true),
+		`{ __schema { queryType { fields { name type { ofType { ofType { name } } } } } } }`, nil)
+	require.Equal(t, http.StatusOK, status)
+	require.Empty(t, response.Errors)
+	require.Contains(t, string(response.Data), `"name":"tasks"`)
+
+	recorder := httptest.NewRecorder()
+	NewGraphQLHandler(ucMock, 10, 1000, true).Playground(recorder, httptest.NewRequest(http.MethodGet, "/api/graphql", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "graphiql")
+
+	recorder = httptest.NewRecorder()
+	NewGraphQLHandler(ucMock, 10, 1000, false).Playground(recorder, httptest.NewRequest(http.MethodGet, "/api/graphql", nil))
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,428 @@
+package handler
+
+import (
+	"api/domain"
+	tasksv1 "api/proto/tasks/v1"
+	"api/uc"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"io"
+	"strings"
+	"time"
+)
+
+// TasksGRPCServer implements the TaskService of proto/tasks/v1 on top of the same use
+// case as TasksHandler.
+type TasksGRPCServer struct {
+	tasksv1.UnimplementedTaskServiceServer
+	tasksService uc.TasksUC
+	adminToken   string
+}
+
+func NewTasksGRPCServer(tasksService uc.TasksUC, adminToken string) *TasksGRPCServer {
+	return &TasksGRPCServer{tasksService: tasksService, adminToken: adminToken}
+}
+
+// NewGRPCServer returns a gRPC server with the TaskService and server reflection
+// registered, so that tools like grpcurl can list and call its methods.
+func NewGRPCServer(tasksService uc.TasksUC, adminToken string, opts ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(opts...)
+	tasksv1.RegisterTaskServiceServer(server, NewTasksGRPCServer(tasksService, adminToken))
+	reflection.Register(server)
+	return server
+}
+
+func (gs TasksGRPCServer) GetTask(ctx context.Context, req *tasksv1.GetTaskRequest) (*tasksv1.GetTaskResponse, error) {
+	id, err := parseGRPCTaskID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := gs.tasksService.GetTaskById(ctx, id)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &tasksv1.GetTaskResponse{Task: taskToProto(task)}, nil
+}
+
+// ListTasks sends the tasks as they are read from the repo, so that a long list is
+// never held in memory.
+func (gs TasksGRPCServer) ListTasks(req *tasksv1.ListTasksRequest, stream grpc.ServerStreamingServer[tasksv1.ListTasksResponse]) error {
+	filter := domain.TaskFilter{Status: req.GetStatus()}
+	err := gs.tasksService.ExportTasks(stream.Context(), filter, func(task domain.Task) error {
+		return stream.Send(&tasksv1.ListTasksResponse{Task: taskToProto(task)})
+	})
+	if err != nil {
+		return grpcError(stream.Context(), err)
+	}
+	return nil
+}
+
+func (gs TasksGRPCServer) CreateTask(ctx context.Context, req *tasksv1.CreateTaskRequest) (*tasksv1.CreateTaskResponse, error) {
+	data, err := taskFromProto(req.GetTask())
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := gs.tasksService.CreateTask(ctx, data)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &tasksv1.CreateTaskResponse{Task: taskToProto(task)}, nil
+}
+
+func (gs TasksGRPCServer) UpdateTask(ctx context.Context, req *tasksv1.UpdateTaskRequest) (*tasksv1.UpdateTaskResponse, error) {
+	data, err := taskFromProto(req.GetTask())
+	if err != nil {
+		return nil, err
+	}
+	if data.ID == uuid.Nil {
+		return nil, status.Error(codes.InvalidArgument, "task id is required")
+	}
+
+	task, err := gs.tasksService.UpdateTask(ctx, data)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &tasksv1.UpdateTaskResponse{Task: taskToProto(task)}, nil
+}
+
+func (gs TasksGRPCServer) DeleteTask(ctx context.Context, req *tasksv1.DeleteTaskRequest) (*tasksv1.DeleteTaskResponse, error) {
+	id, err := parseGRPCTaskID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := gs.tasksService.DeleteTask(ctx, id)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &tasksv1.DeleteTaskResponse{Task: taskToProto(task)}, nil
+}
+
+func (gs TasksGRPCServer) GetOccurrences(ctx context.Context, req *tasksv1.GetOccurrencesRequest) (*tasksv1.GetOccurrencesResponse, error) {
+	id, err := parseGRPCTaskID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	limit, err := grpcLimit("limit", req.GetLimit(), defaultOccurrencesLimit, maxOccurrencesLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences, err := gs.tasksService.GetOccurrences(ctx, id, limit)
+	if err != nil {
+		// The rule is the stored one here, not an argument of the call.
+		if errors.Is(err, domain.ErrInvalidRecurrence) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, grpcError(ctx, err)
+	}
+
+	response := &tasksv1.GetOccurrencesResponse{Occurrences: make([]*timestamppb.Timestamp, len(occurrences))}
+	for i, occurrence := range occurrences {
+		response.Occurrences[i] = timestamppb.New(occurrence)
+	}
+	return response, nil
+}
+
+func (gs TasksGRPCServer) SearchTasks(ctx context.Context, req *tasksv1.SearchTasksRequest) (*tasksv1.SearchTasksResponse, error) {
+	query := strings.TrimSpace(req.GetQuery())
+	if query == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+	limit, err := grpcLimit("limit", req.GetLimit(), defaultSearchLimit, maxSearchLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := gs.tasksService.SearchTasks(ctx, query, limit)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	response := &tasksv1.SearchTasksResponse{Results: make([]*tasksv1.TaskSearchResult, len(results))}
+	for i, result := range results {
+		response.Results[i] = &tasksv1.TaskSearchResult{
+			Task:    taskToProto(result.Task),
+			Rank:    result.Rank,
+			Snippet: result.Snippet,
+			Match:   result.Match,
+		}
+	}
+	return response, nil
+}
+
+func (gs TasksGRPCServer) ListTrash(req *tasksv1.ListTrashRequest, stream grpc.ServerStreamingServer[tasksv1.ListTrashResponse]) error {
+	tasks, err := gs.tasksService.GetTrash(stream.Context())
+	if err != nil {
+		return grpcError(stream.Context(), err)
+	}
+	for _, task := range tasks {
+		if err := stream.Send(&tasksv1.ListTrashResponse{Task: taskToProto(task)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (gs TasksGRPCServer) RestoreTask(ctx context.Context, req *tasksv1.RestoreTaskRequest) (*tasksv1.RestoreTaskResponse, error) {
+	id, err := parseGRPCTaskID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := gs.tasksService.RestoreTask(ctx, id)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &tasksv1.RestoreTaskResponse{Task: taskToProto(task)}, nil
+}
+
+// PurgeTask permanently deletes a task from the trash. Like DELETE /api/trash/{id}, it
+// needs the admin token.
+func (gs TasksGRPCServer) PurgeTask(ctx context.Context, req *tasksv1.PurgeTaskRequest) (*tasksv1.PurgeTaskResponse, error) {
+	if err := requireGRPCAdmin(ctx, gs.adminToken); err != nil {
+		return nil, err
+	}
+	id, err := parseGRPCTaskID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := gs.tasksService.PurgeTask(ctx, id); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &tasksv1.PurgeTaskResponse{}, nil
+}
+
+func (gs TasksGRPCServer) BulkTasks(ctx context.Context, req *tasksv1.BulkTasksRequest) (*tasksv1.BulkTasksResponse, error) {
+	if len(req.GetOperations()) == 0 || len(req.GetOperations()) > maxBulkOperations {
+		return nil, status.Errorf(codes.InvalidArgument, "between 1 and %d operations are allowed", maxBulkOperations)
+	}
+
+	ops := make([]domain.BulkOperation, len(req.GetOperations()))
+	for i, op := range req.GetOperations() {
+		ops[i] = domain.BulkOperation{Op: op.GetOp(), Status: op.GetStatus()}
+		if op.GetId() != "" {
+			id, err := uuid.Parse(op.GetId())
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "operation %d: invalid task id %q", i, op.GetId())
+			}
+			ops[i].ID = id
+		}
+		if op.GetTask() != nil {
+			task, err := taskFromProto(op.GetTask())
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "operation %d: %s", i, status.Convert(err).Message())
+			}
+			ops[i].Task = &task
+		}
+	}
+
+	results, err := gs.tasksService.BulkTasks(ctx, ops, req.GetAtomic())
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	response := &tasksv1.BulkTasksResponse{Results: make([]*tasksv1.BulkResult, len(results))}
+	for i, result := range results {
+		response.Results[i] = &tasksv1.BulkResult{}
+		if result.Task != nil {
+			response.Results[i].Task = taskToProto(*result.Task)
+		}
+		if result.Err != nil {
+			response.Results[i].Code = int32(grpcCode(result.Err))
+			response.Results[i].Error = result.Err.Error()
+		}
+	}
+	return response, nil
+}
+
+// ImportTasks imports the tasks of the stream with the same rules as POST
+// /api/tasks/import. A message which cannot be converted to a task is reported as an
+// invalid row and does not stop the import.
+func (gs TasksGRPCServer) ImportTasks(stream grpc.ClientStreamingServer[tasksv1.ImportTasksRequest, tasksv1.ImportTasksResponse]) error {
+	first, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return status.Error(codes.InvalidArgument, "at least one task is required")
+	}
+	if err != nil {
+		return err
+	}
+
+	source := &grpcTaskSource{stream: stream, first: first}
+	report, err := gs.tasksService.ImportTasks(stream.Context(), source, first.GetDryRun())
+	if err != nil {
+		return grpcError(stream.Context(), err)
+	}
+
+	response := &tasksv1.ImportTasksResponse{
+		DryRun:   report.DryRun,
+		Total:    int32(report.Total),
+		Imported: int32(report.Imported),
+		Skipped:  int32(report.Skipped),
+		Failed:   int32(report.Failed),
+		Errors:   make([]*tasksv1.ImportError, len(report.Errors)),
+	}
+	for i, importError := range report.Errors {
+		response.Errors[i] = &tasksv1.ImportError{Line: int32(importError.Line), Message: importError.Message}
+	}
+	return stream.SendAndClose(response)
+}
+
+// grpcTaskSource reads the tasks of an import from a client stream. Lines are the
+// positions of the messages in the stream, starting at 1.
+type grpcTaskSource struct {
+	stream grpc.ClientStreamingServer[tasksv1.ImportTasksRequest, tasksv1.ImportTasksResponse]
+	first  *tasksv1.ImportTasksRequest
+	line   int
+}
+
+func (gs *grpcTaskSource) Next() (int, domain.Task, error) {
+	req := gs.first
+	if req != nil {
+		gs.first = nil
+	} else {
+		var err error
+		if req, err = gs.stream.Recv(); err != nil {
+			return gs.line, domain.Task{}, err
+		}
+	}
+	gs.line++
+
+	task, err := taskFromProto(req.GetTask())
+	if err != nil {
+		return gs.line, domain.Task{}, fmt.Errorf("%w: %s", domain.ErrInvalidImportRow, status.Convert(err).Message())
+	}
+	return gs.line, task, nil
+}
+
+func requireGRPCAdmin(ctx context.Context, token string) error {
+	if token == "" {
+		return status.Error(codes.PermissionDenied, "admin endpoints are disabled")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		provided, ok := strings.CutPrefix(value, "Bearer ")
+		if ok && subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "admin token required")
+}
+
+// grpcError maps an error of the use case to a gRPC status. The error of a call which
+// was cancelled or timed out is reported as such, whatever the use case returned.
+func grpcError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return status.Error(grpcCode(err), err.Error())
+}
+
+func grpcCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, domain.ErrTaskNotFound):
+		return codes.NotFound
+	case errors.Is(err, domain.ErrTaskExists):
+		return codes.AlreadyExists
+	case errors.Is(err, domain.ErrInvalidTask), errors.Is(err, domain.ErrInvalidRecurrence),
+		errors.Is(err, domain.ErrInvalidBulkOperation), errors.Is(err, domain.ErrInvalidImport):
+		return codes.InvalidArgument
+	case errors.Is(err, domain.ErrBulkRolledBack):
+		return codes.Aborted
+	case errors.Is(err, domain.ErrStorageUnavailable):
+		return codes.Unavailable
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
+
+func parseGRPCTaskID(raw string) (uuid.UUID, error) {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid task id %q", raw)
+	}
+	return id, nil
+}
+
+// grpcLimit applies the defaults of intFromQuery to a limit field, where 0 means unset.
+func grpcLimit(name string, value int32, defaultValue, maxValue int) (int, error) {
+	if value == 0 {
+		return defaultValue, nil
+	}
+	if value < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "%s must be a positive integer", name)
+	}
+	return min(int(value), maxValue), nil
+}
+
+func taskToProto(task domain.Task) *tasksv1.Task {
+	message := &tasksv1.Task{
+		Id:          task.ID.String(),
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      task.Status,
+		Rrule:       task.RRule,
+		Timezone:    task.Timezone,
+		DueDate:     timestampToProto(task.DueDate),
+		CreatedAt:   timestampToProto(task.CreatedAt),
+	}
+	if task.DeletedAt != nil {
+		message.DeletedAt = timestamppb.New(*task.DeletedAt)
+	}
+	return message
+}
+
+func taskFromProto(message *tasksv1.Task) (domain.Task, error) {
+	if message == nil {
+		return domain.Task{}, status.Error(codes.InvalidArgument, "task is required")
+	}
+
+	task := domain.Task{
+		Title:       message.GetTitle(),
+		Description: message.GetDescription(),
+		Status:      message.GetStatus(),
+		RRule:       message.GetRrule(),
+		Timezone:    message.GetTimezone(),
+		DueDate:     timestampFromProto(message.GetDueDate()),
+		CreatedAt:   timestampFromProto(message.GetCreatedAt()),
+	}
+	if message.GetId() != "" {
+		id, err := uuid.Parse(message.GetId())
+		if err != nil {
+			return domain.Task{}, status.Errorf(codes.InvalidArgument, "invalid task id %q", message.GetId())
+		}
+		task.ID = id
+	}
+	return task, nil
+}
+
+// timestampToProto leaves zero times unset rather than sending year 1.
+func timestampToProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func timestampFromProto(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
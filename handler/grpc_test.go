@@ -0,0 +1,537 @@
+package handler
+
+import (
+	"api/domain"
+	mock "api/mocks/mock_uc"
+	tasksv1 "api/proto/tasks/v1"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func newGRPCTestConn(t *testing.T, ucMock *mock.MockTasksUC, adminToken string) *grpc.ClientConn {
+	lis := bufconn.Listen(1024 * 1024)
+	server := NewGRPCServer(ucMock, adminToken)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func newGRPCTestClient(t *testing.T, ucMock *mock.MockTasksUC) tasksv1.TaskServiceClient {
+	return tasksv1.NewTaskServiceClient(newGRPCTestConn(t, ucMock, "secret"))
+}
+
+func getExpectedProto() *tasksv1.Task {
+	return &tasksv1.Task{
+		Id:          "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+		Title:       "Do unit tests",
+		Description: "Create extensive unit tests for all layers",
+		Status:      "PENDING",
+		DueDate:     timestamppb.New(time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)),
+		CreatedAt:   timestamppb.New(time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)),
+	}
+}
+
+func requireGRPCCode(t *testing.T, expected codes.Code, err error) {
+	t.Helper()
+	if expected == codes.OK {
+		require.NoError(t, err)
+		return
+	}
+	require.Error(t, err)
+	require.Equal(t, expected, status.Code(err), err.Error())
+}
+
+func TestGRPC_GetTask(t *testing.T) {
+	tests := []struct {
+		name         string
+		id           string
+		ucMock       func(ucMock mock.MockTasksUC)
+		expectedCode codes.Code
+	}{
+		{
+			name: "happy path - OK",
+			id:   "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTaskById(gomock.Any(), gomock.Eq(getExpectedBody().ID)).Return(getExpectedBody(), nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "wrong id type",
+			id:           "invalid id",
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "no task found",
+			id:   "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTaskById(gomock.Any(), gomock.Any()).Return(domain.Task{}, domain.ErrTaskNotFound)
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name: "internal server error",
+			id:   "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTaskById(gomock.Any(), gomock.Any()).Return(domain.Task{}, errors.New("connection refused"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ucMock := mock.NewMockTasksUC(ctrl)
+			if tt.ucMock != nil {
+				tt.ucMock(*ucMock)
+			}
+
+			response, err := newGRPCTestClient(t, ucMock).GetTask(context.Background(), &tasksv1.GetTaskRequest{Id: tt.id})
+			requireGRPCCode(t, tt.expectedCode, err)
+			if tt.expectedCode == codes.OK {
+				require.Equal(t, getExpectedProto().String(), response.GetTask().String())
+			}
+		})
+	}
+}
+
+func TestGRPC_ListTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ucMock := mock.NewMockTasksUC(ctrl)
+	second := getExpectedBody()
+	second.ID = uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3cf")
+	ucMock.EXPECT().ExportTasks(gomock.Any(), gomock.Eq(domain.TaskFilter{Status: "PENDING"}), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, filter domain.TaskFilter, fn func(domain.Task) error) error {
+			for _, task := range []domain.Task{getExpectedBody(), second} {
+				if err := fn(task); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+	stream, err := newGRPCTestClient(t, ucMock).ListTasks(context.Background(), &tasksv1.ListTasksRequest{Status: "PENDING"})
+	require.NoError(t, err)
+
+	var ids []string
+	for {
+		response, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, response.GetTask().GetId())
+	}
+	require.Equal(t, []string{getExpectedBody().ID.String(), second.ID.String()}, ids)
+}
+
+func TestGRPC_ListTasks_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ucMock := mock.NewMockTasksUC(ctrl)
+	ucMock.EXPECT().ExportTasks(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+
+	stream, err := newGRPCTestClient(t, ucMock).ListTasks(context.Background(), &tasksv1.ListTasksRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	requireGRPCCode(t, codes.Internal, err)
+}
+
+func TestGRPC_Unavailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ucMock := mock.NewMockTasksUC(ctrl)
+	ucMock.EXPECT().GetTaskById(gomock.Any(), gomock.Any()).Return(domain.Task{}, fmt.Errorf("error fetching task: %w", domain.ErrStorageUnavailable))
+
+	_, err := newGRPCTestClient(t, ucMock).GetTask(context.Background(), &tasksv1.GetTaskRequest{Id: "1461ec84-ccff-4f3c-af34-65d0856ac3ce"})
+	requireGRPCCode(t, codes.Unavailable, err)
+}
+
+func TestGRPC_CreateTask(t *testing.T) {
+	tests := []struct {
+		name         string
+		task         *tasksv1.Task
+		ucMock       func(ucMock mock.MockTasksUC)
+		expectedCode codes.Code
+	}{
+		{
+			name: "happy path - OK",
+			task: &tasksv1.Task{Title: "Do unit tests", Status: "PENDING", DueDate: timestamppb.New(time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC))},
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().CreateTask(gomock.Any(), gomock.Eq(domain.Task{
+					Title:   "Do unit tests",
+					Status:  "PENDING",
+					DueDate: time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC),
+				})).Return(getExpectedBody(), nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "missing task",
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "wrong id type",
+			task:         &tasksv1.Task{Id: "invalid id", Title: "Do unit tests"},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "invalid recurrence",
+			task: &tasksv1.Task{Title: "Do unit tests", Rrule: "FREQ=SOMETIMES"},
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Return(domain.Task{}, fmt.Errorf("%w: unknown FREQ", domain.ErrInvalidRecurrence))
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "id already taken",
+			task: &tasksv1.Task{Id: "1461ec84-ccff-4f3c-af34-65d0856ac3ce", Title: "Do unit tests"},
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Return(domain.Task{}, fmt.Errorf("error creating task: %w", domain.ErrTaskExists))
+			},
+			expectedCode: codes.AlreadyExists,
+		},
+		{
+			name: "internal server error",
+			task: &tasksv1.Task{Title: "Do unit tests"},
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Return(domain.Task{}, errors.New("connection refused"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ucMock := mock.NewMockTasksUC(ctrl)
+			if tt.ucMock != nil {
+				tt.ucMock(*ucMock)
+			}
+
+			response, err := newGRPCTestClient(t, ucMock).CreateTask(context.Background(), &tasksv1.CreateTaskRequest{Task: tt.task})
+			requireGRPCCode(t, tt.expectedCode, err)
+			if tt.expectedCode == codes.OK {
+				require.Equal(t, getExpectedProto().String(), response.GetTask().String())
+			}
+		})
+	}
+}
+
+func TestGRPC_UpdateTask(t *testing.T) {
+	tests := []struct {
+		name         string
+		task         *tasksv1.Task
+		ucMock       func(ucMock mock.MockTasksUC)
+		expectedCode codes.Code
+	}{
+		{
+			name: "happy path - OK",
+			task: getExpectedProto(),
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().UpdateTask(gomock.Any(), gomock.Eq(getExpectedBody())).Return(getExpectedBody(), nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "missing id",
+			task:         &tasksv1.Task{Title: "Do unit tests"},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "no task found",
+			task: getExpectedProto(),
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Return(domain.Task{}, domain.ErrTaskNotFound)
+			},
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ucMock := mock.NewMockTasksUC(ctrl)
+			if tt.ucMock != nil {
+				tt.ucMock(*ucMock)
+			}
+
+			_, err := newGRPCTestClient(t, ucMock).UpdateTask(context.Background(), &tasksv1.UpdateTaskRequest{Task: tt.task})
+			requireGRPCCode(t, tt.expectedCode, err)
+		})
+	}
+}
+
+func TestGRPC_TaskLifecycle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ucMock := mock.NewMockTasksUC(ctrl)
+	client := newGRPCTestClient(t, ucMock)
+	ctx := context.Background()
+	id := getExpectedBody().ID
+
+	deleted := getExpectedBody()
+	deletedAt := time.Date(2025, 4, 4, 0, 0, 0, 0, time.UTC)
+	deleted.DeletedAt = &deletedAt
+
+	ucMock.EXPECT().DeleteTask(gomock.Any(), gomock.Eq(id)).Return(deleted, nil)
+	response, err := client.DeleteTask(ctx, &tasksv1.DeleteTaskRequest{Id: id.String()})
+	require.NoError(t, err)
+	require.True(t, deletedAt.Equal(response.GetTask().GetDeletedAt().AsTime()))
+
+	ucMock.EXPECT().GetTrash(gomock.Any()).Return([]domain.Task{deleted}, nil)
+	stream, err := client.ListTrash(ctx, &tasksv1.ListTrashRequest{})
+	require.NoError(t, err)
+	trashed, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, id.String(), trashed.GetTask().GetId())
+	_, err = stream.Recv()
+	require.ErrorIs(t, err, io.EOF)
+
+	ucMock.EXPECT().RestoreTask(gomock.Any(), gomock.Eq(id)).Return(domain.Task{}, domain.ErrTaskNotFound)
+	_, err = client.RestoreTask(ctx, &tasksv1.RestoreTaskRequest{Id: id.String()})
+	requireGRPCCode(t, codes.NotFound, err)
+}
+
+func TestGRPC_PurgeTask(t *testing.T) {
+	tests := []struct {
+		name          string
+		adminToken    string
+		authorization string
+		ucMock        func(ucMock mock.MockTasksUC)
+		expectedCode  codes.Code
+	}{
+		{
+			name:          "happy path - OK",
+			adminToken:    "secret",
+			authorization: "Bearer secret",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().PurgeTask(gomock.Any(), gomock.Eq(getExpectedBody().ID)).Return(nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "missing token",
+			adminToken:   "secret",
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:          "wrong token",
+			adminToken:    "secret",
+			authorization: "Bearer guess",
+			expectedCode:  codes.Unauthenticated,
+		},
+		{
+			name:          "disabled",
+			authorization: "Bearer ",
+			expectedCode:  codes.PermissionDenied,
+		},
+		{
+			name:          "no task found",
+			adminToken:    "secret",
+			authorization: "Bearer secret",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().PurgeTask(gomock.Any(), gomock.Any()).Return(domain.ErrTaskNotFound)
+			},
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ucMock := mock.NewMockTasksUC(ctrl)
+			if tt.ucMock != nil {
+				tt.ucMock(*ucMock)
+			}
+			client := tasksv1.NewTaskServiceClient(newGRPCTestConn(t, ucMock, tt.adminToken))
+
+			ctx := context.Background()
+			if tt.authorization != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", tt.authorization)
+			}
+			_, err := client.PurgeTask(ctx, &tasksv1.PurgeTaskRequest{Id: getExpectedBody().ID.String()})
+			requireGRPCCode(t, tt.expectedCode, err)
+		})
+	}
+}
+
+func TestGRPC_GetOccurrencesAndSearch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ucMock := mock.NewMockTasksUC(ctrl)
+	client := newGRPCTestClient(t, ucMock)
+	ctx := context.Background()
+	id := getExpectedBody().ID
+
+	occurrence := time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC)
+	ucMock.EXPECT().GetOccurrences(gomock.Any(), gomock.Eq(id), gomock.Eq(defaultOccurrencesLimit)).Return([]time.Time{occurrence}, nil)
+	occurrences, err := client.GetOccurrences(ctx, &tasksv1.GetOccurrencesRequest{Id: id.String()})
+	require.NoError(t, err)
+	require.Len(t, occurrences.GetOccurrences(), 1)
+	require.True(t, occurrence.Equal(occurrences.GetOccurrences()[0].AsTime()))
+
+	ucMock.EXPECT().GetOccurrences(gomock.Any(), gomock.Eq(id), gomock.Eq(maxOccurrencesLimit)).Return(nil, fmt.Errorf("%w: unknown timezone Mars/Olympus", domain.ErrInvalidRecurrence))
+	_, err = client.GetOccurrences(ctx, &tasksv1.GetOccurrencesRequest{Id: id.String(), Limit: 1000})
+	requireGRPCCode(t, codes.FailedPrecondition, err)
+
+	_, err = client.GetOccurrences(ctx, &tasksv1.GetOccurrencesRequest{Id: id.String(), Limit: -1})
+	requireGRPCCode(t, codes.InvalidArgument, err)
+
+	ucMock.EXPECT().SearchTasks(gomock.Any(), gomock.Eq("unit tests"), gomock.Eq(defaultSearchLimit)).Return([]domain.TaskSearchResult{
+		{Task: getExpectedBody(), Rank: 0.5, Snippet: "extensive <b>unit</b> <b>tests</b>", Match: domain.MatchFullText},
+	}, nil)
+	results, err := client.SearchTasks(ctx, &tasksv1.SearchTasksRequest{Query: " unit tests "})
+	require.NoError(t, err)
+	require.Len(t, results.GetResults(), 1)
+	require.Equal(t, domain.MatchFullText, results.GetResults()[0].GetMatch())
+	require.Equal(t, 0.5, results.GetResults()[0].GetRank())
+
+	_, err = client.SearchTasks(ctx, &tasksv1.SearchTasksRequest{Query: "  "})
+	requireGRPCCode(t, codes.InvalidArgument, err)
+}
+
+func TestGRPC_BulkTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ucMock := mock.NewMockTasksUC(ctrl)
+	client := newGRPCTestClient(t, ucMock)
+	ctx := context.Background()
+
+	task := getExpectedBody()
+	ucMock.EXPECT().BulkTasks(gomock.Any(), gomock.Eq([]domain.BulkOperation{
+		{Op: domain.BulkOpStatus, ID: task.ID, Status: domain.StatusDone},
+		{Op: domain.BulkOpDelete, ID: uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3cf")},
+	}), gomock.Eq(true)).Return([]domain.BulkResult{
+		{Err: fmt.Errorf("%w: no", domain.ErrBulkRolledBack)},
+		{Err: domain.ErrTaskNotFound},
+	}, nil)
+
+	response, err := client.BulkTasks(ctx, &tasksv1.BulkTasksRequest{
+		Operations: []*tasksv1.BulkOperation{
+			{Op: domain.BulkOpStatus, Id: task.ID.String(), Status: domain.StatusDone},
+			{Op: domain.BulkOpDelete, Id: "1461ec84-ccff-4f3c-af34-65d0856ac3cf"},
+		},
+		Atomic: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, response.GetResults(), 2)
+	require.Equal(t, int32(codes.Aborted), response.GetResults()[0].GetCode())
+	require.Equal(t, int32(codes.NotFound), response.GetResults()[1].GetCode())
+	require.Equal(t, "task not found", response.GetResults()[1].GetError())
+
+	_, err = client.BulkTasks(ctx, &tasksv1.BulkTasksRequest{})
+	requireGRPCCode(t, codes.InvalidArgument, err)
+
+	_, err = client.BulkTasks(ctx, &tasksv1.BulkTasksRequest{Operations: []*tasksv1.BulkOperation{{Op: domain.BulkOpDelete, Id: "42"}}})
+	requireGRPCCode(t, codes.InvalidArgument, err)
+}
+
+func TestGRPC_ImportTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ucMock := mock.NewMockTasksUC(ctrl)
+	client := newGRPCTestClient(t, ucMock)
+
+	ucMock.EXPECT().ImportTasks(gomock.Any(), gomock.Any(), gomock.Eq(true)).
+		DoAndReturn(func(ctx context.Context, source domain.TaskSource, dryRun bool) (domain.ImportReport, error) {
+			report := domain.ImportReport{DryRun: dryRun, Errors: []domain.ImportError{}}
+			for {
+				line, task, err := source.Next()
+				if errors.Is(err, io.EOF) {
+					return report, nil
+				}
+				report.Total++
+				if err != nil {
+					require.ErrorIs(t, err, domain.ErrInvalidImportRow)
+					report.Failed++
+					report.Errors = append(report.Errors, domain.ImportError{Line: line, Message: err.Error()})
+					continue
+				}
+				require.Equal(t, "Do unit tests", task.Title)
+				report.Imported++
+			}
+		})
+
+	stream, err := client.ImportTasks(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&tasksv1.ImportTasksRequest{Task: &tasksv1.Task{Title: "Do unit tests"}, DryRun: true}))
+	require.NoError(t, stream.Send(&tasksv1.ImportTasksRequest{Task: &tasksv1.Task{Id: "42", Title: "Do unit tests"}}))
+	require.NoError(t, stream.Send(&tasksv1.ImportTasksRequest{Task: &tasksv1.Task{Title: "Do unit tests"}}))
+	report, err := stream.CloseAndRecv()
+	require.NoError(t, err)
+
+	require.True(t, report.GetDryRun())
+	require.Equal(t, int32(3), report.GetTotal())
+	require.Equal(t, int32(2), report.GetImported())
+	require.Equal(t, int32(1), report.GetFailed())
+	require.Equal(t, int32(2), report.GetErrors()[0].GetLine())
+	require.Equal(t, `invalid row: invalid task id "42"`, report.GetErrors()[0].GetMessage())
+
+	empty, err := client.ImportTasks(context.Background())
+	require.NoError(t, err)
+	_, err = empty.CloseAndRecv()
+	requireGRPCCode(t, codes.InvalidArgument, err)
+}
+
+func TestGRPC_Reflection(t *testing.T) {
+	conn := newGRPCTestConn(t, mock.NewMockTasksUC(gomock.NewController(t)), "")
+
+	stream, err := grpc_reflection_v1.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&grpc_reflection_v1.ServerReflectionRequest{
+		MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_ListServices{},
+	}))
+	response, err := stream.Recv()
+	require.NoError(t, err)
+
+	var services []string
+	for _, service := range response.GetListServicesResponse().GetService() {
+		services = append(services, service.GetName())
+	}
+	require.Contains(t, services, "tasks.v1.TaskService")
+}
+
+func TestGRPCError(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		err      error
+		expected codes.Code
+	}{
+		{name: "not found", ctx: context.Background(), err: fmt.Errorf("wrapped: %w", domain.ErrTaskNotFound), expected: codes.NotFound},
+		{name: "invalid task", ctx: context.Background(), err: domain.ErrInvalidTask, expected: codes.InvalidArgument},
+		{name: "invalid import", ctx: context.Background(), err: domain.ErrInvalidImport, expected: codes.InvalidArgument},
+		{name: "task exists", ctx: context.Background(), err: domain.ErrTaskExists, expected: codes.AlreadyExists},
+		{name: "deadline", ctx: context.Background(), err: context.DeadlineExceeded, expected: codes.DeadlineExceeded},
+		{name: "cancelled call", ctx: cancelled, err: errors.New("error fetching task: driver: bad connection"), expected: codes.Canceled},
+		{name: "other", ctx: context.Background(), err: errors.New("connection refused"), expected: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, status.Code(grpcError(tt.ctx, tt.err)))
+		})
+	}
+}
@@ -1,11 +1,44 @@
 package handler
 
 import (
+	"api/domain"
 	"context"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 )
 
+const (
+	defaultOccurrencesLimit = 5
+	maxOccurrencesLimit     = 100
+	defaultSearchLimit      = 20
+	maxSearchLimit          = 100
+	defaultTasksLimit       = 100
+	maxTasksLimit           = 1000
+)
+
 func getContextFromRequest(r *http.Request) (context.Context, context.CancelFunc) {
 	return context.WithTimeout(r.Context(), 5*time.Minute)
 }
+
+func taskFilterFromQuery(r *http.Request) domain.TaskFilter {
+	query := r.URL.Query()
+	return domain.TaskFilter{
+		Status: query.Get("status"),
+	}
+}
+
+// intFromQuery reads a positive integer query param, falling back to defaultValue when
+// it is missing and capping it at maxValue.
+func intFromQuery(r *http.Request, name string, defaultValue, maxValue int) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return defaultValue, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 1 {
+		return 0, fmt.Errorf("query param %s must be a positive integer", name)
+	}
+	return min(value, maxValue), nil
+}
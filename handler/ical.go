@@ -0,0 +1,281 @@
+package handler
+
+import (
+	"api/domain"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	icsContentType  = "text/calendar; charset=utf-8"
+	icsTimeFormat   = "20060102T150405Z"
+	icsMaxLineBytes = 75
+)
+
+var errInvalidICS = errors.New("invalid iCalendar data")
+
+// icsEncoder writes an RFC 5545 calendar with one VTODO or VEVENT per task.
+type icsEncoder struct {
+	w             io.Writer
+	name          string
+	componentType string
+}
+
+var (
+	icsEscaper   = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+	icsUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+)
+
+func (ie *icsEncoder) Begin() error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//api//Tasks//EN",
+		"CALSCALE:GREGORIAN",
+	}
+	if ie.name != "" {
+		lines = append(lines, "X-WR-CALNAME:"+icsEscaper.Replace(ie.name))
+	}
+	return ie.writeLines(lines...)
+}
+
+func (ie *icsEncoder) Encode(task domain.Task) error {
+	created := task.CreatedAt.UTC().Format(icsTimeFormat)
+
+	component := "VTODO"
+	dueProperty := "DUE"
+	if ie.componentType == calendarTypeEvent {
+		component = "VEVENT"
+		dueProperty = "DTSTART"
+	}
+
+	lines := []string{
+		"BEGIN:" + component,
+		"UID:" + task.ID.String(),
+		"DTSTAMP:" + created,
+		"CREATED:" + created,
+	}
+	if !task.DueDate.IsZero() {
+		lines = append(lines, dueProperty+":"+task.DueDate.UTC().Format(icsTimeFormat))
+	}
+	lines = append(lines,
+		"SUMMARY:"+icsEscaper.Replace(task.Title),
+		"DESCRIPTION:"+icsEscaper.Replace(task.Description),
+		"STATUS:"+icsStatus(ie.componentType, task.Status),
+		"END:"+component,
+	)
+	return ie.writeLines(lines...)
+}
+
+func (ie *icsEncoder) End() error {
+	return ie.writeLines("END:VCALENDAR")
+}
+
+func (ie *icsEncoder) writeLines(lines ...string) error {
+	for _, line := range lines {
+		if _, err := io.WriteString(ie.w, foldICSLine(line)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// icsStatus maps a task status to the status values RFC 5545 allows for the component.
+func icsStatus(componentType, status string) string {
+	if componentType == calendarTypeEvent {
+		return "CONFIRMED"
+	}
+	switch status {
+	case domain.StatusDone:
+		return "COMPLETED"
+	case domain.StatusPending:
+		return "NEEDS-ACTION"
+	default:
+		return "IN-PROCESS"
+	}
+}
+
+// foldICSLine terminates line with CRLF, breaking it into lines of at most 75 octets
+// continued by a leading space. Lines are never broken inside a UTF-8 sequence.
+func foldICSLine(line string) string {
+	var sb strings.Builder
+	limit := icsMaxLineBytes
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		sb.WriteString(line[:cut])
+		sb.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space counts towards the length of continuation lines.
+		limit = icsMaxLineBytes - 1
+	}
+	sb.WriteString(line)
+	sb.WriteString("\r\n")
+	return sb.String()
+}
+
+// icsTodo holds the VTODO properties that map onto a task.
+type icsTodo struct {
+	UID         string
+	Summary     string
+	Description string
+	Status      string
+	Due         time.Time
+}
+
+// icsProperty is a content line split into its name, parameters and raw value.
+type icsProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// parseICSTodo reads the first VTODO of a calendar object. Other components, such as
+// the VTIMEZONE definitions clients send along, are skipped.
+func parseICSTodo(r io.Reader) (icsTodo, error) {
+	lines, err := unfoldICSLines(r)
+	if err != nil {
+		return icsTodo{}, err
+	}
+
+	var (
+		todo   icsTodo
+		found  bool
+		inTodo bool
+		depth  int
+	)
+	for _, line := range lines {
+		prop, err := parseICSProperty(line)
+		if err != nil {
+			return icsTodo{}, err
+		}
+
+		switch prop.Name {
+		case "BEGIN":
+			depth++
+			if strings.EqualFold(prop.Value, "VTODO") && depth == 2 && !found {
+				inTodo = true
+			}
+			continue
+		case "END":
+			if inTodo && strings.EqualFold(prop.Value, "VTODO") && depth == 2 {
+				inTodo = false
+				found = true
+			}
+			depth--
+			continue
+		}
+		// Properties of components nested in the VTODO, e.g. VALARM, are not ours.
+		if !inTodo || depth != 2 {
+			continue
+		}
+
+		switch prop.Name {
+		case "UID":
+			todo.UID = prop.Value
+		case "SUMMARY":
+			todo.Summary = icsUnescaper.Replace(prop.Value)
+		case "DESCRIPTION":
+			todo.Description = icsUnescaper.Replace(prop.Value)
+		case "STATUS":
+			todo.Status = strings.ToUpper(prop.Value)
+		case "DUE":
+			if todo.Due, err = parseICSTime(prop); err != nil {
+				return icsTodo{}, err
+			}
+		}
+	}
+
+	if !found {
+		return icsTodo{}, fmt.Errorf("%w: no VTODO component", errInvalidICS)
+	}
+	return todo, nil
+}
+
+// unfoldICSLines joins folded content lines and drops empty ones.
+func unfoldICSLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidICS, err)
+	}
+	return lines, nil
+}
+
+func parseICSProperty(line string) (icsProperty, error) {
+	// The value starts after the first colon outside of a quoted parameter value.
+	quoted := false
+	colon := -1
+	for i := 0; i < len(line) && colon < 0; i++ {
+		switch line[i] {
+		case '"':
+			quoted = !quoted
+		case ':':
+			if !quoted {
+				colon = i
+			}
+		}
+	}
+	if colon < 0 {
+		return icsProperty{}, fmt.Errorf("%w: malformed line %q", errInvalidICS, line)
+	}
+
+	prop := icsProperty{Params: map[string]string{}, Value: line[colon+1:]}
+	parts := strings.Split(line[:colon], ";")
+	prop.Name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		name, value, _ := strings.Cut(param, "=")
+		prop.Params[strings.ToUpper(name)] = strings.Trim(value, `"`)
+	}
+	return prop, nil
+}
+
+// parseICSTime reads a DATE or DATE-TIME value. Local times are resolved in the zone
+// named by TZID; floating times and unknown zones are taken as UTC.
+func parseICSTime(prop icsProperty) (time.Time, error) {
+	if prop.Params["VALUE"] == "DATE" {
+		t, err := time.Parse("20060102", prop.Value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: invalid %s %q", errInvalidICS, prop.Name, prop.Value)
+		}
+		return t, nil
+	}
+
+	if strings.HasSuffix(prop.Value, "Z") {
+		t, err := time.Parse(icsTimeFormat, prop.Value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: invalid %s %q", errInvalidICS, prop.Name, prop.Value)
+		}
+		return t, nil
+	}
+
+	loc := time.UTC
+	if tzid := prop.Params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", prop.Value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid %s %q", errInvalidICS, prop.Name, prop.Value)
+	}
+	return t.UTC(), nil
+}
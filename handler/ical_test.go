@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFoldICSLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected string
+	}{
+		{
+			name:     "short line",
+			line:     "SUMMARY:Short",
+			expected: "SUMMARY:Short\r\n",
+		},
+		{
+			name:     "exactly 75 octets",
+			line:     strings.Repeat("a", 75),
+			expected: strings.Repeat("a", 75) + "\r\n",
+		},
+		{
+			name:     "continuation lines hold 74 octets after the space",
+			line:     strings.Repeat("a", 75+74+1),
+			expected: strings.Repeat("a", 75) + "\r\n " + strings.Repeat("a", 74) + "\r\n a\r\n",
+		},
+		{
+			name:     "multi-byte character is not split",
+			line:     strings.Repeat("a", 74) + "ü",
+			expected: strings.Repeat("a", 74) + "\r\n ü\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, foldICSLine(tt.line))
+		})
+	}
+}
+
+func TestParseICSTodo(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		expected      icsTodo
+		expectedError string
+	}{
+		{
+			name: "folded and escaped values",
+			data: "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:abc\r\nSUMMARY:One\\, two\\; three\r\nDESCRIPTION:Line one\\nLine \r\n two with a back\\\\slash\r\n" +
+				"STATUS:completed\r\nDUE:20250403T101500Z\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+			expected: icsTodo{
+				UID:         "abc",
+				Summary:     "One, two; three",
+				Description: "Line one\nLine two with a back\\slash",
+				Status:      "COMPLETED",
+				Due:         time.Date(2025, 4, 3, 10, 15, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "other components and alarms are skipped",
+			data: "BEGIN:VCALENDAR\nBEGIN:VTIMEZONE\nTZID:Europe/Sofia\nEND:VTIMEZONE\nBEGIN:VTODO\nUID:abc\nSUMMARY:Task\n" +
+				"BEGIN:VALARM\nDESCRIPTION:Alarm\nEND:VALARM\nEND:VTODO\nEND:VCALENDAR\n",
+			expected: icsTodo{UID: "abc", Summary: "Task"},
+		},
+		{
+			name:     "quoted parameter with a colon",
+			data:     "BEGIN:VCALENDAR\nBEGIN:VTODO\nUID:abc\nSUMMARY;ALTREP=\"cid:part1\":Task\nEND:VTODO\nEND:VCALENDAR\n",
+			expected: icsTodo{UID: "abc", Summary: "Task"},
+		},
+		{
+			name:          "no VTODO",
+			data:          "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:abc\nEND:VEVENT\nEND:VCALENDAR\n",
+			expectedError: "invalid iCalendar data: no VTODO component",
+		},
+		{
+			name:          "malformed line",
+			data:          "BEGIN:VCALENDAR\nBEGIN:VTODO\nSUMMARY\nEND:VTODO\nEND:VCALENDAR\n",
+			expectedError: `invalid iCalendar data: malformed line "SUMMARY"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo, err := parseICSTodo(strings.NewReader(tt.data))
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, todo)
+		})
+	}
+}
+
+func TestParseICSTime(t *testing.T) {
+	tests := []struct {
+		name          string
+		line          string
+		expected      time.Time
+		expectedError bool
+	}{
+		{name: "UTC", line: "DUE:20250403T101500Z", expected: time.Date(2025, 4, 3, 10, 15, 0, 0, time.UTC)},
+		{name: "local time with TZID", line: "DUE;TZID=Europe/Sofia:20250403T101500", expected: time.Date(2025, 4, 3, 7, 15, 0, 0, time.UTC)},
+		{name: "unknown TZID", line: "DUE;TZID=W. Europe Standard Time:20250403T101500", expected: time.Date(2025, 4, 3, 10, 15, 0, 0, time.UTC)},
+		{name: "floating time", line: "DUE:20250403T101500", expected: time.Date(2025, 4, 3, 10, 15, 0, 0, time.UTC)},
+		{name: "date", line: "DUE;VALUE=DATE:20250403", expected: time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)},
+		{name: "invalid", line: "DUE:tomorrow", expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prop, err := parseICSProperty(tt.line)
+			require.NoError(t, err)
+
+			due, err := parseICSTime(prop)
+			if tt.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, due)
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"api/domain"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxImportLineSize = 1024 * 1024
+
+var errUnsupportedImportFormat = errors.New("unsupported import format, use text/csv or application/x-ndjson")
+
+// importFields are the task fields which can be imported, by their JSON names.
+var importFields = []string{"id", "title", "description", "status", "due_date", "rrule", "timezone"}
+
+// ImportTasks imports the CSV or NDJSON request body. The body is parsed while the
+// tasks are being inserted, so arbitrarily large files can be imported.
+func (th TasksHandler) ImportTasks(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := getContextFromRequest(r)
+	defer cancel()
+
+	dryRun := false
+	if raw := r.URL.Query().Get("dry_run"); raw != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "query param dry_run must be a boolean",
+			})
+			return
+		}
+	}
+
+	source, err := taskSourceFromRequest(r)
+	if err != nil {
+		code := bodyErrorStatus(err)
+		if errors.Is(err, errUnsupportedImportFormat) {
+			code = http.StatusUnsupportedMediaType
+		}
+		render.Status(r, code)
+		render.JSON(w, r, ErrorResponse{
+			Code:    code,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	report, err := th.tasksService.ImportTasks(ctx, source, dryRun)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidImport) {
+			code := bodyErrorStatus(err)
+			render.Status(r, code)
+			render.JSON(w, r, ErrorResponse{
+				Code:    code,
+				Message: err.Error(),
+			})
+			return
+		}
+
+		renderServerError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, report)
+}
+
+// taskSourceFromRequest picks the parser by the 'format' query param, falling back to
+// the Content-Type of the request.
+func taskSourceFromRequest(r *http.Request) (domain.TaskSource, error) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		switch mediaType {
+		case "text/csv":
+			format = "csv"
+		case "application/x-ndjson", "application/ndjson", "application/jsonl":
+			format = "ndjson"
+		}
+	}
+
+	switch format {
+	case "csv":
+		mapping, err := importMappingFromQuery(r)
+		if err != nil {
+			return nil, err
+		}
+		return newCSVTaskSource(r.Body, mapping)
+	case "ndjson":
+		return newNDJSONTaskSource(r.Body), nil
+	default:
+		return nil, errUnsupportedImportFormat
+	}
+}
+
+// importMappingFromQuery reads the 'mapping' query param, e.g.
+// 'title:Task name,due_date:Deadline', which maps task fields to CSV header names.
+func importMappingFromQuery(r *http.Request) (map[string]string, error) {
+	mapping := make(map[string]string)
+	raw := r.URL.Query().Get("mapping")
+	if raw == "" {
+		return mapping, nil
+	}
+	for _, pair := range strings.Split(raw, ",") {
+		field, column, ok := strings.Cut(pair, ":")
+		field = strings.TrimSpace(field)
+		if !ok || !isImportField(field) {
+			return nil, fmt.Errorf("invalid mapping %q, expected <field>:<column> with a field out of %s", pair, strings.Join(importFields, ", "))
+		}
+		mapping[field] = strings.TrimSpace(column)
+	}
+	return mapping, nil
+}
+
+func isImportField(field string) bool {
+	for _, f := range importFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// csvTaskSource reads tasks from CSV with a header row. Columns are matched to task
+// fields by name, case-insensitively, unless a mapping says otherwise; other columns
+// are ignored.
+type csvTaskSource struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCSVTaskSource(in io.Reader, mapping map[string]string) (*csvTaskSource, error) {
+	reader := csv.NewReader(in)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		// The error is kept for bodies cut off by LimitBody.
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		field := strings.ToLower(name)
+		for f, column := range mapping {
+			if strings.EqualFold(column, name) {
+				field = f
+			}
+		}
+		if isImportField(field) {
+			columns[field] = i
+		}
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, errors.New("csv header has no title column")
+	}
+
+	return &csvTaskSource{reader: reader, columns: columns}, nil
+}
+
+func (cs *csvTaskSource) Next() (int, domain.Task, error) {
+	record, err := cs.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return 0, domain.Task{}, io.EOF
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return parseErr.StartLine, domain.Task{}, fmt.Errorf("%w: %v", domain.ErrInvalidImportRow, parseErr.Err)
+	}
+	if err != nil {
+		return 0, domain.Task{}, fmt.Errorf("%w: %w", domain.ErrInvalidImport, err)
+	}
+
+	line, _ := cs.reader.FieldPos(0)
+	value := func(field string) string {
+		if i, ok := cs.columns[field]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	task := domain.Task{
+		Title:       value("title"),
+		Description: value("description"),
+		Status:      value("status"),
+		RRule:       value("rrule"),
+		Timezone:    value("timezone"),
+	}
+	if raw := value("id"); raw != "" {
+		if task.ID, err = uuid.Parse(raw); err != nil {
+			return line, domain.Task{}, fmt.Errorf("%w: invalid id: %v", domain.ErrInvalidImportRow, err)
+		}
+	}
+	if raw := value("due_date"); raw != "" {
+		if task.DueDate, err = parseImportDate(raw); err != nil {
+			return line, domain.Task{}, fmt.Errorf("%w: invalid due_date %q", domain.ErrInvalidImportRow, raw)
+		}
+	}
+	return line, task, nil
+}
+
+// parseImportDate accepts RFC 3339 timestamps and plain dates, which are taken as
+// midnight UTC.
+func parseImportDate(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse(time.DateOnly, raw)
+}
+
+// ndjsonTaskSource reads one JSON task per line, skipping blank lines.
+type ndjsonTaskSource struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newNDJSONTaskSource(in io.Reader) *ndjsonTaskSource {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineSize)
+	return &ndjsonTaskSource{scanner: scanner}
+}
+
+func (ns *ndjsonTaskSource) Next() (int, domain.Task, error) {
+	for ns.scanner.Scan() {
+		ns.line++
+		data := bytes.TrimSpace(ns.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var task domain.Task
+		if err := json.Unmarshal(data, &task); err != nil {
+			return ns.line, domain.Task{}, fmt.Errorf("%w: %v", domain.ErrInvalidImportRow, err)
+		}
+		task.DueDate = task.DueDate.UTC()
+		return ns.line, task, nil
+	}
+	if err := ns.scanner.Err(); err != nil {
+		return ns.line + 1, domain.Task{}, fmt.Errorf("%w: line %d: %w", domain.ErrInvalidImport, ns.line+1, err)
+	}
+	return ns.line, domain.Task{}, io.EOF
+}
@@ -0,0 +1,261 @@
+package handler
+
+import (
+	"api/domain"
+	mock "api/mocks/mock_uc"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type parsedRow struct {
+	line int
+	task domain.Task
+	err  error
+}
+
+func readAll(t *testing.T, source domain.TaskSource) []parsedRow {
+	t.Helper()
+	var rows []parsedRow
+	for {
+		line, task, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			return rows
+		}
+		rows = append(rows, parsedRow{line: line, task: task, err: err})
+		if err != nil && !errors.Is(err, domain.ErrInvalidImportRow) {
+			return rows
+		}
+	}
+}
+
+func TestCSVTaskSource(t *testing.T) {
+	input := "\ufeffID,Title,Notes,Status,Due date,Ignored\n" +
+		"1461ec84-ccff-4f3c-af34-65d0856ac3ce,Do unit tests,\"All layers,\nincluding handlers\",PENDING,2025-04-03,x\n" +
+		"not-a-uuid,Broken id,,PENDING,,\n" +
+		",Broken date,,PENDING,tomorrow,\n" +
+		",No due date\n" +
+		",Bare \"quote,,PENDING,,\n" +
+		",After the broken row,,DONE,2025-04-03T10:00:00+03:00,\n"
+
+	source, err := newCSVTaskSource(strings.NewReader(input), map[string]string{"description": "Notes", "due_date": "due date"})
+	require.NoError(t, err)
+
+	rows := readAll(t, source)
+	require.Len(t, rows, 6)
+
+	require.NoError(t, rows[0].err)
+	require.Equal(t, 2, rows[0].line)
+	require.Equal(t, domain.Task{
+		ID:          uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce"),
+		Title:       "Do unit tests",
+		Description: "All layers,\nincluding handlers",
+		Status:      "PENDING",
+		DueDate:     time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC),
+	}, rows[0].task)
+
+	require.ErrorIs(t, rows[1].err, domain.ErrInvalidImportRow)
+	require.Equal(t, 4, rows[1].line)
+	require.ErrorIs(t, rows[2].err, domain.ErrInvalidImportRow)
+	require.Equal(t, 5, rows[2].line)
+
+	require.NoError(t, rows[3].err)
+	require.Equal(t, "No due date", rows[3].task.Title)
+
+	require.ErrorIs(t, rows[4].err, domain.ErrInvalidImportRow)
+	require.Equal(t, 7, rows[4].line)
+
+	require.NoError(t, rows[5].err)
+	require.Equal(t, 8, rows[5].line)
+	require.Equal(t, time.Date(2025, 4, 3, 7, 0, 0, 0, time.UTC), rows[5].task.DueDate)
+}
+
+func TestCSVTaskSource_InvalidHeader(t *testing.T) {
+	_, err := newCSVTaskSource(strings.NewReader("name,notes\nDo unit tests,x\n"), nil)
+	require.ErrorContains(t, err, "no title column")
+
+	_, err = newCSVTaskSource(strings.NewReader(""), nil)
+	require.Error(t, err)
+}
+
+func TestNDJSONTaskSource(t *testing.T) {
+	input := `{"title":"Do unit tests","status":"PENDING","due_date":"2025-04-03T10:00:00+03:00"}` + "\n" +
+		"\n" +
+		`{"title":` + "\n" +
+		`{"title":"After the broken row"}` + "\n"
+
+	rows := readAll(t, newNDJSONTaskSource(strings.NewReader(input)))
+	require.Len(t, rows, 3)
+
+	require.NoError(t, rows[0].err)
+	require.Equal(t, 1, rows[0].line)
+	require.Equal(t, "Do unit tests", rows[0].task.Title)
+	require.Equal(t, time.Date(2025, 4, 3, 7, 0, 0, 0, time.UTC), rows[0].task.DueDate)
+
+	require.ErrorIs(t, rows[1].err, domain.ErrInvalidImportRow)
+	require.Equal(t, 3, rows[1].line)
+
+	require.NoError(t, rows[2].err)
+	require.Equal(t, 4, rows[2].line)
+}
+
+func TestNDJSONTaskSource_LineTooLong(t *testing.T) {
+	input := `{"title":"` + strings.Repeat("x", maxImportLineSize) + `"}` + "\n"
+
+	rows := readAll(t, newNDJSONTaskSource(strings.NewReader(input)))
+	require.Len(t, rows, 1)
+	require.ErrorIs(t, rows[0].err, domain.ErrInvalidImport)
+}
+
+func TestImportTasks(t *testing.T) {
+	// countRows stands in for the use case and reports every parsed row as imported.
+	countRows := func(ctx context.Context, source domain.TaskSource, dryRun bool) (domain.ImportReport, error) {
+		report := domain.ImportReport{DryRun: dryRun, Errors: []domain.ImportError{}}
+		for {
+			line, _, err := source.Next()
+			if errors.Is(err, io.EOF) {
+				return report, nil
+			}
+			report.Total++
+			if err != nil {
+				report.Failed++
+				report.Errors = append(report.Errors, domain.ImportError{Line: line, Message: err.Error()})
+				continue
+			}
+			report.Imported++
+		}
+	}
+
+	tests := []struct {
+		name               string
+		query              string
+		contentType        string
+		body               string
+		ucMock             func(ucMock mock.MockTasksUC)
+		expectedStatusCode int
+		expectedReport     *domain.ImportReport
+	}{
+		{
+			name:        "csv - OK",
+			contentType: "text/csv; charset=utf-8",
+			query:       "?mapping=title:Name",
+			body:        "Name,status\nDo unit tests,PENDING\nDo other tests,PENDING\n",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().ImportTasks(gomock.Any(), gomock.Any(), false).DoAndReturn(countRows)
+			},
+			expectedStatusCode: 200,
+			expectedReport:     &domain.ImportReport{Total: 2, Imported: 2, Errors: []domain.ImportError{}},
+		},
+		{
+			name:        "ndjson dry run - OK",
+			contentType: "application/x-ndjson",
+			query:       "?dry_run=true",
+			body:        "{\"title\":\"Do unit tests\"}\n{\n",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().ImportTasks(gomock.Any(), gomock.Any(), true).DoAndReturn(countRows)
+			},
+			expectedStatusCode: 200,
+			expectedReport: &domain.ImportReport{DryRun: true, Total: 2, Imported: 1, Failed: 1, Errors: []domain.ImportError{
+				{Line: 2, Message: "invalid row: unexpected end of JSON input"},
+			}},
+		},
+		{
+			name:  "format query param wins",
+			query: "?format=ndjson",
+			body:  "{\"title\":\"Do unit tests\"}\n",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().ImportTasks(gomock.Any(), gomock.Any(), false).DoAndReturn(countRows)
+			},
+			expectedStatusCode: 200,
+			expectedReport:     &domain.ImportReport{Total: 1, Imported: 1, Errors: []domain.ImportError{}},
+		},
+		{
+			name:               "unsupported format",
+			contentType:        "application/json",
+			body:               "[]",
+			expectedStatusCode: 415,
+		},
+		{
+			name:               "invalid mapping",
+			contentType:        "text/csv",
+			query:              "?mapping=owner:Name",
+			body:               "Name\nDo unit tests\n",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "missing title column",
+			contentType:        "text/csv",
+			body:               "Name\nDo unit tests\n",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "invalid dry_run",
+			contentType:        "text/csv",
+			query:              "?dry_run=maybe",
+			body:               "title\nDo unit tests\n",
+			expectedStatusCode: 400,
+		},
+		{
+			name:        "unreadable input",
+			contentType: "application/x-ndjson",
+			body:        "{}\n",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().ImportTasks(gomock.Any(), gomock.Any(), false).Return(domain.ImportReport{}, domain.ErrInvalidImport)
+			},
+			expectedStatusCode: 400,
+		},
+		{
+			name:        "import error",
+			contentType: "application/x-ndjson",
+			body:        "{}\n",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().ImportTasks(gomock.Any(), gomock.Any(), false).Return(domain.ImportReport{}, errors.New("db error"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			r := chi.NewRouter()
+			recorder := httptest.NewRecorder()
+			ucMock := mock.NewMockTasksUC(ctrl)
+			handler := NewTasksHandler(ucMock)
+
+			if tt.ucMock != nil {
+				tt.ucMock(*ucMock)
+			}
+
+			r.Post("/api/tasks/import", handler.ImportTasks)
+			req, err := http.NewRequest(http.MethodPost, "/api/tasks/import"+tt.query, bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			r.ServeHTTP(recorder, req)
+			require.Equal(t, tt.expectedStatusCode, recorder.Code)
+
+			if tt.expectedReport != nil {
+				var report domain.ImportReport
+				err = json.Unmarshal(recorder.Body.Bytes(), &report)
+				require.NoError(t, err)
+				require.Equal(t, *tt.expectedReport, report)
+			}
+		})
+	}
+}
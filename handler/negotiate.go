@@ -0,0 +1,241 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/go-chi/render"
+	"github.com/vmihailenco/msgpack/v5"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// responseFormat is a representation the task endpoints can answer with. The binary
+// formats carry the same fields as JSON, so that clients can switch between them
+// without another schema.
+type responseFormat struct {
+	contentType string
+	aliases     []string
+	encode      func(w io.Writer, v any) error
+}
+
+// responseFormats are in the order of preference of the server, which settles ties
+// between media types the client accepts equally.
+var responseFormats = []responseFormat{
+	{contentType: "application/json", encode: encodeJSON},
+	{contentType: "application/x-ndjson", aliases: []string{"application/ndjson", "application/jsonl"}, encode: encodeNDJSON},
+	{contentType: "application/msgpack", aliases: []string{"application/x-msgpack", "application/vnd.msgpack"}, encode: encodeMsgpack},
+	{contentType: "application/cbor", encode: encodeCBOR},
+}
+
+type responseFormatCtxKey struct{}
+
+// Negotiate picks the response format of the request from its Accept header and
+// answers with HTTP 406 when none of the accepted media types can be produced. Error
+// responses are always sent as JSON.
+func Negotiate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+
+		format, ok := negotiateFormat(r.Header.Get("Accept"))
+		if !ok {
+			contentTypes := make([]string, len(responseFormats))
+			for i, supported := range responseFormats {
+				contentTypes[i] = supported.contentType
+			}
+			render.Status(r, http.StatusNotAcceptable)
+			render.JSON(w, r, ErrorResponse{
+				Code:    http.StatusNotAcceptable,
+				Message: "none of the accepted media types is supported, use one of " + strings.Join(contentTypes, ", "),
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), responseFormatCtxKey{}, format)))
+	})
+}
+
+// negotiateFormat returns the format with the highest quality in accept. A missing
+// header accepts anything, which is JSON.
+func negotiateFormat(accept string) (responseFormat, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return responseFormats[0], true
+	}
+
+	ranges := parseQualities(accept)
+	var best responseFormat
+	bestQuality := 0.0
+	for _, format := range responseFormats {
+		quality := 0.0
+		for _, contentType := range append([]string{format.contentType}, format.aliases...) {
+			quality = max(quality, mediaRangeQuality(ranges, contentType))
+		}
+		if quality > bestQuality {
+			best, bestQuality = format, quality
+		}
+	}
+	return best, bestQuality > 0
+}
+
+// mediaRangeQuality returns the quality of the most specific range matching
+// contentType, so that "application/cbor;q=0, */*" rules out CBOR only.
+func mediaRangeQuality(ranges []qualityValue, contentType string) float64 {
+	mainType, _, _ := strings.Cut(contentType, "/")
+	quality, specificity := 0.0, -1
+	for _, mediaRange := range ranges {
+		var rank int
+		switch mediaRange.value {
+		case contentType:
+			rank = 2
+		case mainType + "/*":
+			rank = 1
+		case "*/*":
+			rank = 0
+		default:
+			continue
+		}
+		if rank > specificity {
+			quality, specificity = mediaRange.quality, rank
+		}
+	}
+	return quality
+}
+
+// qualityValue is an entry of a header like Accept or Accept-Encoding.
+type qualityValue struct {
+	value   string
+	quality float64
+}
+
+// parseQualities splits a header into its lower-cased values and their q parameters.
+// Other parameters are dropped, and malformed qualities count as 0.
+func parseQualities(header string) []qualityValue {
+	var values []qualityValue
+	for _, entry := range strings.Split(header, ",") {
+		params := strings.Split(entry, ";")
+		value := strings.ToLower(strings.TrimSpace(params[0]))
+		if value == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			name, raw, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(strings.TrimSpace(name), "q") {
+				parsed, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+				if err != nil || parsed < 0 || parsed > 1 {
+					parsed = 0
+				}
+				quality = parsed
+			}
+		}
+		values = append(values, qualityValue{value: value, quality: quality})
+	}
+	return values
+}
+
+// respond writes v in the format picked by Negotiate, with the status set through
+// render.Status. Requests which did not pass through Negotiate get JSON.
+func respond(w http.ResponseWriter, r *http.Request, v any) {
+	format, ok := r.Context().Value(responseFormatCtxKey{}).(responseFormat)
+	if !ok || format.contentType == responseFormats[0].contentType {
+		render.JSON(w, r, v)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := format.encode(&buf, v); err != nil {
+		renderServerError(w, r, fmt.Errorf("error encoding response: %w", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", format.contentType)
+	if status, ok := r.Context().Value(render.StatusCtxKey).(int); ok {
+		w.WriteHeader(status)
+	}
+	_, _ = w.Write(buf.Bytes())
+}
+
+func encodeJSON(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+// encodeNDJSON writes the elements of a list one per line, and anything else as a
+// single line.
+func encodeNDJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Slice {
+		return encoder.Encode(v)
+	}
+	for i := range value.Len() {
+		if err := encoder.Encode(value.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeMsgpack(w io.Writer, v any) error {
+	value, err := jsonValue(v)
+	if err != nil {
+		return err
+	}
+	encoder := msgpack.NewEncoder(w)
+	encoder.SetSortMapKeys(true)
+	return encoder.Encode(value)
+}
+
+var cborEncMode, _ = cbor.EncOptions{Sort: cbor.SortCoreDeterministic}.EncMode()
+
+func encodeCBOR(w io.Writer, v any) error {
+	value, err := jsonValue(v)
+	if err != nil {
+		return err
+	}
+	return cborEncMode.NewEncoder(w).Encode(value)
+}
+
+// jsonValue converts v into the maps, slices and scalars of its JSON representation,
+// which keeps the field names, omitted fields and the formatting of ids and times of
+// the JSON responses in the binary formats.
+func jsonValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return convertNumbers(value), nil
+}
+
+// convertNumbers turns the numbers decoded by jsonValue into integers where possible,
+// as the binary formats have a separate, smaller encoding for them.
+func convertNumbers(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for key, item := range v {
+			v[key] = convertNumbers(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = convertNumbers(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"api/domain"
+	mock "api/mocks/mock_uc"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// decodeAs decodes a negotiated response body and converts it back into the JSON
+// representation, so that every format can be compared to the same expectation.
+func decodeAs(t *testing.T, contentType string, body []byte) []byte {
+	t.Helper()
+
+	var value any
+	switch contentType {
+	case "application/json":
+		return body
+	case "application/x-ndjson":
+		var items []json.RawMessage
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		for scanner.Scan() {
+			items = append(items, json.RawMessage(scanner.Text()))
+		}
+		require.NoError(t, scanner.Err())
+		if len(items) == 1 {
+			return items[0]
+		}
+		value = items
+	case "application/msgpack":
+		require.NoError(t, msgpack.Unmarshal(body, &value))
+	case "application/cbor":
+		decMode, err := cbor.DecOptions{DefaultMapType: reflect.TypeOf(map[string]any{})}.DecMode()
+		require.NoError(t, err)
+		require.NoError(t, decMode.Unmarshal(body, &value))
+	default:
+		t.Fatalf("unexpected content type %q", contentType)
+	}
+
+	data, err := json.Marshal(value)
+	require.NoError(t, err)
+	return data
+}
+
+func TestNegotiate_TaskEndpoints(t *testing.T) {
+	otherTask := getExpectedBody()
+	otherTask.ID = [16]byte{1}
+	otherTask.Title = "Write the docs"
+	tasksList := []domain.Task{getExpectedBody(), otherTask}
+
+	endpoints := []struct {
+		name     string
+		target   string
+		ucMock   func(ucMock *mock.MockTasksUC)
+		expected any
+	}{
+		{
+			name:   "get tasks",
+			target: "/api/tasks",
+			ucMock: func(ucMock *mock.MockTasksUC) {
+				ucMock.EXPECT().GetTasks(gomock.Any(), gomock.Any()).Return(tasksList, nil)
+			},
+			expected: tasksList,
+		},
+		{
+			name:   "get task",
+			target: "/api/task/1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			ucMock: func(ucMock *mock.MockTasksUC) {
+				ucMock.EXPECT().GetTaskById(gomock.Any(), gomock.Any()).Return(getExpectedBody(), nil)
+			},
+			expected: getExpectedBody(),
+		},
+	}
+
+	formats := []struct {
+		accept              string
+		expectedContentType string
+	}{
+		{accept: "", expectedContentType: "application/json"},
+		{accept: "application/json", expectedContentType: "application/json"},
+		{accept: "application/x-ndjson", expectedContentType: "application/x-ndjson"},
+		{accept: "application/msgpack", expectedContentType: "application/msgpack"},
+		{accept: "application/x-msgpack", expectedContentType: "application/msgpack"},
+		{accept: "application/cbor", expectedContentType: "application/cbor"},
+	}
+
+	for _, endpoint := range endpoints {
+		for _, format := range formats {
+			t.Run(endpoint.name+" - "+format.accept, func(t *testing.T) {
+				ucMock := mock.NewMockTasksUC(gomock.NewController(t))
+				endpoint.ucMock(ucMock)
+				tasksHandler := NewTasksHandler(ucMock)
+
+				r := chi.NewRouter()
+				r.Use(Negotiate)
+				r.Get("/api/tasks", tasksHandler.GetTasks)
+				r.Get("/api/task/{id}", tasksHandler.GetTaskById)
+
+				req := httptest.NewRequest(http.MethodGet, endpoint.target, nil)
+				req.Header.Set("Accept", format.accept)
+				recorder := httptest.NewRecorder()
+				r.ServeHTTP(recorder, req)
+
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "Accept", recorder.Header().Get("Vary"))
+				contentType := recorder.Header().Get("Content-Type")
+				require.Equal(t, format.expectedContentType, contentType)
+
+				expected, err := json.Marshal(endpoint.expected)
+				require.NoError(t, err)
+				require.JSONEq(t, string(expected), string(decodeAs(t, contentType, recorder.Body.Bytes())))
+			})
+		}
+	}
+}
+
+func TestNegotiate_NotAcceptable(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+	}{
+		{name: "other type", accept: "text/html"},
+		{name: "every format refused", accept: "application/json;q=0, application/*;q=0"},
+		{name: "wildcard refused", accept: "*/*;q=0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+			req.Header.Set("Accept", tt.accept)
+			recorder := httptest.NewRecorder()
+			Negotiate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Fatal("handler must not be called")
+			})).ServeHTTP(recorder, req)
+
+			require.Equal(t, http.StatusNotAcceptable, recorder.Code)
+			var response ErrorResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+			require.Equal(t, ErrorResponse{
+				Code:    http.StatusNotAcceptable,
+				Message: "none of the accepted media types is supported, use one of application/json, application/x-ndjson, application/msgpack, application/cbor",
+			}, response)
+		})
+	}
+}
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		accept              string
+		expectedContentType string
+	}{
+		{accept: "*/*", expectedContentType: "application/json"},
+		{accept: "application/*", expectedContentType: "application/json"},
+		{accept: "text/html, application/cbor", expectedContentType: "application/cbor"},
+		{accept: "application/json;q=0.5, application/msgpack", expectedContentType: "application/msgpack"},
+		{accept: "APPLICATION/CBOR; charset=utf-8", expectedContentType: "application/cbor"},
+		{accept: "application/json;q=0, */*", expectedContentType: "application/x-ndjson"},
+		{accept: "application/ndjson;q=0.9, application/vnd.msgpack;q=0.9, */*;q=0.1", expectedContentType: "application/x-ndjson"},
+		{accept: "application/json;q=invalid, application/cbor;q=0.1", expectedContentType: "application/cbor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			format, ok := negotiateFormat(tt.accept)
+			require.True(t, ok)
+			require.Equal(t, tt.expectedContentType, format.contentType)
+		})
+	}
+}
+
+func TestRespond_WithoutNegotiate(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+	req.Header.Set("Accept", "application/cbor")
+	recorder := httptest.NewRecorder()
+	respond(recorder, req, getExpectedBody())
+
+	require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+}
@@ -0,0 +1,747 @@
+package handler
+
+import (
+	"api/adapter/ratelimit"
+	"api/domain"
+	mock "api/mocks/mock_uc"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type openAPITestHandlers struct {
+	tasks    TasksHandler
+	stream   StreamHandler
+	ws       WebSocketHandler
+	calendar CalendarHandler
+	graphQL  GraphQLHandler
+	openAPI  OpenAPIHandler
+}
+
+type openAPITestMocks struct {
+	tasks    *mock.MockTasksUC
+	changes  *mock.MockTaskChangesUC
+	calendar *mock.MockCalendarUC
+}
+
+// requireMatchesSpec checks the recorded response against the operation the request
+// is routed to. Bodies other than JSON are only checked for a documented media type.
+func requireMatchesSpec(t *testing.T, validator *OpenAPIValidator, req *http.Request, recorder *httptest.ResponseRecorder) {
+	route, pathParams, err := validator.router.FindRoute(req)
+	require.NoError(t, err)
+
+	mediaType, _, _ := mime.ParseMediaType(recorder.Header().Get("Content-Type"))
+	isJSON := mediaType == "application/json"
+	if response := route.Operation.Responses.Status(recorder.Code); response != nil && len(response.Value.Content) > 0 && !isJSON {
+		require.NotNil(t, response.Value.Content.Get(mediaType), "undocumented media type %q", mediaType)
+	}
+
+	err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+		},
+		Status: recorder.Code,
+		Header: recorder.Header(),
+		Body:   io.NopCloser(bytes.NewReader(recorder.Body.Bytes())),
+		Options: &openapi3filter.Options{
+			IncludeResponseStatus: true,
+			ExcludeResponseBody:   !isJSON,
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestOpenAPI_ResponsesMatchSpec(t *testing.T) {
+	taskID := "1461ec84-ccff-4f3c-af34-65d0856ac3ce"
+	task := getExpectedBody()
+	deletedAt := time.Date(2025, 4, 4, 0, 0, 0, 0, time.UTC)
+	deletedTask := task
+	deletedTask.DeletedAt = &deletedAt
+	taskBody := `{"title":"Do unit tests","status":"PENDING","due_date":"2025-04-03T00:00:00Z"}`
+
+	tests := []struct {
+		name               string
+		method             string
+		pattern            string
+		target             string
+		contentType        string
+		header             http.Header
+		body               string
+		handler            func(h openAPITestHandlers) http.HandlerFunc
+		mocks              func(m openAPITestMocks)
+		expectedStatusCode int
+	}{
+		{
+			name:    "get task",
+			method:  http.MethodGet,
+			pattern: "/api/task/{id}",
+			target:  "/api/task/" + taskID,
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.tasks.GetTaskById },
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().GetTaskById(gomock.Any(), gomock.Any()).Return(task, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "get task - invalid id",
+			method:             http.MethodGet,
+			pattern:            "/api/task/{id}",
+			target:             "/api/task/invalid",
+			handler:            func(h openAPITestHandlers) http.HandlerFunc { return h.tasks.GetTaskById },
+			expectedStatusCode: 400,
+		},
+		{
+			name:    "get task - not found",
+			method:  http.MethodGet,
+			pattern: "/api/task/{id}",
+			target:  "/api/task/" + taskID,
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.tasks.GetTaskById },
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().GetTaskById(gomock.Any(), gomock.Any()).Return(domain.Task{}, domain.ErrTaskNotFound)
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name:    "get tasks",
+			method:  http.MethodGet,
+			pattern: "/api/tasks",
+			target:  "/api/tasks?status=PENDING",
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.tasks.GetTasks },
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().GetTasks(gomock.Any(), gomock.Any()).Return([]domain.Task{task}, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:    "create task",
+			method:  http.MethodPost,
+			pattern: "/api/task",
+			target:  "/api/task",
+			body:    taskBody,
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.tasks.CreateTask },
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Return(task, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:    "create task - invalid",
+			method:  http.MethodPost,
+			pattern: "/api/task",
+			target:  "/api/task",
+			body:    taskBody,
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.tasks.CreateTask },
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Return(domain.Task{}, domain.ErrInvalidTask)
+			},
+			expectedStatusCode: 400,
+		},
+		{
+			name:    "create task - too large",
+			method:  http.MethodPost,
+			pattern: "/api/task",
+			target:  "/api/task",
+			body:    taskBody,
+			handler: func(h openAPITestHandlers) http.HandlerFunc {
+				return LimitBody(16)(http.HandlerFunc(h.tasks.CreateTask)).ServeHTTP
+			},
+			expectedStatusCode: 413,
+		},
+		{
+			name:    "update task",
+			method:  http.MethodPut,
+			pattern: "/api/task/{id}",
+			target:  "/api/task/" + taskID,
+			body:    taskBody,
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.tasks.UpdateTask },
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Return(task, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:    "update task - not found",
+			method:  http.MethodPut,
+			pattern: "/api/task/{id}",
+			target:  "/api/task/" + taskID,
+			body:    taskBody,
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.tasks.UpdateTask },
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Return(domain.Task{}, domain.ErrTaskNotFound)
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name:    "delete task",
+			method:  http.MethodDelete,
+			pattern: "/api/task/{id}",
+			target:  "/api/task/" + taskID,
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.tasks.DeleteTask },
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().DeleteTask(gomock.Any(), gomock.Any()).Return(deletedTask, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:    "get occurrences",
+			method:  http.MethodGet,
+			pattern: "/api/task/{id}/occurrences",
+			target:  "/api/task/" + taskID + "/occurrences?limit=2",
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.tasks.GetOccurrences },
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().GetOccurrences(gomock.Any(), gomock.Any(), 2).Return([]time.Time{task.DueDate, task.DueDate.AddDate(0, 0, 7)}, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:    "get occurrences - not recurring",
+			method:  http.MethodGet,
+			pattern: "/api/task/{id}/occurrences",
+			target:  "/api/task/" + taskID + "/occurrences",
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.tasks.GetOccurrences },
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().GetOccurrences(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, domain.ErrInvalidRecurrence)
+			},
+			expectedStatusCode: 422,
+		},
+		{
+			name:    "restore task",
+			method:  http.MethodPost,
+			pattern: "/api/task/{id}/restore",
+			target:  "/api/task/" + taskID + "/restore",
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.tasks.RestoreTask },
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().RestoreTask(gomock.Any(), gomock.Any()).Return(task, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:    "search tasks",
+			method:  http.MethodGet,
+			pattern: "/api/tasks/search",
+			target:  "/api/tasks/search?q=tests",
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.tasks.SearchTasks },
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().SearchTasks(gomock.Any(), "tests", gomock.Any()).Return([]domain.TaskSearchResult{
+					{Task: task, Rank: 0.5, Snippet: "Create extensive unit <mark>tests</mark>", Match: "fulltext"},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "search tasks - missing query",
+			method:             http.MethodGet,
+			pattern:            "/api/tasks/search",
+			target:             "/api/tasks/search?q=%20",
+			handler:            func(h openAPITestHandlers) http.HandlerFunc { return h.tasks.SearchTasks },
+			expectedStatusCode: 400,
+		},
+		{
+			name:    "bulk tasks",
+			method:  http.MethodPost,
+			pattern: "/api/tasks/bulk",
+			target:  "/api/tasks/bulk",
+			body:    `[{"op":"delete","id":"` + taskID + `"}]`,
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.tasks.BulkTasks },
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().BulkTasks(gomock.Any(), gomock.Any(), false).Return([]domain.BulkResult{{Task: &deletedTask}}, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:    "bulk tasks - partial failure",
+			method:  http.MethodPost,
+			pattern: "/api/tasks/bulk",
+			target:  "/api/tasks/bulk?atomic=false",
+			body:    `[{"op":"delete","id":"` + taskID + `"},{"op":"rename"}]`,
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.tasks.BulkTasks },
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().BulkTasks(gomock.Any(), gomock.Any(), false).Return([]domain.BulkResult{
+					{Task: &deletedTask},
+					{Err: domain.ErrInvalidBulkOperation},
+				}, nil)
+			},
+			expectedStatusCode: 207,
+		},
+		{
+			name:        "import tasks",
+			method:      http.MethodPost,
+			pattern:     "/api/tasks/import",
+			target:      "/api/tasks/import?dry_run=true",
+			contentType: "text/csv",
+			body:        "title,status\nDo unit tests,PENDING\n",
+			handler:     func(h openAPITestHandlers) http.HandlerFunc { return h.tasks.ImportTasks },
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().ImportTasks(gomock.Any(), gomock.Any(), true).Return(domain.ImportReport{
+					DryRun: true, Total: 1, Imported: 1, Errors: []domain.ImportError{},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "import tasks - unsupported format",
+			method:             http.MethodPost,
+			pattern:            "/api/tasks/import",
+			target:             "/api/tasks/import",
+			contentType:        "application/xml",
+			body:               "<tasks/>",
+			handler:            func(h openAPITestHandlers) http.HandlerFunc { return h.tasks.ImportTasks },
+			expectedStatusCode: 415,
+		},
+		{
+			name:    "export tasks - csv",
+			method:  http.MethodGet,
+			pattern: "/api/tasks/export",
+			target:  "/api/tasks/export?format=csv",
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.tasks.ExportTasks },
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().ExportTasks(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(exportTasks(getExportTasks()))
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:    "export tasks - xlsx",
+			method:  http.MethodGet,
+			pattern: "/api/tasks/export",
+			target:  "/api/tasks/export?format=xlsx",
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.tasks.ExportTasks },
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().ExportTasks(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(exportTasks(getExportTasks()))
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "export tasks - unknown format",
+			method:             http.MethodGet,
+			pattern:            "/api/tasks/export",
+			target:             "/api/tasks/export?format=pdf",
+			handler:            func(h openAPITestHandlers) http.HandlerFunc { return h.tasks.ExportTasks },
+			expectedStatusCode: 400,
+		},
+		{
+			name:    "stream tasks",
+			method:  http.MethodGet,
+			pattern: "/api/tasks/stream",
+			target:  "/api/tasks/stream",
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.stream.StreamTasks },
+			mocks: func(m openAPITestMocks) {
+				changes := make(chan domain.TaskChange, 1)
+				changes <- domain.TaskChange{ID: "1", Type: domain.EventTaskCreated, Task: task}
+				close(changes)
+				m.changes.EXPECT().Subscribe(gomock.Any(), gomock.Any(), gomock.Any()).Return(changes, true)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "websocket - not a handshake",
+			method:             http.MethodGet,
+			pattern:            "/api/ws",
+			target:             "/api/ws",
+			handler:            func(h openAPITestHandlers) http.HandlerFunc { return h.ws.Serve },
+			expectedStatusCode: 400,
+		},
+		{
+			name:    "get trash",
+			method:  http.MethodGet,
+			pattern: "/api/trash",
+			target:  "/api/trash",
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.tasks.GetTrash },
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().GetTrash(gomock.Any()).Return([]domain.Task{deletedTask}, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:    "purge task",
+			method:  http.MethodDelete,
+			pattern: "/api/trash/{id}",
+			target:  "/api/trash/" + taskID,
+			header:  http.Header{"Authorization": {"Bearer secret"}},
+			handler: func(h openAPITestHandlers) http.HandlerFunc {
+				return RequireAdmin("secret")(http.HandlerFunc(h.tasks.PurgeTask)).ServeHTTP
+			},
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().PurgeTask(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedStatusCode: 204,
+		},
+		{
+			name:    "purge task - no token",
+			method:  http.MethodDelete,
+			pattern: "/api/trash/{id}",
+			target:  "/api/trash/" + taskID,
+			handler: func(h openAPITestHandlers) http.HandlerFunc {
+				return RequireAdmin("secret")(http.HandlerFunc(h.tasks.PurgeTask)).ServeHTTP
+			},
+			expectedStatusCode: 401,
+		},
+		{
+			name:    "get calendar",
+			method:  http.MethodGet,
+			pattern: "/api/calendar.ics",
+			target:  "/api/calendar.ics?token=secret",
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.calendar.GetCalendar },
+			mocks: func(m openAPITestMocks) {
+				m.calendar.EXPECT().GetFeed(gomock.Any(), "secret").Return(getCalendarFeed(), nil)
+				m.tasks.EXPECT().ExportTasks(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(exportTasks(getCalendarTasks()))
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:    "get calendar - unknown token",
+			method:  http.MethodGet,
+			pattern: "/api/calendar.ics",
+			target:  "/api/calendar.ics?token=unknown",
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.calendar.GetCalendar },
+			mocks: func(m openAPITestMocks) {
+				m.calendar.EXPECT().GetFeed(gomock.Any(), "unknown").Return(domain.CalendarFeed{}, domain.ErrCalendarFeedNotFound)
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name:    "create calendar feed",
+			method:  http.MethodPost,
+			pattern: "/api/calendar/feeds",
+			target:  "/api/calendar/feeds",
+			body:    `{"name":"My tasks","status":"PENDING"}`,
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.calendar.CreateFeed },
+			mocks: func(m openAPITestMocks) {
+				m.calendar.EXPECT().CreateFeed(gomock.Any(), gomock.Any()).Return(getCalendarFeed(), "secret", nil)
+			},
+			expectedStatusCode: 201,
+		},
+		{
+			name:    "rotate calendar feed",
+			method:  http.MethodPost,
+			pattern: "/api/calendar/feeds/{token}/rotate",
+			target:  "/api/calendar/feeds/secret/rotate",
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.calendar.RotateFeed },
+			mocks: func(m openAPITestMocks) {
+				feed := getCalendarFeed()
+				feed.RotatedAt = &deletedAt
+				m.calendar.EXPECT().RotateFeed(gomock.Any(), "secret").Return(feed, "new-secret", nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:    "delete calendar feed",
+			method:  http.MethodDelete,
+			pattern: "/api/calendar/feeds/{token}",
+			target:  "/api/calendar/feeds/secret",
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.calendar.DeleteFeed },
+			mocks: func(m openAPITestMocks) {
+				m.calendar.EXPECT().DeleteFeed(gomock.Any(), "secret").Return(nil)
+			},
+			expectedStatusCode: 204,
+		},
+		{
+			name:    "delete calendar feed - not found",
+			method:  http.MethodDelete,
+			pattern: "/api/calendar/feeds/{token}",
+			target:  "/api/calendar/feeds/unknown",
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.calendar.DeleteFeed },
+			mocks: func(m openAPITestMocks) {
+				m.calendar.EXPECT().DeleteFeed(gomock.Any(), "unknown").Return(domain.ErrCalendarFeedNotFound)
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name:    "graphql",
+			method:  http.MethodPost,
+			pattern: "/api/graphql",
+			target:  "/api/graphql",
+			body:    `{"query":"{ tasks { edges { node { id title } } totalCount } missing: task(id: \"1461ec84-ccff-4f3c-af34-65d0856ac3cf\") { id } }","operationName":null}`,
+			handler: func(h openAPITestHandlers) http.HandlerFunc { return h.graphQL.Serve },
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().GetTasksPage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]domain.Task{task}, nil)
+				m.tasks.EXPECT().CountTasks(gomock.Any(), gomock.Any()).Return(1, nil)
+				m.tasks.EXPECT().GetTasksByIds(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused"))
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "graphql - invalid query",
+			method:             http.MethodPost,
+			pattern:            "/api/graphql",
+			target:             "/api/graphql",
+			body:               `{"query":"{ tasks { unknown } }"}`,
+			handler:            func(h openAPITestHandlers) http.HandlerFunc { return h.graphQL.Serve },
+			expectedStatusCode: 422,
+		},
+		{
+			name:               "graphql playground",
+			method:             http.MethodGet,
+			pattern:            "/api/graphql",
+			target:             "/api/graphql",
+			handler:            func(h openAPITestHandlers) http.HandlerFunc { return h.graphQL.Playground },
+			expectedStatusCode: 200,
+		},
+		{
+			name:    "get tasks - rate limited",
+			method:  http.MethodGet,
+			pattern: "/api/tasks",
+			target:  "/api/tasks",
+			handler: func(h openAPITestHandlers) http.HandlerFunc {
+				limited := NewRateLimiter(ratelimit.NewMemoryStore(), []domain.RateLimitPolicy{
+					{Pattern: "/api/*", Limit: 1, Window: time.Minute, Identity: domain.IdentityIP},
+				}, nil).Middleware(http.HandlerFunc(h.tasks.GetTasks))
+				return func(w http.ResponseWriter, r *http.Request) {
+					limited.ServeHTTP(httptest.NewRecorder(), r)
+					limited.ServeHTTP(w, r)
+				}
+			},
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().GetTasks(gomock.Any(), gomock.Any()).Return([]domain.Task{task}, nil)
+			},
+			expectedStatusCode: 429,
+		},
+		{
+			name:    "get tasks - msgpack",
+			method:  http.MethodGet,
+			pattern: "/api/tasks",
+			target:  "/api/tasks",
+			header:  http.Header{"Accept": {"application/msgpack"}},
+			handler: func(h openAPITestHandlers) http.HandlerFunc {
+				return Negotiate(http.HandlerFunc(h.tasks.GetTasks)).ServeHTTP
+			},
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().GetTasks(gomock.Any(), gomock.Any()).Return([]domain.Task{task}, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:    "get task - cbor",
+			method:  http.MethodGet,
+			pattern: "/api/task/{id}",
+			target:  "/api/task/" + taskID,
+			header:  http.Header{"Accept": {"application/cbor"}},
+			handler: func(h openAPITestHandlers) http.HandlerFunc {
+				return Negotiate(http.HandlerFunc(h.tasks.GetTaskById)).ServeHTTP
+			},
+			mocks: func(m openAPITestMocks) {
+				m.tasks.EXPECT().GetTaskById(gomock.Any(), gomock.Any()).Return(task, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:    "get task - not acceptable",
+			method:  http.MethodGet,
+			pattern: "/api/task/{id}",
+			target:  "/api/task/" + taskID,
+			header:  http.Header{"Accept": {"text/html"}},
+			handler: func(h openAPITestHandlers) http.HandlerFunc {
+				return Negotiate(http.HandlerFunc(h.tasks.GetTaskById)).ServeHTTP
+			},
+			expectedStatusCode: 406,
+		},
+		{
+			name:               "openapi spec",
+			method:             http.MethodGet,
+			pattern:            "/api/openapi.json",
+			target:             "/api/openapi.json",
+			handler:            func(h openAPITestHandlers) http.HandlerFunc { return h.openAPI.Spec },
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "docs",
+			method:             http.MethodGet,
+			pattern:            "/api/docs",
+			target:             "/api/docs",
+			handler:            func(h openAPITestHandlers) http.HandlerFunc { return h.openAPI.Docs },
+			expectedStatusCode: 200,
+		},
+	}
+
+	validator, err := NewOpenAPIValidator()
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mocks := openAPITestMocks{
+				tasks:    mock.NewMockTasksUC(ctrl),
+				changes:  mock.NewMockTaskChangesUC(ctrl),
+				calendar: mock.NewMockCalendarUC(ctrl),
+			}
+			if tt.mocks != nil {
+				tt.mocks(mocks)
+			}
+			handlers := openAPITestHandlers{
+				tasks:    *NewTasksHandler(mocks.tasks),
+				stream:   *NewStreamHandler(mocks.changes, time.Minute),
+				ws:       *NewWebSocketHandler(mocks.tasks, mocks.changes),
+				calendar: *NewCalendarHandler(mocks.calendar, mocks.tasks),
+				graphQL:  *NewGraphQLHandler(mocks.tasks, 10, 1000, true),
+				openAPI:  *NewOpenAPIHandler(),
+			}
+
+			r := chi.NewRouter()
+			r.Method(tt.method, tt.pattern, tt.handler(handlers))
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			for name, values := range tt.header {
+				req.Header[name] = values
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			} else if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+
+			require.Equal(t, tt.expectedStatusCode, recorder.Code, recorder.Body.String())
+			requireMatchesSpec(t, validator, req, recorder)
+		})
+	}
+}
+
+func TestOpenAPIValidator_Middleware(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		target             string
+		contentType        string
+		body               string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "valid request",
+			method:             http.MethodGet,
+			target:             "/api/tasks/search?q=tests&limit=5",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "missing required query param",
+			method:             http.MethodGet,
+			target:             "/api/tasks/search",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "query param of the wrong type",
+			method:             http.MethodGet,
+			target:             "/api/tasks/search?q=tests&limit=many",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "query param outside of enum",
+			method:             http.MethodGet,
+			target:             "/api/tasks/export?format=pdf",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "valid body is passed on",
+			method:             http.MethodPost,
+			target:             "/api/task",
+			contentType:        "application/json",
+			body:               `{"title":"Do unit tests"}`,
+			expectedStatusCode: 200,
+			expectedBody:       `{"title":"Do unit tests"}`,
+		},
+		{
+			name:               "body without content type is taken as json",
+			method:             http.MethodPost,
+			target:             "/api/task",
+			body:               `{"title":"Do unit tests"}`,
+			expectedStatusCode: 200,
+			expectedBody:       `{"title":"Do unit tests"}`,
+		},
+		{
+			name:               "body of the wrong type",
+			method:             http.MethodPost,
+			target:             "/api/task",
+			contentType:        "application/json",
+			body:               `{"title":5}`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "malformed body",
+			method:             http.MethodPost,
+			target:             "/api/task",
+			contentType:        "application/json",
+			body:               `{"title":`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "too many bulk operations",
+			method:             http.MethodPost,
+			target:             "/api/tasks/bulk",
+			contentType:        "application/json",
+			body:               "[" + strings.Repeat(`{"op":"delete"},`, maxBulkOperations) + `{"op":"delete"}]`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "streamed bodies are left to the handler",
+			method:             http.MethodPost,
+			target:             "/api/tasks/import",
+			contentType:        "text/csv",
+			body:               "title\nDo unit tests\n",
+			expectedStatusCode: 200,
+			expectedBody:       "title\nDo unit tests\n",
+		},
+		{
+			name:               "route missing from the spec",
+			method:             http.MethodGet,
+			target:             "/api/unknown",
+			expectedStatusCode: 200,
+		},
+	}
+
+	validator, err := NewOpenAPIValidator()
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received []byte
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received, _ = io.ReadAll(r.Body)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			recorder := httptest.NewRecorder()
+			validator.Middleware(next).ServeHTTP(recorder, req)
+
+			require.Equal(t, tt.expectedStatusCode, recorder.Code, recorder.Body.String())
+			if tt.expectedStatusCode != http.StatusOK {
+				var body ErrorResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+				require.Equal(t, tt.expectedStatusCode, body.Code)
+				return
+			}
+			require.Equal(t, tt.expectedBody, string(received))
+		})
+	}
+}
+
+func TestOpenAPIHandler_Spec(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	NewOpenAPIHandler().Spec(recorder, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+	var spec map[string]any
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &spec))
+	require.Equal(t, "3.1.0", spec["openapi"])
+}
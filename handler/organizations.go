@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"api/domain"
+	"api/uc"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/render"
+	"net/http"
+)
+
+type OrganizationsHandler struct {
+	organizationsService uc.OrganizationsUC
+}
+
+func NewOrganizationsHandler(organizationsService uc.OrganizationsUC) *OrganizationsHandler {
+	return &OrganizationsHandler{organizationsService: organizationsService}
+}
+
+type OrganizationRequest struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+func (oh OrganizationsHandler) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := getContextFromRequest(r)
+	defer cancel()
+
+	var body OrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		code := bodyErrorStatus(err)
+		render.Status(r, code)
+		render.JSON(w, r, ErrorResponse{
+			Code:    code,
+			Message: "invalid organization: " + err.Error(),
+		})
+		return
+	}
+
+	org, err := oh.organizationsService.CreateOrganization(ctx, domain.Organization{Slug: body.Slug, Name: body.Name})
+	if err != nil {
+		code := serverErrorStatus(err)
+		switch {
+		case errors.Is(err, domain.ErrInvalidOrganization):
+			code = http.StatusBadRequest
+		case errors.Is(err, domain.ErrOrganizationExists):
+			code = http.StatusConflict
+		}
+		render.Status(r, code)
+		render.JSON(w, r, ErrorResponse{
+			Code:    code,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, org)
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"api/domain"
+	mock "api/mocks/mock_uc"
+	"encoding/json"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func getOrganization() domain.Organization {
+	return domain.Organization{
+		ID:        uuid.MustParse("7d4b1c9e-2f1a-4c3b-8e5d-6a7f8b9c0d1e"),
+		Slug:      "acme",
+		Name:      "Acme",
+		CreatedAt: time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestCreateOrganization(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		ucMock             func(ucMock *mock.MockOrganizationsUC)
+		expectedStatusCode int
+		expectedBody       any
+	}{
+		{
+			name: "happy path - OK",
+			body: `{"slug":"acme","name":"Acme"}`,
+			ucMock: func(ucMock *mock.MockOrganizationsUC) {
+				ucMock.EXPECT().CreateOrganization(gomock.Any(), domain.Organization{Slug: "acme", Name: "Acme"}).Return(getOrganization(), nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedBody:       getOrganization(),
+		},
+		{
+			name:               "invalid body",
+			body:               `{"slug":`,
+			ucMock:             func(ucMock *mock.MockOrganizationsUC) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       ErrorResponse{Code: http.StatusBadRequest, Message: "invalid organization: unexpected EOF"},
+		},
+		{
+			name: "invalid organization",
+			body: `{"slug":"Acme Inc","name":"Acme"}`,
+			ucMock: func(ucMock *mock.MockOrganizationsUC) {
+				ucMock.EXPECT().CreateOrganization(gomock.Any(), gomock.Any()).Return(domain.Organization{}, domain.ErrInvalidOrganization)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       ErrorResponse{Code: http.StatusBadRequest, Message: "invalid organization"},
+		},
+		{
+			name: "already exists",
+			body: `{"slug":"acme","name":"Acme"}`,
+			ucMock: func(ucMock *mock.MockOrganizationsUC) {
+				ucMock.EXPECT().CreateOrganization(gomock.Any(), gomock.Any()).Return(domain.Organization{}, domain.ErrOrganizationExists)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedBody:       ErrorResponse{Code: http.StatusConflict, Message: "organization already exists"},
+		},
+		{
+			name: "error",
+			body: `{"slug":"acme","name":"Acme"}`,
+			ucMock: func(ucMock *mock.MockOrganizationsUC) {
+				ucMock.EXPECT().CreateOrganization(gomock.Any(), gomock.Any()).Return(domain.Organization{}, errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       ErrorResponse{Code: http.StatusInternalServerError, Message: "db error"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ucMock := mock.NewMockOrganizationsUC(gomock.NewController(t))
+			tt.ucMock(ucMock)
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/organizations", strings.NewReader(tt.body))
+			NewOrganizationsHandler(ucMock).CreateOrganization(recorder, req)
+
+			require.Equal(t, tt.expectedStatusCode, recorder.Code)
+			expected, err := json.Marshal(tt.expectedBody)
+			require.NoError(t, err)
+			require.JSONEq(t, string(expected), recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"api/domain"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/go-chi/render"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimiter limits the requests of every client with the first policy matching the
+// request. Requests which no policy matches are not limited. Requests counted by an API
+// key or a bearer token are counted by their IP under the guard as well, when there is
+// one.
+type RateLimiter struct {
+	store    domain.RateLimitStore
+	policies []domain.RateLimitPolicy
+	guard    *domain.RateLimitPolicy
+	now      func() time.Time
+}
+
+func NewRateLimiter(store domain.RateLimitStore, policies []domain.RateLimitPolicy, guard *domain.RateLimitPolicy) *RateLimiter {
+	return &RateLimiter{store: store, policies: policies, guard: guard, now: time.Now}
+}
+
+func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		policy, ok := rl.match(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		policy, result, err := rl.take(r, policy)
+		if err != nil {
+			log.Printf("error while rate limiting request: %v", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		w.Header().Set("RateLimit-Reset", ceilSeconds(result.Reset))
+		w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%s", policy.Limit, ceilSeconds(policy.Window)))
+		if !result.Allowed {
+			w.Header().Set("Retry-After", ceilSeconds(result.RetryAfter))
+			render.Status(r, http.StatusTooManyRequests)
+			render.JSON(w, r, ErrorResponse{
+				Code:    http.StatusTooManyRequests,
+				Message: fmt.Sprintf("rate limit exceeded, retry in %ss", ceilSeconds(result.RetryAfter)),
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (rl *RateLimiter) match(r *http.Request) (domain.RateLimitPolicy, bool) {
+	for _, policy := range rl.policies {
+		if policy.Matches(r.Method, r.URL.Path) {
+			return policy, true
+		}
+	}
+	return domain.RateLimitPolicy{}, false
+}
+
+// take takes a request from the bucket of the client under policy and returns the
+// policy and result of the bucket restricting the client most. Nothing verifies API keys
+// and bearer tokens before the limiter runs, so a client could make up a new one for
+// every request: the requests it is allowed to make with one are taken from the bucket
+// of its IP under the guard as well.
+func (rl *RateLimiter) take(r *http.Request, policy domain.RateLimitPolicy) (domain.RateLimitPolicy, domain.RateLimitResult, error) {
+	key, identified := clientKey(r, policy.Identity)
+	result, err := rl.store.Take(r.Context(), policy.String()+"|"+key, policy, rl.now())
+	if err != nil {
+		return domain.RateLimitPolicy{}, domain.RateLimitResult{}, err
+	}
+	if !identified || rl.guard == nil || !result.Allowed {
+		return policy, result, nil
+	}
+
+	guarded, err := rl.store.Take(r.Context(), rl.guard.String()+"|"+clientIP(r), *rl.guard, rl.now())
+	if err != nil {
+		return domain.RateLimitPolicy{}, domain.RateLimitResult{}, err
+	}
+	if restricts(guarded, result) {
+		return *rl.guard, guarded, nil
+	}
+	return policy, result, nil
+}
+
+// restricts tells whether a leaves the client with fewer requests than b.
+func restricts(a, b domain.RateLimitResult) bool {
+	if a.Allowed != b.Allowed {
+		return !a.Allowed
+	}
+	if !a.Allowed {
+		return a.RetryAfter > b.RetryAfter
+	}
+	return a.Remaining < b.Remaining
+}
+
+// clientKey returns the key of the bucket the requests of a client are counted in and
+// whether the client is identified by an API key or a bearer token rather than its IP.
+// API keys and bearer tokens are hashed, so that they are never written to the store.
+func clientKey(r *http.Request, identity domain.RateLimitIdentity) (string, bool) {
+	switch identity {
+	case domain.IdentityAPIKey:
+		if key := r.Header.Get("X-API-Key"); key != "" {
+			return "key:" + hashIdentity(key), true
+		}
+	case domain.IdentityUser:
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && token != "" {
+			return "user:" + hashIdentity(token), true
+		}
+	}
+	return clientIP(r), false
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+func hashIdentity(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
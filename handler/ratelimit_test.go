@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"api/adapter/ratelimit"
+	"api/domain"
+	mock "api/mocks/mock_domain"
+	"encoding/json"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestRateLimiter(t *testing.T, spec, guardSpec string, now *time.Time) http.Handler {
+	policies, err := domain.ParseRateLimitPolicies(spec)
+	require.NoError(t, err)
+	guard, err := domain.ParseRateLimitGuard(guardSpec)
+	require.NoError(t, err)
+
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(), policies, guard)
+	limiter.now = func() time.Time { return *now }
+	return limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func sendRateLimited(handler http.Handler, method, path, remoteAddr string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = remoteAddr
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestRateLimiter_Middleware(t *testing.T) {
+	now := time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)
+	handler := newTestRateLimiter(t, "GET /api/tasks 2/1m", "none", &now)
+
+	recorder := sendRateLimited(handler, http.MethodGet, "/api/tasks", "10.0.0.1:1234", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "2", recorder.Header().Get("RateLimit-Limit"))
+	require.Equal(t, "1", recorder.Header().Get("RateLimit-Remaining"))
+	require.Equal(t, "30", recorder.Header().Get("RateLimit-Reset"))
+	require.Equal(t, "2;w=60", recorder.Header().Get("RateLimit-Policy"))
+
+	recorder = sendRateLimited(handler, http.MethodGet, "/api/tasks", "10.0.0.1:1234", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "0", recorder.Header().Get("RateLimit-Remaining"))
+
+	now = now.Add(10 * time.Second)
+	recorder = sendRateLimited(handler, http.MethodGet, "/api/tasks", "10.0.0.1:4321", nil)
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	require.Equal(t, "20", recorder.Header().Get("Retry-After"))
+	require.Equal(t, "0", recorder.Header().Get("RateLimit-Remaining"))
+	require.Equal(t, "50", recorder.Header().Get("RateLimit-Reset"))
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Equal(t, ErrorResponse{Code: http.StatusTooManyRequests, Message: "rate limit exceeded, retry in 20s"}, response)
+
+	// Other clients and routes are not affected.
+	recorder = sendRateLimited(handler, http.MethodGet, "/api/tasks", "10.0.0.2:1234", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	recorder = sendRateLimited(handler, http.MethodPost, "/api/tasks/bulk", "10.0.0.1:1234", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Empty(t, recorder.Header().Get("RateLimit-Limit"))
+
+	now = now.Add(20 * time.Second)
+	recorder = sendRateLimited(handler, http.MethodGet, "/api/tasks", "10.0.0.1:1234", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestRateLimiter_FirstMatchingPolicy(t *testing.T) {
+	now := time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)
+	handler := newTestRateLimiter(t, "GET /api/tasks 1/1m, /api/* 100/1m", "none", &now)
+
+	require.Equal(t, http.StatusOK, sendRateLimited(handler, http.MethodGet, "/api/tasks", "10.0.0.1:1234", nil).Code)
+	require.Equal(t, http.StatusTooManyRequests, sendRateLimited(handler, http.MethodGet, "/api/tasks", "10.0.0.1:1234", nil).Code)
+
+	recorder := sendRateLimited(handler, http.MethodGet, "/api/task/1461ec84-ccff-4f3c-af34-65d0856ac3ce", "10.0.0.1:1234", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "100", recorder.Header().Get("RateLimit-Limit"))
+}
+
+func TestRateLimiter_Identity(t *testing.T) {
+	tests := []struct {
+		name       string
+		spec       string
+		first      map[string]string
+		second     map[string]string
+		secondAddr string
+		separate   bool
+	}{
+		{
+			name:       "api keys are counted separately",
+			spec:       "/api/* 1/1m api_key",
+			first:      map[string]string{"X-API-Key": "first"},
+			second:     map[string]string{"X-API-Key": "second"},
+			secondAddr: "10.0.0.2:1234",
+			separate:   true,
+		},
+		{
+			name:       "same api key from another ip",
+			spec:       "/api/* 1/1m api_key",
+			first:      map[string]string{"X-API-Key": "first"},
+			second:     map[string]string{"X-API-Key": "first"},
+			secondAddr: "10.0.0.2:1234",
+		},
+		{
+			name:     "api keys behind one ip are counted separately",
+			spec:     "/api/* 1/1m api_key",
+			first:    map[string]string{"X-API-Key": "first"},
+			second:   map[string]string{"X-API-Key": "second"},
+			separate: true,
+		},
+		{
+			name:   "requests without an api key are counted by ip",
+			spec:   "/api/* 1/1m api_key",
+			first:  map[string]string{},
+			second: map[string]string{},
+		},
+		{
+			name:       "users are counted separately",
+			spec:       "/api/* 1/1m user",
+			first:      map[string]string{"Authorization": "Bearer first"},
+			second:     map[string]string{"Authorization": "Bearer second"},
+			secondAddr: "10.0.0.2:1234",
+			separate:   true,
+		},
+		{
+			name:     "users behind one ip are counted separately",
+			spec:     "/api/* 1/1m user",
+			first:    map[string]string{"Authorization": "Bearer first"},
+			second:   map[string]string{"Authorization": "Bearer second"},
+			separate: true,
+		},
+		{
+			name:   "ip ignores the api key",
+			spec:   "/api/* 1/1m ip",
+			first:  map[string]string{"X-API-Key": "first"},
+			second: map[string]string{"X-API-Key": "second"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)
+			handler := newTestRateLimiter(t, tt.spec, "100/1m", &now)
+			secondAddr := tt.secondAddr
+			if secondAddr == "" {
+				secondAddr = "10.0.0.1:1234"
+			}
+
+			require.Equal(t, http.StatusOK, sendRateLimited(handler, http.MethodGet, "/api/tasks", "10.0.0.1:1234", tt.first).Code)
+			recorder := sendRateLimited(handler, http.MethodGet, "/api/tasks", secondAddr, tt.second)
+			if tt.separate {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			} else {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+			}
+		})
+	}
+}
+
+func TestRateLimiter_IPGuard(t *testing.T) {
+	now := time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)
+	handler := newTestRateLimiter(t, "/api/* 10/1m api_key", "3/1m", &now)
+
+	// The headers report the bucket with the fewest requests left.
+	recorder := sendRateLimited(handler, http.MethodGet, "/api/tasks", "10.0.0.1:1234", map[string]string{"X-API-Key": "first"})
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "2", recorder.Header().Get("RateLimit-Remaining"))
+	require.Equal(t, "3;w=60", recorder.Header().Get("RateLimit-Policy"))
+
+	// Requests without an api key are counted by ip under the policy, not the guard.
+	recorder = sendRateLimited(handler, http.MethodGet, "/api/tasks", "10.0.0.1:1234", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "9", recorder.Header().Get("RateLimit-Remaining"))
+	require.Equal(t, "10;w=60", recorder.Header().Get("RateLimit-Policy"))
+
+	// Making up a new api key for every request does not get around the guard.
+	require.Equal(t, http.StatusOK, sendRateLimited(handler, http.MethodGet, "/api/tasks", "10.0.0.1:1234", map[string]string{"X-API-Key": "second"}).Code)
+	require.Equal(t, http.StatusOK, sendRateLimited(handler, http.MethodGet, "/api/tasks", "10.0.0.1:1234", map[string]string{"X-API-Key": "third"}).Code)
+	recorder = sendRateLimited(handler, http.MethodGet, "/api/tasks", "10.0.0.1:1234", map[string]string{"X-API-Key": "fourth"})
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	require.Equal(t, "20", recorder.Header().Get("Retry-After"))
+
+	// The same api key from another ip is limited by its own bucket only.
+	recorder = sendRateLimited(handler, http.MethodGet, "/api/tasks", "10.0.0.2:1234", map[string]string{"X-API-Key": "first"})
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "2", recorder.Header().Get("RateLimit-Remaining"))
+}
+
+func TestRateLimiter_NoIPGuard(t *testing.T) {
+	now := time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)
+	handler := newTestRateLimiter(t, "/api/* 1/1m api_key", "none", &now)
+
+	for _, key := range []string{"first", "second", "third"} {
+		recorder := sendRateLimited(handler, http.MethodGet, "/api/tasks", "10.0.0.1:1234", map[string]string{"X-API-Key": key})
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Equal(t, "0", recorder.Header().Get("RateLimit-Remaining"))
+	}
+}
+
+func TestRateLimiter_StoreError(t *testing.T) {
+	policies, err := domain.ParseRateLimitPolicies("/api/* 1/1m")
+	require.NoError(t, err)
+
+	store := mock.NewMockRateLimitStore(gomock.NewController(t))
+	store.EXPECT().Take(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(domain.RateLimitResult{}, errors.New("connection refused"))
+	handler := NewRateLimiter(store, policies, nil).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	recorder := sendRateLimited(handler, http.MethodGet, "/api/tasks", "10.0.0.1:1234", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Empty(t, recorder.Header().Get("RateLimit-Limit"))
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/go-chi/cors"
+	"github.com/go-chi/render"
+	"mime"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// CORSConfig lists what browser frontends on other origins may do with the API. An
+// origin may contain a single * standing for any subdomain, e.g. https://*.example.com.
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// exposedHeaders are the response headers, besides the CORS-safelisted ones, which
+// frontends need to read.
+var exposedHeaders = []string{
+	"Content-Disposition",
+	"RateLimit-Limit",
+	"RateLimit-Remaining",
+	"RateLimit-Reset",
+	"RateLimit-Policy",
+	"Retry-After",
+}
+
+// CORS answers preflight requests and adds the CORS headers to the responses to the
+// allowed origins. Credentials are never shared with every origin.
+func CORS(conf CORSConfig) (func(http.Handler) http.Handler, error) {
+	if len(conf.AllowedOrigins) == 0 {
+		return nil, errors.New("at least one allowed origin is required")
+	}
+	if conf.AllowCredentials && slices.Contains(conf.AllowedOrigins, "*") {
+		return nil, errors.New("credentials cannot be allowed for every origin")
+	}
+
+	return cors.Handler(cors.Options{
+		AllowedOrigins:   conf.AllowedOrigins,
+		AllowedMethods:   conf.AllowedMethods,
+		AllowedHeaders:   conf.AllowedHeaders,
+		ExposedHeaders:   exposedHeaders,
+		AllowCredentials: conf.AllowCredentials,
+		MaxAge:           int(conf.MaxAge.Seconds()),
+	}), nil
+}
+
+// SecurityHeaders adds the standard security headers to every response. HSTS is left
+// out when hstsMaxAge is zero, and the Content-Security-Policy is only sent with HTML
+// pages, as it means nothing to the clients of a JSON API.
+func SecurityHeaders(hstsMaxAge time.Duration, contentSecurityPolicy string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			headers := w.Header()
+			headers.Set("X-Content-Type-Options", "nosniff")
+			headers.Set("X-Frame-Options", "DENY")
+			headers.Set("Referrer-Policy", "no-referrer")
+			if hstsMaxAge > 0 {
+				headers.Set("Strict-Transport-Security", fmt.Sprintf("max-age=%d", int(hstsMaxAge.Seconds())))
+			}
+
+			next.ServeHTTP(&htmlPolicyWriter{ResponseWriter: w, policy: contentSecurityPolicy}, r)
+		})
+	}
+}
+
+// htmlPolicyWriter adds the Content-Security-Policy just before the headers are sent,
+// once the Content-Type of the response is known. It passes on flushes and hijacks,
+// which the event stream and the WebSocket rely on.
+type htmlPolicyWriter struct {
+	http.ResponseWriter
+	policy      string
+	wroteHeader bool
+}
+
+func (hw *htmlPolicyWriter) WriteHeader(statusCode int) {
+	if !hw.wroteHeader {
+		hw.wroteHeader = true
+		mediaType, _, _ := mime.ParseMediaType(hw.Header().Get("Content-Type"))
+		if mediaType == "text/html" && hw.policy != "" {
+			hw.Header().Set("Content-Security-Policy", hw.policy)
+		}
+	}
+	hw.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (hw *htmlPolicyWriter) Write(data []byte) (int, error) {
+	if !hw.wroteHeader {
+		if hw.Header().Get("Content-Type") == "" {
+			hw.Header().Set("Content-Type", http.DetectContentType(data))
+		}
+		hw.WriteHeader(http.StatusOK)
+	}
+	return hw.ResponseWriter.Write(data)
+}
+
+func (hw *htmlPolicyWriter) Flush() {
+	if flusher, ok := hw.ResponseWriter.(http.Flusher); ok {
+		if !hw.wroteHeader {
+			hw.WriteHeader(http.StatusOK)
+		}
+		flusher.Flush()
+	}
+}
+
+func (hw *htmlPolicyWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := hw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response does not implement http.Hijacker")
+	}
+	return hijacker.Hijack()
+}
+
+func (hw *htmlPolicyWriter) Unwrap() http.ResponseWriter {
+	return hw.ResponseWriter
+}
+
+// LimitBody rejects request bodies larger than maxBytes with HTTP 413. Bodies which do
+// not announce their length are cut off once they exceed it, and the handlers reading
+// them answer with HTTP 413 as well. Zero disables the limit.
+func LimitBody(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if maxBytes <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				render.Status(r, http.StatusRequestEntityTooLarge)
+				render.JSON(w, r, ErrorResponse{
+					Code:    http.StatusRequestEntityTooLarge,
+					Message: fmt.Sprintf("request body is larger than %d bytes", maxBytes),
+				})
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// bodyErrorStatus is HTTP 413 for bodies cut off by LimitBody and HTTP 400 for any
+// other body which cannot be read.
+func bodyErrorStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
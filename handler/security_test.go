@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"api/domain"
+	mock "api/mocks/mock_uc"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCORS(t *testing.T) {
+	corsMiddleware, err := CORS(CORSConfig{
+		AllowedOrigins:   []string{"https://tasks.example.com", "https://*.example.org"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	})
+	require.NoError(t, err)
+	handler := corsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name            string
+		method          string
+		origin          string
+		requestMethod   string
+		requestHeaders  string
+		expectedOrigin  string
+		expectedHeaders map[string]string
+	}{
+		{
+			name:           "allowed origin",
+			method:         http.MethodGet,
+			origin:         "https://tasks.example.com",
+			expectedOrigin: "https://tasks.example.com",
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    "Content-Disposition, Ratelimit-Limit, Ratelimit-Remaining, Ratelimit-Reset, Ratelimit-Policy, Retry-After",
+			},
+		},
+		{
+			name:           "wildcard subdomain",
+			method:         http.MethodGet,
+			origin:         "https://app.staging.example.org",
+			expectedOrigin: "https://app.staging.example.org",
+		},
+		{
+			name:   "not a subdomain",
+			method: http.MethodGet,
+			origin: "https://example.org.evil.com",
+		},
+		{
+			name:   "other origin",
+			method: http.MethodGet,
+			origin: "https://evil.com",
+		},
+		{
+			name:           "preflight",
+			method:         http.MethodOptions,
+			origin:         "https://tasks.example.com",
+			requestMethod:  http.MethodPut,
+			requestHeaders: "content-type, authorization",
+			expectedOrigin: "https://tasks.example.com",
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Methods":     "PUT",
+				"Access-Control-Allow-Headers":     "Content-Type, Authorization",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Max-Age":           "600",
+			},
+		},
+		{
+			name:          "preflight - method not allowed",
+			method:        http.MethodOptions,
+			origin:        "https://tasks.example.com",
+			requestMethod: http.MethodPatch,
+		},
+		{
+			name:           "preflight - header not allowed",
+			method:         http.MethodOptions,
+			origin:         "https://tasks.example.com",
+			requestMethod:  http.MethodPost,
+			requestHeaders: "X-Debug",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/tasks", nil)
+			req.Header.Set("Origin", tt.origin)
+			if tt.requestMethod != "" {
+				req.Header.Set("Access-Control-Request-Method", tt.requestMethod)
+			}
+			if tt.requestHeaders != "" {
+				req.Header.Set("Access-Control-Request-Headers", tt.requestHeaders)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			require.Equal(t, tt.expectedOrigin, recorder.Header().Get("Access-Control-Allow-Origin"))
+			for name, value := range tt.expectedHeaders {
+				require.Equal(t, value, recorder.Header().Get(name), name)
+			}
+		})
+	}
+}
+
+func TestCORS_InvalidConfig(t *testing.T) {
+	_, err := CORS(CORSConfig{})
+	require.EqualError(t, err, "at least one allowed origin is required")
+
+	_, err = CORS(CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+	require.EqualError(t, err, "credentials cannot be allowed for every origin")
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	policy := "default-src 'self'"
+	tests := []struct {
+		name        string
+		hstsMaxAge  time.Duration
+		handler     http.HandlerFunc
+		expectedCSP string
+		expectedSTS string
+	}{
+		{
+			name:       "json",
+			hstsMaxAge: time.Hour,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{}`))
+			},
+			expectedSTS: "max-age=3600",
+		},
+		{
+			name: "html",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte(`<!doctype html>`))
+			},
+			expectedCSP: policy,
+		},
+		{
+			name: "sniffed html",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`<!DOCTYPE html><html><body></body></html>`))
+			},
+			expectedCSP: policy,
+		},
+		{
+			name: "docs page",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				NewOpenAPIHandler().Docs(w, r)
+			},
+			expectedCSP: policy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			SecurityHeaders(tt.hstsMaxAge, policy)(tt.handler).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			require.Equal(t, http.StatusOK, recorder.Code)
+			require.Equal(t, "nosniff", recorder.Header().Get("X-Content-Type-Options"))
+			require.Equal(t, "DENY", recorder.Header().Get("X-Frame-Options"))
+			require.Equal(t, "no-referrer", recorder.Header().Get("Referrer-Policy"))
+			require.Equal(t, tt.expectedSTS, recorder.Header().Get("Strict-Transport-Security"))
+			require.Equal(t, tt.expectedCSP, recorder.Header().Get("Content-Security-Policy"))
+		})
+	}
+}
+
+func TestSecurityHeaders_PassesOnFlushAndHijack(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	SecurityHeaders(0, "")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher, ok := w.(http.Flusher)
+		require.True(t, ok)
+		flusher.Flush()
+
+		// The recorder cannot be hijacked, which has to be reported rather than hidden.
+		_, _, err := w.(http.Hijacker).Hijack()
+		require.Error(t, err)
+	})).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/tasks/stream", nil))
+
+	require.True(t, recorder.Flushed)
+}
+
+func TestLimitBody(t *testing.T) {
+	tests := []struct {
+		name               string
+		maxBytes           int64
+		body               string
+		chunked            bool
+		expectedStatusCode int
+	}{
+		{name: "within the limit", maxBytes: 10, body: "0123456789", expectedStatusCode: 200},
+		{name: "content length over the limit", maxBytes: 10, body: "0123456789a", expectedStatusCode: 413},
+		{name: "chunked body over the limit", maxBytes: 10, body: "0123456789a", chunked: true, expectedStatusCode: 413},
+		{name: "no limit", body: strings.Repeat("a", 1<<20), chunked: true, expectedStatusCode: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := LimitBody(tt.maxBytes)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if _, err := io.ReadAll(r.Body); err != nil {
+					w.WriteHeader(bodyErrorStatus(err))
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/task", strings.NewReader(tt.body))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			require.Equal(t, tt.expectedStatusCode, recorder.Code)
+		})
+	}
+}
+
+func TestLimitBody_Handlers(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		contentType string
+		handler     func(h *TasksHandler) http.HandlerFunc
+	}{
+		{name: "create task", target: "/api/task", handler: func(h *TasksHandler) http.HandlerFunc { return h.CreateTask }},
+		{name: "bulk tasks", target: "/api/tasks/bulk", handler: func(h *TasksHandler) http.HandlerFunc { return h.BulkTasks }},
+		{name: "import tasks", target: "/api/tasks/import", contentType: "application/x-ndjson", handler: func(h *TasksHandler) http.HandlerFunc { return h.ImportTasks }},
+	}
+
+	// readAll stands in for the use case, which stops at the first error of the source.
+	readAll := func(ctx context.Context, source domain.TaskSource, dryRun bool) (domain.ImportReport, error) {
+		for {
+			_, _, err := source.Next()
+			if errors.Is(err, io.EOF) {
+				return domain.ImportReport{}, nil
+			}
+			if err != nil && !errors.Is(err, domain.ErrInvalidImportRow) {
+				return domain.ImportReport{}, err
+			}
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ucMock := mock.NewMockTasksUC(gomock.NewController(t))
+			ucMock.EXPECT().ImportTasks(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(readAll).AnyTimes()
+
+			// A body without a Content-Length is only cut off while it is read.
+			body := `[{"op":"create","task":{"title":"` + strings.Repeat("a", 2048) + `"}}]`
+			if tt.contentType != "" {
+				body = strings.Repeat(`{"title":"Do unit tests"}`+"\n", 100)
+			}
+			req := httptest.NewRequest(http.MethodPost, tt.target, bytes.NewBufferString(body))
+			req.ContentLength = -1
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			recorder := httptest.NewRecorder()
+			LimitBody(1024)(tt.handler(NewTasksHandler(ucMock))).ServeHTTP(recorder, req)
+
+			require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code, recorder.Body.String())
+			var response ErrorResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+			require.Equal(t, http.StatusRequestEntityTooLarge, response.Code)
+		})
+	}
+}
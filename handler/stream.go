@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"api/domain"
+	"api/uc"
+	"encoding/json"
+	"fmt"
+	"github.com/go-chi/render"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var streamEventNames = map[domain.EventType]string{
+	domain.EventTaskCreated: "created",
+	domain.EventTaskUpdated: "updated",
+	domain.EventTaskDeleted: "deleted",
+}
+
+type StreamHandler struct {
+	changesService uc.TaskChangesUC
+	heartbeat      time.Duration
+}
+
+func NewStreamHandler(changesService uc.TaskChangesUC, heartbeat time.Duration) *StreamHandler {
+	return &StreamHandler{changesService: changesService, heartbeat: heartbeat}
+}
+
+// StreamTasks pushes task changes as Server-Sent Events until the client disconnects.
+// If the Last-Event-ID sent on reconnect is no longer buffered, a "reset" event tells
+// the client to reload the full list.
+func (sh StreamHandler) StreamTasks(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "streaming is not supported",
+		})
+		return
+	}
+
+	ctx := r.Context()
+	changes, resumed := sh.changesService.Subscribe(ctx, r.Header.Get("Last-Event-ID"), taskFilterFromQuery(r))
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprint(w, "retry: 3000\n\n")
+	if !resumed {
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sh.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+		case change, ok := <-changes:
+			if !ok {
+				return
+			}
+			if err := writeStreamEvent(w, change); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeStreamEvent(w http.ResponseWriter, change domain.TaskChange) error {
+	data, err := json.Marshal(change.Task)
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("id: " + change.ID + "\n")
+	sb.WriteString("event: " + streamEventNames[change.Type] + "\n")
+	sb.WriteString("data: " + string(data) + "\n\n")
+
+	_, err = fmt.Fprint(w, sb.String())
+	return err
+}
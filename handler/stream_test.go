@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"api/domain"
+	mock "api/mocks/mock_uc"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStreamTasks(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		lastEventId   string
+		ucMock        func(ucMock mock.MockTaskChangesUC)
+		expectedParts []string
+	}{
+		{
+			name: "happy path - OK",
+			ucMock: func(ucMock mock.MockTaskChangesUC) {
+				changes := make(chan domain.TaskChange, 1)
+				changes <- domain.TaskChange{ID: "5", Type: domain.EventTaskCreated, Task: getExpectedBody()}
+				close(changes)
+				ucMock.EXPECT().Subscribe(gomock.Any(), gomock.Eq(""), gomock.Eq(domain.TaskFilter{})).Return(changes, true)
+			},
+			expectedParts: []string{
+				"retry: 3000\n\n",
+				"id: 5\nevent: created\ndata: {\"id\":\"1461ec84-ccff-4f3c-af34-65d0856ac3ce\"",
+			},
+		},
+		{
+			name:        "resume with filter",
+			query:       "?status=DONE",
+			lastEventId: "4",
+			ucMock: func(ucMock mock.MockTaskChangesUC) {
+				changes := make(chan domain.TaskChange, 1)
+				changes <- domain.TaskChange{ID: "5", Type: domain.EventTaskDeleted, Task: getExpectedBody()}
+				close(changes)
+				ucMock.EXPECT().Subscribe(gomock.Any(), gomock.Eq("4"), gomock.Eq(domain.TaskFilter{Status: "DONE"})).Return(changes, true)
+			},
+			expectedParts: []string{"id: 5\nevent: deleted\n"},
+		},
+		{
+			name:        "resume not possible",
+			lastEventId: "1",
+			ucMock: func(ucMock mock.MockTaskChangesUC) {
+				changes := make(chan domain.TaskChange)
+				close(changes)
+				ucMock.EXPECT().Subscribe(gomock.Any(), gomock.Eq("1"), gomock.Any()).Return(changes, false)
+			},
+			expectedParts: []string{"event: reset\ndata: {}\n\n"},
+		},
+		{
+			name: "heartbeat",
+			ucMock: func(ucMock mock.MockTaskChangesUC) {
+				changes := make(chan domain.TaskChange)
+				time.AfterFunc(100*time.Millisecond, func() { close(changes) })
+				ucMock.EXPECT().Subscribe(gomock.Any(), gomock.Any(), gomock.Any()).Return(changes, true)
+			},
+			expectedParts: []string{": heartbeat\n\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			r := chi.NewRouter()
+			recorder := httptest.NewRecorder()
+			ucMock := mock.NewMockTaskChangesUC(ctrl)
+			handler := NewStreamHandler(ucMock, 10*time.Millisecond)
+
+			if tt.ucMock != nil {
+				tt.ucMock(*ucMock)
+			}
+
+			r.Get("/api/tasks/stream", handler.StreamTasks)
+			req, err := http.NewRequest(http.MethodGet, "/api/tasks/stream"+tt.query, nil)
+			require.NoError(t, err)
+			if tt.lastEventId != "" {
+				req.Header.Set("Last-Event-ID", tt.lastEventId)
+			}
+
+			r.ServeHTTP(recorder, req)
+			require.Equal(t, http.StatusOK, recorder.Code)
+			require.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+
+			for _, part := range tt.expectedParts {
+				require.Contains(t, recorder.Body.String(), part)
+			}
+		})
+	}
+}
@@ -12,6 +12,8 @@ import (
 	"io"
 	"net/http"
 	"reflect"
+	"strconv"
+	"strings"
 )
 
 type TasksHandler struct {
@@ -48,41 +50,173 @@ func (th TasksHandler) GetTaskById(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		render.Status(r, http.StatusInternalServerError)
+		renderServerError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	respond(w, r, task)
+}
+
+// GetTasks returns every task matching the filter, or a page of them, oldest first,
+// once the client asks for one with 'limit' or 'cursor'. A page which is followed by
+// another links to it in a Link header with rel="next".
+func (th TasksHandler) GetTasks(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := getContextFromRequest(r)
+	defer cancel()
+
+	query := r.URL.Query()
+	if !query.Has("limit") && !query.Has("cursor") {
+		tasksList, err := th.tasksService.GetTasks(ctx, taskFilterFromQuery(r))
+		if err != nil {
+			renderServerError(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		respond(w, r, tasksList)
+		return
+	}
+
+	limit, err := intFromQuery(r, "limit", defaultTasksLimit, maxTasksLimit)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, ErrorResponse{
-			Code:    http.StatusInternalServerError,
+			Code:    http.StatusBadRequest,
 			Message: err.Error(),
 		})
 		return
 	}
+	var after *domain.TaskCursor
+	if query.Has("cursor") {
+		cursor, err := domain.ParseTaskCursor(query.Get("cursor"))
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+			return
+		}
+		after = &cursor
+	}
+
+	// One task more than the page holds tells whether there is a next page.
+	tasksList, err := th.tasksService.GetTasksPage(ctx, taskFilterFromQuery(r), after, limit+1)
+	if err != nil {
+		renderServerError(w, r, err)
+		return
+	}
+	if len(tasksList) > limit {
+		tasksList = tasksList[:limit]
+		query.Set("limit", strconv.Itoa(limit))
+		query.Set("cursor", domain.CursorOf(tasksList[limit-1]).String())
+		w.Header().Set("Link", "<"+r.URL.Path+"?"+query.Encode()+`>; rel="next"`)
+	}
 
 	render.Status(r, http.StatusOK)
-	render.JSON(w, r, task)
+	respond(w, r, tasksList)
 }
 
-func (th TasksHandler) GetTasks(w http.ResponseWriter, r *http.Request) {
+func (th TasksHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := getContextFromRequest(r)
 	defer cancel()
 
-	tasksList, err := th.tasksService.GetTasks(ctx)
+	data, err := taskFromBody(r.Body)
 	if err != nil {
-		render.Status(r, http.StatusInternalServerError)
+		code := bodyErrorStatus(err)
+		render.Status(r, code)
 		render.JSON(w, r, ErrorResponse{
-			Code:    http.StatusInternalServerError,
+			Code:    code,
 			Message: err.Error(),
 		})
 		return
 	}
 
+	task, err := th.tasksService.CreateTask(ctx, *data)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidTask) || errors.Is(err, domain.ErrInvalidRecurrence) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+			return
+		}
+		// The id of the body belongs to another task, which may be in the trash.
+		if errors.Is(err, domain.ErrTaskExists) {
+			render.Status(r, http.StatusConflict)
+			render.JSON(w, r, ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+			return
+		}
+
+		renderServerError(w, r, fmt.Errorf("error creating new task: %w", err))
+		return
+	}
+
 	render.Status(r, http.StatusOK)
-	render.JSON(w, r, tasksList)
+	respond(w, r, task)
 }
 
-func (th TasksHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
+func (th TasksHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := getContextFromRequest(r)
 	defer cancel()
 
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
 	data, err := taskFromBody(r.Body)
+	if err != nil {
+		code := bodyErrorStatus(err)
+		render.Status(r, code)
+		render.JSON(w, r, ErrorResponse{
+			Code:    code,
+			Message: err.Error(),
+		})
+		return
+	}
+	data.ID = id
+
+	task, err := th.tasksService.UpdateTask(ctx, *data)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrTaskNotFound):
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "task not found",
+			})
+		case errors.Is(err, domain.ErrInvalidRecurrence):
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+		default:
+			renderServerError(w, r, fmt.Errorf("error updating task: %w", err))
+		}
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	respond(w, r, task)
+}
+
+func (th TasksHandler) GetOccurrences(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := getContextFromRequest(r)
+	defer cancel()
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, ErrorResponse{
@@ -92,18 +226,186 @@ func (th TasksHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	task, err := th.tasksService.CreateTask(ctx, *data)
+	limit, err := intFromQuery(r, "limit", defaultOccurrencesLimit, maxOccurrencesLimit)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	occurrences, err := th.tasksService.GetOccurrences(ctx, id, limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrTaskNotFound):
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "task not found",
+			})
+		case errors.Is(err, domain.ErrInvalidRecurrence):
+			render.Status(r, http.StatusUnprocessableEntity)
+			render.JSON(w, r, ErrorResponse{
+				Code:    http.StatusUnprocessableEntity,
+				Message: err.Error(),
+			})
+		default:
+			renderServerError(w, r, err)
+		}
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	respond(w, r, occurrences)
+}
+
+func (th TasksHandler) SearchTasks(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := getContextFromRequest(r)
+	defer cancel()
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "query param q is required",
+		})
+		return
+	}
+
+	limit, err := intFromQuery(r, "limit", defaultSearchLimit, maxSearchLimit)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	results, err := th.tasksService.SearchTasks(ctx, query, limit)
+	if err != nil {
+		renderServerError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	respond(w, r, results)
+}
+
+// DeleteTask moves the task to the trash.
+func (th TasksHandler) DeleteTask(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := getContextFromRequest(r)
+	defer cancel()
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	task, err := th.tasksService.DeleteTask(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrTaskNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "task not found",
+			})
+			return
+		}
+
+		renderServerError(w, r, fmt.Errorf("error deleting task: %w", err))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	respond(w, r, task)
+}
+
+func (th TasksHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := getContextFromRequest(r)
+	defer cancel()
+
+	tasksList, err := th.tasksService.GetTrash(ctx)
+	if err != nil {
+		renderServerError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	respond(w, r, tasksList)
+}
+
+func (th TasksHandler) RestoreTask(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := getContextFromRequest(r)
+	defer cancel()
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
-		render.Status(r, http.StatusInternalServerError)
+		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: fmt.Sprintf("error creating new task: %v", err),
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
 		})
 		return
 	}
 
+	task, err := th.tasksService.RestoreTask(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrTaskNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "task not found in trash",
+			})
+			return
+		}
+
+		renderServerError(w, r, fmt.Errorf("error restoring task: %w", err))
+		return
+	}
+
 	render.Status(r, http.StatusOK)
-	render.JSON(w, r, task)
+	respond(w, r, task)
+}
+
+// PurgeTask permanently deletes a task from the trash.
+func (th TasksHandler) PurgeTask(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := getContextFromRequest(r)
+	defer cancel()
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := th.tasksService.PurgeTask(ctx, id); err != nil {
+		if errors.Is(err, domain.ErrTaskNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "task not found in trash",
+			})
+			return
+		}
+
+		renderServerError(w, r, fmt.Errorf("error purging task: %w", err))
+		return
+	}
+
+	render.NoContent(w, r)
 }
 
 func taskFromBody(in io.ReadCloser) (*domain.Task, error) {
@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
@@ -68,6 +69,15 @@ func TestGetTaskById(t *testing.T) {
 			expectedStatusCode: 500,
 			expectedBody:       domain.Task{},
 		},
+		{
+			name: "database unreachable",
+			id:   "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTaskById(gomock.Any(), gomock.Eq(uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce"))).Return(domain.Task{}, fmt.Errorf("error fetching task: %w", domain.ErrStorageUnavailable))
+			},
+			expectedStatusCode: 503,
+			expectedBody:       domain.Task{},
+		},
 	}
 
 	for _, tt := range tests {
@@ -100,24 +110,66 @@ func TestGetTaskById(t *testing.T) {
 }
 
 func TestGetTasks(t *testing.T) {
+	cursor := domain.CursorOf(getExpectedBody())
+
 	tests := []struct {
 		name               string
+		query              string
 		ucMock             func(ucMock mock.MockTasksUC)
 		expectedStatusCode int
 		expectedCount      int
+		expectedLink       string
 	}{
 		{
 			name: "happy path - OK",
 			ucMock: func(ucMock mock.MockTasksUC) {
-				ucMock.EXPECT().GetTasks(gomock.Any()).Return([]domain.Task{getExpectedBody()}, nil)
+				ucMock.EXPECT().GetTasks(gomock.Any(), gomock.Any()).Return([]domain.Task{getExpectedBody()}, nil)
 			},
 			expectedStatusCode: 200,
 			expectedCount:      1,
 		},
+		{
+			name:  "filter by status",
+			query: "?status=PENDING",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTasks(gomock.Any(), gomock.Eq(domain.TaskFilter{Status: "PENDING"})).Return([]domain.Task{getExpectedBody()}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedCount:      1,
+		},
+		{
+			name:  "page followed by another",
+			query: "?status=PENDING&limit=1",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTasksPage(gomock.Any(), domain.TaskFilter{Status: "PENDING"}, nil, 2).Return([]domain.Task{getExpectedBody(), getExpectedBody()}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedCount:      1,
+			expectedLink:       `</api/tasks?cursor=` + cursor.String() + `&limit=1&status=PENDING>; rel="next"`,
+		},
+		{
+			name:  "last page",
+			query: "?cursor=" + cursor.String(),
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTasksPage(gomock.Any(), domain.TaskFilter{}, &cursor, defaultTasksLimit+1).Return([]domain.Task{getExpectedBody()}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedCount:      1,
+		},
+		{
+			name:               "invalid cursor",
+			query:              "?cursor=abc",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "invalid limit",
+			query:              "?limit=0",
+			expectedStatusCode: 400,
+		},
 		{
 			name: "no tasks found",
 			ucMock: func(ucMock mock.MockTasksUC) {
-				ucMock.EXPECT().GetTasks(gomock.Any()).Return([]domain.Task{}, nil)
+				ucMock.EXPECT().GetTasks(gomock.Any(), gomock.Any()).Return([]domain.Task{}, nil)
 			},
 			expectedStatusCode: 200,
 			expectedCount:      0,
@@ -125,7 +177,7 @@ func TestGetTasks(t *testing.T) {
 		{
 			name: "internal server error",
 			ucMock: func(ucMock mock.MockTasksUC) {
-				ucMock.EXPECT().GetTasks(gomock.Any()).Return(nil, errors.New("error occurred"))
+				ucMock.EXPECT().GetTasks(gomock.Any(), gomock.Any()).Return(nil, errors.New("error occurred"))
 			},
 			expectedStatusCode: 500,
 			expectedCount:      0,
@@ -147,11 +199,12 @@ func TestGetTasks(t *testing.T) {
 			}
 
 			r.Get("/api/tasks", handler.GetTasks)
-			req, err := http.NewRequest(http.MethodGet, "/api/tasks", nil)
+			req, err := http.NewRequest(http.MethodGet, "/api/tasks"+tt.query, nil)
 			require.NoError(t, err)
 
 			r.ServeHTTP(recorder, req)
 			require.Equal(t, tt.expectedStatusCode, recorder.Code)
+			require.Equal(t, tt.expectedLink, recorder.Header().Get("Link"))
 
 			if tt.expectedStatusCode == http.StatusOK {
 				var body []domain.Task
@@ -190,6 +243,14 @@ func TestCreateTask(t *testing.T) {
 			body:               "invalid body",
 			expectedStatusCode: 400,
 		},
+		{
+			name: "id already taken",
+			body: `{"id": "1461ec84-ccff-4f3c-af34-65d0856ac3ce", "title": "Do unit tests", "description": "Create extensive unit tests for all layers", "status": "PENDING", "due_date": "2025-05-12T00:00:00Z"}`,
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Return(domain.Task{}, domain.ErrTaskExists)
+			},
+			expectedStatusCode: 409,
+		},
 		{
 			name: "internal server error",
 			body: `{"id": "1461ec84-ccff-4f3c-af34-65d0856ac3ce", "title": "Do unit tests", "description": "Create extensive unit tests for all layers", "status": "PENDING", "due_date": "2025-05-12T00:00:00Z"}`,
@@ -237,3 +298,527 @@ func TestCreateTask(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateTask(t *testing.T) {
+	tests := []struct {
+		name               string
+		id                 string
+		body               string
+		ucMock             func(ucMock mock.MockTasksUC)
+		expectedStatusCode int
+	}{
+		{
+			name: "happy path - OK",
+			id:   "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			body: `{"title": "Do unit tests", "description": "Create extensive unit tests for all layers", "status": "DONE", "due_date": "2025-05-12T00:00:00Z"}`,
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, data domain.Task) (domain.Task, error) {
+					require.Equal(t, getExpectedBody().ID, data.ID)
+					return data, nil
+				})
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "wrong id type",
+			id:                 "invalid id",
+			body:               `{"status": "DONE"}`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "bad request",
+			id:                 "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			body:               "invalid body",
+			expectedStatusCode: 400,
+		},
+		{
+			name: "invalid recurrence",
+			id:   "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			body: `{"status": "DONE", "rrule": "FREQ=SECONDLY"}`,
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Return(domain.Task{}, domain.ErrInvalidRecurrence)
+			},
+			expectedStatusCode: 400,
+		},
+		{
+			name: "no task found",
+			id:   "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			body: `{"status": "DONE"}`,
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Return(domain.Task{}, domain.ErrTaskNotFound)
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name: "internal server error",
+			id:   "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			body: `{"status": "DONE"}`,
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Return(domain.Task{}, errors.New("connection refused"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			r := chi.NewRouter()
+			recorder := httptest.NewRecorder()
+			ucMock := mock.NewMockTasksUC(ctrl)
+			handler := NewTasksHandler(ucMock)
+
+			if tt.ucMock != nil {
+				tt.ucMock(*ucMock)
+			}
+
+			r.Put("/api/task/{id}", handler.UpdateTask)
+			req, err := http.NewRequest(http.MethodPut, "/api/task/"+tt.id, bytes.NewReader([]byte(tt.body)))
+			require.NoError(t, err)
+
+			r.ServeHTTP(recorder, req)
+			require.Equal(t, tt.expectedStatusCode, recorder.Code)
+
+			if tt.expectedStatusCode != http.StatusOK {
+				var errResp ErrorResponse
+				err = json.Unmarshal(recorder.Body.Bytes(), &errResp)
+				require.NoError(t, err)
+				require.Equal(t, tt.expectedStatusCode, errResp.Code)
+			}
+		})
+	}
+}
+
+func TestGetOccurrences(t *testing.T) {
+	tests := []struct {
+		name               string
+		id                 string
+		query              string
+		ucMock             func(ucMock mock.MockTasksUC)
+		expectedStatusCode int
+		expectedCount      int
+	}{
+		{
+			name: "happy path - OK",
+			id:   "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetOccurrences(gomock.Any(), gomock.Any(), gomock.Eq(defaultOccurrencesLimit)).Return([]time.Time{
+					time.Date(2025, 5, 12, 0, 0, 0, 0, time.UTC),
+					time.Date(2025, 5, 19, 0, 0, 0, 0, time.UTC),
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedCount:      2,
+		},
+		{
+			name:  "limit is capped",
+			id:    "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			query: "?limit=1000",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetOccurrences(gomock.Any(), gomock.Any(), gomock.Eq(maxOccurrencesLimit)).Return([]time.Time{}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedCount:      0,
+		},
+		{
+			name:               "invalid limit",
+			id:                 "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			query:              "?limit=abc",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "wrong id type",
+			id:                 "invalid id",
+			expectedStatusCode: 400,
+		},
+		{
+			name: "no task found",
+			id:   "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetOccurrences(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, domain.ErrTaskNotFound)
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name: "stored rule is invalid",
+			id:   "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetOccurrences(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, domain.ErrInvalidRecurrence)
+			},
+			expectedStatusCode: 422,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			r := chi.NewRouter()
+			recorder := httptest.NewRecorder()
+			ucMock := mock.NewMockTasksUC(ctrl)
+			handler := NewTasksHandler(ucMock)
+
+			if tt.ucMock != nil {
+				tt.ucMock(*ucMock)
+			}
+
+			r.Get("/api/task/{id}/occurrences", handler.GetOccurrences)
+			req, err := http.NewRequest(http.MethodGet, "/api/task/"+tt.id+"/occurrences"+tt.query, nil)
+			require.NoError(t, err)
+
+			r.ServeHTTP(recorder, req)
+			require.Equal(t, tt.expectedStatusCode, recorder.Code)
+
+			if tt.expectedStatusCode == http.StatusOK {
+				var body []time.Time
+				err = json.Unmarshal(recorder.Body.Bytes(), &body)
+				require.NoError(t, err)
+				require.Equal(t, tt.expectedCount, len(body))
+			}
+		})
+	}
+}
+
+func TestSearchTasks(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		ucMock             func(ucMock mock.MockTasksUC)
+		expectedStatusCode int
+		expectedCount      int
+	}{
+		{
+			name:  "happy path - OK",
+			query: "?q=weekly+report",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().SearchTasks(gomock.Any(), "weekly report", defaultSearchLimit).Return([]domain.TaskSearchResult{
+					{Task: domain.Task{Title: "weekly report"}, Rank: 0.5, Snippet: "<mark>weekly</mark>", Match: domain.MatchFullText},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedCount:      1,
+		},
+		{
+			name:  "limit is capped",
+			query: "?q=report&limit=1000",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().SearchTasks(gomock.Any(), "report", maxSearchLimit).Return([]domain.TaskSearchResult{}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedCount:      0,
+		},
+		{
+			name:               "missing query",
+			query:              "?q=+",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "invalid limit",
+			query:              "?q=report&limit=0",
+			expectedStatusCode: 400,
+		},
+		{
+			name:  "search error",
+			query: "?q=report",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().SearchTasks(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			r := chi.NewRouter()
+			recorder := httptest.NewRecorder()
+			ucMock := mock.NewMockTasksUC(ctrl)
+			handler := NewTasksHandler(ucMock)
+
+			if tt.ucMock != nil {
+				tt.ucMock(*ucMock)
+			}
+
+			r.Get("/api/tasks/search", handler.SearchTasks)
+			req, err := http.NewRequest(http.MethodGet, "/api/tasks/search"+tt.query, nil)
+			require.NoError(t, err)
+
+			r.ServeHTTP(recorder, req)
+			require.Equal(t, tt.expectedStatusCode, recorder.Code)
+
+			if tt.expectedStatusCode == http.StatusOK {
+				var body []domain.TaskSearchResult
+				err = json.Unmarshal(recorder.Body.Bytes(), &body)
+				require.NoError(t, err)
+				require.Equal(t, tt.expectedCount, len(body))
+			}
+		})
+	}
+}
+
+func TestDeleteTask(t *testing.T) {
+	tests := []struct {
+		name               string
+		id                 string
+		ucMock             func(ucMock mock.MockTasksUC)
+		expectedStatusCode int
+	}{
+		{
+			name: "happy path - OK",
+			id:   "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().DeleteTask(gomock.Any(), uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce")).Return(domain.Task{}, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "wrong id type",
+			id:                 "invalid id",
+			expectedStatusCode: 400,
+		},
+		{
+			name: "no task found",
+			id:   "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().DeleteTask(gomock.Any(), gomock.Any()).Return(domain.Task{}, domain.ErrTaskNotFound)
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name: "delete error",
+			id:   "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().DeleteTask(gomock.Any(), gomock.Any()).Return(domain.Task{}, errors.New("db error"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			r := chi.NewRouter()
+			recorder := httptest.NewRecorder()
+			ucMock := mock.NewMockTasksUC(ctrl)
+			handler := NewTasksHandler(ucMock)
+
+			if tt.ucMock != nil {
+				tt.ucMock(*ucMock)
+			}
+
+			r.Delete("/api/task/{id}", handler.DeleteTask)
+			req, err := http.NewRequest(http.MethodDelete, "/api/task/"+tt.id, nil)
+			require.NoError(t, err)
+
+			r.ServeHTTP(recorder, req)
+			require.Equal(t, tt.expectedStatusCode, recorder.Code)
+		})
+	}
+}
+
+func TestGetTrash(t *testing.T) {
+	tests := []struct {
+		name               string
+		ucMock             func(ucMock mock.MockTasksUC)
+		expectedStatusCode int
+		expectedCount      int
+	}{
+		{
+			name: "happy path - OK",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				deletedAt := time.Now()
+				ucMock.EXPECT().GetTrash(gomock.Any()).Return([]domain.Task{{Title: "deleted", DeletedAt: &deletedAt}}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedCount:      1,
+		},
+		{
+			name: "trash error",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().GetTrash(gomock.Any()).Return(nil, errors.New("db error"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			r := chi.NewRouter()
+			recorder := httptest.NewRecorder()
+			ucMock := mock.NewMockTasksUC(ctrl)
+			handler := NewTasksHandler(ucMock)
+
+			if tt.ucMock != nil {
+				tt.ucMock(*ucMock)
+			}
+
+			r.Get("/api/trash", handler.GetTrash)
+			req, err := http.NewRequest(http.MethodGet, "/api/trash", nil)
+			require.NoError(t, err)
+
+			r.ServeHTTP(recorder, req)
+			require.Equal(t, tt.expectedStatusCode, recorder.Code)
+
+			if tt.expectedStatusCode == http.StatusOK {
+				var body []domain.Task
+				err = json.Unmarshal(recorder.Body.Bytes(), &body)
+				require.NoError(t, err)
+				require.Equal(t, tt.expectedCount, len(body))
+				require.NotNil(t, body[0].DeletedAt)
+			}
+		})
+	}
+}
+
+func TestRestoreTask(t *testing.T) {
+	tests := []struct {
+		name               string
+		id                 string
+		ucMock             func(ucMock mock.MockTasksUC)
+		expectedStatusCode int
+	}{
+		{
+			name: "happy path - OK",
+			id:   "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().RestoreTask(gomock.Any(), uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce")).Return(domain.Task{}, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "wrong id type",
+			id:                 "invalid id",
+			expectedStatusCode: 400,
+		},
+		{
+			name: "not in trash",
+			id:   "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().RestoreTask(gomock.Any(), gomock.Any()).Return(domain.Task{}, domain.ErrTaskNotFound)
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name: "restore error",
+			id:   "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().RestoreTask(gomock.Any(), gomock.Any()).Return(domain.Task{}, errors.New("db error"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			r := chi.NewRouter()
+			recorder := httptest.NewRecorder()
+			ucMock := mock.NewMockTasksUC(ctrl)
+			handler := NewTasksHandler(ucMock)
+
+			if tt.ucMock != nil {
+				tt.ucMock(*ucMock)
+			}
+
+			r.Post("/api/task/{id}/restore", handler.RestoreTask)
+			req, err := http.NewRequest(http.MethodPost, "/api/task/"+tt.id+"/restore", nil)
+			require.NoError(t, err)
+
+			r.ServeHTTP(recorder, req)
+			require.Equal(t, tt.expectedStatusCode, recorder.Code)
+		})
+	}
+}
+
+func TestPurgeTask(t *testing.T) {
+	tests := []struct {
+		name               string
+		id                 string
+		token              string
+		ucMock             func(ucMock mock.MockTasksUC)
+		expectedStatusCode int
+	}{
+		{
+			name:  "happy path - OK",
+			id:    "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			token: "secret",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().PurgeTask(gomock.Any(), uuid.MustParse("1461ec84-ccff-4f3c-af34-65d0856ac3ce")).Return(nil)
+			},
+			expectedStatusCode: 204,
+		},
+		{
+			name:               "missing admin token",
+			id:                 "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			expectedStatusCode: 401,
+		},
+		{
+			name:               "wrong admin token",
+			id:                 "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			token:              "guess",
+			expectedStatusCode: 401,
+		},
+		{
+			name:               "wrong id type",
+			id:                 "invalid id",
+			token:              "secret",
+			expectedStatusCode: 400,
+		},
+		{
+			name:  "not in trash",
+			id:    "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			token: "secret",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().PurgeTask(gomock.Any(), gomock.Any()).Return(domain.ErrTaskNotFound)
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name:  "purge error",
+			id:    "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			token: "secret",
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().PurgeTask(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+			},
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			r := chi.NewRouter()
+			recorder := httptest.NewRecorder()
+			ucMock := mock.NewMockTasksUC(ctrl)
+			handler := NewTasksHandler(ucMock)
+
+			if tt.ucMock != nil {
+				tt.ucMock(*ucMock)
+			}
+
+			r.With(RequireAdmin("secret")).Delete("/api/trash/{id}", handler.PurgeTask)
+			req, err := http.NewRequest(http.MethodDelete, "/api/trash/"+tt.id, nil)
+			require.NoError(t, err)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			r.ServeHTTP(recorder, req)
+			require.Equal(t, tt.expectedStatusCode, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package handler
+
+import (
+	"api/domain"
+	"api/uc"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-chi/render"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// The strategies a TenantResolver can find the organization of a request with.
+const (
+	TenantBySubdomain = "subdomain"
+	TenantByHeader    = "header"
+	TenantByToken     = "token"
+)
+
+const tenantHeader = "X-Organization"
+
+var (
+	errTenantRequired     = errors.New("organization required")
+	errInvalidTenantToken = errors.New("invalid token")
+)
+
+type TenantConfig struct {
+	// Strategies are tried in order until one of them names an organization. Without
+	// any, every request belongs to domain.DefaultOrganizationID.
+	Strategies []string
+	// BaseDomain is the domain the subdomains of the organizations are under.
+	BaseDomain string
+	// TokenSecret verifies the HS256 bearer tokens carrying the org claim.
+	TokenSecret string
+	// TrustedProxy tells that a proxy in front of the API authenticates the callers and
+	// sets the subdomain or header to their own organization only. The subdomain and
+	// header strategies let any caller pick any organization otherwise, so they require
+	// it.
+	TrustedProxy bool
+}
+
+// TenantResolver scopes the context of every request to the organization it is made
+// for, which the repos then restrict all their queries to.
+type TenantResolver struct {
+	organizationsService uc.OrganizationsUC
+	strategies           []string
+	baseDomain           string
+	tokenSecret          []byte
+	// orgIDs caches the ids of the organizations by slug. Organizations are never
+	// deleted nor renamed, so an entry never goes stale.
+	orgIDs sync.Map
+}
+
+func NewTenantResolver(organizationsService uc.OrganizationsUC, conf TenantConfig) (*TenantResolver, error) {
+	for _, strategy := range conf.Strategies {
+		if (strategy == TenantBySubdomain || strategy == TenantByHeader) && !conf.TrustedProxy {
+			return nil, fmt.Errorf("tenant resolution by %s requires a trusted proxy", strategy)
+		}
+		switch strategy {
+		case TenantBySubdomain:
+			if conf.BaseDomain == "" {
+				return nil, errors.New("tenant resolution by subdomain requires a base domain")
+			}
+		case TenantByToken:
+			if conf.TokenSecret == "" {
+				return nil, errors.New("tenant resolution by token requires a token secret")
+			}
+		case TenantByHeader:
+		default:
+			return nil, fmt.Errorf("unknown tenant resolution strategy %q", strategy)
+		}
+	}
+
+	return &TenantResolver{
+		organizationsService: organizationsService,
+		strategies:           conf.Strategies,
+		baseDomain:           strings.ToLower(strings.Trim(conf.BaseDomain, ".")),
+		tokenSecret:          []byte(conf.TokenSecret),
+	}, nil
+}
+
+// tenantRequest holds what the strategies look at, which HTTP and gRPC requests carry
+// in different places.
+type tenantRequest struct {
+	host          string
+	header        string
+	authorization string
+}
+
+// Middleware answers with HTTP 400 when no strategy names an organization, and with
+// HTTP 404 when the named organization does not exist.
+func (tr *TenantResolver) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		orgID, err := tr.resolve(r.Context(), tenantRequest{
+			host:          r.Host,
+			header:        r.Header.Get(tenantHeader),
+			authorization: r.Header.Get("Authorization"),
+		})
+		if err != nil {
+			code := tenantErrorStatus(err)
+			if code == http.StatusUnauthorized {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="tasks"`)
+			}
+			render.Status(r, code)
+			render.JSON(w, r, ErrorResponse{
+				Code:    code,
+				Message: err.Error(),
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(domain.WithOrganization(r.Context(), orgID)))
+	})
+}
+
+// UnaryInterceptor does for gRPC calls what Middleware does for HTTP requests, reading
+// the :authority, x-organization and authorization metadata.
+func (tr *TenantResolver) UnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := tr.grpcContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamInterceptor leaves the reflection service alone, so that tools can list the
+// methods of the server without naming an organization.
+func (tr *TenantResolver) StreamInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if strings.HasPrefix(info.FullMethod, "/grpc.reflection.") {
+		return handler(srv, stream)
+	}
+	ctx, err := tr.grpcContext(stream.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &tenantServerStream{ServerStream: stream, ctx: ctx})
+}
+
+type tenantServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (ts *tenantServerStream) Context() context.Context {
+	return ts.ctx
+}
+
+func (tr *TenantResolver) grpcContext(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	first := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	orgID, err := tr.resolve(ctx, tenantRequest{
+		host:          first(":authority"),
+		header:        first(strings.ToLower(tenantHeader)),
+		authorization: first("authorization"),
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		return nil, status.Error(tenantErrorCode(err), err.Error())
+	}
+	return domain.WithOrganization(ctx, orgID), nil
+}
+
+func (tr *TenantResolver) resolve(ctx context.Context, req tenantRequest) (uuid.UUID, error) {
+	if len(tr.strategies) == 0 {
+		return domain.DefaultOrganizationID, nil
+	}
+
+	for _, strategy := range tr.strategies {
+		slug, err := tr.slug(strategy, req)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if slug != "" {
+			return tr.lookup(ctx, slug)
+		}
+	}
+	return uuid.Nil, errTenantRequired
+}
+
+// slug returns the slug of the organization strategy finds in req, or "" when it finds
+// none and the next strategy has to be tried.
+func (tr *TenantResolver) slug(strategy string, req tenantRequest) (string, error) {
+	switch strategy {
+	case TenantBySubdomain:
+		host := req.host
+		if hostname, _, err := net.SplitHostPort(host); err == nil {
+			host = hostname
+		}
+		subdomain, ok := strings.CutSuffix(strings.ToLower(host), "."+tr.baseDomain)
+		if !ok || strings.Contains(subdomain, ".") {
+			return "", nil
+		}
+		return subdomain, nil
+	case TenantByHeader:
+		return strings.TrimSpace(req.header), nil
+	case TenantByToken:
+		return tr.tokenSlug(req.authorization)
+	}
+	return "", nil
+}
+
+// tokenSlug returns the org claim of a bearer JWT signed with the token secret. Bearer
+// tokens which are not JWTs at all, like the admin token, are left to the other
+// strategies.
+func (tr *TenantResolver) tokenSlug(authorization string) (string, error) {
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok || strings.Count(token, ".") != 2 {
+		return "", nil
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return tr.tokenSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", errInvalidTenantToken, err)
+	}
+
+	slug, _ := claims["org"].(string)
+	if slug == "" {
+		return "", fmt.Errorf("%w: org claim is missing", errInvalidTenantToken)
+	}
+	return slug, nil
+}
+
+func (tr *TenantResolver) lookup(ctx context.Context, slug string) (uuid.UUID, error) {
+	slug = strings.ToLower(slug)
+	if orgID, ok := tr.orgIDs.Load(slug); ok {
+		return orgID.(uuid.UUID), nil
+	}
+
+	org, err := tr.organizationsService.GetOrganizationBySlug(ctx, slug)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	tr.orgIDs.Store(slug, org.ID)
+	return org.ID, nil
+}
+
+func tenantErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errTenantRequired):
+		return http.StatusBadRequest
+	case errors.Is(err, errInvalidTenantToken):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain.ErrOrganizationNotFound):
+		return http.StatusNotFound
+	default:
+		return serverErrorStatus(err)
+	}
+}
+
+func tenantErrorCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, errTenantRequired):
+		return codes.InvalidArgument
+	case errors.Is(err, errInvalidTenantToken):
+		return codes.Unauthenticated
+	case errors.Is(err, domain.ErrOrganizationNotFound):
+		return codes.NotFound
+	case errors.Is(err, domain.ErrStorageUnavailable):
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
@@ -0,0 +1,337 @@
+package handler
+
+import (
+	"api/domain"
+	mock "api/mocks/mock_uc"
+	tasksv1 "api/proto/tasks/v1"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/test/bufconn"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const tenantTokenSecret = "tenant-secret"
+
+func signTenantToken(t *testing.T, method jwt.SigningMethod, key any, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	require.NoError(t, err)
+	return token
+}
+
+func TestTenantResolver_Middleware(t *testing.T) {
+	acme := getOrganization()
+	validToken := signTenantToken(t, jwt.SigningMethodHS256, []byte(tenantTokenSecret), jwt.MapClaims{"org": "acme"})
+
+	tests := []struct {
+		name               string
+		strategies         []string
+		host               string
+		headers            map[string]string
+		ucMock             func(ucMock *mock.MockOrganizationsUC)
+		expectedStatusCode int
+		expectedOrgID      uuid.UUID
+		expectedMessage    string
+	}{
+		{
+			name:               "single tenant",
+			host:               "acme.tasks.example.com",
+			ucMock:             func(ucMock *mock.MockOrganizationsUC) {},
+			expectedStatusCode: http.StatusOK,
+			expectedOrgID:      domain.DefaultOrganizationID,
+		},
+		{
+			name:       "subdomain",
+			strategies: []string{TenantBySubdomain},
+			host:       "acme.tasks.example.com:8080",
+			ucMock: func(ucMock *mock.MockOrganizationsUC) {
+				ucMock.EXPECT().GetOrganizationBySlug(gomock.Any(), "acme").Return(acme, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedOrgID:      acme.ID,
+		},
+		{
+			name:               "base domain without subdomain",
+			strategies:         []string{TenantBySubdomain},
+			host:               "tasks.example.com",
+			ucMock:             func(ucMock *mock.MockOrganizationsUC) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedMessage:    "organization required",
+		},
+		{
+			name:               "nested subdomain",
+			strategies:         []string{TenantBySubdomain},
+			host:               "www.acme.tasks.example.com",
+			ucMock:             func(ucMock *mock.MockOrganizationsUC) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedMessage:    "organization required",
+		},
+		{
+			name:       "header",
+			strategies: []string{TenantByHeader},
+			host:       "tasks.example.com",
+			headers:    map[string]string{"X-Organization": "Acme"},
+			ucMock: func(ucMock *mock.MockOrganizationsUC) {
+				ucMock.EXPECT().GetOrganizationBySlug(gomock.Any(), "acme").Return(acme, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedOrgID:      acme.ID,
+		},
+		{
+			name:       "token",
+			strategies: []string{TenantByToken},
+			headers:    map[string]string{"Authorization": "Bearer " + validToken},
+			ucMock: func(ucMock *mock.MockOrganizationsUC) {
+				ucMock.EXPECT().GetOrganizationBySlug(gomock.Any(), "acme").Return(acme, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedOrgID:      acme.ID,
+		},
+		{
+			name:       "token before header",
+			strategies: []string{TenantByToken, TenantByHeader},
+			headers:    map[string]string{"Authorization": "Bearer " + validToken, "X-Organization": "globex"},
+			ucMock: func(ucMock *mock.MockOrganizationsUC) {
+				ucMock.EXPECT().GetOrganizationBySlug(gomock.Any(), "acme").Return(acme, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedOrgID:      acme.ID,
+		},
+		{
+			name:       "bearer token which is not a jwt",
+			strategies: []string{TenantByToken, TenantByHeader},
+			headers:    map[string]string{"Authorization": "Bearer admin-token", "X-Organization": "acme"},
+			ucMock: func(ucMock *mock.MockOrganizationsUC) {
+				ucMock.EXPECT().GetOrganizationBySlug(gomock.Any(), "acme").Return(acme, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedOrgID:      acme.ID,
+		},
+		{
+			name:       "token signed with another secret",
+			strategies: []string{TenantByToken, TenantByHeader},
+			headers: map[string]string{
+				"Authorization":  "Bearer " + signTenantToken(t, jwt.SigningMethodHS256, []byte("other"), jwt.MapClaims{"org": "acme"}),
+				"X-Organization": "acme",
+			},
+			ucMock:             func(ucMock *mock.MockOrganizationsUC) {},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedMessage:    "invalid token: token signature is invalid: signature is invalid",
+		},
+		{
+			name:       "unsigned token",
+			strategies: []string{TenantByToken},
+			headers: map[string]string{
+				"Authorization": "Bearer " + signTenantToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, jwt.MapClaims{"org": "acme"}),
+			},
+			ucMock:             func(ucMock *mock.MockOrganizationsUC) {},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedMessage:    "invalid token: token signature is invalid: signing method none is invalid",
+		},
+		{
+			name:       "token without org claim",
+			strategies: []string{TenantByToken},
+			headers: map[string]string{
+				"Authorization": "Bearer " + signTenantToken(t, jwt.SigningMethodHS256, []byte(tenantTokenSecret), jwt.MapClaims{"sub": "someone"}),
+			},
+			ucMock:             func(ucMock *mock.MockOrganizationsUC) {},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedMessage:    "invalid token: org claim is missing",
+		},
+		{
+			name:       "unknown organization",
+			strategies: []string{TenantByHeader},
+			headers:    map[string]string{"X-Organization": "globex"},
+			ucMock: func(ucMock *mock.MockOrganizationsUC) {
+				ucMock.EXPECT().GetOrganizationBySlug(gomock.Any(), "globex").Return(domain.Organization{}, fmt.Errorf("organization globex: %w", domain.ErrOrganizationNotFound))
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedMessage:    "organization globex: organization not found",
+		},
+		{
+			name:       "error",
+			strategies: []string{TenantByHeader},
+			headers:    map[string]string{"X-Organization": "acme"},
+			ucMock: func(ucMock *mock.MockOrganizationsUC) {
+				ucMock.EXPECT().GetOrganizationBySlug(gomock.Any(), "acme").Return(domain.Organization{}, fmt.Errorf("error fetching organization: db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedMessage:    "error fetching organization: db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ucMock := mock.NewMockOrganizationsUC(gomock.NewController(t))
+			tt.ucMock(ucMock)
+			resolver, err := NewTenantResolver(ucMock, TenantConfig{
+				Strategies:   tt.strategies,
+				BaseDomain:   "tasks.example.com",
+				TokenSecret:  tenantTokenSecret,
+				TrustedProxy: true,
+			})
+			require.NoError(t, err)
+
+			var orgID uuid.UUID
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var ok bool
+				orgID, ok = domain.OrganizationFromContext(r.Context())
+				require.True(t, ok)
+				w.WriteHeader(http.StatusOK)
+			})
+			req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+			req.Host = tt.host
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			recorder := httptest.NewRecorder()
+			resolver.Middleware(next).ServeHTTP(recorder, req)
+
+			require.Equal(t, tt.expectedStatusCode, recorder.Code)
+			if tt.expectedStatusCode == http.StatusOK {
+				require.Equal(t, tt.expectedOrgID, orgID)
+				return
+			}
+			var response ErrorResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+			require.Equal(t, ErrorResponse{Code: tt.expectedStatusCode, Message: tt.expectedMessage}, response)
+		})
+	}
+}
+
+func TestTenantResolver_CachesOrganizations(t *testing.T) {
+	ucMock := mock.NewMockOrganizationsUC(gomock.NewController(t))
+	ucMock.EXPECT().GetOrganizationBySlug(gomock.Any(), "acme").Return(getOrganization(), nil).Times(1)
+	resolver, err := NewTenantResolver(ucMock, TenantConfig{Strategies: []string{TenantByHeader}, TrustedProxy: true})
+	require.NoError(t, err)
+
+	for _, slug := range []string{"acme", "ACME", "acme"} {
+		orgID, err := resolver.resolve(context.Background(), tenantRequest{header: slug})
+		require.NoError(t, err)
+		require.Equal(t, getOrganization().ID, orgID)
+	}
+}
+
+func TestNewTenantResolver_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		conf          TenantConfig
+		expectedError string
+	}{
+		{
+			name:          "unknown strategy",
+			conf:          TenantConfig{Strategies: []string{"cookie"}},
+			expectedError: `unknown tenant resolution strategy "cookie"`,
+		},
+		{
+			name:          "subdomain without base domain",
+			conf:          TenantConfig{Strategies: []string{TenantBySubdomain}, TrustedProxy: true},
+			expectedError: "tenant resolution by subdomain requires a base domain",
+		},
+		{
+			name:          "subdomain without trusted proxy",
+			conf:          TenantConfig{Strategies: []string{TenantBySubdomain}, BaseDomain: "tasks.example.com"},
+			expectedError: "tenant resolution by subdomain requires a trusted proxy",
+		},
+		{
+			name:          "header without trusted proxy",
+			conf:          TenantConfig{Strategies: []string{TenantByToken, TenantByHeader}, TokenSecret: tenantTokenSecret},
+			expectedError: "tenant resolution by header requires a trusted proxy",
+		},
+		{
+			name:          "token without secret",
+			conf:          TenantConfig{Strategies: []string{TenantByToken}},
+			expectedError: "tenant resolution by token requires a token secret",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTenantResolver(nil, tt.conf)
+			require.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestTenantResolver_GRPC(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	orgsMock := mock.NewMockOrganizationsUC(ctrl)
+	orgsMock.EXPECT().GetOrganizationBySlug(gomock.Any(), "acme").Return(getOrganization(), nil).AnyTimes()
+	tasksMock := mock.NewMockTasksUC(ctrl)
+	resolver, err := NewTenantResolver(orgsMock, TenantConfig{Strategies: []string{TenantByHeader}, TrustedProxy: true})
+	require.NoError(t, err)
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := NewGRPCServer(tasksMock, "secret",
+		grpc.UnaryInterceptor(resolver.UnaryInterceptor),
+		grpc.StreamInterceptor(resolver.StreamInterceptor),
+	)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	client := tasksv1.NewTaskServiceClient(conn)
+
+	requireOrganization := func(ctx context.Context) {
+		orgID, ok := domain.OrganizationFromContext(ctx)
+		require.True(t, ok)
+		require.Equal(t, getOrganization().ID, orgID)
+	}
+
+	t.Run("unary", func(t *testing.T) {
+		tasksMock.EXPECT().GetTaskById(gomock.Any(), getExpectedBody().ID).DoAndReturn(
+			func(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+				requireOrganization(ctx)
+				return getExpectedBody(), nil
+			})
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "x-organization", "acme")
+		_, err := client.GetTask(ctx, &tasksv1.GetTaskRequest{Id: getExpectedBody().ID.String()})
+		require.NoError(t, err)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		tasksMock.EXPECT().ExportTasks(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, filter domain.TaskFilter, fn func(task domain.Task) error) error {
+				requireOrganization(ctx)
+				return nil
+			})
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "x-organization", "acme")
+		stream, err := client.ListTasks(ctx, &tasksv1.ListTasksRequest{})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		require.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("without organization", func(t *testing.T) {
+		_, err := client.GetTask(context.Background(), &tasksv1.GetTaskRequest{Id: getExpectedBody().ID.String()})
+		requireGRPCCode(t, codes.InvalidArgument, err)
+	})
+
+	t.Run("reflection", func(t *testing.T) {
+		stream, err := grpc_reflection_v1.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, stream.Send(&grpc_reflection_v1.ServerReflectionRequest{
+			MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_ListServices{},
+		}))
+		_, err = stream.Recv()
+		require.NoError(t, err)
+	})
+}
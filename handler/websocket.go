@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"api/domain"
+	"api/uc"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+)
+
+const (
+	wsWriteWait      = 10 * time.Second
+	wsPongWait       = 60 * time.Second
+	wsPingPeriod     = wsPongWait * 9 / 10
+	wsMaxMessageSize = 64 * 1024
+	wsSendBufferSize = 64
+)
+
+const (
+	wsTypeSubscribe   = "subscribe"
+	wsTypeUnsubscribe = "unsubscribe"
+	wsTypeCreate      = "create"
+	wsTypeUpdate      = "update"
+	wsTypeAck         = "ack"
+	wsTypeError       = "error"
+	wsTypeEvent       = "event"
+)
+
+// WsMessage is the envelope of every message exchanged over /api/ws. Clients send
+// subscribe, unsubscribe, create and update messages; the server answers each of them
+// with an ack or an error carrying the same request_id and pushes changes matching a
+// subscription as event messages.
+type WsMessage struct {
+	Type           string         `json:"type"`
+	RequestID      string         `json:"request_id,omitempty"`
+	SubscriptionID string         `json:"subscription_id,omitempty"`
+	TaskID         *uuid.UUID     `json:"task_id,omitempty"`
+	Status         string         `json:"status,omitempty"`
+	Event          string         `json:"event,omitempty"`
+	EventID        string         `json:"event_id,omitempty"`
+	Task           *domain.Task   `json:"task,omitempty"`
+	Error          *ErrorResponse `json:"error,omitempty"`
+}
+
+type WebSocketHandler struct {
+	tasksService   uc.TasksUC
+	changesService uc.TaskChangesUC
+	upgrader       websocket.Upgrader
+}
+
+func NewWebSocketHandler(tasksService uc.TasksUC, changesService uc.TaskChangesUC) *WebSocketHandler {
+	return &WebSocketHandler{
+		tasksService:   tasksService,
+		changesService: changesService,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  4096,
+			WriteBufferSize: 4096,
+		},
+	}
+}
+
+// wsSubscription matches either a single task or every task passing the filter.
+type wsSubscription struct {
+	taskID *uuid.UUID
+	filter domain.TaskFilter
+}
+
+// deliverable returns change as the subscription gets it, if it gets it at all. A task
+// updated so that it no longer matches the filter is announced as deleted, as the
+// subscriber only knows the tasks matching it.
+func (s wsSubscription) deliverable(change domain.TaskChange) (domain.TaskChange, bool) {
+	if s.taskID != nil {
+		return change, *s.taskID == change.Task.ID
+	}
+	if s.filter.Matches(change.Task) {
+		return change, true
+	}
+
+	previous := change.Task
+	previous.Status = change.PreviousStatus
+	if change.Type != domain.EventTaskUpdated || change.PreviousStatus == "" || !s.filter.Matches(previous) {
+		return domain.TaskChange{}, false
+	}
+	change.Type = domain.EventTaskDeleted
+	change.Task = domain.Task{ID: change.Task.ID, Status: change.Task.Status}
+	return change, true
+}
+
+type wsClient struct {
+	conn   *websocket.Conn
+	send   chan WsMessage
+	cancel context.CancelFunc
+
+	mu            sync.Mutex
+	subscriptions map[string]wsSubscription
+	closeReason   string
+}
+
+func (wh WebSocketHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	conn, err := wh.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already replied with an HTTP error.
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	client := &wsClient{
+		conn:          conn,
+		send:          make(chan WsMessage, wsSendBufferSize),
+		cancel:        cancel,
+		subscriptions: make(map[string]wsSubscription),
+	}
+
+	changes, _ := wh.changesService.Subscribe(ctx, "", domain.TaskFilter{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		client.writeLoop(ctx, changes)
+	}()
+
+	wh.readLoop(ctx, client)
+	cancel()
+	<-done
+}
+
+func (wh WebSocketHandler) readLoop(ctx context.Context, client *wsClient) {
+	client.conn.SetReadLimit(wsMaxMessageSize)
+	_ = client.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	client.conn.SetPongHandler(func(string) error {
+		return client.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		_, data, err := client.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var msg WsMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			client.reply(wsError("", http.StatusBadRequest, "invalid message: "+err.Error()))
+			continue
+		}
+		client.reply(wh.handleMessage(ctx, client, msg))
+	}
+}
+
+func (wh WebSocketHandler) handleMessage(ctx context.Context, client *wsClient, msg WsMessage) WsMessage {
+	switch msg.Type {
+	case wsTypeSubscribe:
+		subscriptionID := uuid.NewString()
+		client.mu.Lock()
+		client.subscriptions[subscriptionID] = wsSubscription{taskID: msg.TaskID, filter: domain.TaskFilter{Status: msg.Status}}
+		client.mu.Unlock()
+		return WsMessage{Type: wsTypeAck, RequestID: msg.RequestID, SubscriptionID: subscriptionID}
+
+	case wsTypeUnsubscribe:
+		client.mu.Lock()
+		_, ok := client.subscriptions[msg.SubscriptionID]
+		delete(client.subscriptions, msg.SubscriptionID)
+		client.mu.Unlock()
+		if !ok {
+			return wsError(msg.RequestID, http.StatusNotFound, "subscription not found")
+		}
+		return WsMessage{Type: wsTypeAck, RequestID: msg.RequestID, SubscriptionID: msg.SubscriptionID}
+
+	case wsTypeCreate:
+		if msg.Task == nil || reflect.DeepEqual(*msg.Task, domain.Task{}) {
+			return wsError(msg.RequestID, http.StatusBadRequest, "invalid task")
+		}
+		task, err := wh.tasksService.CreateTask(ctx, *msg.Task)
+		if err != nil {
+			if errors.Is(err, domain.ErrInvalidTask) || errors.Is(err, domain.ErrInvalidRecurrence) {
+				return wsError(msg.RequestID, http.StatusBadRequest, err.Error())
+			}
+			if errors.Is(err, domain.ErrTaskExists) {
+				return wsError(msg.RequestID, http.StatusConflict, err.Error())
+			}
+			return wsError(msg.RequestID, serverErrorStatus(err), "error creating new task: "+err.Error())
+		}
+		return WsMessage{Type: wsTypeAck, RequestID: msg.RequestID, Task: &task}
+
+	case wsTypeUpdate:
+		if msg.Task == nil || msg.Task.ID == uuid.Nil {
+			return wsError(msg.RequestID, http.StatusBadRequest, "task id is required")
+		}
+		task, err := wh.tasksService.UpdateTask(ctx, *msg.Task)
+		if err != nil {
+			if errors.Is(err, domain.ErrTaskNotFound) {
+				return wsError(msg.RequestID, http.StatusNotFound, "task not found")
+			}
+			if errors.Is(err, domain.ErrInvalidRecurrence) {
+				return wsError(msg.RequestID, http.StatusBadRequest, err.Error())
+			}
+			return wsError(msg.RequestID, serverErrorStatus(err), "error updating task: "+err.Error())
+		}
+		return WsMessage{Type: wsTypeAck, RequestID: msg.RequestID, Task: &task}
+
+	default:
+		return wsError(msg.RequestID, http.StatusBadRequest, "unknown message type: "+msg.Type)
+	}
+}
+
+func wsError(requestID string, code int, message string) WsMessage {
+	return WsMessage{Type: wsTypeError, RequestID: requestID, Error: &ErrorResponse{Code: code, Message: message}}
+}
+
+// reply queues msg for the writer. A client that does not read its replies fast enough
+// is disconnected rather than blocking the read loop.
+func (c *wsClient) reply(msg WsMessage) {
+	select {
+	case c.send <- msg:
+	default:
+		c.disconnect("slow consumer")
+	}
+}
+
+func (c *wsClient) disconnect(reason string) {
+	c.mu.Lock()
+	if c.closeReason == "" {
+		c.closeReason = reason
+	}
+	c.mu.Unlock()
+	c.cancel()
+}
+
+// writeLoop is the only goroutine writing to the connection.
+func (c *wsClient) writeLoop(ctx context.Context, changes <-chan domain.TaskChange) {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+	defer c.close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-c.send:
+			if err := c.write(msg); err != nil {
+				c.disconnect("")
+				return
+			}
+		case change, ok := <-changes:
+			if !ok {
+				// The hub drops subscribers that fall behind on task changes.
+				c.disconnect("slow consumer")
+				changes = nil
+				continue
+			}
+			for _, msg := range c.events(change) {
+				if err := c.write(msg); err != nil {
+					c.disconnect("")
+					return
+				}
+			}
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				c.disconnect("")
+				return
+			}
+		}
+	}
+}
+
+func (c *wsClient) events(change domain.TaskChange) []WsMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var msgs []WsMessage
+	for id, sub := range c.subscriptions {
+		delivered, ok := sub.deliverable(change)
+		if !ok {
+			continue
+		}
+		task := delivered.Task
+		msgs = append(msgs, WsMessage{
+			Type:           wsTypeEvent,
+			SubscriptionID: id,
+			Event:          streamEventNames[delivered.Type],
+			EventID:        delivered.ID,
+			Task:           &task,
+		})
+	}
+	return msgs
+}
+
+func (c *wsClient) write(msg WsMessage) error {
+	_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	return c.conn.WriteJSON(msg)
+}
+
+func (c *wsClient) close() {
+	c.mu.Lock()
+	reason := c.closeReason
+	c.mu.Unlock()
+
+	code := websocket.CloseNormalClosure
+	if reason != "" {
+		code = websocket.ClosePolicyViolation
+	}
+	// The peer may already be gone, in which case there is nobody to tell.
+	_ = c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(wsWriteWait))
+	// Unblocks the read loop when the disconnect was initiated by the server.
+	_ = c.conn.SetReadDeadline(time.Now())
+}
@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"api/domain"
+	mock "api/mocks/mock_uc"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newWsTestClient(t *testing.T, tasksMock *mock.MockTasksUC, changes chan domain.TaskChange) *websocket.Conn {
+	ctrl := gomock.NewController(t)
+	changesMock := mock.NewMockTaskChangesUC(ctrl)
+	changesMock.EXPECT().Subscribe(gomock.Any(), gomock.Eq(""), gomock.Eq(domain.TaskFilter{})).Return(changes, true)
+
+	r := chi.NewRouter()
+	r.Get("/api/ws", NewWebSocketHandler(tasksMock, changesMock).Serve)
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/api/ws", nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func exchange(t *testing.T, conn *websocket.Conn, msg WsMessage) WsMessage {
+	require.NoError(t, conn.WriteJSON(msg))
+	return readWsMessage(t, conn)
+}
+
+func readWsMessage(t *testing.T, conn *websocket.Conn) WsMessage {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var reply WsMessage
+	require.NoError(t, conn.ReadJSON(&reply))
+	return reply
+}
+
+func TestWebSocket_Mutations(t *testing.T) {
+	tests := []struct {
+		name          string
+		msg           WsMessage
+		ucMock        func(ucMock mock.MockTasksUC)
+		expectedType  string
+		expectedCode  int
+		expectedTitle string
+	}{
+		{
+			name: "create - OK",
+			msg:  WsMessage{Type: wsTypeCreate, RequestID: "1", Task: &domain.Task{Title: "Do unit tests", Status: "PENDING"}},
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Return(getExpectedBody(), nil)
+			},
+			expectedType:  wsTypeAck,
+			expectedTitle: "Do unit tests",
+		},
+		{
+			name: "create - id already taken",
+			msg:  WsMessage{Type: wsTypeCreate, RequestID: "1", Task: &domain.Task{ID: getExpectedBody().ID, Title: "Do unit tests", Status: "PENDING"}},
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Return(domain.Task{}, domain.ErrTaskExists)
+			},
+			expectedType: wsTypeError,
+			expectedCode: 409,
+		},
+		{
+			name:         "create - empty task",
+			msg:          WsMessage{Type: wsTypeCreate, RequestID: "1", Task: &domain.Task{}},
+			expectedType: wsTypeError,
+			expectedCode: 400,
+		},
+		{
+			name: "update - OK",
+			msg:  WsMessage{Type: wsTypeUpdate, RequestID: "1", Task: &domain.Task{ID: getExpectedBody().ID, Title: "Do unit tests", Status: "DONE"}},
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Return(getExpectedBody(), nil)
+			},
+			expectedType:  wsTypeAck,
+			expectedTitle: "Do unit tests",
+		},
+		{
+			name: "update - not found",
+			msg:  WsMessage{Type: wsTypeUpdate, RequestID: "1", Task: &domain.Task{ID: getExpectedBody().ID, Status: "DONE"}},
+			ucMock: func(ucMock mock.MockTasksUC) {
+				ucMock.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Return(domain.Task{}, domain.ErrTaskNotFound)
+			},
+			expectedType: wsTypeError,
+			expectedCode: 404,
+		},
+		{
+			name:         "update - missing id",
+			msg:          WsMessage{Type: wsTypeUpdate, RequestID: "1", Task: &domain.Task{Status: "DONE"}},
+			expectedType: wsTypeError,
+			expectedCode: 400,
+		},
+		{
+			name:         "unsubscribe - unknown subscription",
+			msg:          WsMessage{Type: wsTypeUnsubscribe, RequestID: "1", SubscriptionID: "unknown"},
+			expectedType: wsTypeError,
+			expectedCode: 404,
+		},
+		{
+			name:         "unknown message type",
+			msg:          WsMessage{Type: "delete", RequestID: "1"},
+			expectedType: wsTypeError,
+			expectedCode: 400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ucMock := mock.NewMockTasksUC(ctrl)
+			if tt.ucMock != nil {
+				tt.ucMock(*ucMock)
+			}
+
+			conn := newWsTestClient(t, ucMock, make(chan domain.TaskChange))
+
+			reply := exchange(t, conn, tt.msg)
+			require.Equal(t, tt.expectedType, reply.Type)
+			require.Equal(t, tt.msg.RequestID, reply.RequestID)
+			if tt.expectedType == wsTypeError {
+				require.Equal(t, tt.expectedCode, reply.Error.Code)
+			} else {
+				require.Equal(t, tt.expectedTitle, reply.Task.Title)
+			}
+		})
+	}
+}
+
+func TestWebSocket_InvalidMessage(t *testing.T) {
+	conn := newWsTestClient(t, mock.NewMockTasksUC(gomock.NewController(t)), make(chan domain.TaskChange))
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("not json")))
+	reply := readWsMessage(t, conn)
+	require.Equal(t, wsTypeError, reply.Type)
+	require.Equal(t, 400, reply.Error.Code)
+
+	// The connection stays usable after a malformed message.
+	reply = exchange(t, conn, WsMessage{Type: wsTypeSubscribe, RequestID: "2"})
+	require.Equal(t, wsTypeAck, reply.Type)
+}
+
+func TestWebSocket_Subscriptions(t *testing.T) {
+	changes := make(chan domain.TaskChange, 10)
+	conn := newWsTestClient(t, mock.NewMockTasksUC(gomock.NewController(t)), changes)
+
+	otherTask := domain.Task{ID: uuid.MustParse("9d373cde-0ba2-45ba-b3dc-61bcfe2faadb"), Status: "PENDING"}
+	taskID := getExpectedBody().ID
+
+	byTask := exchange(t, conn, WsMessage{Type: wsTypeSubscribe, RequestID: "1", TaskID: &taskID})
+	require.Equal(t, wsTypeAck, byTask.Type)
+	require.NotEmpty(t, byTask.SubscriptionID)
+
+	changes <- domain.TaskChange{ID: "1", Type: domain.EventTaskUpdated, Task: otherTask}
+	changes <- domain.TaskChange{ID: "2", Type: domain.EventTaskUpdated, Task: getExpectedBody()}
+
+	event := readWsMessage(t, conn)
+	require.Equal(t, wsTypeEvent, event.Type)
+	require.Equal(t, byTask.SubscriptionID, event.SubscriptionID)
+	require.Equal(t, "updated", event.Event)
+	require.Equal(t, "2", event.EventID)
+	require.Equal(t, taskID, event.Task.ID)
+
+	unsubscribed := exchange(t, conn, WsMessage{Type: wsTypeUnsubscribe, RequestID: "2", SubscriptionID: byTask.SubscriptionID})
+	require.Equal(t, wsTypeAck, unsubscribed.Type)
+
+	byStatus := exchange(t, conn, WsMessage{Type: wsTypeSubscribe, RequestID: "3", Status: "PENDING"})
+	require.Equal(t, wsTypeAck, byStatus.Type)
+
+	changes <- domain.TaskChange{ID: "3", Type: domain.EventTaskCreated, Task: otherTask}
+
+	event = readWsMessage(t, conn)
+	require.Equal(t, byStatus.SubscriptionID, event.SubscriptionID)
+	require.Equal(t, "created", event.Event)
+	require.Equal(t, otherTask.ID, event.Task.ID)
+
+	// A task leaving the filter is announced as deleted, one that never matched it is not.
+	doneTask := otherTask
+	doneTask.Status = "DONE"
+	changes <- domain.TaskChange{ID: "4", Type: domain.EventTaskUpdated, Task: doneTask, PreviousStatus: "DONE"}
+	changes <- domain.TaskChange{ID: "5", Type: domain.EventTaskUpdated, Task: doneTask, PreviousStatus: "PENDING"}
+
+	event = readWsMessage(t, conn)
+	require.Equal(t, byStatus.SubscriptionID, event.SubscriptionID)
+	require.Equal(t, "deleted", event.Event)
+	require.Equal(t, "5", event.EventID)
+	require.Equal(t, otherTask.ID, event.Task.ID)
+	require.Equal(t, "DONE", event.Task.Status)
+}
+
+func TestWebSocket_SlowConsumerDisconnected(t *testing.T) {
+	changes := make(chan domain.TaskChange)
+	conn := newWsTestClient(t, mock.NewMockTasksUC(gomock.NewController(t)), changes)
+
+	// The hub closes the channel of subscribers that fall behind.
+	close(changes)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, _, err := conn.ReadMessage()
+	require.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation), err)
+}
@@ -1,62 +1,424 @@
 package main
 
 import (
+	"api/adapter/cache"
+	"api/adapter/publisher"
+	"api/adapter/ratelimit"
+	"api/adapter/repo/memory"
 	repo "api/adapter/repo/postgres"
-	"api/adapter/repo/postgres/gen"
+	"api/adapter/repo/sqlite"
 	"api/config"
+	"api/domain"
 	"api/handler"
 	"api/uc"
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"google.golang.org/grpc"
 	"log"
+	"net"
 	"net/http"
 	"time"
 )
 
 func main() {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
 	conf, err := config.FromEnv()
 	if err != nil {
 		log.Fatalf("failed to load env vars to config: %v", err)
 	}
 
-	db, err := repo.NewPostgresClient(ctx, *conf)
+	// Postgres may still be starting, which startup waits for on top.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second+conf.DbConnectTimeout)
+	defer cancel()
+
+	eventPublisher, err := newPublisher(*conf)
 	if err != nil {
-		log.Fatalf("error while creating postgres connection: %v", err)
+		log.Fatalf("error while creating outbox publisher: %v", err)
+	}
+	if eventPublisher != nil {
+		defer eventPublisher.Close()
+	} else {
+		log.Println("no outbox publisher configured, events are kept in the outbox until OUTBOX_PUBLISHER is set")
 	}
 
-	if err := repo.RunMigrations("file://adapter/repo/postgres/migrations", *conf); err != nil {
-		log.Fatalf("error while migrating postgres scripts: %v", err)
+	taskCache, err := newTaskCache(*conf)
+	if err != nil {
+		log.Fatalf("error while creating task cache: %v", err)
 	}
 
-	dbRepo := gen.New(db)
+	changesHub := uc.NewTaskChangesHub(conf.StreamReplaySize)
+	onChange, onMissedChanges := changesHub.Publish, func() {}
+	if taskCache != nil {
+		// The changes made through the other instances evict the tasks cached here.
+		onChange = func(change domain.TaskChange) {
+			taskCache.Invalidate(change)
+			changesHub.Publish(change)
+		}
+		onMissedChanges = func() { taskCache.Clear(context.Background()) }
+	}
+	store, err := newStorage(ctx, *conf, eventPublisher, onChange, onMissedChanges)
+	if err != nil {
+		log.Fatalf("error while creating storage: %v", err)
+	}
+	if taskCache != nil {
+		store.tasks = taskCache.Wrap(store.tasks)
+	}
 
-	r := createRouter(dbRepo)
+	if conf.GrpcPort != "" {
+		lis, err := net.Listen("tcp", ":"+conf.GrpcPort)
+		if err != nil {
+			log.Fatalf("error while listening grpc port: %v", err)
+		}
+		tenantResolver, err := newTenantResolver(store.organizations, *conf)
+		if err != nil {
+			log.Fatalf("error while configuring tenant resolution: %v", err)
+		}
+		grpcServer := handler.NewGRPCServer(uc.NewTasksService(store.tasks), conf.AdminToken,
+			grpc.UnaryInterceptor(tenantResolver.UnaryInterceptor),
+			grpc.StreamInterceptor(tenantResolver.StreamInterceptor),
+		)
+		go func() {
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Printf("error occured while serving grpc: %v", err)
+			}
+		}()
+	}
+
+	rateLimitStore, err := newRateLimitStore(*conf, store.db)
+	if err != nil {
+		log.Fatalf("error while creating rate limit store: %v", err)
+	}
+
+	r, err := createRouter(store.tasks, store.calendarFeeds, store.organizations, changesHub, rateLimitStore, store.breaker, *conf)
+	if err != nil {
+		log.Fatalf("error while creating router: %v", err)
+	}
 	if err := http.ListenAndServe(":"+conf.ApiPort, r); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Printf("error occured while listening port: %v", err)
 	}
 }
 
-func createRouter(dbRepo *gen.Queries) http.Handler {
+// createRouter limits the rate of requests only when rateLimitStore is not nil, and fails
+// them fast while the database is unreachable only when breaker is not nil.
+func createRouter(tasksRepo domain.TasksRepo, calendarFeedsRepo domain.CalendarFeedsRepo, organizationsRepo domain.OrganizationsRepo, changesHub *uc.TaskChangesHub, rateLimitStore domain.RateLimitStore, breaker handler.StorageBreaker, conf config.Config) (http.Handler, error) {
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
+	r.Use(handler.SecurityHeaders(conf.HstsMaxAge, conf.ContentSecurityPolicy))
+	if len(conf.CorsAllowedOrigins) > 0 {
+		corsMiddleware, err := handler.CORS(handler.CORSConfig{
+			AllowedOrigins:   conf.CorsAllowedOrigins,
+			AllowedMethods:   conf.CorsAllowedMethods,
+			AllowedHeaders:   conf.CorsAllowedHeaders,
+			AllowCredentials: conf.CorsAllowCredentials,
+			MaxAge:           conf.CorsMaxAge,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error while configuring cors: %v", err)
+		}
+		r.Use(corsMiddleware)
+	}
+	if rateLimitStore != nil {
+		policies, err := domain.ParseRateLimitPolicies(conf.RateLimitPolicies)
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing rate limit policies: %v", err)
+		}
+		guard, err := domain.ParseRateLimitGuard(conf.RateLimitIPGuard)
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing rate limit ip guard: %v", err)
+		}
+		r.Use(handler.NewRateLimiter(rateLimitStore, policies, guard).Middleware)
+	}
+	r.Use(handler.Compress(conf.CompressionMinSize))
+	if breaker != nil {
+		r.Use(handler.FailFast(breaker))
+	}
+	if len(conf.DbReplicaUrls) > 0 {
+		r.Use(handler.ReadYourWrites(conf.ReadYourWritesWindow))
+	}
+
+	tenantResolver, err := newTenantResolver(organizationsRepo, conf)
+	if err != nil {
+		return nil, fmt.Errorf("error while configuring tenant resolution: %v", err)
+	}
 
-	tasksRepo := repo.NewTasksRepo(dbRepo)
 	tasksService := uc.NewTasksService(tasksRepo)
 	tasksHandler := handler.NewTasksHandler(tasksService)
+	streamHandler := handler.NewStreamHandler(changesHub, conf.StreamHeartbeatInterval)
+	wsHandler := handler.NewWebSocketHandler(tasksService, changesHub)
+	calendarService := uc.NewCalendarService(calendarFeedsRepo)
+	calendarHandler := handler.NewCalendarHandler(calendarService, tasksService)
+	caldavHandler := handler.NewCalDAVHandler(tasksService, "/caldav")
+	graphQLHandler := handler.NewGraphQLHandler(tasksService, conf.GraphQLMaxDepth, conf.GraphQLMaxComplexity, conf.DevMode)
+	organizationsHandler := handler.NewOrganizationsHandler(uc.NewOrganizationsService(organizationsRepo))
+	openAPIHandler := handler.NewOpenAPIHandler()
+	openAPIValidator, err := handler.NewOpenAPIValidator()
+	if err != nil {
+		return nil, fmt.Errorf("error while loading openapi spec: %v", err)
+	}
+
+	// WebDAV methods have to be known to chi before routes accepting them are added.
+	chi.RegisterMethod("PROPFIND")
+	chi.RegisterMethod("REPORT")
+	r.Get("/.well-known/caldav", caldavHandler.WellKnown)
+	r.With(tenantResolver.Middleware).Handle("/caldav", caldavHandler)
+	r.With(tenantResolver.Middleware).Handle("/caldav/*", caldavHandler)
 
 	r.Group(func(r chi.Router) {
 		r.Route("/api", func(r chi.Router) {
-			r.Get("/task/{id}", tasksHandler.GetTaskById)
-			r.Get("/tasks", tasksHandler.GetTasks)
-			r.Post("/task", tasksHandler.CreateTask)
+			// Imports are streamed rather than read at once, so they may be much larger.
+			r.With(handler.LimitBody(conf.MaxImportBodySize), tenantResolver.Middleware, openAPIValidator.Middleware).Post("/tasks/import", tasksHandler.ImportTasks)
+
+			r.Group(func(r chi.Router) {
+				r.Use(handler.LimitBody(conf.MaxBodySize))
+				r.Use(openAPIValidator.Middleware)
+				r.With(handler.RequireAdmin(conf.AdminToken)).Post("/organizations", organizationsHandler.CreateOrganization)
+				r.Get("/openapi.json", openAPIHandler.Spec)
+				r.Get("/docs", openAPIHandler.Docs)
+
+				// Everything else only sees the data of the organization of the request.
+				r.Group(func(r chi.Router) {
+					r.Use(tenantResolver.Middleware)
+					r.Group(func(r chi.Router) {
+						// Tasks can also be sent as NDJSON, MessagePack or CBOR.
+						r.Use(handler.Negotiate)
+						r.Get("/task/{id}", tasksHandler.GetTaskById)
+						r.Get("/tasks", tasksHandler.GetTasks)
+						r.Get("/tasks/search", tasksHandler.SearchTasks)
+						r.Post("/task", tasksHandler.CreateTask)
+						r.Put("/task/{id}", tasksHandler.UpdateTask)
+						r.Delete("/task/{id}", tasksHandler.DeleteTask)
+						r.Get("/task/{id}/occurrences", tasksHandler.GetOccurrences)
+						r.Post("/task/{id}/restore", tasksHandler.RestoreTask)
+						r.Get("/trash", tasksHandler.GetTrash)
+					})
+					r.Get("/tasks/stream", streamHandler.StreamTasks)
+					r.Post("/tasks/bulk", tasksHandler.BulkTasks)
+					r.Get("/tasks/export", tasksHandler.ExportTasks)
+					r.With(handler.RequireAdmin(conf.AdminToken)).Delete("/trash/{id}", tasksHandler.PurgeTask)
+					r.Get("/ws", wsHandler.Serve)
+					r.Get("/calendar.ics", calendarHandler.GetCalendar)
+					r.Post("/calendar/feeds", calendarHandler.CreateFeed)
+					r.Post("/calendar/feeds/{token}/rotate", calendarHandler.RotateFeed)
+					r.Delete("/calendar/feeds/{token}", calendarHandler.DeleteFeed)
+					r.Post("/graphql", graphQLHandler.Serve)
+					r.Get("/graphql", graphQLHandler.Playground)
+				})
+			})
 		})
 	})
 
-	return r
+	return r, nil
+}
+
+func newTenantResolver(organizationsRepo domain.OrganizationsRepo, conf config.Config) (*handler.TenantResolver, error) {
+	return handler.NewTenantResolver(uc.NewOrganizationsService(organizationsRepo), handler.TenantConfig{
+		Strategies:   conf.TenantResolution,
+		BaseDomain:   conf.TenantBaseDomain,
+		TokenSecret:  conf.TenantTokenSecret,
+		TrustedProxy: conf.TenantTrustedProxy,
+	})
+}
+
+// storage holds the repos of the configured storage adapter. db is nil unless the
+// adapter is Postgres.
+type storage struct {
+	tasks         domain.TasksRepo
+	calendarFeeds domain.CalendarFeedsRepo
+	organizations domain.OrganizationsRepo
+	// db is the Postgres database, nil for the other storages.
+	db *sql.DB
+	// breaker trips when the Postgres database is unreachable, nil for the other storages.
+	breaker handler.StorageBreaker
+}
+
+// newStorage also starts the background jobs of the storage: the outbox relay when
+// eventPublisher is not nil, the trash retention and the delivery of task changes to
+// onChange. onMissedChanges is called when changes may not have been delivered.
+func newStorage(ctx context.Context, conf config.Config, eventPublisher domain.Publisher, onChange func(change domain.TaskChange), onMissedChanges func()) (storage, error) {
+	switch conf.Storage {
+	case "postgres":
+		return newPostgresStorage(ctx, conf, eventPublisher, onChange, onMissedChanges)
+	case "sqlite":
+		return newSQLiteStorage(ctx, conf, eventPublisher, onChange)
+	case "memory":
+		return newMemoryStorage(conf, eventPublisher, onChange)
+	default:
+		return storage{}, fmt.Errorf("unknown storage %q", conf.Storage)
+	}
+}
+
+func newPostgresStorage(ctx context.Context, conf config.Config, eventPublisher domain.Publisher, onChange func(change domain.TaskChange), onMissedChanges func()) (storage, error) {
+	breaker := repo.NewBreaker(conf.DbBreakerFailures, conf.DbBreakerCooldown)
+	db, err := repo.NewPostgresClient(ctx, conf, breaker)
+	if err != nil {
+		return storage{}, fmt.Errorf("error while creating postgres connection: %v", err)
+	}
+
+	if err := repo.RunMigrations("file://adapter/repo/postgres/migrations", conf); err != nil {
+		return storage{}, fmt.Errorf("error while migrating postgres scripts: %v", err)
+	}
+
+	if eventPublisher != nil {
+		relay, err := repo.NewOutboxRelay(db, eventPublisher, conf.OutboxPollInterval, conf.OutboxBatchSize)
+		if err != nil {
+			return storage{}, fmt.Errorf("error while creating outbox relay: %v", err)
+		}
+		go relay.Run(context.Background())
+	}
+
+	if conf.TrashRetention > 0 {
+		retention := repo.NewTrashRetention(db, conf.TrashRetention, conf.TrashPurgeInterval, conf.TrashPurgeBatchSize)
+		go retention.Run(context.Background())
+	}
+
+	changesListener, err := repo.NewTaskChangesListener(conf.DbConnectionUrl, db)
+	if err != nil {
+		return storage{}, fmt.Errorf("error while listening for task changes: %v", err)
+	}
+	changesListener.OnReconnect(onMissedChanges)
+	go changesListener.Run(context.Background(), onChange)
+
+	tasksRepo := repo.NewTasksRepo(db).WithSearchLanguage(conf.SearchLanguage)
+	if len(conf.DbReplicaUrls) > 0 {
+		replicas, err := repo.NewReplicaSet(ctx, conf)
+		if err != nil {
+			return storage{}, fmt.Errorf("error while opening read replicas: %v", err)
+		}
+		go replicas.Run(context.Background(), conf.DbReplicaHealthInterval)
+		tasksRepo = tasksRepo.WithReplicas(replicas)
+	}
+
+	return storage{
+		tasks:         tasksRepo,
+		calendarFeeds: repo.NewCalendarFeedsRepo(db),
+		organizations: repo.NewOrganizationsRepo(db),
+		db:            db,
+		breaker:       breaker,
+	}, nil
+}
+
+// newSQLiteStorage keeps everything in a single database file, which suits a single
+// instance without any database server. Task changes are delivered within the process.
+func newSQLiteStorage(ctx context.Context, conf config.Config, eventPublisher domain.Publisher, onChange func(change domain.TaskChange)) (storage, error) {
+	db, err := sqlite.NewSQLiteClient(ctx, conf)
+	if err != nil {
+		return storage{}, fmt.Errorf("error while opening sqlite database: %v", err)
+	}
+
+	if err := sqlite.RunMigrations(db); err != nil {
+		return storage{}, fmt.Errorf("error while migrating sqlite scripts: %v", err)
+	}
+
+	if eventPublisher != nil {
+		relay, err := sqlite.NewOutboxRelay(db, eventPublisher, conf.OutboxPollInterval, conf.OutboxBatchSize)
+		if err != nil {
+			return storage{}, fmt.Errorf("error while creating outbox relay: %v", err)
+		}
+		go relay.Run(context.Background())
+	}
+
+	if conf.TrashRetention > 0 {
+		retention := sqlite.NewTrashRetention(db, conf.TrashRetention, conf.TrashPurgeInterval, conf.TrashPurgeBatchSize)
+		go retention.Run(context.Background())
+	}
+
+	tasksRepo := sqlite.NewTasksRepo(db)
+	tasksRepo.OnChange(onChange)
+
+	return storage{
+		tasks:         tasksRepo,
+		calendarFeeds: sqlite.NewCalendarFeedsRepo(db),
+		organizations: sqlite.NewOrganizationsRepo(db),
+	}, nil
+}
+
+// newMemoryStorage keeps everything in the memory of the process, optionally seeded from
+// the fixture file. It suits development and a single instance only.
+func newMemoryStorage(conf config.Config, eventPublisher domain.Publisher, onChange func(change domain.TaskChange)) (storage, error) {
+	store := memory.NewStore()
+	if conf.StorageFixture != "" {
+		if err := store.LoadFixture(conf.StorageFixture); err != nil {
+			return storage{}, fmt.Errorf("error while loading storage fixture: %v", err)
+		}
+	}
+	store.OnChange(onChange)
+
+	if eventPublisher != nil {
+		relay := memory.NewOutboxRelay(store, eventPublisher, conf.OutboxPollInterval, conf.OutboxBatchSize)
+		go relay.Run(context.Background())
+	}
+
+	if conf.TrashRetention > 0 {
+		retention := memory.NewTrashRetention(store, conf.TrashRetention, conf.TrashPurgeInterval)
+		go retention.Run(context.Background())
+	}
+
+	return storage{
+		tasks:         memory.NewTasksRepo(store).WithSearchLanguage(conf.SearchLanguage),
+		calendarFeeds: memory.NewCalendarFeedsRepo(store),
+		organizations: memory.NewOrganizationsRepo(store),
+	}, nil
+}
+
+// newTaskCache returns nil when tasks are not cached. The memory cache suits a single
+// instance, replicas may share the redis one.
+func newTaskCache(conf config.Config) (*cache.Cache, error) {
+	var backend cache.Backend
+	switch conf.TaskCache {
+	case "none":
+		return nil, nil
+	case "memory":
+		backend = cache.NewMemoryBackend(conf.TaskCacheSize, conf.TaskCacheTTL)
+	case "redis":
+		redisBackend, err := cache.NewRedisBackend(conf.RedisUrl, conf.TaskCacheTTL)
+		if err != nil {
+			return nil, err
+		}
+		backend = redisBackend
+	default:
+		return nil, fmt.Errorf("unknown task cache %q", conf.TaskCache)
+	}
+	return cache.New(backend)
+}
+
+// newRateLimitStore returns nil when rate limiting is disabled. The memory store suits a
+// single instance, replicas have to share the postgres one.
+func newRateLimitStore(conf config.Config, db *sql.DB) (domain.RateLimitStore, error) {
+	switch conf.RateLimitStore {
+	case "none":
+		return nil, nil
+	case "memory":
+		store := ratelimit.NewMemoryStore()
+		go store.Run(context.Background(), conf.RateLimitSweepInterval)
+		return store, nil
+	case "postgres":
+		if db == nil {
+			return nil, errors.New("the postgres rate limit store requires postgres storage")
+		}
+		store := repo.NewRateLimitRepo(db)
+		go store.Run(context.Background(), conf.RateLimitSweepInterval)
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", conf.RateLimitStore)
+	}
+}
+
+// newPublisher returns nil when the outbox relay is disabled.
+func newPublisher(conf config.Config) (domain.Publisher, error) {
+	switch conf.OutboxPublisher {
+	case "none":
+		return nil, nil
+	case "stdout":
+		return publisher.NewStdoutPublisher(), nil
+	case "file":
+		return publisher.NewFilePublisher(conf.OutboxFilePath)
+	case "nats":
+		return publisher.NewNatsPublisher(conf.NatsUrl, conf.NatsSubject)
+	default:
+		return nil, fmt.Errorf("unknown outbox publisher %q", conf.OutboxPublisher)
+	}
 }
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"api/config"
+	"api/domain"
+	mock "api/mocks/mock_domain"
+	"api/uc"
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestRoutesMatchOpenAPISpec checks that the spec describes every route under /api and
+// nothing else. CalDAV is left out of the spec as OpenAPI cannot describe WebDAV methods.
+func TestRoutesMatchOpenAPISpec(t *testing.T) {
+	router, err := createRouter(nil, nil, nil, uc.NewTaskChangesHub(0), nil, nil, config.Config{})
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &spec))
+
+	var documented []string
+	for path, item := range spec.Paths {
+		for method := range item {
+			if method == "parameters" {
+				continue
+			}
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	var registered []string
+	err = chi.Walk(router.(chi.Routes), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if strings.HasPrefix(route, "/api/") {
+			registered = append(registered, method+" "+route)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	sort.Strings(documented)
+	sort.Strings(registered)
+	require.Equal(t, registered, documented)
+}
+
+func TestRouterSecurityMiddleware(t *testing.T) {
+	router, err := createRouter(nil, nil, nil, uc.NewTaskChangesHub(0), nil, nil, config.Config{
+		CorsAllowedOrigins: []string{"https://*.example.com"},
+		CorsAllowedMethods: []string{"GET", "POST"},
+		CorsAllowedHeaders: []string{"Content-Type"},
+		MaxBodySize:        64,
+		MaxImportBodySize:  1024,
+	})
+	require.NoError(t, err)
+
+	// chi would answer OPTIONS with 405, so the preflight has to be handled before routing.
+	req := httptest.NewRequest(http.MethodOptions, "/api/task", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "https://app.example.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+	require.Equal(t, "nosniff", recorder.Header().Get("X-Content-Type-Options"))
+
+	// The body is cut off while the request is validated against the spec.
+	req = httptest.NewRequest(http.MethodPost, "/api/task", strings.NewReader(`{"title":"`+strings.Repeat("a", 64)+`"}`))
+	req.ContentLength = -1
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code, recorder.Body.String())
+
+	// Imports have a limit of their own.
+	req = httptest.NewRequest(http.MethodPost, "/api/tasks/import", strings.NewReader(strings.Repeat("a", 512)))
+	req.Header.Set("Content-Type", "text/csv")
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusBadRequest, recorder.Code, recorder.Body.String())
+
+	req = httptest.NewRequest(http.MethodPost, "/api/tasks/import", strings.NewReader(strings.Repeat("a", 2048)))
+	req.Header.Set("Content-Type", "text/csv")
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code, recorder.Body.String())
+}
+
+func TestRouterCompressionAndNegotiation(t *testing.T) {
+	router, err := createRouter(nil, nil, nil, uc.NewTaskChangesHub(0), nil, nil, config.Config{CompressionMinSize: 1024})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil)
+	req.Header.Set("Accept", "text/html")
+	req.Header.Set("Accept-Encoding", "gzip")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+
+	// Only the task endpoints are negotiated, and before the use case is reached.
+	req = httptest.NewRequest(http.MethodGet, "/api/task/1461ec84-ccff-4f3c-af34-65d0856ac3ce", nil)
+	req.Header.Set("Accept", "text/html")
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusNotAcceptable, recorder.Code, recorder.Body.String())
+}
+
+func TestRouterTenantResolution(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tasksRepo := mock.NewMockTasksRepo(ctrl)
+	organizationsRepo := mock.NewMockOrganizationsRepo(ctrl)
+	acme := domain.Organization{ID: uuid.MustParse("7d4b1c9e-2f1a-4c3b-8e5d-6a7f8b9c0d1e"), Slug: "acme", Name: "Acme"}
+	organizationsRepo.EXPECT().GetOrganizationBySlug(gomock.Any(), "acme").Return(acme, nil)
+	tasksRepo.EXPECT().GetTasks(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
+		orgID, ok := domain.OrganizationFromContext(ctx)
+		require.True(t, ok)
+		require.Equal(t, acme.ID, orgID)
+		return nil, nil
+	})
+
+	router, err := createRouter(tasksRepo, nil, organizationsRepo, uc.NewTaskChangesHub(0), nil, nil, config.Config{
+		TenantResolution:   []string{"header"},
+		TenantTrustedProxy: true,
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+	req.Header.Set("X-Organization", "acme")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/tasks", nil))
+	require.Equal(t, http.StatusBadRequest, recorder.Code, recorder.Body.String())
+
+	// The document is served whatever the organization.
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./domain/calendar.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	domain "api/domain"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCalendarFeedsRepo is a mock of CalendarFeedsRepo interface.
+type MockCalendarFeedsRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockCalendarFeedsRepoMockRecorder
+}
+
+// MockCalendarFeedsRepoMockRecorder is the mock recorder for MockCalendarFeedsRepo.
+type MockCalendarFeedsRepoMockRecorder struct {
+	mock *MockCalendarFeedsRepo
+}
+
+// NewMockCalendarFeedsRepo creates a new mock instance.
+func NewMockCalendarFeedsRepo(ctrl *gomock.Controller) *MockCalendarFeedsRepo {
+	mock := &MockCalendarFeedsRepo{ctrl: ctrl}
+	mock.recorder = &MockCalendarFeedsRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCalendarFeedsRepo) EXPECT() *MockCalendarFeedsRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateFeed mocks base method.
+func (m *MockCalendarFeedsRepo) CreateFeed(ctx context.Context, feed domain.CalendarFeed, tokenHash string) (domain.CalendarFeed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFeed", ctx, feed, tokenHash)
+	ret0, _ := ret[0].(domain.CalendarFeed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFeed indicates an expected call of CreateFeed.
+func (mr *MockCalendarFeedsRepoMockRecorder) CreateFeed(ctx, feed, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFeed", reflect.TypeOf((*MockCalendarFeedsRepo)(nil).CreateFeed), ctx, feed, tokenHash)
+}
+
+// DeleteFeed mocks base method.
+func (m *MockCalendarFeedsRepo) DeleteFeed(ctx context.Context, tokenHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFeed", ctx, tokenHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFeed indicates an expected call of DeleteFeed.
+func (mr *MockCalendarFeedsRepoMockRecorder) DeleteFeed(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFeed", reflect.TypeOf((*MockCalendarFeedsRepo)(nil).DeleteFeed), ctx, tokenHash)
+}
+
+// GetFeedByTokenHash mocks base method.
+func (m *MockCalendarFeedsRepo) GetFeedByTokenHash(ctx context.Context, tokenHash string) (domain.CalendarFeed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeedByTokenHash", ctx, tokenHash)
+	ret0, _ := ret[0].(domain.CalendarFeed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeedByTokenHash indicates an expected call of GetFeedByTokenHash.
+func (mr *MockCalendarFeedsRepoMockRecorder) GetFeedByTokenHash(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeedByTokenHash", reflect.TypeOf((*MockCalendarFeedsRepo)(nil).GetFeedByTokenHash), ctx, tokenHash)
+}
+
+// RotateFeedToken mocks base method.
+func (m *MockCalendarFeedsRepo) RotateFeedToken(ctx context.Context, tokenHash, newTokenHash string) (domain.CalendarFeed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateFeedToken", ctx, tokenHash, newTokenHash)
+	ret0, _ := ret[0].(domain.CalendarFeed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateFeedToken indicates an expected call of RotateFeedToken.
+func (mr *MockCalendarFeedsRepoMockRecorder) RotateFeedToken(ctx, tokenHash, newTokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateFeedToken", reflect.TypeOf((*MockCalendarFeedsRepo)(nil).RotateFeedToken), ctx, tokenHash, newTokenHash)
+}
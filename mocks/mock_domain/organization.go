@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./domain/organization.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	domain "api/domain"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOrganizationsRepo is a mock of OrganizationsRepo interface.
+type MockOrganizationsRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrganizationsRepoMockRecorder
+}
+
+// MockOrganizationsRepoMockRecorder is the mock recorder for MockOrganizationsRepo.
+type MockOrganizationsRepoMockRecorder struct {
+	mock *MockOrganizationsRepo
+}
+
+// NewMockOrganizationsRepo creates a new mock instance.
+func NewMockOrganizationsRepo(ctrl *gomock.Controller) *MockOrganizationsRepo {
+	mock := &MockOrganizationsRepo{ctrl: ctrl}
+	mock.recorder = &MockOrganizationsRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrganizationsRepo) EXPECT() *MockOrganizationsRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateOrganization mocks base method.
+func (m *MockOrganizationsRepo) CreateOrganization(ctx context.Context, org domain.Organization) (domain.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrganization", ctx, org)
+	ret0, _ := ret[0].(domain.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrganization indicates an expected call of CreateOrganization.
+func (mr *MockOrganizationsRepoMockRecorder) CreateOrganization(ctx, org interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrganization", reflect.TypeOf((*MockOrganizationsRepo)(nil).CreateOrganization), ctx, org)
+}
+
+// GetOrganizationBySlug mocks base method.
+func (m *MockOrganizationsRepo) GetOrganizationBySlug(ctx context.Context, slug string) (domain.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationBySlug", ctx, slug)
+	ret0, _ := ret[0].(domain.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationBySlug indicates an expected call of GetOrganizationBySlug.
+func (mr *MockOrganizationsRepoMockRecorder) GetOrganizationBySlug(ctx, slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationBySlug", reflect.TypeOf((*MockOrganizationsRepo)(nil).GetOrganizationBySlug), ctx, slug)
+}
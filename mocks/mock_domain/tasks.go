@@ -36,6 +36,21 @@ func (m *MockTasksRepo) EXPECT() *MockTasksRepoMockRecorder {
 	return m.recorder
 }
 
+// CountTasks mocks base method.
+func (m *MockTasksRepo) CountTasks(ctx context.Context, filter domain.TaskFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTasks", ctx, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTasks indicates an expected call of CountTasks.
+func (mr *MockTasksRepoMockRecorder) CountTasks(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTasks", reflect.TypeOf((*MockTasksRepo)(nil).CountTasks), ctx, filter)
+}
+
 // CreateTask mocks base method.
 func (m *MockTasksRepo) CreateTask(ctx context.Context, data domain.Task) (domain.Task, error) {
 	m.ctrl.T.Helper()
@@ -51,6 +66,50 @@ func (mr *MockTasksRepoMockRecorder) CreateTask(ctx, data interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTask", reflect.TypeOf((*MockTasksRepo)(nil).CreateTask), ctx, data)
 }
 
+// DeleteTask mocks base method.
+func (m *MockTasksRepo) DeleteTask(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTask", ctx, id)
+	ret0, _ := ret[0].(domain.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTask indicates an expected call of DeleteTask.
+func (mr *MockTasksRepoMockRecorder) DeleteTask(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockTasksRepo)(nil).DeleteTask), ctx, id)
+}
+
+// ExportTasks mocks base method.
+func (m *MockTasksRepo) ExportTasks(ctx context.Context, filter domain.TaskFilter, fn func(domain.Task) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportTasks", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportTasks indicates an expected call of ExportTasks.
+func (mr *MockTasksRepoMockRecorder) ExportTasks(ctx, filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportTasks", reflect.TypeOf((*MockTasksRepo)(nil).ExportTasks), ctx, filter, fn)
+}
+
+// GetDeletedTasks mocks base method.
+func (m *MockTasksRepo) GetDeletedTasks(ctx context.Context) ([]domain.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedTasks", ctx)
+	ret0, _ := ret[0].([]domain.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedTasks indicates an expected call of GetDeletedTasks.
+func (mr *MockTasksRepoMockRecorder) GetDeletedTasks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedTasks", reflect.TypeOf((*MockTasksRepo)(nil).GetDeletedTasks), ctx)
+}
+
 // GetTaskById mocks base method.
 func (m *MockTasksRepo) GetTaskById(ctx context.Context, id uuid.UUID) (domain.Task, error) {
 	m.ctrl.T.Helper()
@@ -66,17 +125,180 @@ func (mr *MockTasksRepoMockRecorder) GetTaskById(ctx, id interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskById", reflect.TypeOf((*MockTasksRepo)(nil).GetTaskById), ctx, id)
 }
 
+// GetTaskByIdForUpdate mocks base method.
+func (m *MockTasksRepo) GetTaskByIdForUpdate(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskByIdForUpdate", ctx, id)
+	ret0, _ := ret[0].(domain.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskByIdForUpdate indicates an expected call of GetTaskByIdForUpdate.
+func (mr *MockTasksRepoMockRecorder) GetTaskByIdForUpdate(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskByIdForUpdate", reflect.TypeOf((*MockTasksRepo)(nil).GetTaskByIdForUpdate), ctx, id)
+}
+
+// GetTaskEvents mocks base method.
+func (m *MockTasksRepo) GetTaskEvents(ctx context.Context, taskIDs []uuid.UUID) ([]domain.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskEvents", ctx, taskIDs)
+	ret0, _ := ret[0].([]domain.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskEvents indicates an expected call of GetTaskEvents.
+func (mr *MockTasksRepoMockRecorder) GetTaskEvents(ctx, taskIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskEvents", reflect.TypeOf((*MockTasksRepo)(nil).GetTaskEvents), ctx, taskIDs)
+}
+
 // GetTasks mocks base method.
-func (m *MockTasksRepo) GetTasks(ctx context.Context) ([]domain.Task, error) {
+func (m *MockTasksRepo) GetTasks(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTasks", ctx)
+	ret := m.ctrl.Call(m, "GetTasks", ctx, filter)
 	ret0, _ := ret[0].([]domain.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTasks indicates an expected call of GetTasks.
-func (mr *MockTasksRepoMockRecorder) GetTasks(ctx interface{}) *gomock.Call {
+func (mr *MockTasksRepoMockRecorder) GetTasks(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasks", reflect.TypeOf((*MockTasksRepo)(nil).GetTasks), ctx, filter)
+}
+
+// GetTasksByIds mocks base method.
+func (m *MockTasksRepo) GetTasksByIds(ctx context.Context, ids []uuid.UUID) ([]domain.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTasksByIds", ctx, ids)
+	ret0, _ := ret[0].([]domain.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTasksByIds indicates an expected call of GetTasksByIds.
+func (mr *MockTasksRepoMockRecorder) GetTasksByIds(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasksByIds", reflect.TypeOf((*MockTasksRepo)(nil).GetTasksByIds), ctx, ids)
+}
+
+// GetTasksPage mocks base method.
+func (m *MockTasksRepo) GetTasksPage(ctx context.Context, filter domain.TaskFilter, after *domain.TaskCursor, limit int) ([]domain.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTasksPage", ctx, filter, after, limit)
+	ret0, _ := ret[0].([]domain.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTasksPage indicates an expected call of GetTasksPage.
+func (mr *MockTasksRepoMockRecorder) GetTasksPage(ctx, filter, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasksPage", reflect.TypeOf((*MockTasksRepo)(nil).GetTasksPage), ctx, filter, after, limit)
+}
+
+// ImportTasks mocks base method.
+func (m *MockTasksRepo) ImportTasks(ctx context.Context, next func() (domain.Task, error), dryRun bool) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportTasks", ctx, next, dryRun)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportTasks indicates an expected call of ImportTasks.
+func (mr *MockTasksRepoMockRecorder) ImportTasks(ctx, next, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTasks", reflect.TypeOf((*MockTasksRepo)(nil).ImportTasks), ctx, next, dryRun)
+}
+
+// InTx mocks base method.
+func (m *MockTasksRepo) InTx(ctx context.Context, fn func(domain.TasksRepo) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InTx indicates an expected call of InTx.
+func (mr *MockTasksRepoMockRecorder) InTx(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InTx", reflect.TypeOf((*MockTasksRepo)(nil).InTx), ctx, fn)
+}
+
+// PurgeTask mocks base method.
+func (m *MockTasksRepo) PurgeTask(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTask", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeTask indicates an expected call of PurgeTask.
+func (mr *MockTasksRepoMockRecorder) PurgeTask(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTask", reflect.TypeOf((*MockTasksRepo)(nil).PurgeTask), ctx, id)
+}
+
+// RestoreTask mocks base method.
+func (m *MockTasksRepo) RestoreTask(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTask", ctx, id)
+	ret0, _ := ret[0].(domain.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreTask indicates an expected call of RestoreTask.
+func (mr *MockTasksRepoMockRecorder) RestoreTask(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTask", reflect.TypeOf((*MockTasksRepo)(nil).RestoreTask), ctx, id)
+}
+
+// SearchTasks mocks base method.
+func (m *MockTasksRepo) SearchTasks(ctx context.Context, query string, limit int) ([]domain.TaskSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTasks", ctx, query, limit)
+	ret0, _ := ret[0].([]domain.TaskSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTasks indicates an expected call of SearchTasks.
+func (mr *MockTasksRepoMockRecorder) SearchTasks(ctx, query, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTasks", reflect.TypeOf((*MockTasksRepo)(nil).SearchTasks), ctx, query, limit)
+}
+
+// SearchTasksBySimilarity mocks base method.
+func (m *MockTasksRepo) SearchTasksBySimilarity(ctx context.Context, query string, limit int) ([]domain.TaskSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTasksBySimilarity", ctx, query, limit)
+	ret0, _ := ret[0].([]domain.TaskSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTasksBySimilarity indicates an expected call of SearchTasksBySimilarity.
+func (mr *MockTasksRepoMockRecorder) SearchTasksBySimilarity(ctx, query, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTasksBySimilarity", reflect.TypeOf((*MockTasksRepo)(nil).SearchTasksBySimilarity), ctx, query, limit)
+}
+
+// UpdateTask mocks base method.
+func (m *MockTasksRepo) UpdateTask(ctx context.Context, data domain.Task) (domain.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTask", ctx, data)
+	ret0, _ := ret[0].(domain.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTask indicates an expected call of UpdateTask.
+func (mr *MockTasksRepoMockRecorder) UpdateTask(ctx, data interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasks", reflect.TypeOf((*MockTasksRepo)(nil).GetTasks), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockTasksRepo)(nil).UpdateTask), ctx, data)
 }
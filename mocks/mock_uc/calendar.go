@@ -0,0 +1,97 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./uc/calendar.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	domain "api/domain"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCalendarUC is a mock of CalendarUC interface.
+type MockCalendarUC struct {
+	ctrl     *gomock.Controller
+	recorder *MockCalendarUCMockRecorder
+}
+
+// MockCalendarUCMockRecorder is the mock recorder for MockCalendarUC.
+type MockCalendarUCMockRecorder struct {
+	mock *MockCalendarUC
+}
+
+// NewMockCalendarUC creates a new mock instance.
+func NewMockCalendarUC(ctrl *gomock.Controller) *MockCalendarUC {
+	mock := &MockCalendarUC{ctrl: ctrl}
+	mock.recorder = &MockCalendarUCMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCalendarUC) EXPECT() *MockCalendarUCMockRecorder {
+	return m.recorder
+}
+
+// CreateFeed mocks base method.
+func (m *MockCalendarUC) CreateFeed(ctx context.Context, data domain.CalendarFeed) (domain.CalendarFeed, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFeed", ctx, data)
+	ret0, _ := ret[0].(domain.CalendarFeed)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateFeed indicates an expected call of CreateFeed.
+func (mr *MockCalendarUCMockRecorder) CreateFeed(ctx, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFeed", reflect.TypeOf((*MockCalendarUC)(nil).CreateFeed), ctx, data)
+}
+
+// DeleteFeed mocks base method.
+func (m *MockCalendarUC) DeleteFeed(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFeed", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFeed indicates an expected call of DeleteFeed.
+func (mr *MockCalendarUCMockRecorder) DeleteFeed(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFeed", reflect.TypeOf((*MockCalendarUC)(nil).DeleteFeed), ctx, token)
+}
+
+// GetFeed mocks base method.
+func (m *MockCalendarUC) GetFeed(ctx context.Context, token string) (domain.CalendarFeed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeed", ctx, token)
+	ret0, _ := ret[0].(domain.CalendarFeed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeed indicates an expected call of GetFeed.
+func (mr *MockCalendarUCMockRecorder) GetFeed(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeed", reflect.TypeOf((*MockCalendarUC)(nil).GetFeed), ctx, token)
+}
+
+// RotateFeed mocks base method.
+func (m *MockCalendarUC) RotateFeed(ctx context.Context, token string) (domain.CalendarFeed, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateFeed", ctx, token)
+	ret0, _ := ret[0].(domain.CalendarFeed)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RotateFeed indicates an expected call of RotateFeed.
+func (mr *MockCalendarUCMockRecorder) RotateFeed(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateFeed", reflect.TypeOf((*MockCalendarUC)(nil).RotateFeed), ctx, token)
+}
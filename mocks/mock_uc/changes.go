@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./uc/changes.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	domain "api/domain"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTaskChangesUC is a mock of TaskChangesUC interface.
+type MockTaskChangesUC struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaskChangesUCMockRecorder
+}
+
+// MockTaskChangesUCMockRecorder is the mock recorder for MockTaskChangesUC.
+type MockTaskChangesUCMockRecorder struct {
+	mock *MockTaskChangesUC
+}
+
+// NewMockTaskChangesUC creates a new mock instance.
+func NewMockTaskChangesUC(ctrl *gomock.Controller) *MockTaskChangesUC {
+	mock := &MockTaskChangesUC{ctrl: ctrl}
+	mock.recorder = &MockTaskChangesUCMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaskChangesUC) EXPECT() *MockTaskChangesUCMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockTaskChangesUC) Subscribe(ctx context.Context, lastEventId string, filter domain.TaskFilter) (<-chan domain.TaskChange, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, lastEventId, filter)
+	ret0, _ := ret[0].(<-chan domain.TaskChange)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockTaskChangesUCMockRecorder) Subscribe(ctx, lastEventId, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockTaskChangesUC)(nil).Subscribe), ctx, lastEventId, filter)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./uc/organizations.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	domain "api/domain"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOrganizationsUC is a mock of OrganizationsUC interface.
+type MockOrganizationsUC struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrganizationsUCMockRecorder
+}
+
+// MockOrganizationsUCMockRecorder is the mock recorder for MockOrganizationsUC.
+type MockOrganizationsUCMockRecorder struct {
+	mock *MockOrganizationsUC
+}
+
+// NewMockOrganizationsUC creates a new mock instance.
+func NewMockOrganizationsUC(ctrl *gomock.Controller) *MockOrganizationsUC {
+	mock := &MockOrganizationsUC{ctrl: ctrl}
+	mock.recorder = &MockOrganizationsUCMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrganizationsUC) EXPECT() *MockOrganizationsUCMockRecorder {
+	return m.recorder
+}
+
+// CreateOrganization mocks base method.
+func (m *MockOrganizationsUC) CreateOrganization(ctx context.Context, data domain.Organization) (domain.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrganization", ctx, data)
+	ret0, _ := ret[0].(domain.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrganization indicates an expected call of CreateOrganization.
+func (mr *MockOrganizationsUCMockRecorder) CreateOrganization(ctx, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrganization", reflect.TypeOf((*MockOrganizationsUC)(nil).CreateOrganization), ctx, data)
+}
+
+// GetOrganizationBySlug mocks base method.
+func (m *MockOrganizationsUC) GetOrganizationBySlug(ctx context.Context, slug string) (domain.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationBySlug", ctx, slug)
+	ret0, _ := ret[0].(domain.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationBySlug indicates an expected call of GetOrganizationBySlug.
+func (mr *MockOrganizationsUCMockRecorder) GetOrganizationBySlug(ctx, slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationBySlug", reflect.TypeOf((*MockOrganizationsUC)(nil).GetOrganizationBySlug), ctx, slug)
+}
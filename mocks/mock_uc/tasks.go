@@ -8,6 +8,7 @@ import (
 	domain "api/domain"
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -36,6 +37,36 @@ func (m *MockTasksUC) EXPECT() *MockTasksUCMockRecorder {
 	return m.recorder
 }
 
+// BulkTasks mocks base method.
+func (m *MockTasksUC) BulkTasks(ctx context.Context, ops []domain.BulkOperation, atomic bool) ([]domain.BulkResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkTasks", ctx, ops, atomic)
+	ret0, _ := ret[0].([]domain.BulkResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkTasks indicates an expected call of BulkTasks.
+func (mr *MockTasksUCMockRecorder) BulkTasks(ctx, ops, atomic interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkTasks", reflect.TypeOf((*MockTasksUC)(nil).BulkTasks), ctx, ops, atomic)
+}
+
+// CountTasks mocks base method.
+func (m *MockTasksUC) CountTasks(ctx context.Context, filter domain.TaskFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTasks", ctx, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTasks indicates an expected call of CountTasks.
+func (mr *MockTasksUCMockRecorder) CountTasks(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTasks", reflect.TypeOf((*MockTasksUC)(nil).CountTasks), ctx, filter)
+}
+
 // CreateTask mocks base method.
 func (m *MockTasksUC) CreateTask(ctx context.Context, data domain.Task) (domain.Task, error) {
 	m.ctrl.T.Helper()
@@ -51,6 +82,50 @@ func (mr *MockTasksUCMockRecorder) CreateTask(ctx, data interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTask", reflect.TypeOf((*MockTasksUC)(nil).CreateTask), ctx, data)
 }
 
+// DeleteTask mocks base method.
+func (m *MockTasksUC) DeleteTask(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTask", ctx, id)
+	ret0, _ := ret[0].(domain.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTask indicates an expected call of DeleteTask.
+func (mr *MockTasksUCMockRecorder) DeleteTask(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockTasksUC)(nil).DeleteTask), ctx, id)
+}
+
+// ExportTasks mocks base method.
+func (m *MockTasksUC) ExportTasks(ctx context.Context, filter domain.TaskFilter, fn func(domain.Task) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportTasks", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportTasks indicates an expected call of ExportTasks.
+func (mr *MockTasksUCMockRecorder) ExportTasks(ctx, filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportTasks", reflect.TypeOf((*MockTasksUC)(nil).ExportTasks), ctx, filter, fn)
+}
+
+// GetOccurrences mocks base method.
+func (m *MockTasksUC) GetOccurrences(ctx context.Context, id uuid.UUID, limit int) ([]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOccurrences", ctx, id, limit)
+	ret0, _ := ret[0].([]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOccurrences indicates an expected call of GetOccurrences.
+func (mr *MockTasksUCMockRecorder) GetOccurrences(ctx, id, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOccurrences", reflect.TypeOf((*MockTasksUC)(nil).GetOccurrences), ctx, id, limit)
+}
+
 // GetTaskById mocks base method.
 func (m *MockTasksUC) GetTaskById(ctx context.Context, id uuid.UUID) (domain.Task, error) {
 	m.ctrl.T.Helper()
@@ -66,17 +141,151 @@ func (mr *MockTasksUCMockRecorder) GetTaskById(ctx, id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskById", reflect.TypeOf((*MockTasksUC)(nil).GetTaskById), ctx, id)
 }
 
+// GetTaskEvents mocks base method.
+func (m *MockTasksUC) GetTaskEvents(ctx context.Context, taskIDs []uuid.UUID) ([]domain.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskEvents", ctx, taskIDs)
+	ret0, _ := ret[0].([]domain.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskEvents indicates an expected call of GetTaskEvents.
+func (mr *MockTasksUCMockRecorder) GetTaskEvents(ctx, taskIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskEvents", reflect.TypeOf((*MockTasksUC)(nil).GetTaskEvents), ctx, taskIDs)
+}
+
 // GetTasks mocks base method.
-func (m *MockTasksUC) GetTasks(ctx context.Context) ([]domain.Task, error) {
+func (m *MockTasksUC) GetTasks(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTasks", ctx)
+	ret := m.ctrl.Call(m, "GetTasks", ctx, filter)
 	ret0, _ := ret[0].([]domain.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTasks indicates an expected call of GetTasks.
-func (mr *MockTasksUCMockRecorder) GetTasks(ctx interface{}) *gomock.Call {
+func (mr *MockTasksUCMockRecorder) GetTasks(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasks", reflect.TypeOf((*MockTasksUC)(nil).GetTasks), ctx, filter)
+}
+
+// GetTasksByIds mocks base method.
+func (m *MockTasksUC) GetTasksByIds(ctx context.Context, ids []uuid.UUID) ([]domain.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTasksByIds", ctx, ids)
+	ret0, _ := ret[0].([]domain.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTasksByIds indicates an expected call of GetTasksByIds.
+func (mr *MockTasksUCMockRecorder) GetTasksByIds(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasksByIds", reflect.TypeOf((*MockTasksUC)(nil).GetTasksByIds), ctx, ids)
+}
+
+// GetTasksPage mocks base method.
+func (m *MockTasksUC) GetTasksPage(ctx context.Context, filter domain.TaskFilter, after *domain.TaskCursor, limit int) ([]domain.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTasksPage", ctx, filter, after, limit)
+	ret0, _ := ret[0].([]domain.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTasksPage indicates an expected call of GetTasksPage.
+func (mr *MockTasksUCMockRecorder) GetTasksPage(ctx, filter, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasksPage", reflect.TypeOf((*MockTasksUC)(nil).GetTasksPage), ctx, filter, after, limit)
+}
+
+// GetTrash mocks base method.
+func (m *MockTasksUC) GetTrash(ctx context.Context) ([]domain.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", ctx)
+	ret0, _ := ret[0].([]domain.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockTasksUCMockRecorder) GetTrash(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockTasksUC)(nil).GetTrash), ctx)
+}
+
+// ImportTasks mocks base method.
+func (m *MockTasksUC) ImportTasks(ctx context.Context, source domain.TaskSource, dryRun bool) (domain.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportTasks", ctx, source, dryRun)
+	ret0, _ := ret[0].(domain.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportTasks indicates an expected call of ImportTasks.
+func (mr *MockTasksUCMockRecorder) ImportTasks(ctx, source, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTasks", reflect.TypeOf((*MockTasksUC)(nil).ImportTasks), ctx, source, dryRun)
+}
+
+// PurgeTask mocks base method.
+func (m *MockTasksUC) PurgeTask(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTask", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeTask indicates an expected call of PurgeTask.
+func (mr *MockTasksUCMockRecorder) PurgeTask(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTask", reflect.TypeOf((*MockTasksUC)(nil).PurgeTask), ctx, id)
+}
+
+// RestoreTask mocks base method.
+func (m *MockTasksUC) RestoreTask(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTask", ctx, id)
+	ret0, _ := ret[0].(domain.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreTask indicates an expected call of RestoreTask.
+func (mr *MockTasksUCMockRecorder) RestoreTask(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTask", reflect.TypeOf((*MockTasksUC)(nil).RestoreTask), ctx, id)
+}
+
+// SearchTasks mocks base method.
+func (m *MockTasksUC) SearchTasks(ctx context.Context, query string, limit int) ([]domain.TaskSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTasks", ctx, query, limit)
+	ret0, _ := ret[0].([]domain.TaskSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTasks indicates an expected call of SearchTasks.
+func (mr *MockTasksUCMockRecorder) SearchTasks(ctx, query, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTasks", reflect.TypeOf((*MockTasksUC)(nil).SearchTasks), ctx, query, limit)
+}
+
+// UpdateTask mocks base method.
+func (m *MockTasksUC) UpdateTask(ctx context.Context, data domain.Task) (domain.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTask", ctx, data)
+	ret0, _ := ret[0].(domain.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTask indicates an expected call of UpdateTask.
+func (mr *MockTasksUCMockRecorder) UpdateTask(ctx, data interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasks", reflect.TypeOf((*MockTasksUC)(nil).GetTasks), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockTasksUC)(nil).UpdateTask), ctx, data)
 }
@@ -0,0 +1,166 @@
+package uc
+
+import (
+	"api/domain"
+	mock "api/mocks/mock_domain"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// runInTx makes the repo mock run the transaction callback against itself.
+func runInTx(repoMock *mock.MockTasksRepo, err error) {
+	repoMock.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repo domain.TasksRepo) error) error {
+		if fnErr := fn(repoMock); fnErr != nil {
+			return fnErr
+		}
+		return err
+	})
+}
+
+func getBulkOperations() []domain.BulkOperation {
+	task := getTask()
+	return []domain.BulkOperation{
+		{Op: domain.BulkOpCreate, Task: &task},
+		{Op: domain.BulkOpStatus, ID: task.ID, Status: domain.StatusDone},
+		{Op: domain.BulkOpDelete, ID: task.ID},
+	}
+}
+
+func TestBulkTasks(t *testing.T) {
+	done := getTask()
+	done.Status = domain.StatusDone
+
+	tests := []struct {
+		name     string
+		ops      []domain.BulkOperation
+		atomic   bool
+		repoMock func(repoMock *mock.MockTasksRepo)
+		expected []error
+		checks   func(t *testing.T, err error)
+	}{
+		{
+			name: "best effort - OK",
+			ops:  getBulkOperations(),
+			repoMock: func(repoMock *mock.MockTasksRepo) {
+				repoMock.EXPECT().CreateTask(gomock.Any(), getTask()).Return(getTask(), nil)
+				repoMock.EXPECT().GetTaskById(gomock.Any(), getTask().ID).Return(getTask(), nil)
+				repoMock.EXPECT().UpdateTask(gomock.Any(), done).Return(done, nil)
+				repoMock.EXPECT().DeleteTask(gomock.Any(), getTask().ID).Return(done, nil)
+			},
+			expected: []error{nil, nil, nil},
+			checks: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "best effort - failures do not affect other operations",
+			ops:  append(getBulkOperations(), domain.BulkOperation{Op: "archive"}),
+			repoMock: func(repoMock *mock.MockTasksRepo) {
+				repoMock.EXPECT().CreateTask(gomock.Any(), getTask()).Return(domain.Task{}, errors.New("db error"))
+				repoMock.EXPECT().GetTaskById(gomock.Any(), getTask().ID).Return(domain.Task{}, domain.ErrTaskNotFound)
+				repoMock.EXPECT().DeleteTask(gomock.Any(), getTask().ID).Return(done, nil)
+			},
+			expected: []error{errors.New("db error"), domain.ErrTaskNotFound, nil, domain.ErrInvalidBulkOperation},
+			checks: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "atomic - OK",
+			ops:    getBulkOperations(),
+			atomic: true,
+			repoMock: func(repoMock *mock.MockTasksRepo) {
+				runInTx(repoMock, nil)
+				repoMock.EXPECT().CreateTask(gomock.Any(), getTask()).Return(getTask(), nil)
+				repoMock.EXPECT().GetTaskById(gomock.Any(), getTask().ID).Return(getTask(), nil)
+				repoMock.EXPECT().UpdateTask(gomock.Any(), done).Return(done, nil)
+				repoMock.EXPECT().DeleteTask(gomock.Any(), getTask().ID).Return(done, nil)
+			},
+			expected: []error{nil, nil, nil},
+			checks: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "atomic - failure rolls back the batch",
+			ops:    getBulkOperations(),
+			atomic: true,
+			repoMock: func(repoMock *mock.MockTasksRepo) {
+				runInTx(repoMock, nil)
+				repoMock.EXPECT().CreateTask(gomock.Any(), getTask()).Return(getTask(), nil)
+				repoMock.EXPECT().GetTaskById(gomock.Any(), getTask().ID).Return(domain.Task{}, domain.ErrTaskNotFound)
+			},
+			expected: []error{domain.ErrBulkRolledBack, domain.ErrTaskNotFound, domain.ErrBulkRolledBack},
+			checks: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "atomic - commit error",
+			ops:    getBulkOperations()[:1],
+			atomic: true,
+			repoMock: func(repoMock *mock.MockTasksRepo) {
+				runInTx(repoMock, errors.New("failed to commit transaction"))
+				repoMock.EXPECT().CreateTask(gomock.Any(), getTask()).Return(getTask(), nil)
+			},
+			checks: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "failed to commit transaction")
+			},
+		},
+		{
+			name: "invalid operations",
+			ops: []domain.BulkOperation{
+				{Op: domain.BulkOpCreate},
+				{Op: domain.BulkOpUpdate, Task: &domain.Task{Title: "no id"}},
+				{Op: domain.BulkOpDelete},
+				{Op: domain.BulkOpStatus, ID: uuid.New()},
+			},
+			expected: []error{
+				domain.ErrInvalidBulkOperation,
+				domain.ErrInvalidBulkOperation,
+				domain.ErrInvalidBulkOperation,
+				domain.ErrInvalidBulkOperation,
+			},
+			checks: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock.NewMockTasksRepo(ctrl)
+			service := NewTasksService(repo)
+
+			if tt.repoMock != nil {
+				tt.repoMock(repo)
+			}
+
+			results, err := service.BulkTasks(context.Background(), tt.ops, tt.atomic)
+			tt.checks(t, err)
+			if err != nil {
+				return
+			}
+
+			require.Len(t, results, len(tt.expected))
+			for i, expected := range tt.expected {
+				switch {
+				case expected == nil:
+					require.NoError(t, results[i].Err)
+					require.NotNil(t, results[i].Task)
+				case errors.Is(expected, domain.ErrTaskNotFound), errors.Is(expected, domain.ErrInvalidBulkOperation), errors.Is(expected, domain.ErrBulkRolledBack):
+					require.ErrorIs(t, results[i].Err, expected)
+				default:
+					require.ErrorContains(t, results[i].Err, expected.Error())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package uc
+
+import (
+	"api/domain"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+const calendarTokenSize = 32
+
+type CalendarUC interface {
+	CreateFeed(ctx context.Context, data domain.CalendarFeed) (domain.CalendarFeed, string, error)
+	GetFeed(ctx context.Context, token string) (domain.CalendarFeed, error)
+	RotateFeed(ctx context.Context, token string) (domain.CalendarFeed, string, error)
+	DeleteFeed(ctx context.Context, token string) error
+}
+
+// CalendarService hands out the secret tokens identifying calendar feeds. Only their
+// hashes are stored, so a leaked database does not leak subscribable feed URLs.
+type CalendarService struct {
+	feedsRepo domain.CalendarFeedsRepo
+}
+
+func NewCalendarService(feedsRepo domain.CalendarFeedsRepo) *CalendarService {
+	return &CalendarService{feedsRepo: feedsRepo}
+}
+
+// CreateFeed saves a new feed and returns it together with its token, which cannot be
+// recovered later on.
+func (cs CalendarService) CreateFeed(ctx context.Context, data domain.CalendarFeed) (domain.CalendarFeed, string, error) {
+	token, err := newCalendarToken()
+	if err != nil {
+		return domain.CalendarFeed{}, "", err
+	}
+
+	data.ID = uuid.New()
+	feed, err := cs.feedsRepo.CreateFeed(ctx, data, hashCalendarToken(token))
+	if err != nil {
+		return domain.CalendarFeed{}, "", fmt.Errorf("error creating calendar feed: %w", err)
+	}
+	return feed, token, nil
+}
+
+func (cs CalendarService) GetFeed(ctx context.Context, token string) (domain.CalendarFeed, error) {
+	feed, err := cs.feedsRepo.GetFeedByTokenHash(ctx, hashCalendarToken(token))
+	if err != nil {
+		if errors.Is(err, domain.ErrCalendarFeedNotFound) {
+			return domain.CalendarFeed{}, err
+		}
+		return domain.CalendarFeed{}, fmt.Errorf("error fetching calendar feed: %w", err)
+	}
+	return feed, nil
+}
+
+// RotateFeed replaces the token of the feed identified by token, after which the old
+// feed URL stops working.
+func (cs CalendarService) RotateFeed(ctx context.Context, token string) (domain.CalendarFeed, string, error) {
+	newToken, err := newCalendarToken()
+	if err != nil {
+		return domain.CalendarFeed{}, "", err
+	}
+
+	feed, err := cs.feedsRepo.RotateFeedToken(ctx, hashCalendarToken(token), hashCalendarToken(newToken))
+	if err != nil {
+		if errors.Is(err, domain.ErrCalendarFeedNotFound) {
+			return domain.CalendarFeed{}, "", err
+		}
+		return domain.CalendarFeed{}, "", fmt.Errorf("error rotating calendar feed: %w", err)
+	}
+	return feed, newToken, nil
+}
+
+func (cs CalendarService) DeleteFeed(ctx context.Context, token string) error {
+	if err := cs.feedsRepo.DeleteFeed(ctx, hashCalendarToken(token)); err != nil {
+		if errors.Is(err, domain.ErrCalendarFeedNotFound) {
+			return err
+		}
+		return fmt.Errorf("error deleting calendar feed: %w", err)
+	}
+	return nil
+}
+
+func newCalendarToken() (string, error) {
+	b := make([]byte, calendarTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating calendar feed token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,230 @@
+package uc
+
+import (
+	"api/domain"
+	mock "api/mocks/mock_domain"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func getCalendarFeed() domain.CalendarFeed {
+	return domain.CalendarFeed{
+		ID:        uuid.MustParse("5e0c1f53-5d0a-4c39-9d7e-1b1a8f0a7c11"),
+		Name:      "My tasks",
+		Status:    "PENDING",
+		CreatedAt: time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestHashCalendarToken(t *testing.T) {
+	sum := sha256.Sum256([]byte("secret"))
+	require.Equal(t, hex.EncodeToString(sum[:]), hashCalendarToken("secret"))
+}
+
+func TestCreateFeed(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoMock func(repoMock *mock.MockCalendarFeedsRepo, tokenHash *string)
+		checks   func(t *testing.T, feed domain.CalendarFeed, token, tokenHash string, err error)
+	}{
+		{
+			name: "happy path - OK",
+			repoMock: func(repoMock *mock.MockCalendarFeedsRepo, tokenHash *string) {
+				repoMock.EXPECT().CreateFeed(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, data domain.CalendarFeed, hash string) (domain.CalendarFeed, error) {
+						require.NotEqual(t, uuid.Nil, data.ID)
+						require.Equal(t, "My tasks", data.Name)
+						*tokenHash = hash
+						return getCalendarFeed(), nil
+					})
+			},
+			checks: func(t *testing.T, feed domain.CalendarFeed, token, tokenHash string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, getCalendarFeed(), feed)
+				require.Len(t, token, 43)
+				require.Equal(t, hashCalendarToken(token), tokenHash)
+			},
+		},
+		{
+			name: "error",
+			repoMock: func(repoMock *mock.MockCalendarFeedsRepo, tokenHash *string) {
+				repoMock.EXPECT().CreateFeed(gomock.Any(), gomock.Any(), gomock.Any()).Return(domain.CalendarFeed{}, errors.New("db error"))
+			},
+			checks: func(t *testing.T, feed domain.CalendarFeed, token, tokenHash string, err error) {
+				require.EqualError(t, err, "error creating calendar feed: db error")
+				require.Empty(t, token)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var tokenHash string
+			repoMock := mock.NewMockCalendarFeedsRepo(ctrl)
+			tt.repoMock(repoMock, &tokenHash)
+
+			service := NewCalendarService(repoMock)
+			feed, token, err := service.CreateFeed(context.Background(), domain.CalendarFeed{Name: "My tasks", Status: "PENDING"})
+			tt.checks(t, feed, token, tokenHash, err)
+		})
+	}
+}
+
+func TestGetFeed(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoMock func(repoMock *mock.MockCalendarFeedsRepo)
+		checks   func(t *testing.T, feed domain.CalendarFeed, err error)
+	}{
+		{
+			name: "happy path - OK",
+			repoMock: func(repoMock *mock.MockCalendarFeedsRepo) {
+				repoMock.EXPECT().GetFeedByTokenHash(gomock.Any(), hashCalendarToken("secret")).Return(getCalendarFeed(), nil)
+			},
+			checks: func(t *testing.T, feed domain.CalendarFeed, err error) {
+				require.NoError(t, err)
+				require.Equal(t, getCalendarFeed(), feed)
+			},
+		},
+		{
+			name: "not found",
+			repoMock: func(repoMock *mock.MockCalendarFeedsRepo) {
+				repoMock.EXPECT().GetFeedByTokenHash(gomock.Any(), gomock.Any()).Return(domain.CalendarFeed{}, domain.ErrCalendarFeedNotFound)
+			},
+			checks: func(t *testing.T, feed domain.CalendarFeed, err error) {
+				require.ErrorIs(t, err, domain.ErrCalendarFeedNotFound)
+			},
+		},
+		{
+			name: "error",
+			repoMock: func(repoMock *mock.MockCalendarFeedsRepo) {
+				repoMock.EXPECT().GetFeedByTokenHash(gomock.Any(), gomock.Any()).Return(domain.CalendarFeed{}, errors.New("db error"))
+			},
+			checks: func(t *testing.T, feed domain.CalendarFeed, err error) {
+				require.EqualError(t, err, "error fetching calendar feed: db error")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := mock.NewMockCalendarFeedsRepo(ctrl)
+			tt.repoMock(repoMock)
+
+			service := NewCalendarService(repoMock)
+			feed, err := service.GetFeed(context.Background(), "secret")
+			tt.checks(t, feed, err)
+		})
+	}
+}
+
+func TestRotateFeed(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoMock func(repoMock *mock.MockCalendarFeedsRepo, newTokenHash *string)
+		checks   func(t *testing.T, token, newTokenHash string, err error)
+	}{
+		{
+			name: "happy path - OK",
+			repoMock: func(repoMock *mock.MockCalendarFeedsRepo, newTokenHash *string) {
+				repoMock.EXPECT().RotateFeedToken(gomock.Any(), hashCalendarToken("secret"), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tokenHash, hash string) (domain.CalendarFeed, error) {
+						*newTokenHash = hash
+						return getCalendarFeed(), nil
+					})
+			},
+			checks: func(t *testing.T, token, newTokenHash string, err error) {
+				require.NoError(t, err)
+				require.NotEqual(t, "secret", token)
+				require.Equal(t, hashCalendarToken(token), newTokenHash)
+			},
+		},
+		{
+			name: "not found",
+			repoMock: func(repoMock *mock.MockCalendarFeedsRepo, newTokenHash *string) {
+				repoMock.EXPECT().RotateFeedToken(gomock.Any(), gomock.Any(), gomock.Any()).Return(domain.CalendarFeed{}, domain.ErrCalendarFeedNotFound)
+			},
+			checks: func(t *testing.T, token, newTokenHash string, err error) {
+				require.ErrorIs(t, err, domain.ErrCalendarFeedNotFound)
+				require.Empty(t, token)
+			},
+		},
+		{
+			name: "error",
+			repoMock: func(repoMock *mock.MockCalendarFeedsRepo, newTokenHash *string) {
+				repoMock.EXPECT().RotateFeedToken(gomock.Any(), gomock.Any(), gomock.Any()).Return(domain.CalendarFeed{}, errors.New("db error"))
+			},
+			checks: func(t *testing.T, token, newTokenHash string, err error) {
+				require.EqualError(t, err, "error rotating calendar feed: db error")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var newTokenHash string
+			repoMock := mock.NewMockCalendarFeedsRepo(ctrl)
+			tt.repoMock(repoMock, &newTokenHash)
+
+			service := NewCalendarService(repoMock)
+			_, token, err := service.RotateFeed(context.Background(), "secret")
+			tt.checks(t, token, newTokenHash, err)
+		})
+	}
+}
+
+func TestDeleteFeed(t *testing.T) {
+	tests := []struct {
+		name          string
+		repoErr       error
+		expectedError string
+	}{
+		{
+			name: "happy path - OK",
+		},
+		{
+			name:          "not found",
+			repoErr:       domain.ErrCalendarFeedNotFound,
+			expectedError: domain.ErrCalendarFeedNotFound.Error(),
+		},
+		{
+			name:          "error",
+			repoErr:       errors.New("db error"),
+			expectedError: "error deleting calendar feed: db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := mock.NewMockCalendarFeedsRepo(ctrl)
+			repoMock.EXPECT().DeleteFeed(gomock.Any(), hashCalendarToken("secret")).Return(tt.repoErr)
+
+			service := NewCalendarService(repoMock)
+			err := service.DeleteFeed(context.Background(), "secret")
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
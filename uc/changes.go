@@ -0,0 +1,137 @@
+package uc
+
+import (
+	"api/domain"
+	"context"
+	"github.com/google/uuid"
+	"sync"
+)
+
+const subscriberBufferSize = 64
+
+type TaskChangesUC interface {
+	Subscribe(ctx context.Context, lastEventId string, filter domain.TaskFilter) (<-chan domain.TaskChange, bool)
+}
+
+// TaskChangesHub fans out task changes to in-process subscribers and keeps the most
+// recent ones so that reconnecting clients can resume where they left off.
+type TaskChangesHub struct {
+	mu          sync.Mutex
+	replay      []domain.TaskChange
+	replaySize  int
+	subscribers map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	changes chan domain.TaskChange
+	filter  domain.TaskFilter
+	orgID   uuid.UUID
+}
+
+// deliverable returns change as the subscriber gets it, if it gets it at all. A task
+// updated so that it no longer matches the filter is announced as deleted, as the
+// subscriber only knows the tasks matching it.
+func (sub *subscriber) deliverable(change domain.TaskChange) (domain.TaskChange, bool) {
+	if change.OrgID != sub.orgID {
+		return domain.TaskChange{}, false
+	}
+	if sub.filter.Matches(change.Task) {
+		return change, true
+	}
+
+	previous := change.Task
+	previous.Status = change.PreviousStatus
+	if change.Type != domain.EventTaskUpdated || change.PreviousStatus == "" || !sub.filter.Matches(previous) {
+		return domain.TaskChange{}, false
+	}
+	change.Type = domain.EventTaskDeleted
+	change.Task = domain.Task{ID: change.Task.ID, Status: change.Task.Status}
+	return change, true
+}
+
+func NewTaskChangesHub(replaySize int) *TaskChangesHub {
+	return &TaskChangesHub{
+		replaySize:  replaySize,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// Publish delivers change to every matching subscriber. A subscriber whose buffer is
+// full is disconnected instead of blocking the others; it can resume from the replay
+// buffer once it reconnects.
+func (h *TaskChangesHub) Publish(change domain.TaskChange) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.replaySize > 0 {
+		if len(h.replay) == h.replaySize {
+			h.replay = append(h.replay[:0], h.replay[1:]...)
+		}
+		h.replay = append(h.replay, change)
+	}
+
+	for sub := range h.subscribers {
+		delivered, ok := sub.deliverable(change)
+		if !ok {
+			continue
+		}
+		select {
+		case sub.changes <- delivered:
+		default:
+			h.remove(sub)
+		}
+	}
+}
+
+// Subscribe returns a channel of the changes in the organization of ctx matching filter,
+// which is closed once ctx is done or the subscriber falls behind. When lastEventId is set, the buffered changes
+// after it are delivered first; the returned bool is false if lastEventId is no longer
+// in the buffer and the caller has to assume it missed changes.
+func (h *TaskChangesHub) Subscribe(ctx context.Context, lastEventId string, filter domain.TaskFilter) (<-chan domain.TaskChange, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	missed, resumed := h.since(lastEventId)
+	orgID, _ := domain.OrganizationFromContext(ctx)
+	sub := &subscriber{
+		changes: make(chan domain.TaskChange, len(missed)+subscriberBufferSize),
+		filter:  filter,
+		orgID:   orgID,
+	}
+	for _, change := range missed {
+		if delivered, ok := sub.deliverable(change); ok {
+			sub.changes <- delivered
+		}
+	}
+	h.subscribers[sub] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.remove(sub)
+	}()
+
+	return sub.changes, resumed
+}
+
+func (h *TaskChangesHub) since(lastEventId string) ([]domain.TaskChange, bool) {
+	if lastEventId == "" {
+		return nil, true
+	}
+	for i, change := range h.replay {
+		if change.ID == lastEventId {
+			return append([]domain.TaskChange(nil), h.replay[i+1:]...), true
+		}
+	}
+	return nil, false
+}
+
+// remove must be called with h.mu held.
+func (h *TaskChangesHub) remove(sub *subscriber) {
+	if _, ok := h.subscribers[sub]; !ok {
+		return
+	}
+	delete(h.subscribers, sub)
+	close(sub.changes)
+}
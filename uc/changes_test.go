@@ -0,0 +1,178 @@
+package uc
+
+import (
+	"api/domain"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"testing"
+)
+
+func getChange(seq int, status string) domain.TaskChange {
+	return domain.TaskChange{
+		ID:   strconv.Itoa(seq),
+		Type: domain.EventTaskCreated,
+		Task: domain.Task{ID: uuid.New(), Status: status},
+	}
+}
+
+func drain(changes <-chan domain.TaskChange) []string {
+	var ids []string
+	for {
+		select {
+		case change, ok := <-changes:
+			if !ok {
+				return ids
+			}
+			ids = append(ids, change.ID)
+		default:
+			return ids
+		}
+	}
+}
+
+func TestTaskChangesHub_Subscribe(t *testing.T) {
+	tests := []struct {
+		name            string
+		replaySize      int
+		before          []domain.TaskChange
+		after           []domain.TaskChange
+		lastEventId     string
+		filter          domain.TaskFilter
+		expectedIds     []string
+		expectedResumed bool
+	}{
+		{
+			name:            "live changes only",
+			replaySize:      10,
+			before:          []domain.TaskChange{getChange(1, "PENDING")},
+			after:           []domain.TaskChange{getChange(2, "PENDING"), getChange(3, "PENDING")},
+			expectedIds:     []string{"2", "3"},
+			expectedResumed: true,
+		},
+		{
+			name:            "resume from last event id",
+			replaySize:      10,
+			before:          []domain.TaskChange{getChange(1, "PENDING"), getChange(2, "PENDING"), getChange(3, "PENDING")},
+			after:           []domain.TaskChange{getChange(4, "PENDING")},
+			lastEventId:     "1",
+			expectedIds:     []string{"2", "3", "4"},
+			expectedResumed: true,
+		},
+		{
+			name:            "last event id evicted from buffer",
+			replaySize:      2,
+			before:          []domain.TaskChange{getChange(1, "PENDING"), getChange(2, "PENDING"), getChange(3, "PENDING")},
+			after:           []domain.TaskChange{getChange(4, "PENDING")},
+			lastEventId:     "1",
+			expectedIds:     []string{"4"},
+			expectedResumed: false,
+		},
+		{
+			name:            "filter by status",
+			replaySize:      10,
+			before:          []domain.TaskChange{getChange(1, "PENDING"), getChange(2, "DONE")},
+			after:           []domain.TaskChange{getChange(3, "DONE"), getChange(4, "PENDING")},
+			lastEventId:     "1",
+			filter:          domain.TaskFilter{Status: "DONE"},
+			expectedIds:     []string{"2", "3"},
+			expectedResumed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			hub := NewTaskChangesHub(tt.replaySize)
+			for _, change := range tt.before {
+				hub.Publish(change)
+			}
+
+			changes, resumed := hub.Subscribe(ctx, tt.lastEventId, tt.filter)
+			for _, change := range tt.after {
+				hub.Publish(change)
+			}
+
+			require.Equal(t, tt.expectedResumed, resumed)
+			require.Equal(t, tt.expectedIds, drain(changes))
+		})
+	}
+}
+
+func TestTaskChangesHub_SlowSubscriberDisconnected(t *testing.T) {
+	hub := NewTaskChangesHub(0)
+	changes, _ := hub.Subscribe(context.Background(), "", domain.TaskFilter{})
+
+	for i := 0; i <= subscriberBufferSize; i++ {
+		hub.Publish(getChange(i, "PENDING"))
+	}
+
+	require.Equal(t, subscriberBufferSize, len(drain(changes)))
+	_, ok := <-changes
+	require.False(t, ok)
+}
+
+func TestTaskChangesHub_UnsubscribeOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	hub := NewTaskChangesHub(0)
+	changes, _ := hub.Subscribe(ctx, "", domain.TaskFilter{})
+
+	cancel()
+	_, ok := <-changes
+	require.False(t, ok)
+}
+
+func TestTaskChangesHub_SeparatesOrganizations(t *testing.T) {
+	acme, globex := uuid.New(), uuid.New()
+	ctx, cancel := context.WithCancel(domain.WithOrganization(context.Background(), acme))
+	defer cancel()
+
+	publish := func(hub *TaskChangesHub, seq int, orgID uuid.UUID) {
+		change := getChange(seq, "PENDING")
+		change.OrgID = orgID
+		hub.Publish(change)
+	}
+
+	hub := NewTaskChangesHub(10)
+	publish(hub, 1, acme)
+	publish(hub, 2, globex)
+	publish(hub, 3, acme)
+
+	changes, resumed := hub.Subscribe(ctx, "1", domain.TaskFilter{})
+	publish(hub, 4, globex)
+	publish(hub, 5, acme)
+
+	require.True(t, resumed)
+	require.Equal(t, []string{"3", "5"}, drain(changes))
+}
+
+func TestTaskChangesHub_TaskLeavingFilter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	update := func(seq int, previousStatus, status string) domain.TaskChange {
+		change := getChange(seq, status)
+		change.Type = domain.EventTaskUpdated
+		change.PreviousStatus = previousStatus
+		return change
+	}
+
+	hub := NewTaskChangesHub(10)
+	hub.Publish(getChange(1, "PENDING"))
+	left := update(2, "PENDING", "DONE")
+	hub.Publish(left)
+
+	changes, _ := hub.Subscribe(ctx, "1", domain.TaskFilter{Status: "PENDING"})
+	hub.Publish(update(3, "DONE", "DONE"))
+	hub.Publish(update(4, "DONE", "PENDING"))
+
+	// The task which no longer matches the filter is announced as deleted.
+	change := <-changes
+	require.Equal(t, domain.EventTaskDeleted, change.Type)
+	require.Equal(t, left.Task.ID, change.Task.ID)
+	require.Equal(t, "2", change.ID)
+	require.Equal(t, []string{"4"}, drain(changes))
+}
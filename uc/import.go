@@ -0,0 +1,60 @@
+package uc
+
+import (
+	"api/domain"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+)
+
+const maxImportErrors = 1000
+
+// ImportTasks validates every task read from source with the same rules as CreateTask
+// and hands the valid ones to the repo as they are read. Tasks without an id get a
+// new one. Invalid rows are reported by line and do not stop the import.
+func (ts TasksService) ImportTasks(ctx context.Context, source domain.TaskSource, dryRun bool) (domain.ImportReport, error) {
+	report := domain.ImportReport{DryRun: dryRun, Errors: []domain.ImportError{}}
+	valid := 0
+
+	next := func() (domain.Task, error) {
+		for {
+			line, task, err := source.Next()
+			if errors.Is(err, io.EOF) {
+				return domain.Task{}, io.EOF
+			}
+			report.Total++
+			if err == nil {
+				err = task.Validate()
+				if task.ID == uuid.Nil {
+					task.ID = uuid.New()
+				}
+			} else if !errors.Is(err, domain.ErrInvalidImportRow) {
+				return domain.Task{}, err
+			}
+
+			if err != nil {
+				report.Failed++
+				if len(report.Errors) < maxImportErrors {
+					report.Errors = append(report.Errors, domain.ImportError{Line: line, Message: err.Error()})
+				}
+				continue
+			}
+			valid++
+			return task, nil
+		}
+	}
+
+	imported, err := ts.tasksRepo.ImportTasks(ctx, next, dryRun)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidImport) {
+			return domain.ImportReport{}, err
+		}
+		return domain.ImportReport{}, fmt.Errorf("error importing tasks: %w", err)
+	}
+
+	report.Imported = imported
+	report.Skipped = valid - imported
+	return report, nil
+}
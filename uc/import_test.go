@@ -0,0 +1,151 @@
+package uc
+
+import (
+	"api/domain"
+	mock "api/mocks/mock_domain"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"io"
+	"testing"
+)
+
+type sourceRow struct {
+	line int
+	task domain.Task
+	err  error
+}
+
+type fakeTaskSource struct {
+	rows []sourceRow
+}
+
+func (fs *fakeTaskSource) Next() (int, domain.Task, error) {
+	if len(fs.rows) == 0 {
+		return 0, domain.Task{}, io.EOF
+	}
+	row := fs.rows[0]
+	fs.rows = fs.rows[1:]
+	return row.line, row.task, row.err
+}
+
+func getImportRows() []sourceRow {
+	return []sourceRow{
+		{line: 2, task: getTask()},
+		{line: 3, task: domain.Task{Title: "No id"}},
+		{line: 4, err: fmt.Errorf("%w: invalid due_date", domain.ErrInvalidImportRow)},
+		{line: 5, task: domain.Task{Title: "Standup", RRule: "FREQ=HOURLY"}},
+		{line: 6, task: domain.Task{}},
+	}
+}
+
+// drainImport makes the repo mock consume every task and report imported of them as new.
+func drainImport(repoMock *mock.MockTasksRepo, dryRun bool, imported int, tasks *[]domain.Task) {
+	repoMock.EXPECT().ImportTasks(gomock.Any(), gomock.Any(), dryRun).DoAndReturn(
+		func(ctx context.Context, next func() (domain.Task, error), dryRun bool) (int, error) {
+			for {
+				task, err := next()
+				if errors.Is(err, io.EOF) {
+					return imported, nil
+				}
+				if err != nil {
+					return 0, err
+				}
+				*tasks = append(*tasks, task)
+			}
+		})
+}
+
+func TestImportTasks(t *testing.T) {
+	var tasks []domain.Task
+
+	tests := []struct {
+		name     string
+		rows     []sourceRow
+		dryRun   bool
+		repoMock func(repoMock *mock.MockTasksRepo)
+		expected domain.ImportReport
+		checks   func(t *testing.T, err error)
+	}{
+		{
+			name: "happy path - OK",
+			rows: getImportRows(),
+			repoMock: func(repoMock *mock.MockTasksRepo) {
+				drainImport(repoMock, false, 1, &tasks)
+			},
+			expected: domain.ImportReport{
+				Total:    5,
+				Imported: 1,
+				Skipped:  1,
+				Failed:   3,
+				Errors: []domain.ImportError{
+					{Line: 4, Message: "invalid row: invalid due_date"},
+					{Line: 5, Message: "invalid recurrence: unsupported FREQ HOURLY"},
+					{Line: 6, Message: "invalid task"},
+				},
+			},
+			checks: func(t *testing.T, err error) {
+				require.NoError(t, err)
+				require.Len(t, tasks, 2)
+				require.Equal(t, getTask(), tasks[0])
+				require.NotEqual(t, uuid.Nil, tasks[1].ID)
+			},
+		},
+		{
+			name:   "dry run",
+			rows:   getImportRows()[:2],
+			dryRun: true,
+			repoMock: func(repoMock *mock.MockTasksRepo) {
+				drainImport(repoMock, true, 2, &tasks)
+			},
+			expected: domain.ImportReport{DryRun: true, Total: 2, Imported: 2, Errors: []domain.ImportError{}},
+			checks: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "unreadable input",
+			rows: []sourceRow{{line: 1, task: getTask()}, {line: 2, err: fmt.Errorf("%w: line too long", domain.ErrInvalidImport)}},
+			repoMock: func(repoMock *mock.MockTasksRepo) {
+				drainImport(repoMock, false, 0, &tasks)
+			},
+			checks: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrInvalidImport)
+			},
+		},
+		{
+			name: "db error",
+			rows: getImportRows(),
+			repoMock: func(repoMock *mock.MockTasksRepo) {
+				repoMock.EXPECT().ImportTasks(gomock.Any(), gomock.Any(), false).Return(0, errors.New("db error"))
+			},
+			checks: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "error importing tasks")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			tasks = nil
+
+			repo := mock.NewMockTasksRepo(ctrl)
+			service := NewTasksService(repo)
+
+			if tt.repoMock != nil {
+				tt.repoMock(repo)
+			}
+
+			report, err := service.ImportTasks(context.Background(), &fakeTaskSource{rows: tt.rows}, tt.dryRun)
+			tt.checks(t, err)
+			if err == nil {
+				require.Equal(t, tt.expected, report)
+			}
+		})
+	}
+}
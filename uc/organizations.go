@@ -0,0 +1,50 @@
+package uc
+
+import (
+	"api/domain"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"strings"
+)
+
+type OrganizationsUC interface {
+	GetOrganizationBySlug(ctx context.Context, slug string) (domain.Organization, error)
+	CreateOrganization(ctx context.Context, data domain.Organization) (domain.Organization, error)
+}
+
+type OrganizationsService struct {
+	organizationsRepo domain.OrganizationsRepo
+}
+
+func NewOrganizationsService(organizationsRepo domain.OrganizationsRepo) *OrganizationsService {
+	return &OrganizationsService{organizationsRepo: organizationsRepo}
+}
+
+func (orgs OrganizationsService) GetOrganizationBySlug(ctx context.Context, slug string) (domain.Organization, error) {
+	org, err := orgs.organizationsRepo.GetOrganizationBySlug(ctx, strings.ToLower(slug))
+	if err != nil {
+		if errors.Is(err, domain.ErrOrganizationNotFound) {
+			return domain.Organization{}, err
+		}
+		return domain.Organization{}, fmt.Errorf("error fetching organization: %w", err)
+	}
+	return org, nil
+}
+
+func (orgs OrganizationsService) CreateOrganization(ctx context.Context, data domain.Organization) (domain.Organization, error) {
+	if err := data.Validate(); err != nil {
+		return domain.Organization{}, err
+	}
+
+	data.ID = uuid.New()
+	org, err := orgs.organizationsRepo.CreateOrganization(ctx, data)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrganizationExists) {
+			return domain.Organization{}, err
+		}
+		return domain.Organization{}, fmt.Errorf("error creating organization: %w", err)
+	}
+	return org, nil
+}
@@ -0,0 +1,164 @@
+package uc
+
+import (
+	"api/domain"
+	mock "api/mocks/mock_domain"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func getOrganization() domain.Organization {
+	return domain.Organization{
+		ID:        uuid.MustParse("7d4b1c9e-2f1a-4c3b-8e5d-6a7f8b9c0d1e"),
+		Slug:      "acme",
+		Name:      "Acme",
+		CreatedAt: time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestGetOrganizationBySlug(t *testing.T) {
+	tests := []struct {
+		name     string
+		slug     string
+		repoMock func(repoMock *mock.MockOrganizationsRepo)
+		checks   func(t *testing.T, org domain.Organization, err error)
+	}{
+		{
+			name: "happy path - OK",
+			slug: "acme",
+			repoMock: func(repoMock *mock.MockOrganizationsRepo) {
+				repoMock.EXPECT().GetOrganizationBySlug(gomock.Any(), "acme").Return(getOrganization(), nil)
+			},
+			checks: func(t *testing.T, org domain.Organization, err error) {
+				require.NoError(t, err)
+				require.Equal(t, getOrganization(), org)
+			},
+		},
+		{
+			name: "slugs are case insensitive",
+			slug: "ACME",
+			repoMock: func(repoMock *mock.MockOrganizationsRepo) {
+				repoMock.EXPECT().GetOrganizationBySlug(gomock.Any(), "acme").Return(getOrganization(), nil)
+			},
+			checks: func(t *testing.T, org domain.Organization, err error) {
+				require.NoError(t, err)
+				require.Equal(t, getOrganization(), org)
+			},
+		},
+		{
+			name: "not found",
+			slug: "acme",
+			repoMock: func(repoMock *mock.MockOrganizationsRepo) {
+				repoMock.EXPECT().GetOrganizationBySlug(gomock.Any(), gomock.Any()).Return(domain.Organization{}, domain.ErrOrganizationNotFound)
+			},
+			checks: func(t *testing.T, org domain.Organization, err error) {
+				require.ErrorIs(t, err, domain.ErrOrganizationNotFound)
+			},
+		},
+		{
+			name: "error",
+			slug: "acme",
+			repoMock: func(repoMock *mock.MockOrganizationsRepo) {
+				repoMock.EXPECT().GetOrganizationBySlug(gomock.Any(), gomock.Any()).Return(domain.Organization{}, errors.New("db error"))
+			},
+			checks: func(t *testing.T, org domain.Organization, err error) {
+				require.EqualError(t, err, "error fetching organization: db error")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := mock.NewMockOrganizationsRepo(ctrl)
+			tt.repoMock(repoMock)
+
+			service := NewOrganizationsService(repoMock)
+			org, err := service.GetOrganizationBySlug(context.Background(), tt.slug)
+			tt.checks(t, org, err)
+		})
+	}
+}
+
+func TestCreateOrganization(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     domain.Organization
+		repoMock func(repoMock *mock.MockOrganizationsRepo)
+		checks   func(t *testing.T, org domain.Organization, err error)
+	}{
+		{
+			name: "happy path - OK",
+			data: domain.Organization{Slug: "acme", Name: "Acme"},
+			repoMock: func(repoMock *mock.MockOrganizationsRepo) {
+				repoMock.EXPECT().CreateOrganization(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, data domain.Organization) (domain.Organization, error) {
+						require.NotEqual(t, uuid.Nil, data.ID)
+						require.Equal(t, "acme", data.Slug)
+						return getOrganization(), nil
+					})
+			},
+			checks: func(t *testing.T, org domain.Organization, err error) {
+				require.NoError(t, err)
+				require.Equal(t, getOrganization(), org)
+			},
+		},
+		{
+			name:     "invalid slug",
+			data:     domain.Organization{Slug: "Acme Inc", Name: "Acme"},
+			repoMock: func(repoMock *mock.MockOrganizationsRepo) {},
+			checks: func(t *testing.T, org domain.Organization, err error) {
+				require.ErrorIs(t, err, domain.ErrInvalidOrganization)
+			},
+		},
+		{
+			name:     "missing name",
+			data:     domain.Organization{Slug: "acme", Name: " "},
+			repoMock: func(repoMock *mock.MockOrganizationsRepo) {},
+			checks: func(t *testing.T, org domain.Organization, err error) {
+				require.ErrorIs(t, err, domain.ErrInvalidOrganization)
+			},
+		},
+		{
+			name: "already exists",
+			data: domain.Organization{Slug: "acme", Name: "Acme"},
+			repoMock: func(repoMock *mock.MockOrganizationsRepo) {
+				repoMock.EXPECT().CreateOrganization(gomock.Any(), gomock.Any()).Return(domain.Organization{}, domain.ErrOrganizationExists)
+			},
+			checks: func(t *testing.T, org domain.Organization, err error) {
+				require.ErrorIs(t, err, domain.ErrOrganizationExists)
+			},
+		},
+		{
+			name: "error",
+			data: domain.Organization{Slug: "acme", Name: "Acme"},
+			repoMock: func(repoMock *mock.MockOrganizationsRepo) {
+				repoMock.EXPECT().CreateOrganization(gomock.Any(), gomock.Any()).Return(domain.Organization{}, errors.New("db error"))
+			},
+			checks: func(t *testing.T, org domain.Organization, err error) {
+				require.EqualError(t, err, "error creating organization: db error")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repoMock := mock.NewMockOrganizationsRepo(ctrl)
+			tt.repoMock(repoMock)
+
+			service := NewOrganizationsService(repoMock)
+			org, err := service.CreateOrganization(context.Background(), tt.data)
+			tt.checks(t, org, err)
+		})
+	}
+}
@@ -6,12 +6,28 @@ import (
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"reflect"
+	"time"
 )
 
 type TasksUC interface {
 	GetTaskById(ctx context.Context, id uuid.UUID) (domain.Task, error)
-	GetTasks(ctx context.Context) ([]domain.Task, error)
+	GetTasks(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error)
+	GetTasksPage(ctx context.Context, filter domain.TaskFilter, after *domain.TaskCursor, limit int) ([]domain.Task, error)
+	CountTasks(ctx context.Context, filter domain.TaskFilter) (int, error)
+	GetTasksByIds(ctx context.Context, ids []uuid.UUID) ([]domain.Task, error)
+	GetTaskEvents(ctx context.Context, taskIDs []uuid.UUID) ([]domain.Event, error)
 	CreateTask(ctx context.Context, data domain.Task) (domain.Task, error)
+	UpdateTask(ctx context.Context, data domain.Task) (domain.Task, error)
+	GetOccurrences(ctx context.Context, id uuid.UUID, limit int) ([]time.Time, error)
+	SearchTasks(ctx context.Context, query string, limit int) ([]domain.TaskSearchResult, error)
+	DeleteTask(ctx context.Context, id uuid.UUID) (domain.Task, error)
+	GetTrash(ctx context.Context) ([]domain.Task, error)
+	RestoreTask(ctx context.Context, id uuid.UUID) (domain.Task, error)
+	PurgeTask(ctx context.Context, id uuid.UUID) error
+	BulkTasks(ctx context.Context, ops []domain.BulkOperation, atomic bool) ([]domain.BulkResult, error)
+	ImportTasks(ctx context.Context, source domain.TaskSource, dryRun bool) (domain.ImportReport, error)
+	ExportTasks(ctx context.Context, filter domain.TaskFilter, fn func(task domain.Task) error) error
 }
 
 type TasksService struct {
@@ -28,23 +44,303 @@ func (ts TasksService) GetTaskById(ctx context.Context, id uuid.UUID) (domain.Ta
 		if errors.Is(err, domain.ErrTaskNotFound) {
 			return domain.Task{}, err
 		}
-		return domain.Task{}, fmt.Errorf("error fetching task: %v", err)
+		return domain.Task{}, fmt.Errorf("error fetching task: %w", err)
 	}
 	return task, nil
 }
 
-func (ts TasksService) GetTasks(ctx context.Context) ([]domain.Task, error) {
-	task, err := ts.tasksRepo.GetTasks(ctx)
+func (ts TasksService) GetTasks(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
+	task, err := ts.tasksRepo.GetTasks(ctx, filter)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching task: %v", err)
+		return nil, fmt.Errorf("error fetching task: %w", err)
 	}
 	return task, nil
 }
 
+// GetTasksPage returns up to limit tasks matching filter which come after the cursor,
+// oldest first.
+func (ts TasksService) GetTasksPage(ctx context.Context, filter domain.TaskFilter, after *domain.TaskCursor, limit int) ([]domain.Task, error) {
+	tasks, err := ts.tasksRepo.GetTasksPage(ctx, filter, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+func (ts TasksService) CountTasks(ctx context.Context, filter domain.TaskFilter) (int, error) {
+	count, err := ts.tasksRepo.CountTasks(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("error counting tasks: %w", err)
+	}
+	return count, nil
+}
+
+func (ts TasksService) GetTasksByIds(ctx context.Context, ids []uuid.UUID) ([]domain.Task, error) {
+	tasks, err := ts.tasksRepo.GetTasksByIds(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+func (ts TasksService) GetTaskEvents(ctx context.Context, taskIDs []uuid.UUID) ([]domain.Event, error) {
+	events, err := ts.tasksRepo.GetTaskEvents(ctx, taskIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching task events: %w", err)
+	}
+	return events, nil
+}
+
 func (ts TasksService) CreateTask(ctx context.Context, data domain.Task) (domain.Task, error) {
+	if err := data.Validate(); err != nil {
+		return domain.Task{}, err
+	}
+
 	task, err := ts.tasksRepo.CreateTask(ctx, data)
 	if err != nil {
-		return domain.Task{}, fmt.Errorf("error creating task: %v", err)
+		return domain.Task{}, fmt.Errorf("error creating task: %w", err)
 	}
 	return task, nil
 }
+
+// UpdateTask saves data and, when it completes an occurrence of a recurring task,
+// creates the task for the next occurrence of the series. The completion and the next
+// occurrence are saved in a single transaction which locks the task, so that the series
+// is never cut short and concurrent completions create a single next occurrence.
+func (ts TasksService) UpdateTask(ctx context.Context, data domain.Task) (domain.Task, error) {
+	if err := validateRecurrence(data); err != nil {
+		return domain.Task{}, err
+	}
+	if !data.IsRecurring() || data.Status != domain.StatusDone {
+		return ts.updateTask(ctx, data)
+	}
+
+	var task domain.Task
+	err := ts.tasksRepo.InTx(ctx, func(repo domain.TasksRepo) error {
+		txService := TasksService{tasksRepo: repo}
+		previous, err := repo.GetTaskByIdForUpdate(ctx, data.ID)
+		if err != nil {
+			if errors.Is(err, domain.ErrTaskNotFound) {
+				return err
+			}
+			return fmt.Errorf("error fetching task: %w", err)
+		}
+
+		task, err = txService.updateTask(ctx, data)
+		if err != nil {
+			return err
+		}
+		if previous.Status == domain.StatusDone {
+			return nil
+		}
+		return txService.createNextOccurrence(ctx, task)
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return task, nil
+}
+
+func (ts TasksService) updateTask(ctx context.Context, data domain.Task) (domain.Task, error) {
+	task, err := ts.tasksRepo.UpdateTask(ctx, data)
+	if err != nil {
+		if errors.Is(err, domain.ErrTaskNotFound) {
+			return domain.Task{}, err
+		}
+		return domain.Task{}, fmt.Errorf("error updating task: %w", err)
+	}
+	return task, nil
+}
+
+func (ts TasksService) DeleteTask(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	task, err := ts.tasksRepo.DeleteTask(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrTaskNotFound) {
+			return domain.Task{}, err
+		}
+		return domain.Task{}, fmt.Errorf("error deleting task: %w", err)
+	}
+	return task, nil
+}
+
+func (ts TasksService) GetTrash(ctx context.Context) ([]domain.Task, error) {
+	tasks, err := ts.tasksRepo.GetDeletedTasks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching trash: %w", err)
+	}
+	return tasks, nil
+}
+
+func (ts TasksService) RestoreTask(ctx context.Context, id uuid.UUID) (domain.Task, error) {
+	task, err := ts.tasksRepo.RestoreTask(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrTaskNotFound) {
+			return domain.Task{}, err
+		}
+		return domain.Task{}, fmt.Errorf("error restoring task: %w", err)
+	}
+	return task, nil
+}
+
+func (ts TasksService) PurgeTask(ctx context.Context, id uuid.UUID) error {
+	if err := ts.tasksRepo.PurgeTask(ctx, id); err != nil {
+		if errors.Is(err, domain.ErrTaskNotFound) {
+			return err
+		}
+		return fmt.Errorf("error purging task: %w", err)
+	}
+	return nil
+}
+
+// ExportTasks streams the tasks matching filter to fn, oldest first.
+func (ts TasksService) ExportTasks(ctx context.Context, filter domain.TaskFilter, fn func(task domain.Task) error) error {
+	if err := ts.tasksRepo.ExportTasks(ctx, filter, fn); err != nil {
+		return fmt.Errorf("error exporting tasks: %w", err)
+	}
+	return nil
+}
+
+// BulkTasks applies ops in order. In atomic mode they run in a single transaction which
+// is rolled back as soon as one of them fails; otherwise every operation is committed
+// on its own and a failure does not affect the others.
+func (ts TasksService) BulkTasks(ctx context.Context, ops []domain.BulkOperation, atomic bool) ([]domain.BulkResult, error) {
+	results := make([]domain.BulkResult, len(ops))
+	if !atomic {
+		for i, op := range ops {
+			results[i] = ts.applyBulkOperation(ctx, op)
+		}
+		return results, nil
+	}
+
+	failed := -1
+	err := ts.tasksRepo.InTx(ctx, func(repo domain.TasksRepo) error {
+		txService := TasksService{tasksRepo: repo}
+		for i, op := range ops {
+			results[i] = txService.applyBulkOperation(ctx, op)
+			if results[i].Err != nil {
+				failed = i
+				return results[i].Err
+			}
+		}
+		return nil
+	})
+	if failed >= 0 {
+		for i := range results {
+			if i != failed {
+				results[i] = domain.BulkResult{Err: domain.ErrBulkRolledBack}
+			}
+		}
+		return results, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error applying bulk operations: %w", err)
+	}
+	return results, nil
+}
+
+func (ts TasksService) applyBulkOperation(ctx context.Context, op domain.BulkOperation) domain.BulkResult {
+	var task domain.Task
+	var err error
+
+	switch op.Op {
+	case domain.BulkOpCreate:
+		if op.Task == nil || reflect.DeepEqual(*op.Task, domain.Task{}) {
+			return domain.BulkResult{Err: fmt.Errorf("%w: create requires a task", domain.ErrInvalidBulkOperation)}
+		}
+		data := *op.Task
+		if data.ID == uuid.Nil {
+			data.ID = op.ID
+		}
+		task, err = ts.CreateTask(ctx, data)
+	case domain.BulkOpUpdate:
+		if op.ID == uuid.Nil || op.Task == nil {
+			return domain.BulkResult{Err: fmt.Errorf("%w: update requires an id and a task", domain.ErrInvalidBulkOperation)}
+		}
+		data := *op.Task
+		data.ID = op.ID
+		task, err = ts.UpdateTask(ctx, data)
+	case domain.BulkOpDelete:
+		if op.ID == uuid.Nil {
+			return domain.BulkResult{Err: fmt.Errorf("%w: delete requires an id", domain.ErrInvalidBulkOperation)}
+		}
+		task, err = ts.DeleteTask(ctx, op.ID)
+	case domain.BulkOpStatus:
+		if op.ID == uuid.Nil || op.Status == "" {
+			return domain.BulkResult{Err: fmt.Errorf("%w: status requires an id and a status", domain.ErrInvalidBulkOperation)}
+		}
+		task, err = ts.GetTaskById(domain.WithReadYourWrites(ctx), op.ID)
+		if err == nil {
+			task.Status = op.Status
+			task, err = ts.UpdateTask(ctx, task)
+		}
+	default:
+		return domain.BulkResult{Err: fmt.Errorf("%w: unknown op %q", domain.ErrInvalidBulkOperation, op.Op)}
+	}
+
+	if err != nil {
+		return domain.BulkResult{Err: err}
+	}
+	return domain.BulkResult{Task: &task}
+}
+
+func (ts TasksService) createNextOccurrence(ctx context.Context, task domain.Task) error {
+	rule, loc, err := task.Recurrence()
+	if err != nil {
+		return err
+	}
+
+	nextDueDate, nextRule, ok := rule.Next(task.DueDate.In(loc))
+	if !ok {
+		return nil
+	}
+
+	_, err = ts.tasksRepo.CreateTask(ctx, domain.Task{
+		ID:          uuid.New(),
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      domain.StatusPending,
+		DueDate:     nextDueDate.UTC(),
+		RRule:       nextRule.String(),
+		Timezone:    task.Timezone,
+	})
+	if err != nil {
+		return fmt.Errorf("error creating next occurrence: %w", err)
+	}
+	return nil
+}
+
+// GetOccurrences previews up to limit occurrences of a recurring task following its
+// current due date.
+func (ts TasksService) GetOccurrences(ctx context.Context, id uuid.UUID, limit int) ([]time.Time, error) {
+	task, err := ts.GetTaskById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return task.Occurrences(limit)
+}
+
+// SearchTasks runs a full-text search and falls back to trigram similarity when it has
+// no hits, so that misspelled queries still find something.
+func (ts TasksService) SearchTasks(ctx context.Context, query string, limit int) ([]domain.TaskSearchResult, error) {
+	results, err := ts.tasksRepo.SearchTasks(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error searching tasks: %w", err)
+	}
+	if len(results) > 0 {
+		return results, nil
+	}
+
+	results, err = ts.tasksRepo.SearchTasksBySimilarity(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error searching tasks: %w", err)
+	}
+	return results, nil
+}
+
+func validateRecurrence(data domain.Task) error {
+	if !data.IsRecurring() {
+		return nil
+	}
+	_, _, err := data.Recurrence()
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	mock "api/mocks/mock_domain"
 	"context"
 	"errors"
+	"fmt"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -84,6 +85,16 @@ func TestGetTaskById(t *testing.T) {
 				require.EqualError(t, err, "error fetching task: not found")
 			},
 		},
+		{
+			name: "storage unavailable",
+			id:   "1461ec84-ccff-4f3c-af34-65d0856ac3ce",
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().GetTaskById(gomock.Any(), gomock.Any()).Return(domain.Task{}, fmt.Errorf("failed to get task: %w", domain.ErrStorageUnavailable))
+			},
+			checks: func(t *testing.T, expected, result domain.Task, err error) {
+				require.ErrorIs(t, err, domain.ErrStorageUnavailable)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -114,7 +125,7 @@ func TestGetTasks(t *testing.T) {
 		{
 			name: "happy path - OK",
 			repoMock: func(repoMock mock.MockTasksRepo) {
-				repoMock.EXPECT().GetTasks(gomock.Any()).Return(getTasksList(), nil)
+				repoMock.EXPECT().GetTasks(gomock.Any(), gomock.Any()).Return(getTasksList(), nil)
 			},
 			expectedResult: getTasksList(),
 			checks: func(t *testing.T, expected, result []domain.Task, err error) {
@@ -125,7 +136,7 @@ func TestGetTasks(t *testing.T) {
 		{
 			name: "error",
 			repoMock: func(repoMock mock.MockTasksRepo) {
-				repoMock.EXPECT().GetTasks(gomock.Any()).Return(nil, errors.New("not found"))
+				repoMock.EXPECT().GetTasks(gomock.Any(), gomock.Any()).Return(nil, errors.New("not found"))
 			},
 			checks: func(t *testing.T, expected, result []domain.Task, err error) {
 				require.EqualError(t, err, "error fetching task: not found")
@@ -145,12 +156,101 @@ func TestGetTasks(t *testing.T) {
 				tt.repoMock(*repo)
 			}
 
-			result, err := service.GetTasks(context.Background())
+			result, err := service.GetTasks(context.Background(), domain.TaskFilter{})
 			tt.checks(t, tt.expectedResult, result, err)
 		})
 	}
 }
 
+func TestGetTasksByIds(t *testing.T) {
+	ids := []uuid.UUID{getTasksList()[0].ID, getTasksList()[1].ID}
+
+	tests := []struct {
+		name     string
+		repoMock func(repoMock mock.MockTasksRepo)
+		checks   func(t *testing.T, result []domain.Task, err error)
+	}{
+		{
+			name: "happy path - OK",
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().GetTasksByIds(gomock.Any(), gomock.Eq(ids)).Return(getTasksList(), nil)
+			},
+			checks: func(t *testing.T, result []domain.Task, err error) {
+				require.NoError(t, err)
+				require.Equal(t, getTasksList(), result)
+			},
+		},
+		{
+			name: "error",
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().GetTasksByIds(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused"))
+			},
+			checks: func(t *testing.T, result []domain.Task, err error) {
+				require.EqualError(t, err, "error fetching tasks: connection refused")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock.NewMockTasksRepo(ctrl)
+			service := NewTasksService(repo)
+			tt.repoMock(*repo)
+
+			result, err := service.GetTasksByIds(context.Background(), ids)
+			tt.checks(t, result, err)
+		})
+	}
+}
+
+func TestGetTaskEvents(t *testing.T) {
+	ids := []uuid.UUID{getTasksList()[0].ID}
+	events := []domain.Event{{ID: uuid.New(), Type: domain.EventTaskCreated, AggregateID: ids[0]}}
+
+	tests := []struct {
+		name     string
+		repoMock func(repoMock mock.MockTasksRepo)
+		checks   func(t *testing.T, result []domain.Event, err error)
+	}{
+		{
+			name: "happy path - OK",
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().GetTaskEvents(gomock.Any(), gomock.Eq(ids)).Return(events, nil)
+			},
+			checks: func(t *testing.T, result []domain.Event, err error) {
+				require.NoError(t, err)
+				require.Equal(t, events, result)
+			},
+		},
+		{
+			name: "error",
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().GetTaskEvents(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused"))
+			},
+			checks: func(t *testing.T, result []domain.Event, err error) {
+				require.EqualError(t, err, "error fetching task events: connection refused")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock.NewMockTasksRepo(ctrl)
+			service := NewTasksService(repo)
+			tt.repoMock(*repo)
+
+			result, err := service.GetTaskEvents(context.Background(), ids)
+			tt.checks(t, result, err)
+		})
+	}
+}
+
 func TestCreateTask(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -200,3 +300,569 @@ func TestCreateTask(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateTask(t *testing.T) {
+	tests := []struct {
+		name           string
+		data           domain.Task
+		repoMock       func(repoMock mock.MockTasksRepo)
+		expectedResult domain.Task
+		checks         func(t *testing.T, expected, result domain.Task, err error)
+	}{
+		{
+			name: "happy path - OK",
+			data: getTask(),
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().UpdateTask(gomock.Any(), gomock.Eq(getTask())).Return(getTask(), nil)
+			},
+			expectedResult: getTask(),
+			checks: func(t *testing.T, expected, result domain.Task, err error) {
+				require.NoError(t, err)
+				require.Equal(t, expected.ID, result.ID)
+			},
+		},
+		{
+			name: "no task found",
+			data: getTask(),
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().UpdateTask(gomock.Any(), gomock.Eq(getTask())).Return(domain.Task{}, domain.ErrTaskNotFound)
+			},
+			checks: func(t *testing.T, expected, result domain.Task, err error) {
+				require.EqualError(t, err, domain.ErrTaskNotFound.Error())
+			},
+		},
+		{
+			name: "error",
+			data: getTask(),
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().UpdateTask(gomock.Any(), gomock.Eq(getTask())).Return(domain.Task{}, errors.New("connection refused"))
+			},
+			checks: func(t *testing.T, expected, result domain.Task, err error) {
+				require.EqualError(t, err, "error updating task: connection refused")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock.NewMockTasksRepo(ctrl)
+			service := NewTasksService(repo)
+
+			if tt.repoMock != nil {
+				tt.repoMock(*repo)
+			}
+
+			result, err := service.UpdateTask(context.Background(), tt.data)
+			tt.checks(t, tt.expectedResult, result, err)
+		})
+	}
+}
+
+func getRecurringTask(status string) domain.Task {
+	task := getTask()
+	task.Status = status
+	task.DueDate = time.Date(2025, 1, 31, 8, 0, 0, 0, time.UTC)
+	task.RRule = "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3"
+	task.Timezone = "Europe/Sofia"
+	return task
+}
+
+func TestUpdateTask_Recurring(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     domain.Task
+		repoMock func(repoMock *mock.MockTasksRepo)
+		checks   func(t *testing.T, result domain.Task, err error)
+	}{
+		{
+			name: "completing an occurrence creates the next one",
+			data: getRecurringTask(domain.StatusDone),
+			repoMock: func(repoMock *mock.MockTasksRepo) {
+				runInTx(repoMock, nil)
+				repoMock.EXPECT().GetTaskByIdForUpdate(gomock.Any(), gomock.Eq(getTask().ID)).Return(getRecurringTask(domain.StatusPending), nil)
+				repoMock.EXPECT().UpdateTask(gomock.Any(), gomock.Eq(getRecurringTask(domain.StatusDone))).Return(getRecurringTask(domain.StatusDone), nil)
+				repoMock.EXPECT().CreateTask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, next domain.Task) (domain.Task, error) {
+					require.NotEqual(t, getTask().ID, next.ID)
+					require.Equal(t, domain.StatusPending, next.Status)
+					require.Equal(t, time.Date(2025, 2, 28, 8, 0, 0, 0, time.UTC), next.DueDate)
+					require.Equal(t, "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=2", next.RRule)
+					require.Equal(t, "Europe/Sofia", next.Timezone)
+					return next, nil
+				})
+			},
+			checks: func(t *testing.T, result domain.Task, err error) {
+				require.NoError(t, err)
+				require.Equal(t, domain.StatusDone, result.Status)
+			},
+		},
+		{
+			name: "already completed occurrence is not repeated",
+			data: getRecurringTask(domain.StatusDone),
+			repoMock: func(repoMock *mock.MockTasksRepo) {
+				runInTx(repoMock, nil)
+				repoMock.EXPECT().GetTaskByIdForUpdate(gomock.Any(), gomock.Eq(getTask().ID)).Return(getRecurringTask(domain.StatusDone), nil)
+				repoMock.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Return(getRecurringTask(domain.StatusDone), nil)
+			},
+			checks: func(t *testing.T, result domain.Task, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "last occurrence of the series",
+			data: func() domain.Task {
+				task := getRecurringTask(domain.StatusDone)
+				task.RRule = "FREQ=MONTHLY;COUNT=1"
+				return task
+			}(),
+			repoMock: func(repoMock *mock.MockTasksRepo) {
+				runInTx(repoMock, nil)
+				repoMock.EXPECT().GetTaskByIdForUpdate(gomock.Any(), gomock.Any()).Return(getRecurringTask(domain.StatusPending), nil)
+				repoMock.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, data domain.Task) (domain.Task, error) {
+					return data, nil
+				})
+			},
+			checks: func(t *testing.T, result domain.Task, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "failing to create the next occurrence fails the completion",
+			data: getRecurringTask(domain.StatusDone),
+			repoMock: func(repoMock *mock.MockTasksRepo) {
+				runInTx(repoMock, nil)
+				repoMock.EXPECT().GetTaskByIdForUpdate(gomock.Any(), gomock.Eq(getTask().ID)).Return(getRecurringTask(domain.StatusPending), nil)
+				repoMock.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Return(getRecurringTask(domain.StatusDone), nil)
+				repoMock.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Return(domain.Task{}, errors.New("insert failed"))
+			},
+			checks: func(t *testing.T, result domain.Task, err error) {
+				require.ErrorContains(t, err, "error creating next occurrence")
+			},
+		},
+		{
+			name: "not found",
+			data: getRecurringTask(domain.StatusDone),
+			repoMock: func(repoMock *mock.MockTasksRepo) {
+				runInTx(repoMock, nil)
+				repoMock.EXPECT().GetTaskByIdForUpdate(gomock.Any(), gomock.Eq(getTask().ID)).Return(domain.Task{}, domain.ErrTaskNotFound)
+			},
+			checks: func(t *testing.T, result domain.Task, err error) {
+				require.ErrorIs(t, err, domain.ErrTaskNotFound)
+			},
+		},
+		{
+			name: "invalid rrule",
+			data: func() domain.Task {
+				task := getRecurringTask(domain.StatusDone)
+				task.RRule = "FREQ=SECONDLY"
+				return task
+			}(),
+			checks: func(t *testing.T, result domain.Task, err error) {
+				require.ErrorIs(t, err, domain.ErrInvalidRecurrence)
+			},
+		},
+		{
+			name: "invalid timezone",
+			data: func() domain.Task {
+				task := getRecurringTask(domain.StatusDone)
+				task.Timezone = "Mars/Olympus_Mons"
+				return task
+			}(),
+			checks: func(t *testing.T, result domain.Task, err error) {
+				require.ErrorIs(t, err, domain.ErrInvalidRecurrence)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock.NewMockTasksRepo(ctrl)
+			service := NewTasksService(repo)
+
+			if tt.repoMock != nil {
+				tt.repoMock(repo)
+			}
+
+			result, err := service.UpdateTask(context.Background(), tt.data)
+			tt.checks(t, result, err)
+		})
+	}
+}
+
+func TestCreateTask_InvalidRecurrence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewTasksService(mock.NewMockTasksRepo(ctrl))
+
+	task := getTask()
+	task.RRule = "FREQ=WEEKLY;BYMONTHDAY=1"
+
+	_, err := service.CreateTask(context.Background(), task)
+	require.ErrorIs(t, err, domain.ErrInvalidRecurrence)
+}
+
+func TestGetOccurrences(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int
+		repoMock func(repoMock mock.MockTasksRepo)
+		expected []time.Time
+		checks   func(t *testing.T, err error)
+	}{
+		{
+			name:  "happy path - OK",
+			limit: 5,
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().GetTaskById(gomock.Any(), gomock.Any()).Return(getRecurringTask(domain.StatusPending), nil)
+			},
+			// COUNT=3 includes the current occurrence, so two more remain.
+			expected: []time.Time{
+				time.Date(2025, 2, 28, 8, 0, 0, 0, time.UTC),
+				time.Date(2025, 3, 31, 7, 0, 0, 0, time.UTC),
+			},
+			checks: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:  "limit",
+			limit: 1,
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().GetTaskById(gomock.Any(), gomock.Any()).Return(getRecurringTask(domain.StatusPending), nil)
+			},
+			expected: []time.Time{
+				time.Date(2025, 2, 28, 8, 0, 0, 0, time.UTC),
+			},
+			checks: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:  "not recurring",
+			limit: 5,
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().GetTaskById(gomock.Any(), gomock.Any()).Return(getTask(), nil)
+			},
+			expected: []time.Time{},
+			checks: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:  "no task found",
+			limit: 5,
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().GetTaskById(gomock.Any(), gomock.Any()).Return(domain.Task{}, domain.ErrTaskNotFound)
+			},
+			checks: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrTaskNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock.NewMockTasksRepo(ctrl)
+			service := NewTasksService(repo)
+
+			if tt.repoMock != nil {
+				tt.repoMock(*repo)
+			}
+
+			result, err := service.GetOccurrences(context.Background(), getTask().ID, tt.limit)
+			tt.checks(t, err)
+			require.Equal(t, len(tt.expected), len(result))
+			for i := range tt.expected {
+				require.True(t, tt.expected[i].Equal(result[i]), "expected %v, got %v", tt.expected[i], result[i])
+			}
+		})
+	}
+}
+
+func TestSearchTasks(t *testing.T) {
+	fullTextResult := domain.TaskSearchResult{Task: getTask(), Rank: 0.5, Snippet: "<mark>test</mark>", Match: domain.MatchFullText}
+	similarityResult := domain.TaskSearchResult{Task: getTask(), Rank: 0.8, Snippet: "test", Match: domain.MatchSimilarity}
+
+	tests := []struct {
+		name     string
+		repoMock func(repoMock mock.MockTasksRepo)
+		expected []domain.TaskSearchResult
+		checks   func(t *testing.T, err error)
+	}{
+		{
+			name: "full-text hits - OK",
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().SearchTasks(gomock.Any(), "tset", 10).Return([]domain.TaskSearchResult{fullTextResult}, nil)
+			},
+			expected: []domain.TaskSearchResult{fullTextResult},
+			checks: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "no full-text hits - falls back to similarity",
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().SearchTasks(gomock.Any(), "tset", 10).Return([]domain.TaskSearchResult{}, nil)
+				repoMock.EXPECT().SearchTasksBySimilarity(gomock.Any(), "tset", 10).Return([]domain.TaskSearchResult{similarityResult}, nil)
+			},
+			expected: []domain.TaskSearchResult{similarityResult},
+			checks: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "full-text error",
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().SearchTasks(gomock.Any(), "tset", 10).Return(nil, errors.New("db error"))
+			},
+			checks: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "db error")
+			},
+		},
+		{
+			name: "similarity error",
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().SearchTasks(gomock.Any(), "tset", 10).Return([]domain.TaskSearchResult{}, nil)
+				repoMock.EXPECT().SearchTasksBySimilarity(gomock.Any(), "tset", 10).Return(nil, errors.New("db error"))
+			},
+			checks: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "db error")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock.NewMockTasksRepo(ctrl)
+			service := NewTasksService(repo)
+
+			if tt.repoMock != nil {
+				tt.repoMock(*repo)
+			}
+
+			result, err := service.SearchTasks(context.Background(), "tset", 10)
+			tt.checks(t, err)
+			require.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestDeleteTask(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoMock func(repoMock mock.MockTasksRepo)
+		checks   func(t *testing.T, err error)
+	}{
+		{
+			name: "happy path - OK",
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().DeleteTask(gomock.Any(), getTask().ID).Return(getTask(), nil)
+			},
+			checks: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "no task found",
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().DeleteTask(gomock.Any(), getTask().ID).Return(domain.Task{}, domain.ErrTaskNotFound)
+			},
+			checks: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrTaskNotFound)
+			},
+		},
+		{
+			name: "db error",
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().DeleteTask(gomock.Any(), getTask().ID).Return(domain.Task{}, errors.New("db error"))
+			},
+			checks: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "error deleting task")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock.NewMockTasksRepo(ctrl)
+			service := NewTasksService(repo)
+
+			if tt.repoMock != nil {
+				tt.repoMock(*repo)
+			}
+
+			_, err := service.DeleteTask(context.Background(), getTask().ID)
+			tt.checks(t, err)
+		})
+	}
+}
+
+func TestRestoreTask(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoMock func(repoMock mock.MockTasksRepo)
+		checks   func(t *testing.T, err error)
+	}{
+		{
+			name: "happy path - OK",
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().RestoreTask(gomock.Any(), getTask().ID).Return(getTask(), nil)
+			},
+			checks: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "not in trash",
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().RestoreTask(gomock.Any(), getTask().ID).Return(domain.Task{}, domain.ErrTaskNotFound)
+			},
+			checks: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrTaskNotFound)
+			},
+		},
+		{
+			name: "db error",
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().RestoreTask(gomock.Any(), getTask().ID).Return(domain.Task{}, errors.New("db error"))
+			},
+			checks: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "error restoring task")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock.NewMockTasksRepo(ctrl)
+			service := NewTasksService(repo)
+
+			if tt.repoMock != nil {
+				tt.repoMock(*repo)
+			}
+
+			_, err := service.RestoreTask(context.Background(), getTask().ID)
+			tt.checks(t, err)
+		})
+	}
+}
+
+func TestPurgeTask(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoMock func(repoMock mock.MockTasksRepo)
+		checks   func(t *testing.T, err error)
+	}{
+		{
+			name: "happy path - OK",
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().PurgeTask(gomock.Any(), getTask().ID).Return(nil)
+			},
+			checks: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "not in trash",
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().PurgeTask(gomock.Any(), getTask().ID).Return(domain.ErrTaskNotFound)
+			},
+			checks: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrTaskNotFound)
+			},
+		},
+		{
+			name: "db error",
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().PurgeTask(gomock.Any(), getTask().ID).Return(errors.New("db error"))
+			},
+			checks: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "error purging task")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock.NewMockTasksRepo(ctrl)
+			service := NewTasksService(repo)
+
+			if tt.repoMock != nil {
+				tt.repoMock(*repo)
+			}
+
+			err := service.PurgeTask(context.Background(), getTask().ID)
+			tt.checks(t, err)
+		})
+	}
+}
+
+func TestExportTasks(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoMock func(repoMock mock.MockTasksRepo)
+		checks   func(t *testing.T, err error)
+	}{
+		{
+			name: "happy path - OK",
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().ExportTasks(gomock.Any(), domain.TaskFilter{Status: "PENDING"}, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, filter domain.TaskFilter, fn func(task domain.Task) error) error {
+						return fn(getTask())
+					})
+			},
+			checks: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "db error",
+			repoMock: func(repoMock mock.MockTasksRepo) {
+				repoMock.EXPECT().ExportTasks(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+			},
+			checks: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "error exporting tasks")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock.NewMockTasksRepo(ctrl)
+			service := NewTasksService(repo)
+
+			if tt.repoMock != nil {
+				tt.repoMock(*repo)
+			}
+
+			err := service.ExportTasks(context.Background(), domain.TaskFilter{Status: "PENDING"}, func(task domain.Task) error {
+				return nil
+			})
+			tt.checks(t, err)
+		})
+	}
+}